@@ -0,0 +1,233 @@
+package clienthints
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+var (
+	// ErrUnknownHint is returned by Builder for a hint that is not one of the
+	// Hint constants.
+	ErrUnknownHint = errors.New("clienthints: unknown client hint")
+
+	// ErrInvalidOrigin is returned by Builder for a delegation target that is
+	// not "*" or a serialized origin such as "https://cdn.example.com".
+	ErrInvalidOrigin = errors.New("clienthints: invalid origin")
+)
+
+// Hint is the name of a client hint request header, as listed in Accept-CH.
+type Hint string
+
+// Known client hints.
+const (
+	UA                         Hint = headers.SecCHUA
+	UAArch                     Hint = headers.SecCHUAArch
+	UABitness                  Hint = headers.SecCHUABitness
+	UAFormFactors              Hint = headers.SecCHUAFormFactors
+	UAFullVersionList          Hint = headers.SecCHUAFullVersionList
+	UAMobile                   Hint = headers.SecCHUAMobile
+	UAModel                    Hint = headers.SecCHUAModel
+	UAPlatform                 Hint = headers.SecCHUAPlatform
+	UAPlatformVersion          Hint = headers.SecCHUAPlatformVersion
+	UAWoW64                    Hint = headers.SecCHUAWoW64
+	PrefersColorScheme         Hint = headers.SecCHPrefersColorScheme
+	PrefersReducedMotion       Hint = headers.SecCHPrefersReducedMotion
+	PrefersReducedTransparency Hint = headers.SecCHPrefersReducedTransparency
+	DeviceMemory               Hint = headers.SecCHDeviceMemory
+	DPR                        Hint = headers.SecCHDPR
+	ViewportWidth              Hint = headers.SecCHViewportWidth
+	ViewportHeight             Hint = headers.SecCHViewportHeight
+	Downlink                   Hint = headers.Downlink
+	ECT                        Hint = headers.ECT
+	RTT                        Hint = headers.RTT
+	SaveData                   Hint = headers.SaveData
+)
+
+var known = []Hint{
+	UA, UAArch, UABitness, UAFormFactors, UAFullVersionList, UAMobile, UAModel,
+	UAPlatform, UAPlatformVersion, UAWoW64,
+	PrefersColorScheme, PrefersReducedMotion, PrefersReducedTransparency,
+	DeviceMemory, DPR, ViewportWidth, ViewportHeight,
+	Downlink, ECT, RTT, SaveData,
+}
+
+// Known reports whether h is one of the Hint constants, compared
+// case-insensitively.
+func (h Hint) Known() bool {
+	return h.canonical() != ""
+}
+
+// canonical returns the constant matching h, or "" if there is none.
+func (h Hint) canonical() Hint {
+	for _, k := range known {
+		if strings.EqualFold(string(k), string(h)) {
+			return k
+		}
+	}
+	return ""
+}
+
+// Feature returns the Permissions-Policy feature that controls whether the
+// hint is sent to other origins, such as "ch-ua-model" for Sec-CH-UA-Model.
+func (h Hint) Feature() string {
+	name := strings.ToLower(string(h))
+	return "ch-" + strings.TrimPrefix(name, "sec-ch-")
+}
+
+// Builder builds the response headers that ask browsers for client hints:
+// Accept-CH lists the hints wanted on later requests, Critical-CH those the
+// response depends on enough that the browser should retry the request at
+// once with them, and Permissions-Policy the other origins, such as an image
+// CDN, that may receive them. Methods record the first error, which Header
+// and Set return.
+//
+// Example:
+//
+//	err := clienthints.NewBuilder().
+//	    Accept(clienthints.DPR, clienthints.ViewportWidth).
+//	    Critical(clienthints.PrefersColorScheme).
+//	    Delegate("https://img.example.com").
+//	    Set(w)
+type Builder struct {
+	accept   []Hint
+	critical []Hint
+	origins  []string
+	err      error
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Accept adds hints to Accept-CH.
+func (b *Builder) Accept(hints ...Hint) *Builder {
+	for _, h := range hints {
+		if k := b.check(h); k != "" && !slices.Contains(b.accept, k) {
+			b.accept = append(b.accept, k)
+		}
+	}
+	return b
+}
+
+// Critical adds hints to both Accept-CH and Critical-CH. The response also
+// gets a Vary entry for each, since its content depends on them.
+func (b *Builder) Critical(hints ...Hint) *Builder {
+	b.Accept(hints...)
+	for _, h := range hints {
+		if k := b.check(h); k != "" && !slices.Contains(b.critical, k) {
+			b.critical = append(b.critical, k)
+		}
+	}
+	return b
+}
+
+// Delegate allows the accepted hints to be sent to the given origins, such as
+// "https://cdn.example.com", in addition to this one. "*" allows every
+// origin.
+func (b *Builder) Delegate(origins ...string) *Builder {
+	for _, origin := range origins {
+		if origin != "*" && !isOrigin(origin) {
+			b.fail(fmt.Errorf("%w: %q", ErrInvalidOrigin, origin))
+			continue
+		}
+		if !slices.Contains(b.origins, origin) {
+			b.origins = append(b.origins, origin)
+		}
+	}
+	return b
+}
+
+func (b *Builder) check(h Hint) Hint {
+	k := h.canonical()
+	if k == "" {
+		b.fail(fmt.Errorf("%w: %q", ErrUnknownHint, string(h)))
+	}
+	return k
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func isOrigin(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" &&
+		u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == "" &&
+		s == u.Scheme+"://"+u.Host
+}
+
+// Header returns the Accept-CH, Critical-CH, Vary and Permissions-Policy
+// headers to add to a response, leaving out those that would be empty.
+// Returns the first error recorded by the builder's methods.
+//
+// Permissions-Policy gets an entry per accepted hint, such as
+// `ch-dpr=(self "https://cdn.example.com")`, only when Delegate was called. A
+// response that already sends a Permissions-Policy has to merge the two.
+func (b *Builder) Header() (http.Header, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	h := http.Header{}
+	if len(b.accept) > 0 {
+		h.Set(headers.AcceptCH, joinHints(b.accept))
+	}
+	if len(b.critical) > 0 {
+		h.Set(headers.CriticalCH, joinHints(b.critical))
+		h.Set(headers.Vary, joinHints(b.critical))
+	}
+	if len(b.origins) > 0 && len(b.accept) > 0 {
+		var allowlist sfv.Member = sfv.Item{Value: sfv.Token("*")}
+		if !slices.Contains(b.origins, "*") {
+			list := sfv.InnerList{Items: []sfv.Item{{Value: sfv.Token("self")}}}
+			for _, origin := range b.origins {
+				list.Items = append(list.Items, sfv.Item{Value: origin})
+			}
+			allowlist = list
+		}
+		var policy sfv.Dictionary
+		for _, hint := range b.accept {
+			policy = append(policy, sfv.DictMember{Key: hint.Feature(), Value: allowlist})
+		}
+		value, err := sfv.FormatDictionary(policy)
+		if err != nil {
+			return nil, err
+		}
+		h.Set(headers.PermissionsPolicy, value)
+	}
+	return h, nil
+}
+
+// Set adds the headers returned by Header to w. Vary is merged with
+// headers.AddVary rather than replaced. Nothing is written if the builder recorded an error.
+func (b *Builder) Set(w http.ResponseWriter) error {
+	h, err := b.Header()
+	if err != nil {
+		return err
+	}
+	for name, values := range h {
+		if name == headers.Vary {
+			headers.AddVary(w.Header(), headers.VaryFields(h)...)
+			continue
+		}
+		w.Header()[name] = values
+	}
+	return nil
+}
+
+func joinHints(hints []Hint) string {
+	names := make([]string, len(hints))
+	for i, h := range hints {
+		names[i] = string(h)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,127 @@
+package clienthints_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/clienthints"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		hint    clienthints.Hint
+		known   bool
+		feature string
+	}{
+		{clienthints.UAModel, true, "ch-ua-model"},
+		{clienthints.DPR, true, "ch-dpr"},
+		{clienthints.SaveData, true, "ch-save-data"},
+		{"sec-ch-ua-platform", true, "ch-ua-platform"},
+		{"Sec-CH-Unknown", false, "ch-unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.hint), func(t *testing.T) {
+			if got := tt.hint.Known(); got != tt.known {
+				t.Errorf("Known() = %v, want %v", got, tt.known)
+			}
+			if got := tt.hint.Feature(); got != tt.feature {
+				t.Errorf("Feature() = %q, want %q", got, tt.feature)
+			}
+		})
+	}
+}
+
+func TestBuilderHeader(t *testing.T) {
+	h, err := clienthints.NewBuilder().
+		Accept(clienthints.DPR, clienthints.ViewportWidth, "sec-ch-dpr").
+		Critical(clienthints.PrefersColorScheme).
+		Delegate("https://img.example.com", "https://img.example.com").
+		Header()
+	if err != nil {
+		t.Fatalf("Header() error = %v", err)
+	}
+
+	expected := map[string]string{
+		headers.AcceptCH:   "Sec-CH-DPR, Sec-CH-Viewport-Width, Sec-CH-Prefers-Color-Scheme",
+		headers.CriticalCH: "Sec-CH-Prefers-Color-Scheme",
+		headers.Vary:       "Sec-CH-Prefers-Color-Scheme",
+		headers.PermissionsPolicy: `ch-dpr=(self "https://img.example.com"), ` +
+			`ch-viewport-width=(self "https://img.example.com"), ` +
+			`ch-prefers-color-scheme=(self "https://img.example.com")`,
+	}
+	if len(h) != len(expected) {
+		t.Errorf("Header() = %v, want %d headers", h, len(expected))
+	}
+	for name, want := range expected {
+		if got := h.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBuilderWildcardAndMinimal(t *testing.T) {
+	h, err := clienthints.NewBuilder().Accept(clienthints.UAModel).Delegate("https://a.example", "*").Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Get(headers.PermissionsPolicy); got != "ch-ua-model=*" {
+		t.Errorf("Permissions-Policy = %q, want %q", got, "ch-ua-model=*")
+	}
+
+	h, err = clienthints.NewBuilder().Accept(clienthints.UAModel).Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 1 || h.Get(headers.AcceptCH) != "Sec-CH-UA-Model" {
+		t.Errorf("Header() = %v, want Accept-CH only", h)
+	}
+
+	if h, err := clienthints.NewBuilder().Header(); err != nil || len(h) != 0 {
+		t.Errorf("Header() = %v, %v, want empty", h, err)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *clienthints.Builder
+		err     error
+	}{
+		{"unknown hint", clienthints.NewBuilder().Accept(clienthints.DPR, "Sec-CH-Nope"), clienthints.ErrUnknownHint},
+		{"unknown critical hint", clienthints.NewBuilder().Critical("Width"), clienthints.ErrUnknownHint},
+		{"origin with path", clienthints.NewBuilder().Delegate("https://cdn.example.com/images"), clienthints.ErrInvalidOrigin},
+		{"origin without scheme", clienthints.NewBuilder().Delegate("cdn.example.com"), clienthints.ErrInvalidOrigin},
+		{"self keyword", clienthints.NewBuilder().Delegate("self"), clienthints.ErrInvalidOrigin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Header(); !errors.Is(err, tt.err) {
+				t.Errorf("Header() error = %v, want %v", err, tt.err)
+			}
+			w := httptest.NewRecorder()
+			if err := tt.builder.Set(w); !errors.Is(err, tt.err) || len(w.Header()) != 0 {
+				t.Errorf("Set() error = %v, headers %v", err, w.Header())
+			}
+		})
+	}
+}
+
+func TestBuilderSet(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(headers.Vary, "Accept-Encoding")
+
+	err := clienthints.NewBuilder().Critical(clienthints.UAMobile).Set(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Header().Values(headers.Vary); len(got) != 2 || got[1] != "Sec-CH-UA-Mobile" {
+		t.Errorf("Vary = %q, want it appended to", got)
+	}
+	if got := w.Header().Get(headers.AcceptCH); got != "Sec-CH-UA-Mobile" {
+		t.Errorf("Accept-CH = %q", got)
+	}
+}
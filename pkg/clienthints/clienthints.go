@@ -0,0 +1,239 @@
+package clienthints
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// Hints reads the client hints of a request. Create one with From.
+type Hints struct {
+	header http.Header
+}
+
+// From returns the client hints of r.
+func From(r *http.Request) Hints {
+	return Hints{header: r.Header}
+}
+
+// Brand is one entry of the Sec-CH-UA or Sec-CH-UA-Full-Version-List brand
+// lists, such as {"Google Chrome", "124"}.
+type Brand struct {
+	Brand   string
+	Version string
+}
+
+// ColorScheme is the value of the Sec-CH-Prefers-Color-Scheme hint.
+type ColorScheme uint8
+
+// Color schemes reported by PrefersColorScheme.
+const (
+	ColorSchemeUnknown ColorScheme = iota // Hint missing or unrecognized
+	ColorSchemeLight
+	ColorSchemeDark
+)
+
+// String returns "light", "dark" or "unknown".
+func (c ColorScheme) String() string {
+	switch c {
+	case ColorSchemeLight:
+		return "light"
+	case ColorSchemeDark:
+		return "dark"
+	}
+	return "unknown"
+}
+
+// Has reports whether the request carries the named hint, so that a zero
+// result from an accessor can be told apart from a missing hint.
+func (h Hints) Has(name string) bool {
+	return len(h.header.Values(name)) > 0
+}
+
+// item parses the named hint as a structured field item, returning the zero
+// Item if it is missing or invalid.
+func (h Hints) item(name string) sfv.Item {
+	item, err := sfv.ParseItem(h.header.Get(name))
+	if err != nil {
+		return sfv.Item{}
+	}
+	return item
+}
+
+func (h Hints) bool(name string) bool {
+	item := h.item(name)
+	b, _ := item.Value.(bool)
+	return b
+}
+
+// string returns a string hint, also accepting a token as some user agents
+// send one where the specification calls for a string.
+func (h Hints) string(name string) string {
+	item := h.item(name)
+	switch v := item.Value.(type) {
+	case string:
+		return v
+	case sfv.Token:
+		return string(v)
+	}
+	return ""
+}
+
+// number returns a non-negative integer or decimal hint, or 0.
+func (h Hints) number(name string) float64 {
+	item := h.item(name)
+	switch v := item.Value.(type) {
+	case int64:
+		return float64(max(v, 0))
+	case float64:
+		return max(v, 0)
+	}
+	return 0
+}
+
+func (h Hints) brands(name string) []Brand {
+	list, err := sfv.ParseList(strings.Join(h.header.Values(name), ", "))
+	if err != nil {
+		return nil
+	}
+	var brands []Brand
+	for _, m := range list {
+		item, ok := m.(sfv.Item)
+		if !ok {
+			continue
+		}
+		brand, ok := item.Value.(string)
+		if !ok {
+			continue
+		}
+		v, _ := item.Params.Get("v")
+		version, _ := v.(string)
+		brands = append(brands, Brand{Brand: brand, Version: version})
+	}
+	return brands
+}
+
+// Mobile reports whether the user agent prefers a mobile experience, from
+// Sec-CH-UA-Mobile: ?1. Returns false if the hint is missing or invalid.
+func (h Hints) Mobile() bool {
+	return h.bool(headers.SecCHUAMobile)
+}
+
+// Platform returns the operating system from Sec-CH-UA-Platform, such as
+// "Windows", "macOS" or "Android", or "" if the hint is missing or invalid.
+func (h Hints) Platform() string {
+	return h.string(headers.SecCHUAPlatform)
+}
+
+// PlatformVersion returns the operating system version from
+// Sec-CH-UA-Platform-Version, such as "14.4.1", or "" if the hint is missing
+// or invalid.
+func (h Hints) PlatformVersion() string {
+	return h.string(headers.SecCHUAPlatformVersion)
+}
+
+// Model returns the device model from Sec-CH-UA-Model, such as "Pixel 8", or
+// "" if the hint is missing, invalid or empty, as it is on desktops.
+func (h Hints) Model() string {
+	return h.string(headers.SecCHUAModel)
+}
+
+// Arch returns the CPU architecture from Sec-CH-UA-Arch, such as "x86" or
+// "arm", or "" if the hint is missing or invalid.
+func (h Hints) Arch() string {
+	return h.string(headers.SecCHUAArch)
+}
+
+// Brands returns the brand list from Sec-CH-UA with significant versions, such
+// as {"Chromium", "124"}. Browsers add made-up "greasing" brands to this
+// list, so look for the brand you need rather than taking the first. Returns
+// nil if the hint is missing or invalid.
+func (h Hints) Brands() []Brand {
+	return h.brands(headers.SecCHUA)
+}
+
+// FullVersionList returns the brand list from Sec-CH-UA-Full-Version-List,
+// with full versions such as "124.0.6367.119". Returns nil if the hint is
+// missing or invalid.
+func (h Hints) FullVersionList() []Brand {
+	return h.brands(headers.SecCHUAFullVersionList)
+}
+
+// DeviceMemory returns the approximate device memory in GiB from
+// Sec-CH-Device-Memory, such as 0.5 or 8. Returns 0 if the hint is missing or
+// invalid.
+func (h Hints) DeviceMemory() float64 {
+	return h.number(headers.SecCHDeviceMemory)
+}
+
+// DPR returns the device pixel ratio from Sec-CH-DPR, such as 2 or 1.5.
+// Returns 0 if the hint is missing or invalid.
+func (h Hints) DPR() float64 {
+	return h.number(headers.SecCHDPR)
+}
+
+// ViewportWidth returns the layout viewport width in CSS pixels from
+// Sec-CH-Viewport-Width. Returns 0 if the hint is missing or invalid.
+func (h Hints) ViewportWidth() int {
+	item := h.item(headers.SecCHViewportWidth)
+	n, _ := item.Value.(int64)
+	return int(max(n, 0))
+}
+
+// ViewportHeight returns the layout viewport height in CSS pixels from
+// Sec-CH-Viewport-Height. Returns 0 if the hint is missing or invalid.
+func (h Hints) ViewportHeight() int {
+	item := h.item(headers.SecCHViewportHeight)
+	n, _ := item.Value.(int64)
+	return int(max(n, 0))
+}
+
+// PrefersColorScheme returns the color scheme from
+// Sec-CH-Prefers-Color-Scheme, or ColorSchemeUnknown if the hint is missing or
+// invalid.
+func (h Hints) PrefersColorScheme() ColorScheme {
+	switch h.string(headers.SecCHPrefersColorScheme) {
+	case "light":
+		return ColorSchemeLight
+	case "dark":
+		return ColorSchemeDark
+	}
+	return ColorSchemeUnknown
+}
+
+// PrefersReducedMotion reports whether the user asked for fewer animations,
+// from Sec-CH-Prefers-Reduced-Motion: "reduce".
+func (h Hints) PrefersReducedMotion() bool {
+	return h.string(headers.SecCHPrefersReducedMotion) == "reduce"
+}
+
+// SaveData reports whether the user asked for reduced data usage, from
+// Save-Data: on.
+func (h Hints) SaveData() bool {
+	return strings.EqualFold(strings.TrimSpace(h.header.Get(headers.SaveData)), "on")
+}
+
+// ECT returns the effective connection type from the ECT hint: "slow-2g",
+// "2g", "3g" or "4g", or "" if the hint is missing or invalid.
+func (h Hints) ECT() string {
+	switch ect := strings.ToLower(strings.TrimSpace(h.header.Get(headers.ECT))); ect {
+	case "slow-2g", "2g", "3g", "4g":
+		return ect
+	}
+	return ""
+}
+
+// RTT returns the approximate round trip time from the RTT hint. Returns 0 if
+// the hint is missing or invalid.
+func (h Hints) RTT() time.Duration {
+	return time.Duration(h.number(headers.RTT) * float64(time.Millisecond))
+}
+
+// Downlink returns the approximate bandwidth in megabits per second from the
+// Downlink hint. Returns 0 if the hint is missing or invalid.
+func (h Hints) Downlink() float64 {
+	return h.number(headers.Downlink)
+}
@@ -0,0 +1,151 @@
+package clienthints_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/clienthints"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func hints(header map[string]string) clienthints.Hints {
+	r := httptest.NewRequest("GET", "/", nil)
+	for name, value := range header {
+		r.Header.Set(name, value)
+	}
+	return clienthints.From(r)
+}
+
+func TestUserAgentHints(t *testing.T) {
+	ch := hints(map[string]string{
+		headers.SecCHUA:                `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`,
+		headers.SecCHUAFullVersionList: `"Chromium";v="124.0.6367.119", "Not-A.Brand"`,
+		headers.SecCHUAMobile:          "?1",
+		headers.SecCHUAPlatform:        `"Android"`,
+		headers.SecCHUAPlatformVersion: `"14.0.0"`,
+		headers.SecCHUAModel:           `"Pixel 8"`,
+		headers.SecCHUAArch:            "arm",
+	})
+
+	if !ch.Mobile() {
+		t.Error("Mobile() = false, want true")
+	}
+	if got := ch.Platform(); got != "Android" {
+		t.Errorf("Platform() = %q, want %q", got, "Android")
+	}
+	if got := ch.PlatformVersion(); got != "14.0.0" {
+		t.Errorf("PlatformVersion() = %q", got)
+	}
+	if got := ch.Model(); got != "Pixel 8" {
+		t.Errorf("Model() = %q", got)
+	}
+	if got := ch.Arch(); got != "arm" {
+		t.Errorf("Arch() = %q, want token accepted", got)
+	}
+
+	expected := []clienthints.Brand{{"Chromium", "124"}, {"Google Chrome", "124"}, {"Not-A.Brand", "99"}}
+	if got := ch.Brands(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Brands() = %v, want %v", got, expected)
+	}
+	expected = []clienthints.Brand{{"Chromium", "124.0.6367.119"}, {"Not-A.Brand", ""}}
+	if got := ch.FullVersionList(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("FullVersionList() = %v, want %v", got, expected)
+	}
+}
+
+func TestDeviceHints(t *testing.T) {
+	ch := hints(map[string]string{
+		headers.SecCHDeviceMemory:   "0.5",
+		headers.SecCHDPR:            "2",
+		headers.SecCHViewportWidth:  "1280",
+		headers.SecCHViewportHeight: "-5",
+		headers.RTT:                 "150",
+		headers.Downlink:            "1.75",
+		headers.ECT:                 "4G",
+		headers.SaveData:            "on",
+	})
+
+	if got := ch.DeviceMemory(); got != 0.5 {
+		t.Errorf("DeviceMemory() = %v, want 0.5", got)
+	}
+	if got := ch.DPR(); got != 2 {
+		t.Errorf("DPR() = %v, want 2", got)
+	}
+	if got := ch.ViewportWidth(); got != 1280 {
+		t.Errorf("ViewportWidth() = %v, want 1280", got)
+	}
+	if got := ch.ViewportHeight(); got != 0 {
+		t.Errorf("ViewportHeight() = %v, want 0 for a negative value", got)
+	}
+	if got := ch.RTT(); got != 150*time.Millisecond {
+		t.Errorf("RTT() = %v", got)
+	}
+	if got := ch.Downlink(); got != 1.75 {
+		t.Errorf("Downlink() = %v", got)
+	}
+	if got := ch.ECT(); got != "4g" {
+		t.Errorf("ECT() = %q, want %q", got, "4g")
+	}
+	if !ch.SaveData() {
+		t.Error("SaveData() = false, want true")
+	}
+}
+
+func TestPreferenceHints(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected clienthints.ColorScheme
+	}{
+		{`"dark"`, clienthints.ColorSchemeDark},
+		{"light", clienthints.ColorSchemeLight},
+		{`"sepia"`, clienthints.ColorSchemeUnknown},
+		{"", clienthints.ColorSchemeUnknown},
+	}
+	for _, tt := range tests {
+		ch := hints(map[string]string{headers.SecCHPrefersColorScheme: tt.value})
+		if got := ch.PrefersColorScheme(); got != tt.expected {
+			t.Errorf("PrefersColorScheme(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+
+	if !hints(map[string]string{headers.SecCHPrefersReducedMotion: `"reduce"`}).PrefersReducedMotion() {
+		t.Error("PrefersReducedMotion() = false, want true")
+	}
+	if hints(map[string]string{headers.SecCHPrefersReducedMotion: `"no-preference"`}).PrefersReducedMotion() {
+		t.Error("PrefersReducedMotion() = true, want false")
+	}
+}
+
+func TestMissingAndInvalidHints(t *testing.T) {
+	ch := hints(map[string]string{
+		headers.SecCHUAMobile:      "yes",
+		headers.SecCHUAPlatform:    `"unterminated`,
+		headers.SecCHUA:            `"Chromium";v=`,
+		headers.SecCHViewportWidth: "12.5",
+		headers.ECT:                "5g",
+	})
+
+	if ch.Mobile() || ch.Platform() != "" || ch.Brands() != nil || ch.ViewportWidth() != 0 || ch.ECT() != "" {
+		t.Error("invalid hints did not fall back to zero values")
+	}
+	if ch.DPR() != 0 || ch.DeviceMemory() != 0 || ch.SaveData() || ch.PrefersColorScheme() != clienthints.ColorSchemeUnknown {
+		t.Error("missing hints did not fall back to zero values")
+	}
+	if !ch.Has(headers.SecCHUAMobile) || ch.Has(headers.SecCHDPR) {
+		t.Error("Has() did not report which hints were sent")
+	}
+}
+
+func TestColorSchemeString(t *testing.T) {
+	for scheme, expected := range map[clienthints.ColorScheme]string{
+		clienthints.ColorSchemeUnknown: "unknown",
+		clienthints.ColorSchemeLight:   "light",
+		clienthints.ColorSchemeDark:    "dark",
+	} {
+		if got := scheme.String(); got != expected {
+			t.Errorf("String() = %q, want %q", got, expected)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Package clienthints reads HTTP Client Hints, the Sec-CH-* request headers
+// browsers send when a server asks for them with Accept-CH, as typed values.
+//
+// Client hints are Structured Field Values, so they are parsed with the sfv
+// package: quoted strings are unquoted, ?1 and ?0 are booleans, and numbers
+// may be integers or decimals. Like the rest of this module, the accessors
+// are fail-safe: a missing or malformed hint yields the zero value, and Has
+// tells the two apart when it matters.
+//
+// Example:
+//
+//	ch := clienthints.From(r)
+//	if ch.Mobile() {
+//	    // serve the compact layout
+//	}
+//	width := ch.ViewportWidth()        // 0 if unknown
+//	dark := ch.PrefersColorScheme() == clienthints.ColorSchemeDark
+//
+// # Asking for Hints
+//
+// Browsers only send most hints after the server asks for them, and only
+// over HTTPS. Builder emits the three headers involved: Accept-CH for the
+// hints wanted, Critical-CH for those the response cannot do without, and the
+// Permissions-Policy entries that let other origins receive them:
+//
+//	err := clienthints.NewBuilder().
+//	    Accept(clienthints.DPR, clienthints.ViewportWidth).
+//	    Critical(clienthints.PrefersColorScheme).
+//	    Delegate("https://img.example.com").
+//	    Set(w)
+//
+// Hints are given as Hint constants, and unknown names are reported as
+// errors rather than sent.
+package clienthints
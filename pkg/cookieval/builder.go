@@ -0,0 +1,155 @@
+package cookieval
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSecureRequired is returned by Builder when a cookie needs the Secure
+	// attribute: one named with a __Secure- or __Host- prefix, one with
+	// SameSite=None, or a Partitioned one.
+	ErrSecureRequired = errors.New("cookieval: cookie must be Secure")
+
+	// ErrHostPrefix is returned by Builder for a __Host- cookie with a Domain
+	// or a Path other than "/", which browsers reject.
+	ErrHostPrefix = errors.New("cookieval: __Host- cookie must have Path=/ and no Domain")
+)
+
+// Builder builds a Set-Cookie header and checks the attribute combinations
+// that browsers silently reject. Create one with NewBuilder and finish it with
+// Cookie, Header or Set.
+type Builder struct {
+	c http.Cookie
+}
+
+// NewBuilder starts a cookie with the given name and value. Path defaults to
+// "/", so the cookie applies to the whole site rather than the directory of
+// the request that set it.
+//
+// Example:
+//
+//	err := cookieval.NewBuilder("__Host-sid", sid).
+//	    Secure().
+//	    HttpOnly().
+//	    SameSite(http.SameSiteLaxMode).
+//	    MaxAge(24 * time.Hour).
+//	    Set(w)
+func NewBuilder(name, value string) *Builder {
+	return &Builder{c: http.Cookie{Name: name, Value: value, Path: "/"}}
+}
+
+// Path sets the Path attribute.
+func (b *Builder) Path(path string) *Builder {
+	b.c.Path = path
+	return b
+}
+
+// Domain sets the Domain attribute, which also sends the cookie to subdomains.
+func (b *Builder) Domain(domain string) *Builder {
+	b.c.Domain = domain
+	return b
+}
+
+// MaxAge sets the Max-Age attribute to d, rounded down to whole seconds but
+// at least one second, and an Expires attribute at the same moment for
+// clients that ignore Max-Age. A d of zero or less deletes the cookie; see
+// Delete.
+func (b *Builder) MaxAge(d time.Duration) *Builder {
+	if d <= 0 {
+		return b.Delete()
+	}
+	seconds := max(int(d/time.Second), 1)
+	b.c.MaxAge = seconds
+	b.c.Expires = time.Now().Add(time.Duration(seconds) * time.Second)
+	return b
+}
+
+// Expires sets the Expires attribute. It is written in UTC in the IMF-fixdate
+// format regardless of t's location.
+func (b *Builder) Expires(t time.Time) *Builder {
+	b.c.Expires = t
+	return b
+}
+
+// Delete makes the cookie expire immediately, with Max-Age=0 and an Expires
+// date in the past. The name, Path and Domain must match the cookie to delete.
+func (b *Builder) Delete() *Builder {
+	b.c.MaxAge = -1
+	b.c.Expires = time.Unix(0, 0)
+	return b
+}
+
+// Secure sets the Secure attribute, so the cookie is only sent over HTTPS.
+func (b *Builder) Secure() *Builder {
+	b.c.Secure = true
+	return b
+}
+
+// HttpOnly sets the HttpOnly attribute, hiding the cookie from JavaScript.
+func (b *Builder) HttpOnly() *Builder {
+	b.c.HttpOnly = true
+	return b
+}
+
+// SameSite sets the SameSite attribute. http.SameSiteNoneMode requires Secure.
+func (b *Builder) SameSite(mode http.SameSite) *Builder {
+	b.c.SameSite = mode
+	return b
+}
+
+// Partitioned sets the Partitioned attribute, which keys a third-party cookie
+// to the top-level site it is embedded in (CHIPS). It requires Secure.
+func (b *Builder) Partitioned() *Builder {
+	b.c.Partitioned = true
+	return b
+}
+
+// Cookie returns the cookie after checking it. Beyond http.Cookie's own
+// validation of the name, value, path and domain, it enforces the rules of
+// the __Secure- and __Host- name prefixes, and that SameSite=None and
+// Partitioned cookies are Secure.
+func (b *Builder) Cookie() (*http.Cookie, error) {
+	c := b.c
+	host := strings.HasPrefix(c.Name, "__Host-")
+	switch {
+	case host && (c.Domain != "" || c.Path != "/"):
+		return nil, ErrHostPrefix
+	case c.Secure:
+	case host:
+		return nil, fmt.Errorf("%w: required by the __Host- prefix", ErrSecureRequired)
+	case strings.HasPrefix(c.Name, "__Secure-"):
+		return nil, fmt.Errorf("%w: required by the __Secure- prefix", ErrSecureRequired)
+	case c.SameSite == http.SameSiteNoneMode:
+		return nil, fmt.Errorf("%w: required by SameSite=None", ErrSecureRequired)
+	case c.Partitioned:
+		return nil, fmt.Errorf("%w: required by Partitioned", ErrSecureRequired)
+	}
+	if err := c.Valid(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Header returns the Set-Cookie header value after the checks of Cookie.
+func (b *Builder) Header() (string, error) {
+	c, err := b.Cookie()
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+// Set adds the Set-Cookie header to w after the checks of Cookie. Nothing is
+// added if they fail.
+func (b *Builder) Set(w http.ResponseWriter) error {
+	c, err := b.Cookie()
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, c)
+	return nil
+}
@@ -0,0 +1,103 @@
+package cookieval_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/cookieval"
+)
+
+func TestBuilderHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *cookieval.Builder
+		expected string
+	}{
+		{"defaults", cookieval.NewBuilder("theme", "dark"), "theme=dark; Path=/"},
+		{"session", cookieval.NewBuilder("__Host-sid", "abc").Secure().HttpOnly().SameSite(http.SameSiteLaxMode),
+			"__Host-sid=abc; Path=/; HttpOnly; Secure; SameSite=Lax"},
+		{"cross-site", cookieval.NewBuilder("embed", "1").Secure().SameSite(http.SameSiteNoneMode).Partitioned(),
+			"embed=1; Path=/; Secure; SameSite=None; Partitioned"},
+		{"scoped", cookieval.NewBuilder("__Secure-pref", "x").Secure().Domain("example.com").Path("/app"),
+			"__Secure-pref=x; Path=/app; Domain=example.com; Secure"},
+		{"expires", cookieval.NewBuilder("a", "b").Expires(time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))),
+			"a=b; Path=/; Expires=Wed, 02 Jan 2030 02:04:05 GMT"},
+		{"delete", cookieval.NewBuilder("sid", "").Delete(),
+			"sid=; Path=/; Expires=Thu, 01 Jan 1970 00:00:00 GMT; Max-Age=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Header()
+			if err != nil {
+				t.Fatalf("Header() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Header() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuilderMaxAge(t *testing.T) {
+	c, err := cookieval.NewBuilder("a", "b").MaxAge(90 * time.Minute).Cookie()
+	if err != nil {
+		t.Fatalf("Cookie() error = %v", err)
+	}
+	if c.MaxAge != 5400 || time.Until(c.Expires) < 89*time.Minute {
+		t.Errorf("MaxAge = %d, Expires = %v", c.MaxAge, c.Expires)
+	}
+
+	if c, _ := cookieval.NewBuilder("a", "b").MaxAge(time.Millisecond).Cookie(); c.MaxAge != 1 {
+		t.Errorf("MaxAge(1ms) = %d, want 1", c.MaxAge)
+	}
+	if c, _ := cookieval.NewBuilder("a", "b").MaxAge(0).Cookie(); c.MaxAge != -1 {
+		t.Errorf("MaxAge(0) = %d, want a deletion", c.MaxAge)
+	}
+}
+
+func TestBuilderChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *cookieval.Builder
+		err     error
+	}{
+		{"secure prefix", cookieval.NewBuilder("__Secure-a", "b"), cookieval.ErrSecureRequired},
+		{"host prefix", cookieval.NewBuilder("__Host-a", "b"), cookieval.ErrSecureRequired},
+		{"host prefix with domain", cookieval.NewBuilder("__Host-a", "b").Secure().Domain("example.com"), cookieval.ErrHostPrefix},
+		{"host prefix with path", cookieval.NewBuilder("__Host-a", "b").Secure().Path("/app"), cookieval.ErrHostPrefix},
+		{"same-site none", cookieval.NewBuilder("a", "b").SameSite(http.SameSiteNoneMode), cookieval.ErrSecureRequired},
+		{"partitioned", cookieval.NewBuilder("a", "b").Partitioned(), cookieval.ErrSecureRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Cookie(); !errors.Is(err, tt.err) {
+				t.Errorf("Cookie() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := cookieval.NewBuilder("bad name", "b").Header(); err == nil {
+		t.Error("Header() error = nil for an invalid name")
+	}
+}
+
+func TestBuilderSet(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := cookieval.NewBuilder("a", "b").HttpOnly().Set(w); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got := w.Header().Get("Set-Cookie"); got != "a=b; Path=/; HttpOnly" {
+		t.Errorf("Set-Cookie = %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	if err := cookieval.NewBuilder("__Host-a", "b").Set(w); err == nil || strings.Contains(w.Header().Get("Set-Cookie"), "a=") {
+		t.Errorf("Set() = %v and wrote %q for an invalid cookie", err, w.Header().Get("Set-Cookie"))
+	}
+}
@@ -0,0 +1,131 @@
+package cookieval
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+// ErrInvalidJSONTarget is returned by JSON when dst is not a non-nil pointer.
+var ErrInvalidJSONTarget = errors.New("cookieval: JSON destination must be a non-nil pointer")
+
+// std is a query.Extractor reading request cookies. Cookie names are
+// case-sensitive and key[] merging does not apply.
+var std = query.NewExtractor(query.Config{
+	Source:               cookieSource,
+	DisableArrayBrackets: true,
+})
+
+// cookieSource collects the request's cookies by name. A name sent more than
+// once, for example by cookies set on different paths, keeps every value in
+// the order the client sent them.
+func cookieSource(r *http.Request) url.Values {
+	values := make(url.Values)
+	for _, c := range r.Cookies() {
+		values[c.Name] = append(values[c.Name], c.Value)
+	}
+	return values
+}
+
+// Value extracts a cookie and converts it using the provided parser.
+// Returns defaultValue if the cookie is missing, empty, or cannot be parsed.
+func Value[T any](r *http.Request, name string, defaultValue T, parser query.Parser[T]) T {
+	return query.ValueWith(std, r, name, defaultValue, parser)
+}
+
+// String extracts a cookie value.
+// Returns defaultValue if the cookie is missing or empty.
+func String(r *http.Request, name string, defaultValue string) string {
+	return std.String(r, name, defaultValue)
+}
+
+// Int extracts an integer cookie value.
+// Returns defaultValue if the cookie is missing, empty, or not an int.
+//
+// Example:
+//
+//	items := cookieval.Int(r, "cart_size", 0)
+func Int(r *http.Request, name string, defaultValue int) int {
+	return std.Int(r, name, defaultValue)
+}
+
+// Int64 extracts an int64 cookie value.
+// Returns defaultValue if the cookie is missing, empty, or not an int64.
+func Int64(r *http.Request, name string, defaultValue int64) int64 {
+	return std.Int64(r, name, defaultValue)
+}
+
+// Float64 extracts a float64 cookie value.
+// Returns defaultValue if the cookie is missing, empty, or not a float64.
+func Float64(r *http.Request, name string, defaultValue float64) float64 {
+	return std.Float64(r, name, defaultValue)
+}
+
+// Bool extracts a boolean cookie value using the vocabulary of query.Bool.
+// Returns defaultValue if the cookie is missing, empty, or not recognized.
+func Bool(r *http.Request, name string, defaultValue bool) bool {
+	return std.Bool(r, name, defaultValue)
+}
+
+// Time extracts a time.Time cookie value, parsed with the given layouts or
+// query.DefaultTimeLayouts. Returns defaultValue if the cookie is missing,
+// empty, or matches none of the layouts.
+func Time(r *http.Request, name string, defaultValue time.Time, layouts ...string) time.Time {
+	return std.Time(r, name, defaultValue, layouts...)
+}
+
+// Has reports whether the cookie is present, even if empty.
+func Has(r *http.Request, name string) bool {
+	return std.Has(r, name)
+}
+
+// JSON decodes a JSON cookie value into dst, which must be a non-nil pointer.
+// The value may be plain JSON or percent-encoded JSON, as produced by
+// encodeURIComponent(JSON.stringify(v)) in browsers. dst is only modified when
+// decoding succeeds, so values stored in it beforehand act as the default.
+//
+// Returns nil without touching dst if the cookie is missing or empty.
+//
+// Example:
+//
+//	prefs := Prefs{Theme: "light"}  // default
+//	if err := cookieval.JSON(r, "prefs", &prefs); err != nil {
+//	    log.Printf("ignoring malformed prefs cookie: %v", err)
+//	}
+func JSON(r *http.Request, name string, dst any) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return ErrInvalidJSONTarget
+	}
+
+	val := std.String(r, name, "")
+	if val == "" {
+		return nil
+	}
+	if strings.Contains(val, "%") {
+		unescaped, err := url.PathUnescape(val)
+		if err != nil {
+			return err
+		}
+		val = unescaped
+	}
+
+	// Decode into a fresh value so a failure part-way through leaves dst intact.
+	dec := json.NewDecoder(strings.NewReader(val))
+	decoded := reflect.New(target.Elem().Type())
+	if err := dec.Decode(decoded.Interface()); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("cookieval: unexpected data after JSON value")
+	}
+
+	target.Elem().Set(decoded.Elem())
+	return nil
+}
@@ -0,0 +1,172 @@
+package cookieval_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/cookieval"
+)
+
+func newRequest(cookies ...*http.Cookie) *http.Request {
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestExtractors(t *testing.T) {
+	r := newRequest(
+		&http.Cookie{Name: "cart_size", Value: "3"},
+		&http.Cookie{Name: "dark_mode", Value: "on"},
+		&http.Cookie{Name: "ratio", Value: "0.75"},
+		&http.Cookie{Name: "seen", Value: "2024-03-01T12:00:00Z"},
+		&http.Cookie{Name: "name", Value: "alice"},
+		&http.Cookie{Name: "bad", Value: "x"},
+		&http.Cookie{Name: "Name", Value: "BOB"},
+	)
+
+	if got := cookieval.Int(r, "cart_size", 0); got != 3 {
+		t.Errorf("Int() = %v, want 3", got)
+	}
+	if got := cookieval.Int(r, "bad", -1); got != -1 {
+		t.Errorf("Int(invalid) = %v, want default", got)
+	}
+	if got := cookieval.Int64(r, "missing", 9); got != 9 {
+		t.Errorf("Int64(missing) = %v, want default", got)
+	}
+	if !cookieval.Bool(r, "dark_mode", false) {
+		t.Error("Bool() = false, want true")
+	}
+	if got := cookieval.Float64(r, "ratio", 0); got != 0.75 {
+		t.Errorf("Float64() = %v, want 0.75", got)
+	}
+	if got := cookieval.Time(r, "seen", time.Time{}); !got.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time() = %v", got)
+	}
+	if got := cookieval.String(r, "name", ""); got != "alice" {
+		t.Errorf("String() = %q, want alice (names are case-sensitive)", got)
+	}
+	if got := cookieval.Value(r, "name", 0, func(s string) (int, error) { return len(s), nil }); got != 5 {
+		t.Errorf("Value() = %v, want 5", got)
+	}
+	if !cookieval.Has(r, "bad") || cookieval.Has(r, "missing") {
+		t.Error("Has() should report present cookies only")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type prefs struct {
+		Theme string   `json:"theme"`
+		Langs []string `json:"langs"`
+	}
+	fallback := prefs{Theme: "light"}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected prefs
+		wantErr  bool
+	}{
+		{"percent-encoded", url.PathEscape(`{"theme":"dark","langs":["en","de"]}`), prefs{Theme: "dark", Langs: []string{"en", "de"}}, false},
+		{"plain", `{}`, prefs{}, false},
+		{"missing", "", fallback, false},
+		{"invalid", url.PathEscape(`{"theme":`), fallback, true},
+		{"bad escape", "%zz", fallback, true},
+		{"trailing data", url.PathEscape(`{} {}`), fallback, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest()
+			if tt.value != "" {
+				r.AddCookie(&http.Cookie{Name: "prefs", Value: tt.value})
+			}
+
+			got := fallback
+			err := cookieval.JSON(r, "prefs", &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("JSON() decoded %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+
+	if err := cookieval.JSON(newRequest(), "prefs", fallback); !errors.Is(err, cookieval.ErrInvalidJSONTarget) {
+		t.Errorf("JSON(non-pointer) error = %v, want ErrInvalidJSONTarget", err)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	current, previous := []byte("current-key"), []byte("previous-key")
+
+	signed, err := cookieval.Sign("uid", "42", current)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	oldSigned, _ := cookieval.Sign("uid", "7", previous)
+	otherCookie, _ := cookieval.Sign("role", "admin", current)
+
+	tests := []struct {
+		name     string
+		value    string
+		keys     [][]byte
+		expected string
+	}{
+		{"current key", signed, [][]byte{current, previous}, "42"},
+		{"rotated key", oldSigned, [][]byte{current, previous}, "7"},
+		{"retired key", oldSigned, [][]byte{current}, ""},
+		{"no keys", signed, nil, ""},
+		{"empty key ignored", signed, [][]byte{nil, current}, "42"},
+		{"tampered value", "NDM" + signed[3:], [][]byte{current}, ""},
+		{"tampered signature", signed[:len(signed)-2] + "AA", [][]byte{current}, ""},
+		{"other cookie's value", otherCookie, [][]byte{current}, ""},
+		{"unsigned", "42", [][]byte{current}, ""},
+		{"bad encoding", "!!.??", [][]byte{current}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest(&http.Cookie{Name: "uid", Value: tt.value})
+			if got := cookieval.Signed(r, "uid", "", tt.keys...); got != tt.expected {
+				t.Errorf("Signed() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := cookieval.Verify("uid", "42", current); !errors.Is(err, cookieval.ErrInvalidSignature) {
+		t.Errorf("Verify(unsigned) error = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := cookieval.Sign("uid", "42", nil); !errors.Is(err, cookieval.ErrEmptyKey) {
+		t.Errorf("Sign(nil key) error = %v, want ErrEmptyKey", err)
+	}
+}
+
+func TestSignedRoundTrip(t *testing.T) {
+	key := []byte("k")
+	value := `quotes " semicolons ; spaces and ünïcode`
+
+	signed, _ := cookieval.Sign("note", value, key)
+	w := httptest.NewRecorder()
+	http.SetCookie(w, &http.Cookie{Name: "note", Value: signed})
+
+	// The signed value survives a real Set-Cookie/Cookie round trip unchanged.
+	r := newRequest(w.Result().Cookies()...)
+	if got := cookieval.Signed(r, "note", "", key); got != value {
+		t.Errorf("Signed() = %q, want %q", got, value)
+	}
+
+	n, _ := cookieval.Sign("n", "12", key)
+	r = newRequest(&http.Cookie{Name: "n", Value: n})
+	if got := cookieval.SignedValue(r, "n", 0, strconv.Atoi, key); got != 12 {
+		t.Errorf("SignedValue() = %v, want 12", got)
+	}
+}
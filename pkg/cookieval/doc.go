@@ -0,0 +1,36 @@
+// Package cookieval provides typed getters for request cookies with the
+// fail-safe semantics of package query: if a cookie is missing, empty, or
+// cannot be parsed, the provided default value is returned.
+//
+//	items := cookieval.Int(r, "cart_size", 0)
+//	dark  := cookieval.Bool(r, "dark_mode", false)
+//
+// # Signed Cookies
+//
+// Sign appends an HMAC-SHA256 signature to a cookie value, and Signed verifies
+// it on the way back in. Verification accepts a list of keys, newest first, so
+// a key can be rotated without logging everyone out:
+//
+//	var keys = [][]byte{currentKey, previousKey}
+//
+//	signed, _ := cookieval.Sign("uid", "42", keys[0])
+//	http.SetCookie(w, &http.Cookie{Name: "uid", Value: signed, HttpOnly: true, Secure: true})
+//
+//	uid := cookieval.Signed(r, "uid", "", keys...) // "" if missing or tampered with
+//
+// Signing prevents tampering but does not hide the value from the client.
+//
+// # Setting Cookies
+//
+// Builder writes Set-Cookie headers and rejects the attribute combinations
+// browsers drop without a word: a __Secure- or __Host- cookie without Secure,
+// a __Host- cookie with a Domain or a Path other than "/", and SameSite=None
+// or Partitioned cookies without Secure:
+//
+//	err := cookieval.NewBuilder("__Host-sid", sid).
+//	    Secure().
+//	    HttpOnly().
+//	    SameSite(http.SameSiteLaxMode).
+//	    MaxAge(24 * time.Hour).
+//	    Set(w)
+package cookieval
@@ -0,0 +1,160 @@
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// ErrInvalidConfig is returned by Middleware for a Config that browsers would
+// reject or that cannot be matched, such as a malformed origin pattern.
+var ErrInvalidConfig = errors.New("cors: invalid configuration")
+
+// Config describes which cross-origin requests Middleware allows.
+type Config struct {
+	// AllowedOrigins lists the origins that may read responses, as patterns
+	// of headers.NewOriginMatcher: exact origins such as
+	// "https://app.example.com", subdomain patterns such as
+	// "https://*.example.com", any port with "http://localhost:*", or "*" for
+	// any origin except the opaque "null" one, which must be listed itself.
+	AllowedOrigins []string
+
+	// AllowOriginFunc, if set, is asked about origins AllowedOrigins does not
+	// list, for instance to look them up in a database.
+	AllowOriginFunc func(origin string) bool
+
+	// AllowedMethods lists the methods cross-origin requests may use, compared
+	// case-sensitively as methods are. Defaults to GET, HEAD and POST.
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers cross-origin requests may send,
+	// or "*" for any. CORS-safelisted headers such as Accept are always
+	// allowed.
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers, beyond the safelisted ones,
+	// that scripts may read.
+	ExposedHeaders []string
+
+	// AllowCredentials lets requests carry cookies and HTTP authentication.
+	// It cannot be combined with the "*" origin.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight response, in whole
+	// seconds. Zero leaves it to the browser, which defaults to 5 seconds.
+	MaxAge time.Duration
+
+	// AllowPrivateNetwork answers Private Network Access preflights, which
+	// browsers send before a public site may reach a server on a private
+	// network or localhost.
+	AllowPrivateNetwork bool
+}
+
+var defaultMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// safelistedHeaders are the CORS-safelisted request headers, lower case.
+// Browsers list them in Access-Control-Request-Headers when their values are
+// not safelisted, such as a Content-Type of application/json.
+var safelistedHeaders = []string{"accept", "accept-language", "content-language", "content-type", "range"}
+
+// policy is a validated Config.
+type policy struct {
+	Config
+	anyOrigin  bool
+	origins    *headers.OriginMatcher
+	anyHeader  bool
+	allHeaders []string // Lower case
+}
+
+func newPolicy(c Config) (*policy, error) {
+	p := &policy{Config: c, allHeaders: slices.Clone(safelistedHeaders)}
+	if len(p.AllowedMethods) == 0 {
+		p.AllowedMethods = defaultMethods
+	}
+	p.anyOrigin = slices.Contains(c.AllowedOrigins, "*")
+	origins, err := headers.NewOriginMatcher(c.AllowedOrigins...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	p.origins = origins
+	if p.anyOrigin && c.AllowCredentials {
+		return nil, fmt.Errorf("%w: the \"*\" origin cannot allow credentials", ErrInvalidConfig)
+	}
+	for _, m := range p.AllowedMethods {
+		if !isToken(m) {
+			return nil, fmt.Errorf("%w: invalid method %q", ErrInvalidConfig, m)
+		}
+	}
+	for _, h := range c.AllowedHeaders {
+		if h == "*" {
+			p.anyHeader = true
+			continue
+		}
+		if !isToken(h) {
+			return nil, fmt.Errorf("%w: invalid header %q", ErrInvalidConfig, h)
+		}
+		p.allHeaders = append(p.allHeaders, strings.ToLower(h))
+	}
+	for _, h := range c.ExposedHeaders {
+		if !isToken(h) && h != "*" {
+			return nil, fmt.Errorf("%w: invalid header %q", ErrInvalidConfig, h)
+		}
+	}
+	return p, nil
+}
+
+// allowOrigin reports whether the Origin header value is allowed. Even the
+// "*" origin goes through the matcher, which refuses "null" and malformed
+// values.
+func (p *policy) allowOrigin(value string) bool {
+	if p.origins.Match(value) {
+		return true
+	}
+	return p.AllowOriginFunc != nil && p.AllowOriginFunc(value)
+}
+
+// allowHeaders reports whether every header of an
+// Access-Control-Request-Headers list is allowed.
+func (p *policy) allowHeaders(requested []string) bool {
+	if p.anyHeader {
+		return true
+	}
+	for _, h := range requested {
+		if !slices.Contains(p.allHeaders, h) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestedHeaders returns the lower-cased names of the
+// Access-Control-Request-Headers of r.
+func requestedHeaders(r *http.Request) []string {
+	var names []string
+	for _, value := range r.Header.Values(headers.AccessControlRequestHeaders) {
+		for name := range strings.SplitSeq(value, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package cors_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/cors"
+)
+
+func TestMiddlewareInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config cors.Config
+	}{
+		{"wildcard with credentials", cors.Config{AllowedOrigins: []string{"*"}, AllowCredentials: true}},
+		{"no scheme", cors.Config{AllowedOrigins: []string{"example.com"}}},
+		{"path", cors.Config{AllowedOrigins: []string{"https://example.com/app"}}},
+		{"trailing slash", cors.Config{AllowedOrigins: []string{"https://example.com/"}}},
+		{"user info", cors.Config{AllowedOrigins: []string{"https://user@example.com"}}},
+		{"inner wildcard", cors.Config{AllowedOrigins: []string{"https://a.*.example.com"}}},
+		{"method", cors.Config{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET POST"}}},
+		{"header", cors.Config{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"X-A,X-B"}}},
+		{"exposed header", cors.Config{AllowedOrigins: []string{"*"}, ExposedHeaders: []string{""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := cors.Middleware(http.NotFoundHandler(), tt.config)
+			if !errors.Is(err, cors.ErrInvalidConfig) || h != nil {
+				t.Errorf("Middleware() = %v, %v, want nil, %v", h, err, cors.ErrInvalidConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package cors implements Cross-Origin Resource Sharing, as specified by the
+// WHATWG Fetch standard, as middleware.
+//
+// A Config lists the origins, methods and headers to allow. Origins can be
+// exact, "https://app.example.com", match every subdomain,
+// "https://*.example.com", or be decided by a callback. Middleware answers
+// preflight requests itself with 204 No Content, adds the
+// Access-Control-Allow-* headers to the responses of allowed origins, and
+// keeps caches correct with Vary: Origin:
+//
+//	handler, err := cors.Middleware(api, cors.Config{
+//	    AllowedOrigins:   []string{"https://app.example.com", "https://*.example.com"},
+//	    AllowedHeaders:   []string{headers.Authorization, headers.ContentType},
+//	    AllowCredentials: true,
+//	    MaxAge:           time.Hour,
+//	})
+//
+// Requests from origins that are not allowed are still served, without CORS
+// headers: CORS only decides whether the browser lets a script read the
+// response, so it is no substitute for authentication or CSRF protection.
+//
+// # Private Network Access
+//
+// With AllowPrivateNetwork set, preflights that carry
+// Access-Control-Request-Private-Network: true, sent before a public site may
+// reach a server on a private network, are allowed with
+// Access-Control-Allow-Private-Network: true.
+package cors
@@ -0,0 +1,96 @@
+package cors
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// Middleware handles Cross-Origin Resource Sharing for next according to c.
+//
+// Preflight requests, OPTIONS requests with an Origin and an
+// Access-Control-Request-Method, are answered with 204 No Content and never
+// reach next. When the origin, method and headers are all allowed, the
+// response carries the Access-Control-Allow-* headers; otherwise it carries
+// none of them, and the browser blocks the actual request.
+//
+// Other requests with an allowed Origin get Access-Control-Allow-Origin, and
+// Access-Control-Allow-Credentials and Access-Control-Expose-Headers as
+// configured, before next runs. Responses also get Vary: Origin, so that
+// caches keep the answers for different origins apart: even with the "*"
+// origin, the opaque "null" origin and malformed ones are refused.
+//
+// Returns an error wrapping ErrInvalidConfig if c is invalid.
+//
+// Example:
+//
+//	handler, err := cors.Middleware(api, cors.Config{
+//	    AllowedOrigins:   []string{"https://app.example.com", "https://*.example.com"},
+//	    AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+//	    AllowedHeaders:   []string{headers.Authorization, headers.ContentType},
+//	    AllowCredentials: true,
+//	    MaxAge:           time.Hour,
+//	})
+func Middleware(next http.Handler, c Config) (http.Handler, error) {
+	p, err := newPolicy(c)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if headers.IsPreflight(r) {
+			headers.WritePreflight(w, p.preflight(r))
+			return
+		}
+		p.actual(w, r)
+		next.ServeHTTP(w, r)
+	}), nil
+}
+
+// allowOriginValue returns the Access-Control-Allow-Origin value for an
+// allowed origin.
+func (p *policy) allowOriginValue(origin string) string {
+	if p.anyOrigin && !p.AllowCredentials {
+		return "*"
+	}
+	return origin
+}
+
+// preflight returns the answer to the preflight request r, which refuses it
+// unless the origin, method, headers and private network access are all
+// allowed.
+func (p *policy) preflight(r *http.Request) headers.PreflightConfig {
+	origin := r.Header.Get(headers.Origin)
+	method := r.Header.Get(headers.AccessControlRequestMethod)
+	requested := requestedHeaders(r)
+	wantsPrivate := strings.EqualFold(r.Header.Get(headers.AccessControlRequestPrivateNetwork), "true")
+	if !p.allowOrigin(origin) || !slices.Contains(p.AllowedMethods, method) ||
+		!p.allowHeaders(requested) || wantsPrivate && !p.AllowPrivateNetwork {
+		return headers.PreflightConfig{}
+	}
+	return headers.PreflightConfig{
+		AllowOrigin:         p.allowOriginValue(origin),
+		AllowMethods:        []string{method},
+		AllowHeaders:        requested,
+		AllowCredentials:    p.AllowCredentials,
+		MaxAge:              p.MaxAge,
+		AllowPrivateNetwork: wantsPrivate,
+	}
+}
+
+func (p *policy) actual(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	headers.AddVary(h, headers.Origin)
+	origin := r.Header.Get(headers.Origin)
+	if origin == "" || !p.allowOrigin(origin) {
+		return
+	}
+	h.Set(headers.AccessControlAllowOrigin, p.allowOriginValue(origin))
+	if p.AllowCredentials {
+		h.Set(headers.AccessControlAllowCredentials, "true")
+	}
+	if len(p.ExposedHeaders) > 0 {
+		h.Set(headers.AccessControlExposeHeaders, strings.Join(p.ExposedHeaders, ", "))
+	}
+}
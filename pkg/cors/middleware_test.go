@@ -0,0 +1,325 @@
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/cors"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(headers.ContentType, "text/plain")
+	_, _ = w.Write([]byte("ok"))
+})
+
+func serve(t *testing.T, c cors.Config, r *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	h, err := cors.Middleware(ok, c)
+	if err != nil {
+		t.Fatalf("Middleware() error = %v", err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func request(method, origin string, header ...string) *http.Request {
+	r := httptest.NewRequest(method, "https://api.example.com/items", nil)
+	if origin != "" {
+		r.Header.Set(headers.Origin, origin)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	return r
+}
+
+func TestMiddlewareOrigins(t *testing.T) {
+	config := cors.Config{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org", "http://localhost:3000", "http://127.0.0.1:*"},
+		AllowOriginFunc: func(origin string) bool {
+			return origin == "https://partner.example.net"
+		},
+	}
+
+	tests := []struct {
+		origin string
+		want   string
+	}{
+		{"https://app.example.com", "https://app.example.com"},
+		{"https://APP.example.com", "https://APP.example.com"},
+		{"https://app.example.com:443", "https://app.example.com:443"},
+		{"http://app.example.com", ""},
+		{"https://app.example.com:8443", ""},
+		{"https://a.example.org", "https://a.example.org"},
+		{"https://a.b.example.org", "https://a.b.example.org"},
+		{"https://example.org", ""},
+		{"https://badexample.org", ""},
+		{"http://localhost:3000", "http://localhost:3000"},
+		{"http://localhost:3001", ""},
+		{"http://127.0.0.1:5173", "http://127.0.0.1:5173"},
+		{"https://partner.example.net", "https://partner.example.net"},
+		{"https://evil.example", ""},
+		{"null", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			w := serve(t, config, request(http.MethodGet, tt.origin))
+			if got := w.Header().Get(headers.AccessControlAllowOrigin); got != tt.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.want)
+			}
+			if got := w.Header().Get(headers.Vary); got != headers.Origin {
+				t.Errorf("Vary = %q, want %q", got, headers.Origin)
+			}
+			if w.Code != http.StatusOK || w.Body.String() != "ok" {
+				t.Errorf("response = %d %q, want the handler's", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewareActual(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   cors.Config
+		origin   string
+		expected map[string]string
+	}{
+		{
+			name:   "any origin",
+			config: cors.Config{AllowedOrigins: []string{"*"}},
+			origin: "https://a.example",
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:      "*",
+				headers.AccessControlAllowCredentials: "",
+				headers.Vary:                          "Origin",
+			},
+		},
+		{
+			name:   "any origin refuses null",
+			config: cors.Config{AllowedOrigins: []string{"*"}},
+			origin: "null",
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin: "",
+				headers.Vary:                     "Origin",
+			},
+		},
+		{
+			name:   "any origin refuses malformed origins",
+			config: cors.Config{AllowedOrigins: []string{"*"}},
+			origin: "https://a.example/path",
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin: "",
+				headers.Vary:                     "Origin",
+			},
+		},
+		{
+			name: "credentials and exposed headers",
+			config: cors.Config{
+				AllowedOrigins:   []string{"https://a.example"},
+				AllowCredentials: true,
+				ExposedHeaders:   []string{headers.ETag, "X-Total-Count"},
+			},
+			origin: "https://a.example",
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:      "https://a.example",
+				headers.AccessControlAllowCredentials: "true",
+				headers.AccessControlExposeHeaders:    "ETag, X-Total-Count",
+				headers.Vary:                          "Origin",
+			},
+		},
+		{
+			name:   "not allowed",
+			config: cors.Config{AllowedOrigins: []string{"https://a.example"}, AllowCredentials: true},
+			origin: "https://b.example",
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:      "",
+				headers.AccessControlAllowCredentials: "",
+				headers.Vary:                          "Origin",
+			},
+		},
+		{
+			name:   "same-origin request",
+			config: cors.Config{AllowedOrigins: []string{"https://a.example"}},
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin: "",
+				headers.Vary:                     "Origin",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, tt.config, request(http.MethodGet, tt.origin))
+			for name, want := range tt.expected {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewarePreflight(t *testing.T) {
+	config := cors.Config{
+		AllowedOrigins:      []string{"https://app.example.com"},
+		AllowedMethods:      []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders:      []string{headers.Authorization, headers.ContentType},
+		AllowCredentials:    true,
+		MaxAge:              90*time.Minute + 500*time.Millisecond,
+		AllowPrivateNetwork: true,
+	}
+
+	tests := []struct {
+		name     string
+		config   cors.Config
+		request  *http.Request
+		expected map[string]string
+	}{
+		{
+			name:   "allowed",
+			config: config,
+			request: request(http.MethodOptions, "https://app.example.com",
+				headers.AccessControlRequestMethod, http.MethodPut,
+				headers.AccessControlRequestHeaders, "content-type, Authorization"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:         "https://app.example.com",
+				headers.AccessControlAllowCredentials:    "true",
+				headers.AccessControlAllowMethods:        "PUT",
+				headers.AccessControlAllowHeaders:        "content-type, authorization",
+				headers.AccessControlMaxAge:              "5400",
+				headers.AccessControlAllowPrivateNetwork: "",
+			},
+		},
+		{
+			name:   "private network",
+			config: config,
+			request: request(http.MethodOptions, "https://app.example.com",
+				headers.AccessControlRequestMethod, http.MethodGet,
+				headers.AccessControlRequestPrivateNetwork, "true"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:         "https://app.example.com",
+				headers.AccessControlAllowHeaders:        "",
+				headers.AccessControlAllowPrivateNetwork: "true",
+			},
+		},
+		{
+			name:   "private network not allowed",
+			config: cors.Config{AllowedOrigins: []string{"*"}},
+			request: request(http.MethodOptions, "https://app.example.com",
+				headers.AccessControlRequestMethod, http.MethodGet,
+				headers.AccessControlRequestPrivateNetwork, "true"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:         "",
+				headers.AccessControlAllowPrivateNetwork: "",
+			},
+		},
+		{
+			name:   "default methods",
+			config: cors.Config{AllowedOrigins: []string{"*"}},
+			request: request(http.MethodOptions, "https://a.example",
+				headers.AccessControlRequestMethod, http.MethodPost),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:  "*",
+				headers.AccessControlAllowMethods: "POST",
+				headers.AccessControlMaxAge:       "",
+			},
+		},
+		{
+			name:   "safelisted headers",
+			config: cors.Config{AllowedOrigins: []string{"*"}},
+			request: request(http.MethodOptions, "https://a.example",
+				headers.AccessControlRequestMethod, http.MethodPost,
+				headers.AccessControlRequestHeaders, "accept, content-type"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:  "*",
+				headers.AccessControlAllowHeaders: "accept, content-type",
+			},
+		},
+		{
+			name:   "any header",
+			config: cors.Config{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}},
+			request: request(http.MethodOptions, "https://a.example",
+				headers.AccessControlRequestMethod, http.MethodGet,
+				headers.AccessControlRequestHeaders, "X-Custom"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:  "*",
+				headers.AccessControlAllowHeaders: "x-custom",
+			},
+		},
+		{
+			name:   "method not allowed",
+			config: config,
+			request: request(http.MethodOptions, "https://app.example.com",
+				headers.AccessControlRequestMethod, http.MethodDelete),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:  "",
+				headers.AccessControlAllowMethods: "",
+			},
+		},
+		{
+			name:   "method is case-sensitive",
+			config: config,
+			request: request(http.MethodOptions, "https://app.example.com",
+				headers.AccessControlRequestMethod, "put"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin: "",
+			},
+		},
+		{
+			name:   "header not allowed",
+			config: config,
+			request: request(http.MethodOptions, "https://app.example.com",
+				headers.AccessControlRequestMethod, http.MethodGet,
+				headers.AccessControlRequestHeaders, "Content-Type, X-Debug"),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:  "",
+				headers.AccessControlAllowHeaders: "",
+			},
+		},
+		{
+			name:   "origin not allowed",
+			config: config,
+			request: request(http.MethodOptions, "https://evil.example",
+				headers.AccessControlRequestMethod, http.MethodGet),
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:      "",
+				headers.AccessControlAllowCredentials: "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, tt.config, tt.request)
+			if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+				t.Errorf("response = %d %q, want 204 without a body", w.Code, w.Body.String())
+			}
+			if vary := w.Header().Get(headers.Vary); !strings.HasPrefix(vary, "Origin, Access-Control-Request-Method") {
+				t.Errorf("Vary = %q", vary)
+			}
+			for name, want := range tt.expected {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewarePlainOptions(t *testing.T) {
+	// OPTIONS without Access-Control-Request-Method is not a preflight.
+	w := serve(t, cors.Config{AllowedOrigins: []string{"*"}}, request(http.MethodOptions, "https://a.example"))
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("response = %d %q, want the handler's", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get(headers.AccessControlAllowOrigin); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
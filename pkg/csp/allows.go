@@ -0,0 +1,228 @@
+package csp
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// fallbacks lists, for each fetch directive, the directives checked in turn
+// when it is not set, from CSP Level 3, section 6.8.3.
+var fallbacks = map[Directive][]Directive{
+	ScriptSrcElem: {ScriptSrcElem, ScriptSrc, DefaultSrc},
+	ScriptSrcAttr: {ScriptSrcAttr, ScriptSrc, DefaultSrc},
+	StyleSrcElem:  {StyleSrcElem, StyleSrc, DefaultSrc},
+	StyleSrcAttr:  {StyleSrcAttr, StyleSrc, DefaultSrc},
+	WorkerSrc:     {WorkerSrc, ChildSrc, ScriptSrc, DefaultSrc},
+	FrameSrc:      {FrameSrc, ChildSrc, DefaultSrc},
+	ScriptSrc:     {ScriptSrc, DefaultSrc},
+	StyleSrc:      {StyleSrc, DefaultSrc},
+	ChildSrc:      {ChildSrc, DefaultSrc},
+	ImgSrc:        {ImgSrc, DefaultSrc},
+	FontSrc:       {FontSrc, DefaultSrc},
+	ConnectSrc:    {ConnectSrc, DefaultSrc},
+	MediaSrc:      {MediaSrc, DefaultSrc},
+	ObjectSrc:     {ObjectSrc, DefaultSrc},
+	ManifestSrc:   {ManifestSrc, DefaultSrc},
+}
+
+// WithOrigin returns a copy of p for a document served from origin, such as
+// "https://example.com". Allows uses it to match 'self' and to resolve
+// relative URLs. An origin that cannot be parsed is ignored.
+func (p Policy) WithOrigin(origin string) Policy {
+	c := p.clone()
+	c.self = nil
+	if u, err := url.Parse(origin); err == nil && u.Scheme != "" && u.Host != "" {
+		c.self = &url.URL{Scheme: strings.ToLower(u.Scheme), Host: strings.ToLower(u.Host)}
+	}
+	return c
+}
+
+// Allows reports whether p lets the document load sourceURL for directive d,
+// such as a script from "https://cdn.example.com/app.js" for ScriptSrc.
+//
+// A fetch directive that is not set falls back to the directives that cover
+// it, ending with default-src, and a URL is allowed if no directive applies.
+// Source expressions are matched as in CSP Level 3, section 6.7.2: schemes,
+// hosts with *. wildcards, ports and paths, with http allowed to upgrade to
+// https. 'self' and relative URLs need the document's origin, given with
+// WithOrigin; without it they match nothing. Nonces and hashes never match a
+// URL, and 'strict-dynamic' in a script directive turns off every URL-based
+// source. An unparseable sourceURL is not allowed.
+func (p Policy) Allows(d Directive, sourceURL string) bool {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return false
+	}
+	if p.self != nil {
+		u = p.self.ResolveReference(u)
+	}
+	if u.Scheme == "" {
+		return false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	chain, ok := fallbacks[d]
+	if !ok {
+		chain = []Directive{d}
+	}
+	for _, name := range chain {
+		sources, ok := p.values[name]
+		if !ok {
+			continue
+		}
+		if isScriptDirective(d) && containsFold(sources, StrictDynamic) {
+			return false
+		}
+		for _, source := range sources {
+			if p.matches(source, u) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isScriptDirective(d Directive) bool {
+	return d == ScriptSrc || d == ScriptSrcElem || d == ScriptSrcAttr || d == WorkerSrc
+}
+
+func containsFold(sources []string, keyword string) bool {
+	return slices.ContainsFunc(sources, func(s string) bool { return strings.EqualFold(s, keyword) })
+}
+
+// matches reports whether URL u matches one source expression.
+func (p Policy) matches(source string, u *url.URL) bool {
+	if strings.HasPrefix(source, "'") {
+		return strings.EqualFold(source, Self) && p.self != nil && p.matchesSelf(u)
+	}
+	if source == "*" {
+		// * covers network schemes, and the document's own scheme.
+		return isNetworkScheme(u.Scheme) || p.self != nil && u.Scheme == p.self.Scheme
+	}
+	if scheme, ok := strings.CutSuffix(source, ":"); ok && !strings.Contains(scheme, "/") {
+		return schemeMatches(strings.ToLower(scheme), u.Scheme)
+	}
+	return p.matchesHost(source, u)
+}
+
+func (p Policy) matchesSelf(u *url.URL) bool {
+	if !strings.EqualFold(u.Hostname(), p.self.Hostname()) {
+		return false
+	}
+	if u.Scheme == p.self.Scheme && port(u) == port(p.self) {
+		return true
+	}
+	// A secure upgrade of the document's own origin, on default ports.
+	return (p.self.Scheme == "http" && (u.Scheme == "https" || u.Scheme == "wss") ||
+		p.self.Scheme == "https" && u.Scheme == "wss") &&
+		u.Port() == "" && p.self.Port() == ""
+}
+
+// matchesHost matches a host-source, [scheme "://"] host [":" port] [path].
+func (p Policy) matchesHost(source string, u *url.URL) bool {
+	rest := source
+	scheme, afterScheme, hasScheme := strings.Cut(rest, "://")
+	if hasScheme {
+		if !schemeMatches(strings.ToLower(scheme), u.Scheme) {
+			return false
+		}
+		rest = afterScheme
+	} else {
+		selfScheme := "http"
+		if p.self != nil {
+			selfScheme = p.self.Scheme
+		}
+		if !schemeMatches(selfScheme, u.Scheme) {
+			return false
+		}
+	}
+
+	hostPort, path := rest, ""
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		hostPort, path = rest[:i], rest[i:]
+	}
+	host, exprPort, hasPort := hostPort, "", false
+	if i := strings.LastIndexByte(hostPort, ':'); i >= 0 && !strings.HasSuffix(hostPort, "]") {
+		host, exprPort, hasPort = hostPort[:i], hostPort[i+1:], true
+	}
+
+	if !hostMatches(strings.ToLower(host), strings.ToLower(u.Hostname())) {
+		return false
+	}
+
+	urlPort := port(u)
+	switch {
+	case hasPort && exprPort == "*":
+	case hasPort:
+		if exprPort != urlPort && !(exprPort == "80" && urlPort == "443") {
+			return false
+		}
+	default:
+		if urlPort != defaultPort(u.Scheme) {
+			return false
+		}
+	}
+
+	if path == "" || path == "/" {
+		return true
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	if strings.HasSuffix(path, "/") {
+		return strings.HasPrefix(u.Path, path)
+	}
+	return u.Path == path
+}
+
+// hostMatches matches a host-part, where "*.example.com" covers every
+// subdomain of example.com but not example.com itself.
+func hostMatches(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasPrefix(suffix, ".") && strings.HasSuffix(host, suffix)
+	}
+	return strings.Trim(pattern, "[]") == host
+}
+
+// schemeMatches matches a scheme-part, allowing the secure upgrades of CSP
+// Level 3, section 6.7.2.7.
+func schemeMatches(pattern, scheme string) bool {
+	switch {
+	case pattern == scheme:
+		return true
+	case pattern == "http":
+		return scheme == "https"
+	case pattern == "ws":
+		return scheme == "wss" || scheme == "http" || scheme == "https"
+	case pattern == "wss":
+		return scheme == "https"
+	}
+	return false
+}
+
+func isNetworkScheme(scheme string) bool {
+	return scheme == "http" || scheme == "https" || scheme == "ws" || scheme == "wss"
+}
+
+// port returns the port of u, or the default port of its scheme.
+func port(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	return defaultPort(u.Scheme)
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	}
+	return ""
+}
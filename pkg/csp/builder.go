@@ -0,0 +1,227 @@
+package csp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+var (
+	// ErrInvalidSource is returned by Builder for a source expression that
+	// cannot be written into a policy, such as one containing whitespace or a
+	// semicolon, or a keyword written without its quotes.
+	ErrInvalidSource = errors.New("csp: invalid source expression")
+
+	// ErrNoneWithSources is returned by Builder when 'none' is combined with
+	// other sources in one directive, where browsers ignore it.
+	ErrNoneWithSources = errors.New("csp: 'none' combined with other sources")
+)
+
+// Builder builds a Content-Security-Policy one directive at a time. Sources
+// added to a directive twice are written once, and directives are written in
+// the order they were first added. Methods record the first error, which
+// Policy, Header, Enforce and ReportOnly return.
+//
+// Example:
+//
+//	nonce := csp.NewNonce()
+//	err := csp.NewBuilder().
+//	    DefaultSrc(csp.Self).
+//	    ScriptSrc(csp.Self, csp.Nonce(nonce), csp.StrictDynamic).
+//	    ObjectSrc(csp.None).
+//	    FrameAncestors(csp.None).
+//	    Enforce(w)
+type Builder struct {
+	policy Policy
+	err    error
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Clone returns a copy of b that can be changed without affecting b, so that
+// a policy shared by every response can get a per-response nonce:
+//
+//	base := csp.NewBuilder().DefaultSrc(csp.Self)
+//	// in the handler:
+//	err := base.Clone().ScriptSrc(csp.Nonce(nonce)).Enforce(w)
+func (b *Builder) Clone() *Builder {
+	return &Builder{policy: b.policy.clone(), err: b.err}
+}
+
+// Add adds sources to directive d. It is the general form of the typed
+// methods, for directives that have none. Calling Add without sources adds a
+// directive that takes no value, such as upgrade-insecure-requests.
+func (b *Builder) Add(d Directive, sources ...string) *Builder {
+	if !isDirectiveName(string(d)) {
+		b.fail(fmt.Errorf("%w: invalid directive name %q", ErrInvalidSource, string(d)))
+		return b
+	}
+	for _, source := range sources {
+		if err := checkSource(source); err != nil {
+			b.fail(err)
+			return b
+		}
+	}
+	b.policy.add(d, sources...)
+	if values := b.policy.values[d]; len(values) > 1 && slices.Contains(values, None) {
+		b.fail(fmt.Errorf("%w: %s", ErrNoneWithSources, d))
+	}
+	return b
+}
+
+// DefaultSrc adds sources to default-src, the fallback for every fetch
+// directive that is not set.
+func (b *Builder) DefaultSrc(sources ...string) *Builder { return b.Add(DefaultSrc, sources...) }
+
+// ScriptSrc adds sources to script-src.
+func (b *Builder) ScriptSrc(sources ...string) *Builder { return b.Add(ScriptSrc, sources...) }
+
+// StyleSrc adds sources to style-src.
+func (b *Builder) StyleSrc(sources ...string) *Builder { return b.Add(StyleSrc, sources...) }
+
+// ImgSrc adds sources to img-src.
+func (b *Builder) ImgSrc(sources ...string) *Builder { return b.Add(ImgSrc, sources...) }
+
+// FontSrc adds sources to font-src.
+func (b *Builder) FontSrc(sources ...string) *Builder { return b.Add(FontSrc, sources...) }
+
+// ConnectSrc adds sources to connect-src, which covers fetch, XMLHttpRequest,
+// WebSocket and EventSource.
+func (b *Builder) ConnectSrc(sources ...string) *Builder { return b.Add(ConnectSrc, sources...) }
+
+// MediaSrc adds sources to media-src.
+func (b *Builder) MediaSrc(sources ...string) *Builder { return b.Add(MediaSrc, sources...) }
+
+// ObjectSrc adds sources to object-src. Most policies set it to None.
+func (b *Builder) ObjectSrc(sources ...string) *Builder { return b.Add(ObjectSrc, sources...) }
+
+// FrameSrc adds sources to frame-src, the origins this page may embed.
+func (b *Builder) FrameSrc(sources ...string) *Builder { return b.Add(FrameSrc, sources...) }
+
+// WorkerSrc adds sources to worker-src.
+func (b *Builder) WorkerSrc(sources ...string) *Builder { return b.Add(WorkerSrc, sources...) }
+
+// ManifestSrc adds sources to manifest-src.
+func (b *Builder) ManifestSrc(sources ...string) *Builder { return b.Add(ManifestSrc, sources...) }
+
+// BaseURI adds sources to base-uri, the URLs a <base> element may use.
+func (b *Builder) BaseURI(sources ...string) *Builder { return b.Add(BaseURI, sources...) }
+
+// FormAction adds sources to form-action, the URLs forms may submit to.
+func (b *Builder) FormAction(sources ...string) *Builder { return b.Add(FormAction, sources...) }
+
+// FrameAncestors adds sources to frame-ancestors, the origins that may embed
+// this page. FrameAncestors(None) replaces X-Frame-Options: DENY.
+func (b *Builder) FrameAncestors(sources ...string) *Builder {
+	return b.Add(FrameAncestors, sources...)
+}
+
+// Sandbox adds the sandbox directive with the given flags, such as
+// "allow-scripts". Without flags every restriction applies.
+func (b *Builder) Sandbox(flags ...string) *Builder { return b.Add(Sandbox, flags...) }
+
+// ReportTo sets the Reporting API endpoint group violations are sent to. The
+// group itself is declared in a Reporting-Endpoints header.
+func (b *Builder) ReportTo(group string) *Builder {
+	if !isDirectiveName(group) {
+		b.fail(fmt.Errorf("%w: invalid report-to group %q", ErrInvalidSource, group))
+		return b
+	}
+	b.policy.add(ReportTo)
+	b.policy.values[ReportTo] = []string{group}
+	return b
+}
+
+// UpgradeInsecureRequests adds upgrade-insecure-requests, which makes the
+// browser fetch http: subresources over https.
+func (b *Builder) UpgradeInsecureRequests() *Builder { return b.Add(UpgradeInsecureRequests) }
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// checkSource rejects what would break the policy's syntax, and keywords
+// missing their quotes, which browsers read as host names.
+func checkSource(source string) error {
+	if source == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidSource)
+	}
+	for i := 0; i < len(source); i++ {
+		if c := source[i]; c <= ' ' || c == 0x7f || c == ';' || c == ',' {
+			return fmt.Errorf("%w: %q", ErrInvalidSource, source)
+		}
+	}
+	lower := strings.ToLower(source)
+	if slices.Contains(keywords, lower) || strings.HasPrefix(lower, "nonce-") ||
+		strings.HasPrefix(lower, "sha256-") || strings.HasPrefix(lower, "sha384-") ||
+		strings.HasPrefix(lower, "sha512-") {
+		return fmt.Errorf("%w: %q must be single-quoted", ErrInvalidSource, source)
+	}
+	if strings.HasPrefix(source, "'") != strings.HasSuffix(source, "'") || source == "'" {
+		return fmt.Errorf("%w: %q", ErrInvalidSource, source)
+	}
+	return nil
+}
+
+// isDirectiveName reports whether s is made of ASCII letters, digits, dashes
+// and underscores, the characters of directive names and report-to groups.
+func isDirectiveName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Policy returns the policy built so far, or the first error recorded by the
+// builder's methods.
+func (b *Builder) Policy() (Policy, error) {
+	if b.err != nil {
+		return Policy{}, b.err
+	}
+	return b.policy.clone(), nil
+}
+
+// Header returns the policy formatted for a Content-Security-Policy header.
+func (b *Builder) Header() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return b.policy.String(), nil
+}
+
+// Enforce sets the Content-Security-Policy header on w. Nothing is written if
+// the builder recorded an error.
+func (b *Builder) Enforce(w http.ResponseWriter) error {
+	return b.set(w, headers.ContentSecurityPolicy)
+}
+
+// ReportOnly sets the Content-Security-Policy-Report-Only header on w, so that
+// browsers report violations without blocking anything. Use it to try a
+// policy out, alone or next to the enforced one.
+func (b *Builder) ReportOnly(w http.ResponseWriter) error {
+	return b.set(w, headers.ContentSecurityPolicyReportOnly)
+}
+
+func (b *Builder) set(w http.ResponseWriter, name string) error {
+	value, err := b.Header()
+	if err != nil {
+		return err
+	}
+	w.Header().Set(name, value)
+	return nil
+}
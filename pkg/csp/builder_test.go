@@ -0,0 +1,157 @@
+package csp_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestBuilderHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *csp.Builder
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			builder: csp.NewBuilder(),
+			want:    "",
+		},
+		{
+			name: "typical",
+			builder: csp.NewBuilder().
+				DefaultSrc(csp.Self).
+				ScriptSrc(csp.Self, csp.Nonce("abc"), csp.StrictDynamic).
+				ImgSrc(csp.Self, "https://cdn.example.com", "data:").
+				ObjectSrc(csp.None).
+				FrameAncestors(csp.None).
+				ReportTo("csp-endpoint").
+				UpgradeInsecureRequests(),
+			want: "default-src 'self'; script-src 'self' 'nonce-abc' 'strict-dynamic'; " +
+				"img-src 'self' https://cdn.example.com data:; object-src 'none'; " +
+				"frame-ancestors 'none'; report-to csp-endpoint; upgrade-insecure-requests",
+		},
+		{
+			name:    "merges repeated directives",
+			builder: csp.NewBuilder().ScriptSrc(csp.Self).StyleSrc(csp.Self).ScriptSrc("https://a.example", csp.Self),
+			want:    "script-src 'self' https://a.example; style-src 'self'",
+		},
+		{
+			name:    "report-to replaces the group",
+			builder: csp.NewBuilder().ReportTo("a").ReportTo("b"),
+			want:    "report-to b",
+		},
+		{
+			name:    "sandbox",
+			builder: csp.NewBuilder().Sandbox("allow-scripts", "allow-forms"),
+			want:    "sandbox allow-scripts allow-forms",
+		},
+		{
+			name:    "unlisted directive",
+			builder: csp.NewBuilder().Add(csp.RequireTrustedTypesFor, "'script'"),
+			want:    "require-trusted-types-for 'script'",
+		},
+		{
+			name:    "unquoted keyword",
+			builder: csp.NewBuilder().DefaultSrc("self"),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "unquoted nonce",
+			builder: csp.NewBuilder().ScriptSrc("nonce-abc"),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "semicolon",
+			builder: csp.NewBuilder().ImgSrc("https://a.example; script-src *"),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "empty source",
+			builder: csp.NewBuilder().ImgSrc(""),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "unbalanced quote",
+			builder: csp.NewBuilder().ImgSrc("'self"),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "invalid directive",
+			builder: csp.NewBuilder().Add("script src", csp.Self),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "invalid report-to group",
+			builder: csp.NewBuilder().ReportTo("a b"),
+			wantErr: csp.ErrInvalidSource,
+		},
+		{
+			name:    "none with sources",
+			builder: csp.NewBuilder().ObjectSrc(csp.None).ObjectSrc(csp.Self),
+			wantErr: csp.ErrNoneWithSources,
+		},
+		{
+			name:    "first error wins",
+			builder: csp.NewBuilder().ImgSrc("").ObjectSrc(csp.None, csp.Self),
+			wantErr: csp.ErrInvalidSource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Header()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Header() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Header() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderClone(t *testing.T) {
+	base := csp.NewBuilder().DefaultSrc(csp.Self)
+	a, _ := base.Clone().ScriptSrc(csp.Nonce("a")).Header()
+	b, _ := base.Clone().ScriptSrc(csp.Nonce("b")).Header()
+	orig, _ := base.Header()
+
+	if a != "default-src 'self'; script-src 'nonce-a'" {
+		t.Errorf("first clone = %q", a)
+	}
+	if b != "default-src 'self'; script-src 'nonce-b'" {
+		t.Errorf("second clone = %q", b)
+	}
+	if orig != "default-src 'self'" {
+		t.Errorf("base changed by its clones: %q", orig)
+	}
+}
+
+func TestBuilderEnforceReportOnly(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := csp.NewBuilder().DefaultSrc(csp.Self).Enforce(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := csp.NewBuilder().DefaultSrc(csp.None).ReportOnly(w); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Header().Get(headers.ContentSecurityPolicy); got != "default-src 'self'" {
+		t.Errorf("%s = %q", headers.ContentSecurityPolicy, got)
+	}
+	if got := w.Header().Get(headers.ContentSecurityPolicyReportOnly); got != "default-src 'none'" {
+		t.Errorf("%s = %q", headers.ContentSecurityPolicyReportOnly, got)
+	}
+
+	w = httptest.NewRecorder()
+	if err := csp.NewBuilder().DefaultSrc("none").Enforce(w); !errors.Is(err, csp.ErrInvalidSource) {
+		t.Errorf("Enforce() error = %v, want %v", err, csp.ErrInvalidSource)
+	}
+	if len(w.Header()) != 0 {
+		t.Errorf("Enforce() wrote headers despite an error: %v", w.Header())
+	}
+}
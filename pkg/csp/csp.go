@@ -0,0 +1,163 @@
+package csp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Directive is the name of a Content-Security-Policy directive.
+type Directive string
+
+// Directives from CSP Level 3 and the specifications that extend it.
+const (
+	DefaultSrc              Directive = "default-src"
+	ScriptSrc               Directive = "script-src"
+	ScriptSrcElem           Directive = "script-src-elem"
+	ScriptSrcAttr           Directive = "script-src-attr"
+	StyleSrc                Directive = "style-src"
+	StyleSrcElem            Directive = "style-src-elem"
+	StyleSrcAttr            Directive = "style-src-attr"
+	ImgSrc                  Directive = "img-src"
+	FontSrc                 Directive = "font-src"
+	ConnectSrc              Directive = "connect-src"
+	MediaSrc                Directive = "media-src"
+	ObjectSrc               Directive = "object-src"
+	FrameSrc                Directive = "frame-src"
+	ChildSrc                Directive = "child-src"
+	WorkerSrc               Directive = "worker-src"
+	ManifestSrc             Directive = "manifest-src"
+	BaseURI                 Directive = "base-uri"
+	FormAction              Directive = "form-action"
+	FrameAncestors          Directive = "frame-ancestors"
+	Sandbox                 Directive = "sandbox"
+	ReportTo                Directive = "report-to"
+	ReportURI               Directive = "report-uri" // Deprecated in favor of report-to, but still widely read
+	UpgradeInsecureRequests Directive = "upgrade-insecure-requests"
+	RequireTrustedTypesFor  Directive = "require-trusted-types-for"
+	TrustedTypes            Directive = "trusted-types"
+)
+
+// Source keywords. They must be written with their single quotes; an
+// unquoted self is a host name.
+const (
+	Self           = "'self'"
+	None           = "'none'"
+	UnsafeInline   = "'unsafe-inline'"
+	UnsafeEval     = "'unsafe-eval'"
+	UnsafeHashes   = "'unsafe-hashes'"
+	StrictDynamic  = "'strict-dynamic'"
+	WasmUnsafeEval = "'wasm-unsafe-eval'"
+	ReportSample   = "'report-sample'"
+)
+
+// keywords lists the source keywords without their quotes, to catch them
+// written unquoted.
+var keywords = []string{
+	"self", "none", "unsafe-inline", "unsafe-eval", "unsafe-hashes",
+	"strict-dynamic", "wasm-unsafe-eval", "report-sample",
+}
+
+// NewNonce returns a random nonce for a single response: 128 bits from
+// crypto/rand, base64 encoded. Use a new one for every response, and pass it
+// to both the policy, with Nonce, and the nonce attribute of the inline
+// scripts and styles it allows.
+func NewNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // never fails, see crypto/rand.Read
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// Nonce returns the source expression allowing inline elements that carry
+// the given nonce, such as "'nonce-R4nd0m'".
+func Nonce(nonce string) string {
+	return "'nonce-" + nonce + "'"
+}
+
+// SHA256 returns the source expression allowing an inline script or style
+// whose text is exactly content, such as "'sha256-B2yP...='".
+func SHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// SHA384 is like SHA256 with the SHA-384 hash.
+func SHA384(content string) string {
+	sum := sha512.Sum384([]byte(content))
+	return "'sha384-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// SHA512 is like SHA256 with the SHA-512 hash.
+func SHA512(content string) string {
+	sum := sha512.Sum512([]byte(content))
+	return "'sha512-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// Policy is a Content-Security-Policy: directives with their source lists,
+// in order. The zero value is an empty policy.
+type Policy struct {
+	names  []Directive
+	values map[Directive][]string
+	self   *url.URL // Origin of the document, set by WithOrigin
+}
+
+// Get returns the sources of directive d. ok is false if the policy does not
+// contain d; a directive without values, such as upgrade-insecure-requests,
+// returns an empty list and true.
+func (p Policy) Get(d Directive) (sources []string, ok bool) {
+	sources, ok = p.values[d]
+	return slices.Clone(sources), ok
+}
+
+// Directives returns the names of the directives in p, in order.
+func (p Policy) Directives() []Directive {
+	return slices.Clone(p.names)
+}
+
+// String formats p for a Content-Security-Policy header, such as
+// "default-src 'self'; img-src 'self' https://cdn.example.com".
+func (p Policy) String() string {
+	var b strings.Builder
+	for i, d := range p.names {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(string(d))
+		for _, source := range p.values[d] {
+			b.WriteByte(' ')
+			b.WriteString(source)
+		}
+	}
+	return b.String()
+}
+
+// add appends sources to directive d, creating it if needed and skipping
+// sources it already has.
+func (p *Policy) add(d Directive, sources ...string) {
+	if p.values == nil {
+		p.values = make(map[Directive][]string)
+	}
+	existing, ok := p.values[d]
+	if !ok {
+		p.names = append(p.names, d)
+		existing = []string{}
+	}
+	for _, source := range sources {
+		if !slices.Contains(existing, source) {
+			existing = append(existing, source)
+		}
+	}
+	p.values[d] = existing
+}
+
+func (p Policy) clone() Policy {
+	c := Policy{names: slices.Clone(p.names), values: make(map[Directive][]string, len(p.values)), self: p.self}
+	for d, sources := range p.values {
+		c.values[d] = slices.Clone(sources)
+	}
+	return c
+}
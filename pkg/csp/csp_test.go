@@ -0,0 +1,72 @@
+package csp_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+)
+
+func TestNewNonce(t *testing.T) {
+	a, b := csp.NewNonce(), csp.NewNonce()
+	if a == b {
+		t.Errorf("NewNonce() returned %q twice", a)
+	}
+	raw, err := base64.StdEncoding.DecodeString(a)
+	if err != nil {
+		t.Fatalf("NewNonce() = %q, not base64: %v", a, err)
+	}
+	if len(raw) != 16 {
+		t.Errorf("NewNonce() has %d bytes, want 16", len(raw))
+	}
+}
+
+func TestSources(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"nonce", csp.Nonce("abc123"), "'nonce-abc123'"},
+		// Hashes from the examples of CSP Level 3, section 2.3.1.
+		{"sha256", csp.SHA256("alert('Hello, world.');"), "'sha256-qznLcsROx4GACP2dm0UCKCzCG+HiZ1guq6ZZDob/Tng='"},
+		{"sha384", csp.SHA384(""), "'sha384-OLBgp1GsljhM2TJ+sbHjaiH9txEUvgdDTAzHv2P24donTt6/529l+9Ua0vFImLlb'"},
+		{"sha512", csp.SHA512(""), "'sha512-z4PhNX7vuL3xVChQ1m2AB9Yg5AULVxXcg/SpIdNs6c5H0NE8XYXysP+DGNKHfuwvY7kxvUdBeoGlODJ6+SfaPg=='"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyGet(t *testing.T) {
+	p, err := csp.NewBuilder().
+		DefaultSrc(csp.Self).
+		UpgradeInsecureRequests().
+		Policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := p.Get(csp.DefaultSrc); !ok || len(got) != 1 || got[0] != csp.Self {
+		t.Errorf("Get(default-src) = %q, %v", got, ok)
+	}
+	if got, ok := p.Get(csp.UpgradeInsecureRequests); !ok || len(got) != 0 {
+		t.Errorf("Get(upgrade-insecure-requests) = %q, %v", got, ok)
+	}
+	if _, ok := p.Get(csp.ScriptSrc); ok {
+		t.Error("Get(script-src) found a directive that was not set")
+	}
+	if got := p.Directives(); len(got) != 2 || got[0] != csp.DefaultSrc || got[1] != csp.UpgradeInsecureRequests {
+		t.Errorf("Directives() = %q", got)
+	}
+
+	var zero csp.Policy
+	if got := zero.String(); got != "" {
+		t.Errorf("zero Policy String() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,60 @@
+// Package csp builds Content-Security-Policy headers from typed directives,
+// rather than from strings concatenated by hand, where a missing semicolon or
+// an unquoted keyword silently changes what the policy allows.
+//
+// Directives are Directive constants and source keywords are constants that
+// carry their quotes, so 'self' cannot be written as the host name self by
+// mistake. Nonce, SHA256, SHA384 and SHA512 build the source expressions for
+// inline scripts and styles, and NewNonce generates a nonce for a single
+// response.
+//
+// Example:
+//
+//	nonce := csp.NewNonce()
+//	err := csp.NewBuilder().
+//	    DefaultSrc(csp.Self).
+//	    ScriptSrc(csp.Nonce(nonce), csp.StrictDynamic).
+//	    StyleSrc(csp.Self, csp.SHA256(inlineCSS)).
+//	    ObjectSrc(csp.None).
+//	    FrameAncestors(csp.None).
+//	    ReportTo("csp-endpoint").
+//	    Enforce(w)
+//	// render the page with <script nonce="{{nonce}}">
+//
+// # Enforce and Report-Only
+//
+// Enforce sets Content-Security-Policy, which browsers apply. ReportOnly sets
+// Content-Security-Policy-Report-Only, which they only report violations of,
+// to try a stricter policy before enforcing it. Both can be sent on the same
+// response:
+//
+//	_ = current.Enforce(w)
+//	_ = stricter.ReportOnly(w)
+//
+// # Per-Response Nonces
+//
+// A nonce must be unpredictable and different on every response. Build the
+// parts shared by every response once, and Clone the builder to add the nonce
+// in the handler:
+//
+//	var base = csp.NewBuilder().DefaultSrc(csp.Self).ObjectSrc(csp.None)
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    nonce := csp.NewNonce()
+//	    if err := base.Clone().ScriptSrc(csp.Nonce(nonce)).Enforce(w); err != nil {
+//	        // the policy is invalid
+//	    }
+//	}
+//
+// # Checking a Policy
+//
+// Parse reads a served policy back into a Policy, and Allows reports whether
+// it lets the document load a URL, following the directive fallbacks and
+// source matching rules of CSP Level 3. It is meant for tests and security
+// tooling that assert what a policy actually permits:
+//
+//	p, err := csp.Parse(resp.Header.Get(headers.ContentSecurityPolicy))
+//	p = p.WithOrigin("https://example.com")
+//	p.Allows(csp.ScriptSrc, "https://cdn.example.com/app.js")
+//	p.Allows(csp.ImgSrc, "/logo.png") // resolved against the origin
+package csp
@@ -0,0 +1,50 @@
+package csp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPolicy is returned by Parse for a value that is not a serialized
+// Content-Security-Policy.
+var ErrInvalidPolicy = errors.New("csp: invalid policy")
+
+// Parse parses a Content-Security-Policy or Content-Security-Policy-Report-Only
+// header value into a Policy, for instance to check what a served policy
+// allows with Policy.Allows.
+//
+// Parsing follows CSP Level 3, section 2.2.1: directive names are
+// case-insensitive and stored in lower case, empty directives are skipped,
+// and when a directive appears twice the first one wins, as it does in
+// browsers. Sources are kept as written. Returns ErrInvalidPolicy for
+// non-ASCII or control characters, for a directive name that is not made of
+// letters, digits and dashes, and for a comma, which joins several policies
+// that have to be split and parsed on their own.
+func Parse(value string) (Policy, error) {
+	var p Policy
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c >= 0x7f || c < ' ' && c != '\t' {
+			return Policy{}, fmt.Errorf("%w: invalid character at offset %d", ErrInvalidPolicy, i)
+		}
+	}
+	if strings.Contains(value, ",") {
+		return Policy{}, fmt.Errorf("%w: more than one policy", ErrInvalidPolicy)
+	}
+
+	for directive := range strings.SplitSeq(value, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if !isDirectiveName(name) || strings.Contains(name, "_") {
+			return Policy{}, fmt.Errorf("%w: invalid directive name %q", ErrInvalidPolicy, fields[0])
+		}
+		if _, ok := p.values[Directive(name)]; ok {
+			continue
+		}
+		p.add(Directive(name), fields[1:]...)
+	}
+	return p, nil
+}
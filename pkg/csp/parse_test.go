@@ -0,0 +1,134 @@
+package csp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "", "", false},
+		{"single", "default-src 'self'", "default-src 'self'", false},
+		{
+			name:  "whitespace and case",
+			value: "  Default-Src  'self'\thttps://a.example ;;  IMG-SRC * ; upgrade-insecure-requests;",
+			want:  "default-src 'self' https://a.example; img-src *; upgrade-insecure-requests",
+		},
+		{"first duplicate wins", "script-src 'none'; script-src *", "script-src 'none'", false},
+		{"round trip", "script-src 'nonce-abc' 'strict-dynamic'; report-to csp", "script-src 'nonce-abc' 'strict-dynamic'; report-to csp", false},
+		{"comma", "default-src 'self', img-src *", "", true},
+		{"control character", "default-src 'self'\n", "", true},
+		{"non-ASCII", "default-src https://bücher.example", "", true},
+		{"invalid name", "default_src 'self'", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := csp.Parse(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, csp.ErrInvalidPolicy) {
+					t.Errorf("Parse(%q) error = %v, want %v", tt.value, err, csp.ErrInvalidPolicy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if got := p.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyAllows(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		origin    string
+		directive csp.Directive
+		url       string
+		want      bool
+	}{
+		// Directive fallback
+		{"no policy", "", "", csp.ScriptSrc, "https://evil.example/x.js", true},
+		{"unrelated directive", "img-src 'none'", "", csp.ScriptSrc, "https://a.example/x.js", true},
+		{"default-src fallback", "default-src 'none'", "", csp.ImgSrc, "https://a.example/x.png", false},
+		{"specific over default", "default-src 'none'; img-src *", "", csp.ImgSrc, "https://a.example/x.png", true},
+		{"script-src-elem fallback", "script-src https://a.example", "", csp.ScriptSrcElem, "https://a.example/x.js", true},
+		{"worker-src via child-src", "child-src 'none'; script-src *", "", csp.WorkerSrc, "https://a.example/w.js", false},
+		{"no fallback for frame-ancestors", "default-src 'none'", "", csp.FrameAncestors, "https://a.example", true},
+		{"empty directive", "img-src", "", csp.ImgSrc, "https://a.example/x.png", false},
+
+		// 'self'
+		{"self", "default-src 'self'", "https://example.com", csp.ImgSrc, "https://example.com/x.png", true},
+		{"self relative URL", "default-src 'self'", "https://example.com", csp.ImgSrc, "/x.png", true},
+		{"self other host", "default-src 'self'", "https://example.com", csp.ImgSrc, "https://cdn.example.com/x.png", false},
+		{"self other port", "default-src 'self'", "https://example.com", csp.ImgSrc, "https://example.com:8443/x.png", false},
+		{"self upgrade", "default-src 'self'", "http://example.com", csp.ImgSrc, "https://example.com/x.png", true},
+		{"self no downgrade", "default-src 'self'", "https://example.com", csp.ImgSrc, "http://example.com/x.png", false},
+		{"self without origin", "default-src 'self'", "", csp.ImgSrc, "https://example.com/x.png", false},
+
+		// Schemes and *
+		{"star", "img-src *", "", csp.ImgSrc, "https://a.example/x.png", true},
+		{"star excludes data", "img-src *", "", csp.ImgSrc, "data:image/png;base64,AAAA", false},
+		{"scheme source", "img-src data:", "", csp.ImgSrc, "data:image/png;base64,AAAA", true},
+		{"https scheme", "default-src https:", "", csp.ScriptSrc, "https://a.example/x.js", true},
+		{"https scheme refuses http", "default-src https:", "", csp.ScriptSrc, "http://a.example/x.js", false},
+		{"http scheme upgrades", "default-src http:", "", csp.ScriptSrc, "https://a.example/x.js", true},
+		{"ws allows wss", "connect-src ws:", "", csp.ConnectSrc, "wss://a.example/socket", true},
+
+		// Hosts
+		{"host", "img-src cdn.example.com", "", csp.ImgSrc, "https://cdn.example.com/x.png", true},
+		{"host case", "img-src CDN.Example.com", "", csp.ImgSrc, "https://cdn.example.com/x.png", true},
+		{"host with scheme", "img-src https://cdn.example.com", "", csp.ImgSrc, "http://cdn.example.com/x.png", false},
+		{"host no scheme uses origin", "img-src cdn.example.com", "https://example.com", csp.ImgSrc, "http://cdn.example.com/x.png", false},
+		{"wildcard subdomain", "img-src *.example.com", "", csp.ImgSrc, "https://a.b.example.com/x.png", true},
+		{"wildcard excludes apex", "img-src *.example.com", "", csp.ImgSrc, "https://example.com/x.png", false},
+		{"wildcard suffix only", "img-src *.example.com", "", csp.ImgSrc, "https://badexample.com/x.png", false},
+
+		// Ports
+		{"default port", "img-src https://a.example", "", csp.ImgSrc, "https://a.example:443/x.png", true},
+		{"non-default port", "img-src https://a.example", "", csp.ImgSrc, "https://a.example:8443/x.png", false},
+		{"explicit port", "img-src https://a.example:8443", "", csp.ImgSrc, "https://a.example:8443/x.png", true},
+		{"wildcard port", "img-src https://a.example:*", "", csp.ImgSrc, "https://a.example:9000/x.png", true},
+		{"port 80 upgrade", "img-src http://a.example:80", "", csp.ImgSrc, "https://a.example/x.png", true},
+		{"IPv6", "connect-src http://[::1]:8080", "", csp.ConnectSrc, "http://[::1]:8080/api", true},
+
+		// Paths
+		{"path prefix", "script-src https://a.example/js/", "", csp.ScriptSrc, "https://a.example/js/app.js", true},
+		{"path prefix mismatch", "script-src https://a.example/js/", "", csp.ScriptSrc, "https://a.example/css/app.js", false},
+		{"exact path", "script-src https://a.example/js/app.js", "", csp.ScriptSrc, "https://a.example/js/app.js", true},
+		{"exact path mismatch", "script-src https://a.example/js/app.js", "", csp.ScriptSrc, "https://a.example/js/app.js2", false},
+
+		// Keywords that never match URLs
+		{"nonce", "script-src 'nonce-abc'", "", csp.ScriptSrc, "https://a.example/x.js", false},
+		{"strict-dynamic", "script-src 'strict-dynamic' https:", "", csp.ScriptSrc, "https://a.example/x.js", false},
+		{"strict-dynamic only scripts", "default-src 'strict-dynamic' https:", "", csp.ImgSrc, "https://a.example/x.png", true},
+
+		{"invalid URL", "img-src *", "", csp.ImgSrc, "https://a.example/%zz", false},
+		{"relative without origin", "img-src *", "", csp.ImgSrc, "/x.png", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := csp.Parse(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.origin != "" {
+				p = p.WithOrigin(tt.origin)
+			}
+			if got := p.Allows(tt.directive, tt.url); got != tt.want {
+				t.Errorf("Parse(%q).Allows(%s, %q) = %v, want %v", tt.policy, tt.directive, tt.url, got, tt.want)
+			}
+		})
+	}
+}
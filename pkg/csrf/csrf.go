@@ -0,0 +1,280 @@
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+var (
+	// ErrInvalidConfig is returned by New for a Config with a short key or an
+	// invalid trusted origin.
+	ErrInvalidConfig = errors.New("csrf: invalid configuration")
+
+	// ErrMissingToken is reported when a request has no token cookie or
+	// submits no token.
+	ErrMissingToken = errors.New("csrf: missing token")
+
+	// ErrInvalidToken is reported when the token cookie was not issued by this
+	// Protector for the current session, or the submitted token differs from
+	// it.
+	ErrInvalidToken = errors.New("csrf: invalid token")
+
+	// ErrForbiddenOrigin is reported when the Origin or Referer of a request
+	// is neither its own origin nor a trusted one.
+	ErrForbiddenOrigin = errors.New("csrf: forbidden origin")
+)
+
+// Defaults used for the zero fields of Config.
+const (
+	DefaultCookieName = "__Host-csrf"
+	DefaultFormField  = "csrf_token"
+)
+
+// MinKeyLength is the minimum length of Config.Key, in bytes.
+const MinKeyLength = 32
+
+const (
+	nonceSize = 32
+	tokenSize = nonceSize + sha256.Size
+)
+
+// Config configures a Protector.
+type Config struct {
+	// Key signs the tokens. It must be at least MinKeyLength random bytes,
+	// kept secret, and shared by every server of an application.
+	Key []byte
+
+	// SessionID, if set, returns an identifier of the session of r, such as a
+	// hash of its session cookie, or "" for anonymous requests. Tokens are
+	// then bound to the session, so that an attacker able to plant a cookie,
+	// from a sibling subdomain for instance, cannot plant a token that works
+	// for the victim's session.
+	SessionID func(r *http.Request) string
+
+	// TrustedOrigins lists other origins allowed to submit requests, as
+	// patterns of headers.NewOriginMatcher. A request's own origin is always
+	// allowed.
+	TrustedOrigins []string
+
+	// CookieName is the name of the token cookie. Defaults to
+	// DefaultCookieName, or "csrf" with Insecure, since the __Host- prefix
+	// requires the Secure attribute.
+	CookieName string
+
+	// HeaderName is the request header scripts submit the token in. Defaults
+	// to headers.XCSRFToken.
+	HeaderName string
+
+	// FormField is the form field HTML forms submit the token in. Defaults to
+	// DefaultFormField.
+	FormField string
+
+	// MaxAge is the lifetime of the token cookie. Zero makes it a session
+	// cookie.
+	MaxAge time.Duration
+
+	// SameSite is the SameSite attribute of the cookie. Defaults to
+	// http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// Insecure leaves out the Secure attribute of the cookie, for development
+	// over plain HTTP.
+	Insecure bool
+
+	// ErrorHandler writes the response to a request that failed Verify in
+	// Middleware. Defaults to 403 Forbidden with the error message.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Protector issues and verifies signed double-submit tokens. Create one with
+// New; it is safe for concurrent use.
+type Protector struct {
+	Config
+	trusted *headers.OriginMatcher
+}
+
+// New returns a Protector for c. Returns an error wrapping ErrInvalidConfig
+// if c.Key is shorter than MinKeyLength or a trusted origin is invalid.
+func New(c Config) (*Protector, error) {
+	if len(c.Key) < MinKeyLength {
+		return nil, fmt.Errorf("%w: key must be at least %d bytes", ErrInvalidConfig, MinKeyLength)
+	}
+	trusted, err := headers.NewOriginMatcher(c.TrustedOrigins...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if c.CookieName == "" {
+		c.CookieName = DefaultCookieName
+		if c.Insecure {
+			c.CookieName = "csrf"
+		}
+	}
+	if c.HeaderName == "" {
+		c.HeaderName = headers.XCSRFToken
+	}
+	if c.FormField == "" {
+		c.FormField = DefaultFormField
+	}
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+	if c.ErrorHandler == nil {
+		c.ErrorHandler = defaultErrorHandler
+	}
+	c.Key = append([]byte(nil), c.Key...)
+	return &Protector{Config: c, trusted: trusted}, nil
+}
+
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	http.Error(w, err.Error(), http.StatusForbidden)
+}
+
+// Token returns the token to submit with the next unsafe request, to embed in
+// a form field or a meta tag. It reuses the token cookie of r if it is valid
+// and otherwise issues a new one, setting the cookie on w, so call it once
+// per response and before writing the body.
+//
+// Example:
+//
+//	fmt.Fprintf(w, `<input type="hidden" name="csrf_token" value="%s">`, p.Token(w, r))
+func (p *Protector) Token(w http.ResponseWriter, r *http.Request) string {
+	if token, err := p.cookieToken(r); err == nil {
+		return token
+	}
+	nonce := make([]byte, nonceSize)
+	_, _ = rand.Read(nonce) // never fails, see crypto/rand.Read
+	token := base64.RawURLEncoding.EncodeToString(append(nonce, p.mac(r, nonce)...))
+
+	cookie := &http.Cookie{
+		Name:     p.CookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   !p.Insecure,
+		HttpOnly: true,
+		SameSite: p.SameSite,
+	}
+	if p.MaxAge > 0 {
+		cookie.MaxAge = int(p.MaxAge / time.Second)
+	}
+	http.SetCookie(w, cookie)
+	return token
+}
+
+// mac binds nonce to the session of r.
+func (p *Protector) mac(r *http.Request, nonce []byte) []byte {
+	m := hmac.New(sha256.New, p.Key)
+	m.Write(nonce)
+	if p.SessionID != nil {
+		m.Write([]byte(p.SessionID(r)))
+	}
+	return m.Sum(nil)
+}
+
+// cookieToken returns the token cookie of r if this Protector issued it for
+// the session of r.
+func (p *Protector) cookieToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(p.CookieName)
+	if err != nil || cookie.Value == "" {
+		return "", ErrMissingToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil || len(raw) != tokenSize {
+		return "", ErrInvalidToken
+	}
+	if !hmac.Equal(raw[nonceSize:], p.mac(r, raw[:nonceSize])) {
+		return "", ErrInvalidToken
+	}
+	return cookie.Value, nil
+}
+
+// Verify checks an unsafe request, one whose method is not GET, HEAD,
+// OPTIONS or TRACE: its origin must pass CheckOrigin, and the token it
+// submits, in the HeaderName header or else the FormField form field, must
+// equal its valid token cookie. Safe requests always pass, as they must not
+// change state.
+//
+// Returns an error wrapping ErrForbiddenOrigin, ErrMissingToken or
+// ErrInvalidToken.
+func (p *Protector) Verify(r *http.Request) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+	if err := p.CheckOrigin(r); err != nil {
+		return err
+	}
+	expected, err := p.cookieToken(r)
+	if err != nil {
+		return err
+	}
+	submitted := r.Header.Get(p.HeaderName)
+	if submitted == "" {
+		submitted = r.PostFormValue(p.FormField)
+	}
+	if submitted == "" {
+		return ErrMissingToken
+	}
+	if subtle.ConstantTimeCompare([]byte(submitted), []byte(expected)) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// CheckOrigin checks where r comes from, by its Origin header or, when that
+// is missing, the origin of its Referer. Either must be the origin of r
+// itself or a trusted origin; the opaque origin "null" is rejected unless
+// trusted. A request with neither header passes, leaving the decision to the
+// token.
+//
+// Returns an error wrapping ErrForbiddenOrigin.
+func (p *Protector) CheckOrigin(r *http.Request) error {
+	origin := r.Header.Get(headers.Origin)
+	if origin == "" {
+		referer := r.Header.Get(headers.Referer)
+		if referer == "" {
+			return nil
+		}
+		u, err := url.Parse(referer)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: invalid referer", ErrForbiddenOrigin)
+		}
+		origin = u.Scheme + "://" + u.Host
+	}
+	if p.trusted.Match(origin) {
+		return nil
+	}
+	if !headers.IsCORS(&http.Request{Host: r.Host, Header: http.Header{headers.Origin: {origin}}}) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrForbiddenOrigin, origin)
+}
+
+// Middleware verifies every request with Verify before passing it to next,
+// and answers those that fail with p.ErrorHandler.
+//
+// Example:
+//
+//	p, err := csrf.New(csrf.Config{Key: key})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	handler := p.Middleware(mux)
+func (p *Protector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := p.Verify(r); err != nil {
+			p.ErrorHandler(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,213 @@
+package csrf_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csrf"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+var key = bytes.Repeat([]byte("k"), csrf.MinKeyLength)
+
+func newProtector(t *testing.T, c csrf.Config) *csrf.Protector {
+	t.Helper()
+	if c.Key == nil {
+		c.Key = key
+	}
+	p, err := csrf.New(c)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return p
+}
+
+// issue returns a token and its cookie, issued for r.
+func issue(p *csrf.Protector, r *http.Request) (string, *http.Cookie) {
+	w := httptest.NewRecorder()
+	token := p.Token(w, r)
+	return token, w.Result().Cookies()[0]
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config csrf.Config
+	}{
+		{"no key", csrf.Config{}},
+		{"short key", csrf.Config{Key: key[1:]}},
+		{"trusted origin", csrf.Config{Key: key, TrustedOrigins: []string{"example.com"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := csrf.New(tt.config)
+			if !errors.Is(err, csrf.ErrInvalidConfig) || p != nil {
+				t.Errorf("New() = %v, %v, want nil, %v", p, err, csrf.ErrInvalidConfig)
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	p := newProtector(t, csrf.Config{MaxAge: time.Hour})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	token, cookie := issue(p, r)
+	if cookie.Name != csrf.DefaultCookieName || cookie.Value != token {
+		t.Errorf("cookie = %s=%s, want %s=%s", cookie.Name, cookie.Value, csrf.DefaultCookieName, token)
+	}
+	if !cookie.Secure || !cookie.HttpOnly || cookie.Path != "/" || cookie.SameSite != http.SameSiteLaxMode || cookie.MaxAge != 3600 {
+		t.Errorf("cookie attributes = %+v", cookie)
+	}
+	if other, _ := issue(p, r); other == token {
+		t.Error("Token() issued the same token twice")
+	}
+
+	t.Run("reuses valid cookie", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		if got := p.Token(w, r); got != token {
+			t.Errorf("Token() = %q, want %q", got, token)
+		}
+		if got := w.Header().Get(headers.SetCookie); got != "" {
+			t.Errorf("Set-Cookie = %q, want none", got)
+		}
+	})
+
+	t.Run("replaces forged cookie", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: csrf.DefaultCookieName, Value: strings.Repeat("A", 86)})
+		if got, _ := issue(p, r); got == strings.Repeat("A", 86) {
+			t.Error("Token() reused a forged cookie")
+		}
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		p := newProtector(t, csrf.Config{Insecure: true})
+		_, cookie := issue(p, r)
+		if cookie.Name != "csrf" || cookie.Secure {
+			t.Errorf("cookie = %+v, want csrf without Secure", cookie)
+		}
+	})
+}
+
+// post returns a POST request to https://example.com carrying cookie, and
+// token in the X-CSRF-Token header unless it is empty.
+func post(cookie *http.Cookie, token string, header ...string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "https://example.com/transfer", nil)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	if token != "" {
+		r.Header.Set(headers.XCSRFToken, token)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	return r
+}
+
+func TestVerify(t *testing.T) {
+	p := newProtector(t, csrf.Config{TrustedOrigins: []string{"https://*.example.net"}})
+	token, cookie := issue(p, httptest.NewRequest(http.MethodGet, "/", nil))
+	otherToken, otherCookie := issue(p, httptest.NewRequest(http.MethodGet, "/", nil))
+	forged := &http.Cookie{Name: cookie.Name, Value: strings.Repeat("A", len(token))}
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		err     error
+	}{
+		{"safe method", httptest.NewRequest(http.MethodGet, "/", nil), nil},
+		{"valid", post(cookie, token), nil},
+		{"no cookie", post(nil, token), csrf.ErrMissingToken},
+		{"no token", post(cookie, ""), csrf.ErrMissingToken},
+		{"wrong token", post(cookie, otherToken), csrf.ErrInvalidToken},
+		{"other valid pair", post(otherCookie, otherToken), nil},
+		{"forged cookie", post(forged, forged.Value), csrf.ErrInvalidToken},
+		{"same origin", post(cookie, token, headers.Origin, "https://example.com"), nil},
+		{"trusted origin", post(cookie, token, headers.Origin, "https://app.example.net"), nil},
+		{"cross origin", post(cookie, token, headers.Origin, "https://evil.example"), csrf.ErrForbiddenOrigin},
+		{"null origin", post(cookie, token, headers.Origin, "null"), csrf.ErrForbiddenOrigin},
+		{"same-origin referer", post(cookie, token, headers.Referer, "https://example.com/form?x=1"), nil},
+		{"cross-origin referer", post(cookie, token, headers.Referer, "https://evil.example/form"), csrf.ErrForbiddenOrigin},
+		{"invalid referer", post(cookie, token, headers.Referer, "/form"), csrf.ErrForbiddenOrigin},
+		{"origin before referer", post(cookie, token, headers.Origin, "https://evil.example", headers.Referer, "https://example.com/"), csrf.ErrForbiddenOrigin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := p.Verify(tt.request); !errors.Is(err, tt.err) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVerifyFormField(t *testing.T) {
+	p := newProtector(t, csrf.Config{})
+	token, cookie := issue(p, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	form := url.Values{csrf.DefaultFormField: {token}}.Encode()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form))
+	r.Header.Set(headers.ContentType, "application/x-www-form-urlencoded")
+	r.AddCookie(cookie)
+	if err := p.Verify(r); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestVerifySessionBinding(t *testing.T) {
+	p := newProtector(t, csrf.Config{
+		SessionID: func(r *http.Request) string { return r.Header.Get("X-Session") },
+	})
+	alice := httptest.NewRequest(http.MethodGet, "/", nil)
+	alice.Header.Set("X-Session", "alice")
+	token, cookie := issue(p, alice)
+
+	if err := p.Verify(post(cookie, token, "X-Session", "alice")); err != nil {
+		t.Errorf("Verify() same session error = %v", err)
+	}
+	if err := p.Verify(post(cookie, token, "X-Session", "mallory")); !errors.Is(err, csrf.ErrInvalidToken) {
+		t.Errorf("Verify() other session error = %v, want %v", err, csrf.ErrInvalidToken)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	p := newProtector(t, csrf.Config{})
+	token, cookie := issue(p, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	w := httptest.NewRecorder()
+	p.Middleware(next).ServeHTTP(w, post(cookie, token))
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("valid request = %d %q, want the handler's", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	p.Middleware(next).ServeHTTP(w, post(cookie, ""))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	var got error
+	p = newProtector(t, csrf.Config{ErrorHandler: func(w http.ResponseWriter, _ *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusBadRequest)
+	}})
+	w = httptest.NewRecorder()
+	p.Middleware(next).ServeHTTP(w, post(nil, ""))
+	if w.Code != http.StatusBadRequest || !errors.Is(got, csrf.ErrMissingToken) {
+		t.Errorf("ErrorHandler got %d, %v", w.Code, got)
+	}
+}
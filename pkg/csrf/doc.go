@@ -0,0 +1,28 @@
+// Package csrf protects against cross-site request forgery with signed
+// double-submit tokens and Origin checks.
+//
+// A Protector issues a random token in an HttpOnly cookie, signed with HMAC
+// and optionally bound to the user's session. Pages embed the same token,
+// from Token, in their forms or pass it to scripts, which submit it back in a
+// form field or the X-CSRF-Token header. Another site can make the browser
+// send the cookie but cannot read it, so it cannot submit the matching token:
+//
+//	p, err := csrf.New(csrf.Config{
+//	    Key:       key, // at least 32 random bytes
+//	    SessionID: func(r *http.Request) string { return sessionID(r) },
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	mux.HandleFunc("GET /settings", func(w http.ResponseWriter, r *http.Request) {
+//	    renderSettings(w, p.Token(w, r))
+//	})
+//	http.ListenAndServe(":8080", p.Middleware(mux))
+//
+// # Origin Checks
+//
+// Before the token, Verify checks the Origin header of unsafe requests, or
+// their Referer when a browser leaves Origin out, against the request's own
+// origin and Config.TrustedOrigins. CheckOrigin runs this check alone, for
+// APIs that rely on it without tokens.
+package csrf
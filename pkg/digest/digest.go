@@ -0,0 +1,277 @@
+package digest
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+var (
+	// ErrUnsupportedAlgorithm is returned for an algorithm this package cannot
+	// compute.
+	ErrUnsupportedAlgorithm = errors.New("digest: unsupported algorithm")
+
+	// ErrInvalidDigest is returned for a Content-Digest or Repr-Digest value
+	// that is not a dictionary of byte sequences, or that has no digest in a
+	// supported algorithm to verify against.
+	ErrInvalidDigest = errors.New("digest: invalid digest header")
+
+	// ErrMismatch is returned when content does not match its digest.
+	ErrMismatch = errors.New("digest: content does not match digest")
+)
+
+// Algorithm is a hash algorithm from the IANA Hash Algorithms for HTTP
+// Digest Fields registry.
+type Algorithm string
+
+// The active algorithms of RFC 9530. The deprecated ones, such as md5 and
+// sha, are not supported.
+const (
+	SHA256 Algorithm = "sha-256"
+	SHA512 Algorithm = "sha-512"
+)
+
+// supported lists the algorithms this package computes, strongest first,
+// which is also the order Digests.String writes them in.
+var supported = []Algorithm{SHA512, SHA256}
+
+// Supported reports whether a is an algorithm this package computes.
+func (a Algorithm) Supported() bool {
+	return slices.Contains(supported, a)
+}
+
+func (a Algorithm) newHash() hash.Hash {
+	switch a {
+	case SHA256:
+		return sha256.New()
+	case SHA512:
+		return sha512.New()
+	}
+	return nil
+}
+
+// Digests maps algorithms to the digests of some content, as carried by a
+// Content-Digest or Repr-Digest header.
+type Digests map[Algorithm][]byte
+
+// Compute returns the digests of content in each of algorithms, SHA256 if
+// none is given. Returns an error wrapping ErrUnsupportedAlgorithm for an
+// algorithm that is not supported.
+func Compute(content []byte, algorithms ...Algorithm) (Digests, error) {
+	h, err := NewHasher(algorithms...)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = h.Write(content) // never fails
+	return h.Digests(), nil
+}
+
+// String formats d as a Content-Digest or Repr-Digest header value, a
+// structured field dictionary such as "sha-256=:X48E9q...=:". Supported
+// algorithms come first, strongest first, followed by any others in sorted
+// order.
+func (d Digests) String() string {
+	var dict sfv.Dictionary
+	for _, a := range supported {
+		if sum, ok := d[a]; ok {
+			dict = append(dict, sfv.DictMember{Key: string(a), Value: sfv.Item{Value: sum}})
+		}
+	}
+	var others []string
+	for a := range d {
+		if !a.Supported() {
+			others = append(others, string(a))
+		}
+	}
+	slices.Sort(others)
+	for _, a := range others {
+		dict = append(dict, sfv.DictMember{Key: a, Value: sfv.Item{Value: d[Algorithm(a)]}})
+	}
+	s, err := sfv.FormatDictionary(dict)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// Parse parses a Content-Digest or Repr-Digest header value. Digests in
+// algorithms that are not supported are kept, so that they can be passed on.
+// Returns ErrInvalidDigest if value is not a dictionary of byte sequences.
+func Parse(value string) (Digests, error) {
+	dict, err := sfv.ParseDictionary(value)
+	if err != nil || len(dict) == 0 {
+		return nil, ErrInvalidDigest
+	}
+	d := make(Digests, len(dict))
+	for _, m := range dict {
+		item, ok := m.Value.(sfv.Item)
+		if !ok {
+			return nil, ErrInvalidDigest
+		}
+		sum, ok := item.Value.([]byte)
+		if !ok {
+			return nil, ErrInvalidDigest
+		}
+		d[Algorithm(m.Key)] = sum
+	}
+	return d, nil
+}
+
+// Verify checks content against a Content-Digest or Repr-Digest header
+// value, using every supported algorithm it carries. Returns
+// ErrInvalidDigest if value is malformed or has no supported algorithm, and
+// ErrMismatch if a digest differs.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := digest.Verify(r.Header.Get(headers.ContentDigest), body); err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+func Verify(value string, content []byte) error {
+	v, err := NewVerifier(value)
+	if err != nil {
+		return err
+	}
+	_, _ = v.Write(content) // never fails
+	return v.Verify()
+}
+
+// Hasher computes digests in several algorithms at once over content written
+// to it in pieces, so that a body can be hashed while it is streamed. Create
+// one with NewHasher.
+type Hasher struct {
+	algorithms []Algorithm
+	hashes     []hash.Hash
+}
+
+// NewHasher returns a Hasher for algorithms, SHA256 if none is given.
+// Returns an error wrapping ErrUnsupportedAlgorithm for an algorithm that is
+// not supported.
+func NewHasher(algorithms ...Algorithm) (*Hasher, error) {
+	if len(algorithms) == 0 {
+		algorithms = []Algorithm{SHA256}
+	}
+	h := &Hasher{}
+	for _, a := range algorithms {
+		if slices.Contains(h.algorithms, a) {
+			continue
+		}
+		hh := a.newHash()
+		if hh == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, a)
+		}
+		h.algorithms = append(h.algorithms, a)
+		h.hashes = append(h.hashes, hh)
+	}
+	return h, nil
+}
+
+// Write adds p to the content hashed. It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	for _, hh := range h.hashes {
+		hh.Write(p)
+	}
+	return len(p), nil
+}
+
+// Digests returns the digests of the content written so far.
+func (h *Hasher) Digests() Digests {
+	d := make(Digests, len(h.algorithms))
+	for i, a := range h.algorithms {
+		d[a] = h.hashes[i].Sum(nil)
+	}
+	return d
+}
+
+// Verifier checks content written to it in pieces against expected digests.
+// Create one with NewVerifier.
+type Verifier struct {
+	expected Digests
+	hasher   *Hasher
+}
+
+// NewVerifier returns a Verifier for a Content-Digest or Repr-Digest header
+// value. Returns ErrInvalidDigest if value is malformed or has no supported
+// algorithm.
+func NewVerifier(value string) (*Verifier, error) {
+	d, err := Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	var algorithms []Algorithm
+	for _, a := range supported {
+		if _, ok := d[a]; ok {
+			algorithms = append(algorithms, a)
+		}
+	}
+	if len(algorithms) == 0 {
+		return nil, fmt.Errorf("%w: no supported algorithm", ErrInvalidDigest)
+	}
+	h, _ := NewHasher(algorithms...) // supported by construction
+	return &Verifier{expected: d, hasher: h}, nil
+}
+
+// Write adds p to the content verified. It never returns an error.
+func (v *Verifier) Write(p []byte) (int, error) {
+	return v.hasher.Write(p)
+}
+
+// Verify returns ErrMismatch unless the content written so far matches every
+// expected digest in a supported algorithm.
+func (v *Verifier) Verify() error {
+	for a, sum := range v.hasher.Digests() {
+		if !slices.Equal(sum, v.expected[a]) {
+			return fmt.Errorf("%w: %s", ErrMismatch, a)
+		}
+	}
+	return nil
+}
+
+// NewReader returns a reader of r that verifies, as it is read, the content
+// against a Content-Digest or Repr-Digest header value: once r is exhausted,
+// reading returns an error wrapping ErrMismatch in place of io.EOF if the
+// content does not match. The body is never buffered, but it must be read to
+// the end to be verified. Returns ErrInvalidDigest if value is malformed or
+// has no supported algorithm.
+//
+// Example:
+//
+//	body, err := digest.NewReader(r.Body, r.Header.Get(headers.ContentDigest))
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	if _, err := io.Copy(upload, body); err != nil {
+//	    // digest.ErrMismatch: discard the upload
+//	}
+func NewReader(r io.Reader, value string) (io.Reader, error) {
+	v, err := NewVerifier(value)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{r: r, v: v}, nil
+}
+
+type verifyingReader struct {
+	r io.Reader
+	v *Verifier
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	_, _ = r.v.Write(p[:n])
+	if err == io.EOF {
+		if verr := r.v.Verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
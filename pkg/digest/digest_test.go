@@ -0,0 +1,152 @@
+package digest_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/digest"
+)
+
+// From RFC 9530, appendix D.
+const (
+	content   = `{"hello": "world"}`
+	sha256Sum = "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"
+	sha512Sum = "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name       string
+		algorithms []digest.Algorithm
+		expected   string
+		err        error
+	}{
+		{"default", nil, sha256Sum, nil},
+		{"sha-512", []digest.Algorithm{digest.SHA512}, sha512Sum, nil},
+		{"both, strongest first", []digest.Algorithm{digest.SHA256, digest.SHA512, digest.SHA256}, sha512Sum + ", " + sha256Sum, nil},
+		{"unsupported", []digest.Algorithm{"md5"}, "", digest.ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := digest.Compute([]byte(content), tt.algorithms...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Compute() error = %v, want %v", err, tt.err)
+			}
+			if got := d.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasher(t *testing.T) {
+	h, err := digest.NewHasher(digest.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, piece := range strings.SplitAfter(content, " ") {
+		_, _ = h.Write([]byte(piece))
+	}
+	if got := h.Digests().String(); got != sha256Sum {
+		t.Errorf("Digests() = %q, want %q", got, sha256Sum)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []digest.Algorithm
+		err      error
+	}{
+		{"one", sha256Sum, []digest.Algorithm{digest.SHA256}, nil},
+		{"several", sha512Sum + ", unixsum=:AQ==:", []digest.Algorithm{digest.SHA512, "unixsum"}, nil},
+		{"not a byte sequence", "sha-256=abc", nil, digest.ErrInvalidDigest},
+		{"inner list", "sha-256=(:AQ==:)", nil, digest.ErrInvalidDigest},
+		{"malformed", "sha-256=:AQ==", nil, digest.ErrInvalidDigest},
+		{"empty", "", nil, digest.ErrInvalidDigest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := digest.Parse(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+			if len(d) != len(tt.expected) {
+				t.Fatalf("Parse() = %v, want %v", d, tt.expected)
+			}
+			for _, a := range tt.expected {
+				if _, ok := d[a]; !ok {
+					t.Errorf("Parse() has no %s digest", a)
+				}
+			}
+		})
+	}
+}
+
+func TestDigestsStringRoundTrip(t *testing.T) {
+	value := sha512Sum + ", " + sha256Sum + ", a-sum=:AQ==:, b-sum=:Ag==:"
+	d, err := digest.Parse("b-sum=:Ag==:, " + sha256Sum + ", a-sum=:AQ==:, " + sha512Sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.String(); got != value {
+		t.Errorf("String() = %q, want %q", got, value)
+	}
+	again, _ := digest.Parse(d.String())
+	if !reflect.DeepEqual(again, d) {
+		t.Errorf("Parse(String()) = %v, want %v", again, d)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		content string
+		err     error
+	}{
+		{"sha-256", sha256Sum, content, nil},
+		{"sha-512", sha512Sum, content, nil},
+		{"both", sha256Sum + ", " + sha512Sum, content, nil},
+		{"unsupported ignored", "md5=:AQ==:, " + sha256Sum, content, nil},
+		{"altered", sha256Sum, `{"hello": "there"}`, digest.ErrMismatch},
+		{"one of two wrong", sha256Sum + ", sha-512=:AQ==:", content, digest.ErrMismatch},
+		{"only unsupported", "md5=:AQ==:", content, digest.ErrInvalidDigest},
+		{"malformed", "sha-256", content, digest.ErrInvalidDigest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := digest.Verify(tt.value, []byte(tt.content)); !errors.Is(err, tt.err) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	r, err := digest.NewReader(strings.NewReader(content), sha256Sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if _, err := io.Copy(&got, r); err != nil || got.String() != content {
+		t.Errorf("io.Copy() = %q, %v, want %q, nil", got.String(), err, content)
+	}
+
+	r, _ = digest.NewReader(strings.NewReader("tampered"), sha256Sum)
+	if _, err := io.ReadAll(r); !errors.Is(err, digest.ErrMismatch) {
+		t.Errorf("io.ReadAll() error = %v, want %v", err, digest.ErrMismatch)
+	}
+
+	if _, err := digest.NewReader(strings.NewReader(content), "md5=:AQ==:"); !errors.Is(err, digest.ErrInvalidDigest) {
+		t.Errorf("NewReader() error = %v, want %v", err, digest.ErrInvalidDigest)
+	}
+}
@@ -0,0 +1,33 @@
+// Package digest computes and verifies the integrity digests of RFC 9530:
+// the Content-Digest and Repr-Digest headers, and the Want-Content-Digest and
+// Want-Repr-Digest headers clients use to ask for them.
+//
+// Digests are computed with sha-256 or sha-512 and written as structured
+// field dictionaries:
+//
+//	d, _ := digest.Compute([]byte(`{"hello": "world"}`))
+//	w.Header().Set(headers.ContentDigest, d.String())
+//	// Content-Digest: sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:
+//
+// Bodies too large to hold in memory are hashed as they stream: a Hasher is
+// an io.Writer, NewReader verifies a request body while it is read, and
+// Middleware sends the Content-Digest of responses as a trailer.
+//
+// # Verification
+//
+// Verify and NewReader check content against every supported algorithm of a
+// digest header, and report ErrMismatch when it was altered:
+//
+//	if err := digest.Verify(r.Header.Get(headers.ContentDigest), body); err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//
+// # Negotiation
+//
+// Negotiate picks the algorithm a Want-Content-Digest header prefers, and
+// FormatWant writes one:
+//
+//	// Want-Content-Digest: sha-512=3, sha-256=10
+//	a := digest.Negotiate(r.Header.Get(headers.WantContentDigest))  // digest.SHA256
+package digest
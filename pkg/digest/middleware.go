@@ -0,0 +1,111 @@
+package digest
+
+import (
+	"net/http"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// Middleware sends the Content-Digest of every response of next as an HTTP
+// trailer, computed while the body streams to the client, so that it is
+// neither buffered nor hashed twice. Responses get a digest in each of
+// algorithms, SHA256 if none is given, unless the request carries a
+// Want-Content-Digest header: it then gets one in the algorithm the client
+// prefers among them, or none if the client accepts none of them.
+//
+// Responses to HEAD requests, 204 and 304 responses, which have no body, and
+// responses whose handler sets Content-Digest itself are left alone. Trailers
+// need a chunked or HTTP/2 response, so a handler that sets Content-Length
+// gets no digest over HTTP/1.1; Middleware leaves Content-Length as is.
+//
+// Returns an error wrapping ErrUnsupportedAlgorithm for an algorithm that is
+// not supported.
+//
+// Example:
+//
+//	handler, err := digest.Middleware(api, digest.SHA256, digest.SHA512)
+func Middleware(next http.Handler, algorithms ...Algorithm) (http.Handler, error) {
+	if _, err := NewHasher(algorithms...); err != nil {
+		return nil, err
+	}
+	if len(algorithms) == 0 {
+		algorithms = []Algorithm{SHA256}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		algs := algorithms
+		if want := r.Header.Get(headers.WantContentDigest); want != "" {
+			a := Negotiate(want, algorithms...)
+			if a == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			algs = []Algorithm{a}
+		}
+		hasher, _ := NewHasher(algs...) // checked above
+		dw := &digestWriter{ResponseWriter: w, hasher: hasher}
+		next.ServeHTTP(dw, r)
+		dw.finish()
+	}), nil
+}
+
+// digestWriter hashes the body of a response and announces the Content-Digest
+// trailer before the status is written.
+type digestWriter struct {
+	http.ResponseWriter
+	hasher      *Hasher
+	wroteHeader bool
+	active      bool
+}
+
+func (w *digestWriter) WriteHeader(code int) {
+	if w.wroteHeader || code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code) // informational, such as 103 Early Hints
+		return
+	}
+	w.wroteHeader = true
+	h := w.Header()
+	if code >= 200 && code != http.StatusNoContent && code != http.StatusNotModified && h.Get(headers.ContentDigest) == "" {
+		w.active = true
+		h.Add(headers.Trailer, headers.ContentDigest)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *digestWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	if w.active {
+		_, _ = w.hasher.Write(p[:n])
+	}
+	return n, err
+}
+
+func (w *digestWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *digestWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish sets the trailer, once the whole body has been written.
+func (w *digestWriter) finish() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.active {
+		w.Header().Set(headers.ContentDigest, w.hasher.Digests().String())
+	}
+}
@@ -0,0 +1,107 @@
+package digest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/digest"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func serve(t *testing.T, next http.Handler, r *http.Request, algorithms ...digest.Algorithm) *http.Response {
+	t.Helper()
+	h, err := digest.Middleware(next, algorithms...)
+	if err != nil {
+		t.Fatalf("Middleware() error = %v", err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Result()
+}
+
+var hello = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	_, _ = io.WriteString(w, `{"hello": `)
+	_, _ = io.WriteString(w, `"world"}`)
+})
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		algorithms []digest.Algorithm
+		want       string
+		expected   string
+	}{
+		{"default", nil, "", sha256Sum},
+		{"several", []digest.Algorithm{digest.SHA256, digest.SHA512}, "", sha512Sum + ", " + sha256Sum},
+		{"negotiated", []digest.Algorithm{digest.SHA256, digest.SHA512}, "sha-512=10, sha-256=1", sha512Sum},
+		{"nothing acceptable", nil, "sha-512=10", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.want != "" {
+				r.Header.Set(headers.WantContentDigest, tt.want)
+			}
+			resp := serve(t, hello, r, tt.algorithms...)
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != content {
+				t.Errorf("body = %q, want %q", body, content)
+			}
+			if got := resp.Trailer.Get(headers.ContentDigest); got != tt.expected {
+				t.Errorf("Content-Digest trailer = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMiddlewareSkips(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{"HEAD", http.MethodHead, hello},
+		{"no content", http.MethodGet, func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }},
+		{"not modified", http.MethodGet, func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotModified) }},
+		{"set by handler", http.MethodGet, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set(headers.ContentDigest, sha256Sum)
+			_, _ = io.WriteString(w, content)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := serve(t, tt.handler, httptest.NewRequest(tt.method, "/", nil))
+			if got := resp.Header.Get(headers.Trailer); got != "" {
+				t.Errorf("Trailer = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestMiddlewareServer(t *testing.T) {
+	h, _ := digest.Middleware(hello)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if err := digest.Verify(resp.Trailer.Get(headers.ContentDigest), body); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestMiddlewareUnsupported(t *testing.T) {
+	h, err := digest.Middleware(hello, "md5")
+	if !errors.Is(err, digest.ErrUnsupportedAlgorithm) || h != nil {
+		t.Errorf("Middleware() = %v, %v, want nil, %v", h, err, digest.ErrUnsupportedAlgorithm)
+	}
+}
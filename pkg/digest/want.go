@@ -0,0 +1,87 @@
+package digest
+
+import (
+	"slices"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// Preference is one algorithm of a Want-Content-Digest or Want-Repr-Digest
+// header, with its weight from 1, least preferred, to 10.
+type Preference struct {
+	Algorithm Algorithm
+	Weight    int
+}
+
+// ParseWant parses a Want-Content-Digest or Want-Repr-Digest header value,
+// such as "sha-512=3, sha-256=10", into the algorithms the client accepts,
+// most preferred first; algorithms of equal weight keep their order.
+// Algorithms with weight 0, which the client does not accept, and members
+// that are not integers from 0 to 10 are left out, so a missing or malformed
+// header yields nil.
+func ParseWant(value string) []Preference {
+	dict, err := sfv.ParseDictionary(value)
+	if err != nil {
+		return nil
+	}
+	var prefs []Preference
+	for _, m := range dict {
+		item, ok := m.Value.(sfv.Item)
+		if !ok {
+			continue
+		}
+		weight, ok := item.Value.(int64)
+		if !ok || weight < 1 || weight > 10 {
+			continue
+		}
+		prefs = append(prefs, Preference{Algorithm: Algorithm(m.Key), Weight: int(weight)})
+	}
+	slices.SortStableFunc(prefs, func(a, b Preference) int {
+		return b.Weight - a.Weight
+	})
+	return prefs
+}
+
+// FormatWant formats preferences as a Want-Content-Digest or
+// Want-Repr-Digest header value, for clients asking for a digest. Weights
+// are clamped to the range from 0 to 10.
+//
+// Example:
+//
+//	req.Header.Set(headers.WantContentDigest, digest.FormatWant(
+//	    digest.Preference{Algorithm: digest.SHA256, Weight: 10},
+//	    digest.Preference{Algorithm: digest.SHA512, Weight: 5},
+//	))  // "sha-256=10, sha-512=5"
+func FormatWant(prefs ...Preference) string {
+	var dict sfv.Dictionary
+	for _, p := range prefs {
+		weight := int64(min(max(p.Weight, 0), 10))
+		dict = append(dict, sfv.DictMember{Key: string(p.Algorithm), Value: sfv.Item{Value: weight}})
+	}
+	s, err := sfv.FormatDictionary(dict)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// Negotiate returns the algorithm to answer a Want-Content-Digest or
+// Want-Repr-Digest header value with: the supported algorithm the client
+// prefers most, or "" if it accepts none of them. If supported is empty,
+// every algorithm this package computes is considered.
+//
+// Example:
+//
+//	// Want-Content-Digest: sha-512=3, sha-256=10
+//	digest.Negotiate(r.Header.Get(headers.WantContentDigest))  // digest.SHA256
+func Negotiate(want string, supported ...Algorithm) Algorithm {
+	for _, p := range ParseWant(want) {
+		if !p.Algorithm.Supported() {
+			continue
+		}
+		if len(supported) == 0 || slices.Contains(supported, p.Algorithm) {
+			return p.Algorithm
+		}
+	}
+	return ""
+}
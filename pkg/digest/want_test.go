@@ -0,0 +1,67 @@
+package digest_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/digest"
+)
+
+func TestParseWant(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []digest.Preference
+	}{
+		{"one", "sha-256=1", []digest.Preference{{Algorithm: digest.SHA256, Weight: 1}}},
+		{"sorted by weight", "sha-512=3, sha-256=10", []digest.Preference{{Algorithm: digest.SHA256, Weight: 10}, {Algorithm: digest.SHA512, Weight: 3}}},
+		{"ties keep order", "sha-512=5, sha-256=5", []digest.Preference{{Algorithm: digest.SHA512, Weight: 5}, {Algorithm: digest.SHA256, Weight: 5}}},
+		{"not accepted", "sha-256=0, sha-512=1", []digest.Preference{{Algorithm: digest.SHA512, Weight: 1}}},
+		{"invalid weights skipped", "sha-256=11, sha-512=2.5, md5=?1, unixsum=2", []digest.Preference{{Algorithm: "unixsum", Weight: 2}}},
+		{"malformed", "sha-256=", nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digest.ParseWant(tt.value); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseWant(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatWant(t *testing.T) {
+	got := digest.FormatWant(
+		digest.Preference{Algorithm: digest.SHA256, Weight: 10},
+		digest.Preference{Algorithm: digest.SHA512, Weight: 20},
+		digest.Preference{Algorithm: "md5", Weight: -1},
+	)
+	if want := "sha-256=10, sha-512=10, md5=0"; got != want {
+		t.Errorf("FormatWant() = %q, want %q", got, want)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name      string
+		want      string
+		supported []digest.Algorithm
+		expected  digest.Algorithm
+	}{
+		{"client preference", "sha-512=3, sha-256=10", nil, digest.SHA256},
+		{"unsupported skipped", "unixsum=10, sha-512=1", nil, digest.SHA512},
+		{"restricted", "sha-512=10, sha-256=1", []digest.Algorithm{digest.SHA256}, digest.SHA256},
+		{"none in common", "sha-512=10", []digest.Algorithm{digest.SHA256}, ""},
+		{"none accepted", "sha-256=0", nil, ""},
+		{"missing", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digest.Negotiate(tt.want, tt.supported...); got != tt.expected {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.want, got, tt.expected)
+			}
+		})
+	}
+}
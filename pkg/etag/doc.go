@@ -0,0 +1,35 @@
+// Package etag generates and compares entity tags and evaluates conditional
+// requests as described in RFC 9110, sections 8.8.3 and 13.
+//
+// Entity tags are built from a content hash, or parsed from a header:
+//
+//	tag := etag.Of(body)                  // strong, from SHA-256
+//	tag = etag.Weak(sum[:])               // weak, from a hash you already have
+//	tag, err := etag.Parse(`W/"v42"`)
+//
+// Evaluate applies If-Match, If-Unmodified-Since, If-None-Match and
+// If-Modified-Since in the order RFC 9110 prescribes and returns the status to
+// respond with, so a handler only has to act on it:
+//
+//	w.Header().Set(headers.ETag, tag.String())
+//	w.Header().Set(headers.LastModified, modTime.UTC().Format(http.TimeFormat))
+//	if status := etag.Evaluate(r, tag, modTime); status != http.StatusOK {
+//	    w.WriteHeader(status)  // 304 Not Modified or 412 Precondition Failed
+//	    return
+//	}
+//
+// # Middleware
+//
+// Middleware adds this to any GET handler without changing it: the response is
+// buffered, tagged with a hash of its body unless the handler set an ETag, and
+// replaced by 304 Not Modified when the client already has it:
+//
+//	mux.Handle("/catalog", etag.Middleware(catalogHandler))
+//
+// # Comparison
+//
+// If-Match uses strong comparison: two tags match only if neither is weak and
+// their opaque tags are identical. If-None-Match uses weak comparison, which
+// ignores the W/ prefix. StrongEqual and WeakEqual expose both, and Match and
+// NoneMatch evaluate a header value, including "*", against a current tag.
+package etag
@@ -0,0 +1,175 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned by Parse for a value that is not an entity tag.
+var ErrInvalid = errors.New("etag: invalid entity tag")
+
+// ETag is an entity tag as sent in the ETag header, such as "xyzzy" or
+// W/"xyzzy". The zero value means no entity tag, for instance because the
+// resource does not exist.
+type ETag struct {
+	opaque string
+	weak   bool
+	valid  bool
+}
+
+// Strong returns a strong entity tag encoding the content hash sum, such as
+// the result of sha256.Sum256 or a hash.Hash's Sum. A strong tag promises that
+// the representation is byte-for-byte identical whenever the tag matches.
+//
+// Example:
+//
+//	sum := sha256.Sum256(body)
+//	w.Header().Set(headers.ETag, etag.Strong(sum[:]).String())
+func Strong(sum []byte) ETag {
+	return ETag{opaque: base64.RawURLEncoding.EncodeToString(sum), valid: true}
+}
+
+// Weak returns a weak entity tag encoding the content hash sum. A weak tag only
+// promises semantic equivalence, so it suits representations that vary in
+// insignificant ways, such as compressed and uncompressed forms or
+// re-serialized JSON.
+func Weak(sum []byte) ETag {
+	return ETag{opaque: base64.RawURLEncoding.EncodeToString(sum), weak: true, valid: true}
+}
+
+// Of returns a strong entity tag for content, derived from the first 16 bytes
+// of its SHA-256 hash.
+func Of(content []byte) ETag {
+	sum := sha256.Sum256(content)
+	return Strong(sum[:16])
+}
+
+// Parse parses a single entity tag, such as `"xyzzy"` or `W/"xyzzy"`, with the
+// surrounding whitespace ignored.
+func Parse(s string) (ETag, error) {
+	e, rest, ok := parseOne(strings.Trim(s, " \t"))
+	if !ok || rest != "" {
+		return ETag{}, ErrInvalid
+	}
+	return e, nil
+}
+
+// Opaque returns the tag without its W/ prefix and quotes.
+func (e ETag) Opaque() string {
+	return e.opaque
+}
+
+// IsWeak reports whether e is a weak entity tag.
+func (e ETag) IsWeak() bool {
+	return e.weak
+}
+
+// IsZero reports whether e is the zero value, meaning no entity tag.
+func (e ETag) IsZero() bool {
+	return !e.valid
+}
+
+// String formats e for the ETag header, or returns "" for the zero value.
+func (e ETag) String() string {
+	switch {
+	case !e.valid:
+		return ""
+	case e.weak:
+		return `W/"` + e.opaque + `"`
+	}
+	return `"` + e.opaque + `"`
+}
+
+// StrongEqual reports whether a and b match under the strong comparison of
+// RFC 9110: both are strong and their opaque tags are identical. It is used
+// for If-Match and for range requests.
+func StrongEqual(a, b ETag) bool {
+	return a.valid && b.valid && !a.weak && !b.weak && a.opaque == b.opaque
+}
+
+// WeakEqual reports whether a and b match under the weak comparison of
+// RFC 9110: their opaque tags are identical, whether or not either is weak. It
+// is used for If-None-Match.
+func WeakEqual(a, b ETag) bool {
+	return a.valid && b.valid && a.opaque == b.opaque
+}
+
+// Match reports whether the If-Match condition ifMatch holds for the current
+// entity tag: "*" holds if there is a current representation, and a list of
+// tags holds if any of them is strongly equal to current. Malformed list
+// elements never match.
+func Match(ifMatch string, current ETag) bool {
+	tags, star := parseList(ifMatch)
+	if star {
+		return current.valid
+	}
+	for _, t := range tags {
+		if StrongEqual(t, current) {
+			return true
+		}
+	}
+	return false
+}
+
+// NoneMatch reports whether the If-None-Match condition ifNoneMatch holds for
+// the current entity tag: "*" holds if there is no current representation,
+// and a list of tags holds if none of them is weakly equal to current.
+// Malformed list elements never match.
+func NoneMatch(ifNoneMatch string, current ETag) bool {
+	tags, star := parseList(ifNoneMatch)
+	if star {
+		return !current.valid
+	}
+	for _, t := range tags {
+		if WeakEqual(t, current) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseList parses the value of If-Match or If-None-Match: "*" or a
+// comma-separated list of entity tags. Parsing stops at the first malformed
+// element.
+func parseList(s string) (tags []ETag, star bool) {
+	s = strings.Trim(s, " \t")
+	if s == "*" {
+		return nil, true
+	}
+	for s != "" {
+		e, rest, ok := parseOne(s)
+		if !ok {
+			break
+		}
+		tags = append(tags, e)
+
+		rest = strings.TrimLeft(rest, " \t")
+		if rest != "" && rest[0] != ',' {
+			break
+		}
+		s = strings.TrimLeft(rest, ", \t")
+	}
+	return tags, false
+}
+
+// parseOne parses the entity tag at the start of s and returns the rest.
+func parseOne(s string) (ETag, string, bool) {
+	weak := strings.HasPrefix(s, "W/")
+	if weak {
+		s = s[2:]
+	}
+	if !strings.HasPrefix(s, `"`) {
+		return ETag{}, "", false
+	}
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return ETag{opaque: s[1:i], weak: weak, valid: true}, s[i+1:], true
+		case c < 0x21 || c == 0x7f:
+			return ETag{}, "", false
+		}
+	}
+	return ETag{}, "", false
+}
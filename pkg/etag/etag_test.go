@@ -0,0 +1,183 @@
+package etag_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/etag"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input     string
+		opaque    string
+		weak      bool
+		wantError bool
+	}{
+		{`"xyzzy"`, "xyzzy", false, false},
+		{`W/"xyzzy"`, "xyzzy", true, false},
+		{` "" `, "", false, false},
+		{`"a,b"`, "a,b", false, false},
+		{`xyzzy`, "", false, true},
+		{`w/"xyzzy"`, "", false, true},
+		{`"xyzzy`, "", false, true},
+		{`"a b"`, "", false, true},
+		{`"a" "b"`, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := etag.Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Parse() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && (got.Opaque() != tt.opaque || got.IsWeak() != tt.weak) {
+				t.Errorf("Parse() = %s, want opaque %q weak %v", got, tt.opaque, tt.weak)
+			}
+		})
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	sum := []byte{0xde, 0xad, 0xbe, 0xef}
+	if got := etag.Strong(sum).String(); got != `"3q2-7w"` {
+		t.Errorf("Strong() = %s, want \"3q2-7w\"", got)
+	}
+	if got := etag.Weak(sum).String(); got != `W/"3q2-7w"` {
+		t.Errorf("Weak() = %s, want W/\"3q2-7w\"", got)
+	}
+
+	a, b := etag.Of([]byte("hello")), etag.Of([]byte("hello"))
+	if !etag.StrongEqual(a, b) || etag.StrongEqual(a, etag.Of([]byte("world"))) {
+		t.Error("Of() is not deterministic per content")
+	}
+	if parsed, err := etag.Parse(a.String()); err != nil || parsed != a {
+		t.Errorf("Parse(Of().String()) = %v, %v, want a round trip", parsed, err)
+	}
+
+	var zero etag.ETag
+	if !zero.IsZero() || zero.String() != "" || a.IsZero() {
+		t.Error("IsZero() reports the wrong state")
+	}
+}
+
+func TestComparison(t *testing.T) {
+	strong, _ := etag.Parse(`"1"`)
+	weak, _ := etag.Parse(`W/"1"`)
+	other, _ := etag.Parse(`"2"`)
+
+	tests := []struct {
+		name         string
+		a, b         etag.ETag
+		strong, weak bool
+	}{
+		{"strong pair", strong, strong, true, true},
+		{"weak pair", weak, weak, false, true},
+		{"mixed", weak, strong, false, true},
+		{"different", strong, other, false, false},
+		{"zero", etag.ETag{}, etag.ETag{}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etag.StrongEqual(tt.a, tt.b); got != tt.strong {
+				t.Errorf("StrongEqual() = %v, want %v", got, tt.strong)
+			}
+			if got := etag.WeakEqual(tt.a, tt.b); got != tt.weak {
+				t.Errorf("WeakEqual() = %v, want %v", got, tt.weak)
+			}
+		})
+	}
+}
+
+func TestMatchAndNoneMatch(t *testing.T) {
+	current, _ := etag.Parse(`"v2"`)
+	weakCurrent, _ := etag.Parse(`W/"v2"`)
+
+	tests := []struct {
+		name      string
+		header    string
+		current   etag.ETag
+		match     bool
+		noneMatch bool
+	}{
+		{"listed", `"v1", "v2"`, current, true, false},
+		{"not listed", `"v1", "v3"`, current, false, true},
+		{"weak listed", `W/"v2"`, current, false, false},
+		{"weak current", `"v2"`, weakCurrent, false, false},
+		{"star", "*", current, true, false},
+		{"star without representation", "*", etag.ETag{}, false, true},
+		{"no spaces", `"v1","v2"`, current, true, false},
+		{"malformed tail ignored", `"v1", v2`, current, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etag.Match(tt.header, tt.current); got != tt.match {
+				t.Errorf("Match() = %v, want %v", got, tt.match)
+			}
+			if got := etag.NoneMatch(tt.header, tt.current); got != tt.noneMatch {
+				t.Errorf("NoneMatch() = %v, want %v", got, tt.noneMatch)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	current, _ := etag.Parse(`"v2"`)
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	at := modified.Format(http.TimeFormat)
+
+	tests := []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		expected int
+	}{
+		{"unconditional", "GET", nil, http.StatusOK},
+		{"if-none-match hit", "GET", map[string]string{"If-None-Match": `W/"v2"`}, http.StatusNotModified},
+		{"if-none-match miss", "GET", map[string]string{"If-None-Match": `"v1"`}, http.StatusOK},
+		{"if-none-match unsafe", "PUT", map[string]string{"If-None-Match": `"v2"`}, http.StatusPreconditionFailed},
+		{"if-none-match over if-modified-since", "GET",
+			map[string]string{"If-None-Match": `"v1"`, "If-Modified-Since": at}, http.StatusOK},
+		{"not modified since", "GET", map[string]string{"If-Modified-Since": at}, http.StatusNotModified},
+		{"modified since", "GET", map[string]string{"If-Modified-Since": before}, http.StatusOK},
+		{"if-modified-since ignored for POST", "POST", map[string]string{"If-Modified-Since": at}, http.StatusOK},
+		{"invalid date ignored", "GET", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		{"if-match hit", "PUT", map[string]string{"If-Match": `"v2"`}, http.StatusOK},
+		{"if-match miss", "PUT", map[string]string{"If-Match": `"v1"`}, http.StatusPreconditionFailed},
+		{"if-match weak", "PUT", map[string]string{"If-Match": `W/"v2"`}, http.StatusPreconditionFailed},
+		{"unmodified since", "PUT", map[string]string{"If-Unmodified-Since": at}, http.StatusOK},
+		{"modified after", "PUT", map[string]string{"If-Unmodified-Since": before}, http.StatusPreconditionFailed},
+		{"if-match over if-unmodified-since", "PUT",
+			map[string]string{"If-Match": `"v2"`, "If-Unmodified-Since": before}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := etag.Evaluate(r, current, modified); got != tt.expected {
+				t.Errorf("Evaluate() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateWithoutRepresentation(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/", nil)
+	r.Header.Set("If-None-Match", "*")
+	if got := etag.Evaluate(r, etag.ETag{}, time.Time{}); got != http.StatusOK {
+		t.Errorf("Evaluate() = %d, want 200 for a create-only PUT", got)
+	}
+
+	r.Header.Set("If-Match", "*")
+	if got := etag.Evaluate(r, etag.ETag{}, time.Time{}); got != http.StatusPreconditionFailed {
+		t.Errorf("Evaluate() = %d, want 412 for If-Match: * on a missing resource", got)
+	}
+}
@@ -0,0 +1,80 @@
+package etag
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// Evaluate evaluates the conditional headers of r against the current state
+// of the target resource, in the order of RFC 9110 section 13.2.2, and returns
+// the status code to respond with:
+//
+//   - http.StatusPreconditionFailed (412) if If-Match, or If-Unmodified-Since
+//     when If-Match is absent, does not hold, or if If-None-Match does not hold
+//     for a method other than GET or HEAD
+//   - http.StatusNotModified (304) if If-None-Match, or If-Modified-Since when
+//     If-None-Match is absent, does not hold for GET or HEAD
+//   - http.StatusOK otherwise, meaning the request should be processed normally
+//
+// A zero current means the resource has no current representation, and a zero
+// lastModified means its modification time is unknown, in which case the date
+// conditions are ignored. Dates are compared at one-second resolution, as HTTP
+// dates carry no more. If-Range is left to the range handling.
+//
+// A 304 response should carry the same ETag, Last-Modified, Cache-Control and
+// Vary headers as a 200 response would.
+//
+// Example:
+//
+//	tag := etag.Of(body)
+//	w.Header().Set(headers.ETag, tag.String())
+//	if status := etag.Evaluate(r, tag, modTime); status != http.StatusOK {
+//	    w.WriteHeader(status)
+//	    return
+//	}
+//	w.Write(body)
+func Evaluate(r *http.Request, current ETag, lastModified time.Time) int {
+	lastModified = lastModified.Truncate(time.Second)
+
+	if ifMatch := listHeader(r, headers.IfMatch); ifMatch != "" {
+		if !Match(ifMatch, current) {
+			return http.StatusPreconditionFailed
+		}
+	} else if since, ok := headerTime(r, headers.IfUnmodifiedSince); ok && !lastModified.IsZero() {
+		if lastModified.After(since) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	safe := r.Method == http.MethodGet || r.Method == http.MethodHead
+	if ifNoneMatch := listHeader(r, headers.IfNoneMatch); ifNoneMatch != "" {
+		if !NoneMatch(ifNoneMatch, current) {
+			if safe {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	} else if since, ok := headerTime(r, headers.IfModifiedSince); ok && safe && !lastModified.IsZero() {
+		if !lastModified.After(since) {
+			return http.StatusNotModified
+		}
+	}
+	return http.StatusOK
+}
+
+// listHeader returns the list header name combined across all lines it was
+// sent on.
+func listHeader(r *http.Request, name string) string {
+	return strings.Join(r.Header.Values(name), ",")
+}
+
+// headerTime parses the HTTP-date in the named header. ok is false if the
+// header is missing or not a valid date, in which case RFC 9110 requires the
+// condition to be ignored.
+func headerTime(r *http.Request, name string) (time.Time, bool) {
+	t, err := http.ParseTime(r.Header.Get(name))
+	return t, err == nil
+}
@@ -0,0 +1,172 @@
+package etag
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// DefaultMaxBufferSize is the largest response body, in bytes, that Middleware
+// buffers unless MaxBufferSize is given.
+const DefaultMaxBufferSize = 1 << 20
+
+// Option configures Middleware.
+type Option func(*middlewareConfig)
+
+type middlewareConfig struct {
+	maxBufferSize int
+	weak          bool
+}
+
+// MaxBufferSize sets the largest response body, in bytes, that Middleware
+// buffers to compute an entity tag. Larger responses are streamed unchanged.
+func MaxBufferSize(n int) Option {
+	return func(c *middlewareConfig) {
+		c.maxBufferSize = n
+	}
+}
+
+// WeakTags makes Middleware generate weak entity tags, for responses that are
+// later transformed, for instance compressed, by other middleware.
+func WeakTags() Option {
+	return func(c *middlewareConfig) {
+		c.weak = true
+	}
+}
+
+// Middleware answers conditional GET and HEAD requests with 304 Not Modified
+// when the response has not changed. It buffers the response of next and, if
+// next responds 200 OK, sets an ETag computed from the body unless next set
+// one, then evaluates If-None-Match and If-Modified-Since against that ETag and
+// any Last-Modified header next set. The body is only sent when the client's
+// copy is stale.
+//
+// Responses other than 200 OK, bodies larger than DefaultMaxBufferSize (or
+// MaxBufferSize), and handlers that flush are streamed unchanged. Other methods
+// are passed straight to next: If-Match on a PUT or DELETE must be checked
+// against the stored resource before it changes, with Evaluate.
+//
+// The handler still runs on every request, so Middleware saves bandwidth, not
+// work. For HEAD requests it must write the same body as for GET, as net/http
+// handlers usually do, or the ETag will differ.
+//
+// Example:
+//
+//	mux.Handle("/catalog", etag.Middleware(catalogHandler))
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	cfg := middlewareConfig{maxBufferSize: DefaultMaxBufferSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, max: cfg.maxBufferSize}
+		next.ServeHTTP(bw, r)
+		bw.finish(r, cfg.weak)
+	})
+}
+
+// bufferedWriter holds back a 200 OK response until the handler returns, and
+// streams anything else.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status    int
+	buf       bytes.Buffer
+	max       int
+	streaming bool
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	switch {
+	case w.streaming:
+		w.ResponseWriter.WriteHeader(code)
+	case code >= 100 && code < 200:
+		w.ResponseWriter.WriteHeader(code) // informational, such as 103 Early Hints
+	case w.status == 0:
+		w.status = code
+		if code != http.StatusOK {
+			w.stream()
+		}
+	}
+}
+
+func (w *bufferedWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.streaming && w.buf.Len()+len(p) > w.max {
+		if err := w.stream(); err != nil {
+			return 0, err
+		}
+	}
+	if w.streaming {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.buf.Write(p)
+}
+
+// Flush switches to streaming, since a handler that flushes wants its output
+// sent as it is produced.
+func (w *bufferedWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.streaming && w.stream() != nil {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *bufferedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// stream writes the status and anything buffered, and passes later writes
+// through.
+func (w *bufferedWriter) stream() error {
+	w.streaming = true
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf = bytes.Buffer{}
+	return err
+}
+
+// finish sends the buffered response, or 304 Not Modified in its place.
+func (w *bufferedWriter) finish(r *http.Request, weak bool) {
+	if w.streaming {
+		return
+	}
+
+	h := w.Header()
+	current, err := Parse(h.Get(headers.ETag))
+	if err != nil {
+		current = Of(w.buf.Bytes())
+		current.weak = weak
+		h.Set(headers.ETag, current.String())
+	}
+	lastModified, _ := http.ParseTime(h.Get(headers.LastModified))
+
+	if Evaluate(r, current, lastModified) == http.StatusNotModified {
+		for _, name := range []string{headers.ContentType, headers.ContentLength, headers.ContentEncoding, headers.LastModified} {
+			h.Del(name)
+		}
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if h.Get(headers.ContentLength) == "" && (r.Method != http.MethodHead || w.buf.Len() > 0) {
+		h.Set(headers.ContentLength, strconv.Itoa(w.buf.Len()))
+	}
+	w.status = http.StatusOK
+	_ = w.stream() // a failed write means the client has gone away
+}
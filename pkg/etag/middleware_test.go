@@ -0,0 +1,110 @@
+package etag_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/etag"
+)
+
+func serve(h http.Handler, method string, header map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/", nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	h := etag.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, "hello")
+	}))
+
+	first := serve(h, "GET", nil)
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.String() != "hello" || tag == "" {
+		t.Fatalf("first response = %d %q, ETag %q", first.Code, first.Body, tag)
+	}
+	if got := first.Header().Get("Content-Length"); got != "5" {
+		t.Errorf("Content-Length = %q, want 5", got)
+	}
+
+	second := serve(h, "GET", map[string]string{"If-None-Match": tag})
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Errorf("conditional response = %d %q, want 304 with no body", second.Code, second.Body)
+	}
+	if second.Header().Get("ETag") != tag || second.Header().Get("Content-Type") != "" {
+		t.Errorf("304 headers = %v", second.Header())
+	}
+
+	stale := serve(h, "GET", map[string]string{"If-None-Match": `"other"`})
+	if stale.Code != http.StatusOK || stale.Body.String() != "hello" {
+		t.Errorf("stale response = %d %q, want 200 hello", stale.Code, stale.Body)
+	}
+}
+
+func TestMiddlewareHandlerValidators(t *testing.T) {
+	h := etag.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"v7"`)
+		w.Header().Set("Last-Modified", "Wed, 01 May 2024 12:00:00 GMT")
+		_, _ = io.WriteString(w, "body")
+	}))
+
+	if w := serve(h, "GET", map[string]string{"If-None-Match": `W/"v7"`}); w.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match on handler ETag = %d, want 304", w.Code)
+	}
+	if w := serve(h, "GET", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}); w.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since = %d, want 304", w.Code)
+	}
+}
+
+func TestMiddlewarePassThrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+		opts    []etag.Option
+	}{
+		{"not found", "GET", func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "missing", http.StatusNotFound)
+		}, nil},
+		{"unsafe method", "POST", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "created")
+		}, nil},
+		{"too large", "GET", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, strings.Repeat("x", 10))
+			_, _ = io.WriteString(w, strings.Repeat("y", 10))
+		}, []etag.Option{etag.MaxBufferSize(15)}},
+		{"flushed", "GET", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "event")
+			w.(http.Flusher).Flush()
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := serve(tt.handler, tt.method, nil)
+			got := serve(etag.Middleware(tt.handler, tt.opts...), tt.method, map[string]string{"If-None-Match": "*"})
+			if got.Code != want.Code || got.Body.String() != want.Body.String() || got.Header().Get("ETag") != "" {
+				t.Errorf("response = %d %q (ETag %q), want %d %q unchanged",
+					got.Code, got.Body, got.Header().Get("ETag"), want.Code, want.Body)
+			}
+		})
+	}
+}
+
+func TestMiddlewareWeakTags(t *testing.T) {
+	h := etag.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	}), etag.WeakTags())
+
+	if tag := serve(h, "GET", nil).Header().Get("ETag"); !strings.HasPrefix(tag, `W/"`) {
+		t.Errorf("ETag = %q, want a weak tag", tag)
+	}
+}
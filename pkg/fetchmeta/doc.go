@@ -0,0 +1,24 @@
+// Package fetchmeta filters requests by their fetch metadata, the
+// Sec-Fetch-Site, Sec-Fetch-Mode, Sec-Fetch-Dest and Sec-Fetch-User headers
+// browsers send to tell a server where a request comes from and what it is
+// for.
+//
+// From reads the headers of a request, and a Policy, a resource isolation
+// policy, decides whether it is allowed. Rejecting the cross-site requests an
+// application never expects, such as a form posted or an image loaded from
+// another site, defends against CSRF, cross-site script inclusion and
+// cross-site leaks without tokens:
+//
+//	handler := fetchmeta.Middleware(mux, fetchmeta.DefaultPolicy())
+//
+// Requests without fetch metadata, from clients other than browsers or from
+// older browsers, are allowed unless Policy.RequireMetadata is set, so
+// combine the policy with another CSRF defense when those browsers matter.
+//
+// Example:
+//
+//	m := fetchmeta.From(r)
+//	if m.Site == fetchmeta.SiteCrossSite && m.Dest == fetchmeta.DestImage {
+//	    // hotlinked image
+//	}
+package fetchmeta
@@ -0,0 +1,96 @@
+package fetchmeta
+
+import (
+	"net/http"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// Sec-Fetch-Site values: how the origin that initiated a request relates to
+// the origin of its target.
+const (
+	SiteSameOrigin = "same-origin"
+	SiteSameSite   = "same-site"
+	SiteCrossSite  = "cross-site"
+	SiteNone       = "none" // Typed URL, bookmark, or other direct navigation
+)
+
+// Sec-Fetch-Mode values.
+const (
+	ModeNavigate   = "navigate"
+	ModeCORS       = "cors"
+	ModeNoCORS     = "no-cors"
+	ModeSameOrigin = "same-origin"
+	ModeWebSocket  = "websocket"
+)
+
+// Sec-Fetch-Dest values, the common ones. Browsers send others, such as
+// "audioworklet" or "xslt", which Metadata reports as they are.
+const (
+	DestEmpty         = "empty" // fetch() and XMLHttpRequest
+	DestDocument      = "document"
+	DestIframe        = "iframe"
+	DestFrame         = "frame"
+	DestEmbed         = "embed"
+	DestObject        = "object"
+	DestImage         = "image"
+	DestScript        = "script"
+	DestStyle         = "style"
+	DestFont          = "font"
+	DestAudio         = "audio"
+	DestVideo         = "video"
+	DestTrack         = "track"
+	DestManifest      = "manifest"
+	DestWorker        = "worker"
+	DestSharedWorker  = "sharedworker"
+	DestServiceWorker = "serviceworker"
+	DestReport        = "report"
+)
+
+// Metadata holds the Sec-Fetch-* headers of a request. Fields are empty when
+// their header is missing or malformed, as for requests from clients other
+// than browsers, and from browsers older than 2020 or so.
+type Metadata struct {
+	Site string // Sec-Fetch-Site, one of the Site* constants
+	Mode string // Sec-Fetch-Mode, one of the Mode* constants
+	Dest string // Sec-Fetch-Dest, such as DestImage
+	User bool   // Sec-Fetch-User: a navigation triggered by the user
+}
+
+// From returns the fetch metadata of r.
+func From(r *http.Request) Metadata {
+	return Metadata{
+		Site: token(r, headers.SecFetchSite),
+		Mode: token(r, headers.SecFetchMode),
+		Dest: token(r, headers.SecFetchDest),
+		User: boolean(r, headers.SecFetchUser),
+	}
+}
+
+// Present reports whether the request carried fetch metadata, that is a
+// Sec-Fetch-Site header, which browsers that support it send on every
+// request.
+func (m Metadata) Present() bool {
+	return m.Site != ""
+}
+
+// token parses the named header as a structured field token.
+func token(r *http.Request, name string) string {
+	item, err := sfv.ParseItem(r.Header.Get(name))
+	if err != nil {
+		return ""
+	}
+	t, _ := item.Value.(sfv.Token)
+	return string(t)
+}
+
+// boolean parses the named header as a structured field boolean.
+func boolean(r *http.Request, name string) bool {
+	item, err := sfv.ParseItem(r.Header.Get(name))
+	if err != nil {
+		return false
+	}
+	b, _ := item.Value.(bool)
+	return b
+}
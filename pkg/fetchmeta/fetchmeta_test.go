@@ -0,0 +1,85 @@
+package fetchmeta_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/fetchmeta"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// request returns a request with the given Sec-Fetch-Site, -Mode and
+// -Dest headers, leaving out those that are empty.
+func request(method, site, mode, dest string) *http.Request {
+	r := httptest.NewRequest(method, "/", nil)
+	for name, value := range map[string]string{
+		headers.SecFetchSite: site,
+		headers.SecFetchMode: mode,
+		headers.SecFetchDest: dest,
+	} {
+		if value != "" {
+			r.Header.Set(name, value)
+		}
+	}
+	return r
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected fetchmeta.Metadata
+	}{
+		{
+			name: "navigation",
+			header: map[string]string{
+				headers.SecFetchSite: "cross-site",
+				headers.SecFetchMode: "navigate",
+				headers.SecFetchDest: "document",
+				headers.SecFetchUser: "?1",
+			},
+			expected: fetchmeta.Metadata{Site: fetchmeta.SiteCrossSite, Mode: fetchmeta.ModeNavigate, Dest: fetchmeta.DestDocument, User: true},
+		},
+		{
+			name: "fetch",
+			header: map[string]string{
+				headers.SecFetchSite: "same-origin",
+				headers.SecFetchMode: "cors",
+				headers.SecFetchDest: "empty",
+			},
+			expected: fetchmeta.Metadata{Site: fetchmeta.SiteSameOrigin, Mode: fetchmeta.ModeCORS, Dest: fetchmeta.DestEmpty},
+		},
+		{
+			name:     "unknown destination",
+			header:   map[string]string{headers.SecFetchDest: "audioworklet"},
+			expected: fetchmeta.Metadata{Dest: "audioworklet"},
+		},
+		{
+			name: "malformed",
+			header: map[string]string{
+				headers.SecFetchSite: `"cross-site"`,
+				headers.SecFetchMode: "no cors",
+				headers.SecFetchUser: "1",
+			},
+			expected: fetchmeta.Metadata{},
+		},
+		{"missing", nil, fetchmeta.Metadata{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.header {
+				r.Header.Set(name, value)
+			}
+			got := fetchmeta.From(r)
+			if got != tt.expected {
+				t.Errorf("From() = %+v, want %+v", got, tt.expected)
+			}
+			if got.Present() != (tt.expected.Site != "") {
+				t.Errorf("Present() = %v", got.Present())
+			}
+		})
+	}
+}
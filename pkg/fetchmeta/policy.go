@@ -0,0 +1,95 @@
+package fetchmeta
+
+import (
+	"net/http"
+)
+
+// Policy is a resource isolation policy: it decides from the fetch metadata
+// of a request whether another site may make it. The zero Policy only allows
+// requests from the same origin, direct navigations such as a typed URL, and
+// requests without fetch metadata.
+type Policy struct {
+	// AllowSameSite also allows requests from other origins of the same site,
+	// such as a sibling subdomain or another port.
+	AllowSameSite bool
+
+	// AllowNavigation allows cross-site top-level and frame navigations, GET
+	// requests in navigate mode, so that other sites can link to this one.
+	// Navigations that load a document into an <object> or <embed> are still
+	// rejected.
+	AllowNavigation bool
+
+	// AllowCORS allows cross-site requests in cors mode, for endpoints that
+	// decide with CORS which origins may use them.
+	AllowCORS bool
+
+	// RequireMetadata rejects requests without fetch metadata. Clients other
+	// than browsers, and browsers older than 2020 or so, do not send it, so
+	// only set this for endpoints used by modern browsers alone.
+	RequireMetadata bool
+
+	// Exempt, if set, allows the requests it returns true for regardless of
+	// their metadata, such as a public image or a webhook endpoint.
+	Exempt func(r *http.Request) bool
+
+	// Reject writes the response to rejected requests. Defaults to 403
+	// Forbidden.
+	Reject http.Handler
+}
+
+// DefaultPolicy returns the policy commonly recommended for web
+// applications: requests from the same site and cross-site navigations are
+// allowed, so that links from other sites keep working, while cross-site
+// subresource and fetch() requests, the vehicles of CSRF, XSSI and timing
+// attacks, are rejected.
+func DefaultPolicy() Policy {
+	return Policy{AllowSameSite: true, AllowNavigation: true}
+}
+
+// Allow reports whether p allows r.
+func (p Policy) Allow(r *http.Request) bool {
+	if p.Exempt != nil && p.Exempt(r) {
+		return true
+	}
+	m := From(r)
+	switch m.Site {
+	case "":
+		return !p.RequireMetadata
+	case SiteSameOrigin, SiteNone:
+		return true
+	case SiteSameSite:
+		if p.AllowSameSite {
+			return true
+		}
+	}
+	if p.AllowNavigation && m.Mode == ModeNavigate &&
+		(r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		m.Dest != DestObject && m.Dest != DestEmbed {
+		return true
+	}
+	return p.AllowCORS && m.Mode == ModeCORS
+}
+
+// Middleware rejects the requests p does not allow, with p.Reject, and
+// passes the others to next.
+//
+// Example:
+//
+//	handler := fetchmeta.Middleware(mux, fetchmeta.DefaultPolicy())
+func Middleware(next http.Handler, p Policy) http.Handler {
+	reject := p.Reject
+	if reject == nil {
+		reject = http.HandlerFunc(forbidden)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.Allow(r) {
+			reject.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func forbidden(w http.ResponseWriter, _ *http.Request) {
+	http.Error(w, "cross-site request rejected", http.StatusForbidden)
+}
@@ -0,0 +1,84 @@
+package fetchmeta_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/fetchmeta"
+)
+
+func TestPolicyAllow(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   fetchmeta.Policy
+		request  *http.Request
+		expected bool
+	}{
+		{"no metadata", fetchmeta.Policy{}, request(http.MethodPost, "", "", ""), true},
+		{"no metadata required", fetchmeta.Policy{RequireMetadata: true}, request(http.MethodPost, "", "", ""), false},
+		{"same origin", fetchmeta.Policy{}, request(http.MethodPost, "same-origin", "cors", "empty"), true},
+		{"direct navigation", fetchmeta.Policy{}, request(http.MethodGet, "none", "navigate", "document"), true},
+		{"same site", fetchmeta.Policy{}, request(http.MethodGet, "same-site", "no-cors", "image"), false},
+		{"same site allowed", fetchmeta.Policy{AllowSameSite: true}, request(http.MethodGet, "same-site", "no-cors", "image"), true},
+		{"cross-site link", fetchmeta.Policy{}, request(http.MethodGet, "cross-site", "navigate", "document"), false},
+		{"cross-site link allowed", fetchmeta.DefaultPolicy(), request(http.MethodGet, "cross-site", "navigate", "document"), true},
+		{"cross-site frame", fetchmeta.DefaultPolicy(), request(http.MethodGet, "cross-site", "navigate", "iframe"), true},
+		{"cross-site form post", fetchmeta.DefaultPolicy(), request(http.MethodPost, "cross-site", "navigate", "document"), false},
+		{"cross-site object", fetchmeta.DefaultPolicy(), request(http.MethodGet, "cross-site", "navigate", "object"), false},
+		{"cross-site embed", fetchmeta.DefaultPolicy(), request(http.MethodGet, "cross-site", "navigate", "embed"), false},
+		{"cross-site image", fetchmeta.DefaultPolicy(), request(http.MethodGet, "cross-site", "no-cors", "image"), false},
+		{"cross-site script", fetchmeta.DefaultPolicy(), request(http.MethodGet, "cross-site", "no-cors", "script"), false},
+		{"cross-site fetch", fetchmeta.DefaultPolicy(), request(http.MethodPost, "cross-site", "cors", "empty"), false},
+		{"cross-site fetch allowed", fetchmeta.Policy{AllowCORS: true}, request(http.MethodPost, "cross-site", "cors", "empty"), true},
+		{"cross-site no-cors with CORS allowed", fetchmeta.Policy{AllowCORS: true}, request(http.MethodPost, "cross-site", "no-cors", "empty"), false},
+		{"unknown site", fetchmeta.Policy{}, request(http.MethodGet, "elsewhere", "cors", "empty"), false},
+		{
+			name:     "exempt",
+			policy:   fetchmeta.Policy{Exempt: func(r *http.Request) bool { return strings.HasPrefix(r.URL.Path, "/") }},
+			request:  request(http.MethodGet, "cross-site", "no-cors", "image"),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allow(tt.request); got != tt.expected {
+				t.Errorf("Allow() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		fetchmeta.Middleware(next, fetchmeta.DefaultPolicy()).ServeHTTP(w, request(http.MethodPost, "same-origin", "cors", "empty"))
+		if w.Code != http.StatusOK || w.Body.String() != "ok" {
+			t.Errorf("response = %d %q, want the handler's", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		fetchmeta.Middleware(next, fetchmeta.DefaultPolicy()).ServeHTTP(w, request(http.MethodPost, "cross-site", "no-cors", "empty"))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("custom rejection", func(t *testing.T) {
+		policy := fetchmeta.DefaultPolicy()
+		policy.Reject = http.NotFoundHandler()
+		w := httptest.NewRecorder()
+		fetchmeta.Middleware(next, policy).ServeHTTP(w, request(http.MethodGet, "cross-site", "no-cors", "script"))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+// Package form provides the query package's extraction API for HTML form
+// bodies, reading application/x-www-form-urlencoded and multipart/form-data
+// values from r.PostForm instead of the URL query.
+//
+// # Overview
+//
+// Every function mirrors its counterpart in package query, with the same
+// fail-safe semantics: if a field is missing, empty, or cannot be parsed, the
+// provided default value is returned.
+//
+//	// POST /signup with body: name=Alice&age=30&newsletter=on
+//	name       := form.String(r, "name", "")         // "Alice"
+//	age        := form.Int(r, "age", 0)              // 30
+//	newsletter := form.Bool(r, "newsletter", false)  // true
+//
+// Only body values are read; URL query parameters are never mixed in, so a
+// link cannot smuggle values into a POSTed form. Use package query for those.
+//
+// # Parsing
+//
+// The body is parsed on first use with r.ParseForm, or r.ParseMultipartForm for
+// multipart requests, which keeps up to DefaultMaxMemory of file parts in
+// memory and stores the rest in temporary files. A body that fails to parse
+// yields whatever values were read before the error, like r.PostFormValue.
+// Wrap the body with http.MaxBytesReader to bound the total upload size.
+//
+// # Parsing Policies
+//
+// NewExtractor returns a query.Extractor reading form values, combining a
+// query.Config with a custom memory limit:
+//
+//	var f = form.NewExtractor(query.HardenedConfig(), 1<<20)
+//
+//	age := f.IntInRange(r, "age", 0, 0, 150)
+//	err := f.StrictBind(r, &signup)
+//
+// query.ValueWith, query.SliceWith and query.ValuePtrWith accept such an
+// Extractor for custom parsers.
+package form
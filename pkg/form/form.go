@@ -0,0 +1,272 @@
+package form
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+// DefaultMaxMemory is the number of bytes of a multipart body kept in memory by
+// the package-level functions, matching net/http's own default.
+const DefaultMaxMemory = 32 << 20
+
+// std is the Extractor behind the package-level functions.
+var std = NewExtractor(query.Config{}, DefaultMaxMemory)
+
+// NewExtractor returns a query.Extractor that applies cfg to form values,
+// keeping up to maxMemory bytes of a multipart body in memory. cfg.Source is
+// replaced.
+func NewExtractor(cfg query.Config, maxMemory int64) *query.Extractor {
+	cfg.Source = Source(maxMemory)
+	return query.NewExtractor(cfg)
+}
+
+// Source returns a query.Config Source that reads form values, for building an
+// Extractor by hand. See Values.
+func Source(maxMemory int64) func(r *http.Request) url.Values {
+	return func(r *http.Request) url.Values {
+		return Values(r, maxMemory)
+	}
+}
+
+// Values parses the request body if it has not been parsed yet and returns
+// r.PostForm, keeping up to maxMemory bytes of a multipart body in memory.
+// Returns an empty set of values if the body cannot be parsed at all.
+func Values(r *http.Request, maxMemory int64) url.Values {
+	if isMultipart(r) {
+		_ = r.ParseMultipartForm(maxMemory)
+	} else {
+		_ = r.ParseForm()
+	}
+	if r.PostForm == nil {
+		return url.Values{}
+	}
+	return r.PostForm
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// Value extracts a form value and converts it using the provided parser.
+// See query.Value.
+func Value[T any](r *http.Request, key string, defaultValue T, parser query.Parser[T]) T {
+	return query.ValueWith(std, r, key, defaultValue, parser)
+}
+
+// Slice extracts all values of a form field and converts them using the
+// provided parser. See query.Slice.
+func Slice[T any](r *http.Request, key string, defaultValue T, parser query.Parser[T]) []T {
+	return query.SliceWith(std, r, key, defaultValue, parser)
+}
+
+// ValuePtr extracts a form value as a pointer, or nil if it is missing or
+// invalid. See query.ValuePtr.
+func ValuePtr[T any](r *http.Request, key string, parser query.Parser[T]) *T {
+	return query.ValuePtrWith(std, r, key, parser)
+}
+
+// String extracts a string form value. See query.String.
+func String(r *http.Request, key string, defaultValue string) string {
+	return std.String(r, key, defaultValue)
+}
+
+// Strings extracts all values of a form field. See query.Strings.
+func Strings(r *http.Request, key string) []string {
+	return std.Strings(r, key)
+}
+
+// Int extracts an integer form value. See query.Int.
+func Int(r *http.Request, key string, defaultValue int) int {
+	return std.Int(r, key, defaultValue)
+}
+
+// Int64 extracts an int64 form value. See query.Int64.
+func Int64(r *http.Request, key string, defaultValue int64) int64 {
+	return std.Int64(r, key, defaultValue)
+}
+
+// Float64 extracts a float64 form value. See query.Float64.
+func Float64(r *http.Request, key string, defaultValue float64) float64 {
+	return std.Float64(r, key, defaultValue)
+}
+
+// Int8 extracts an int8 form value. See query.Int8.
+func Int8(r *http.Request, key string, defaultValue int8) int8 {
+	return std.Int8(r, key, defaultValue)
+}
+
+// Int16 extracts an int16 form value. See query.Int16.
+func Int16(r *http.Request, key string, defaultValue int16) int16 {
+	return std.Int16(r, key, defaultValue)
+}
+
+// Int32 extracts an int32 form value. See query.Int32.
+func Int32(r *http.Request, key string, defaultValue int32) int32 {
+	return std.Int32(r, key, defaultValue)
+}
+
+// Float32 extracts a float32 form value. See query.Float32.
+func Float32(r *http.Request, key string, defaultValue float32) float32 {
+	return std.Float32(r, key, defaultValue)
+}
+
+// IntInRange extracts an integer form value clamped to [minValue, maxValue].
+// See query.IntInRange.
+func IntInRange(r *http.Request, key string, defaultValue, minValue, maxValue int) int {
+	return std.IntInRange(r, key, defaultValue, minValue, maxValue)
+}
+
+// Int64InRange extracts an int64 form value clamped to [minValue, maxValue].
+// See query.Int64InRange.
+func Int64InRange(r *http.Request, key string, defaultValue, minValue, maxValue int64) int64 {
+	return std.Int64InRange(r, key, defaultValue, minValue, maxValue)
+}
+
+// Float64InRange extracts a float64 form value clamped to [minValue, maxValue].
+// See query.Float64InRange.
+func Float64InRange(r *http.Request, key string, defaultValue, minValue, maxValue float64) float64 {
+	return std.Float64InRange(r, key, defaultValue, minValue, maxValue)
+}
+
+// Bool extracts a boolean form value. Checkboxes submit "on" when checked and
+// are omitted otherwise, so defaultValue should usually be false.
+// See query.Bool.
+func Bool(r *http.Request, key string, defaultValue bool) bool {
+	return std.Bool(r, key, defaultValue)
+}
+
+// BoolStrict extracts a boolean form value using strconv.ParseBool semantics.
+// See query.BoolStrict.
+func BoolStrict(r *http.Request, key string, defaultValue bool) bool {
+	return std.BoolStrict(r, key, defaultValue)
+}
+
+// Flag reports whether a form field is set and not explicitly false.
+// See query.Flag.
+func Flag(r *http.Request, key string) bool {
+	return std.Flag(r, key)
+}
+
+// Time extracts a time.Time form value. Values from <input type="date"> parse
+// with the default layouts; <input type="datetime-local"> needs the
+// "2006-01-02T15:04" layout. See query.Time.
+func Time(r *http.Request, key string, defaultValue time.Time, layouts ...string) time.Time {
+	return std.Time(r, key, defaultValue, layouts...)
+}
+
+// Ints extracts all integer values of a form field. See query.Ints.
+func Ints(r *http.Request, key string, defaultValue int) []int {
+	return std.Ints(r, key, defaultValue)
+}
+
+// Int64s extracts all int64 values of a form field. See query.Int64s.
+func Int64s(r *http.Request, key string, defaultValue int64) []int64 {
+	return std.Int64s(r, key, defaultValue)
+}
+
+// Float64s extracts all float64 values of a form field. See query.Float64s.
+func Float64s(r *http.Request, key string, defaultValue float64) []float64 {
+	return std.Float64s(r, key, defaultValue)
+}
+
+// Bools extracts all boolean values of a form field. See query.Bools.
+func Bools(r *http.Request, key string, defaultValue bool) []bool {
+	return std.Bools(r, key, defaultValue)
+}
+
+// StringPtr extracts a string form value, or nil if the field is missing.
+// See query.StringPtr.
+func StringPtr(r *http.Request, key string) *string {
+	return std.StringPtr(r, key)
+}
+
+// IntPtr extracts an integer form value, or nil if it is missing or invalid.
+func IntPtr(r *http.Request, key string) *int {
+	return std.IntPtr(r, key)
+}
+
+// Int64Ptr extracts an int64 form value, or nil if it is missing or invalid.
+func Int64Ptr(r *http.Request, key string) *int64 {
+	return std.Int64Ptr(r, key)
+}
+
+// Float64Ptr extracts a float64 form value, or nil if it is missing or invalid.
+func Float64Ptr(r *http.Request, key string) *float64 {
+	return std.Float64Ptr(r, key)
+}
+
+// BoolPtr extracts a boolean form value, or nil if it is missing or invalid.
+func BoolPtr(r *http.Request, key string) *bool {
+	return std.BoolPtr(r, key)
+}
+
+// Has checks if a form field was submitted (even if empty). See query.Has.
+func Has(r *http.Request, key string) bool {
+	return std.Has(r, key)
+}
+
+// Count returns the number of values submitted for a form field.
+// See query.Count.
+func Count(r *http.Request, key string) int {
+	return std.Count(r, key)
+}
+
+// IsMultiple checks if a form field was submitted more than once.
+// See query.IsMultiple.
+func IsMultiple(r *http.Request, key string) bool {
+	return std.IsMultiple(r, key)
+}
+
+// AllowKeys returns the submitted form fields that are not in the allowed list.
+// See query.AllowKeys.
+func AllowKeys(r *http.Request, keys ...string) []string {
+	return std.AllowKeys(r, keys...)
+}
+
+// Bind populates a struct from the form body using `query` struct tags.
+// See query.Bind.
+func Bind(r *http.Request, dst any) error {
+	return std.Bind(r, dst)
+}
+
+// StrictBind is like Bind, but additionally rejects unknown form fields.
+// See query.StrictBind.
+func StrictBind(r *http.Request, dst any) error {
+	return std.StrictBind(r, dst)
+}
+
+// ValueOrFail extracts a required form value, writing 400 Bad Request if it is
+// missing, empty or invalid. See query.ValueOrFail.
+func ValueOrFail[T any](w http.ResponseWriter, r *http.Request, key string, parser query.Parser[T]) (T, bool) {
+	return query.ValueOrFailWith(std, w, r, key, parser)
+}
+
+// StringOrFail extracts a required string form value. See query.StringOrFail.
+func StringOrFail(w http.ResponseWriter, r *http.Request, key string) (string, bool) {
+	return std.StringOrFail(w, r, key)
+}
+
+// IntOrFail extracts a required integer form value. See query.IntOrFail.
+func IntOrFail(w http.ResponseWriter, r *http.Request, key string) (int, bool) {
+	return std.IntOrFail(w, r, key)
+}
+
+// Int64OrFail extracts a required int64 form value. See query.Int64OrFail.
+func Int64OrFail(w http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	return std.Int64OrFail(w, r, key)
+}
+
+// Float64OrFail extracts a required float64 form value. See query.Float64OrFail.
+func Float64OrFail(w http.ResponseWriter, r *http.Request, key string) (float64, bool) {
+	return std.Float64OrFail(w, r, key)
+}
+
+// BoolOrFail extracts a required boolean form value. See query.BoolOrFail.
+func BoolOrFail(w http.ResponseWriter, r *http.Request, key string) (bool, bool) {
+	return std.BoolOrFail(w, r, key)
+}
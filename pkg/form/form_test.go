@@ -0,0 +1,168 @@
+package form_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/form"
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+func newFormRequest(target, body string) *http.Request {
+	r := httptest.NewRequest("POST", target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func newMultipartRequest(t *testing.T, fields map[string][]string) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, vals := range fields {
+		for _, v := range vals {
+			if err := mw.WriteField(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("POST", "/upload", &buf)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestExtractors(t *testing.T) {
+	r := newFormRequest("/signup?age=99&ref=link",
+		"name=Alice&age=30&newsletter=on&score=2.5&tag=go&tag=rust&born=1990-04-01&id=1&id=x&empty=")
+
+	if got := form.String(r, "name", ""); got != "Alice" {
+		t.Errorf("String() = %q, want Alice", got)
+	}
+	if got := form.Int(r, "age", 0); got != 30 {
+		t.Errorf("Int() = %v, want 30 from the body, not the query", got)
+	}
+	if got := form.String(r, "ref", "none"); got != "none" {
+		t.Errorf("String(ref) = %q, want query parameters ignored", got)
+	}
+	if !form.Bool(r, "newsletter", false) {
+		t.Error("Bool() = false, want true for a checked checkbox")
+	}
+	if got := form.Float64(r, "score", 0); got != 2.5 {
+		t.Errorf("Float64() = %v, want 2.5", got)
+	}
+	if got := form.Strings(r, "tag"); !reflect.DeepEqual(got, []string{"go", "rust"}) {
+		t.Errorf("Strings() = %v, want [go rust]", got)
+	}
+	if got := form.Ints(r, "id", -1); !reflect.DeepEqual(got, []int{1, -1}) {
+		t.Errorf("Ints() = %v, want [1 -1]", got)
+	}
+	if got := form.Time(r, "born", time.Time{}); !got.Equal(time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time() = %v, want 1990-04-01", got)
+	}
+	if got := form.IntInRange(r, "age", 0, 0, 18); got != 18 {
+		t.Errorf("IntInRange() = %v, want 18", got)
+	}
+	if !form.Has(r, "empty") || form.Has(r, "missing") {
+		t.Error("Has() should report submitted fields only")
+	}
+	if got := form.Count(r, "tag"); got != 2 || !form.IsMultiple(r, "tag") {
+		t.Errorf("Count() = %v, want 2", got)
+	}
+	if got := form.IntPtr(r, "missing"); got != nil {
+		t.Errorf("IntPtr() = %v, want nil", *got)
+	}
+	if got := form.Value(r, "name", 0, func(s string) (int, error) { return len(s), nil }); got != 5 {
+		t.Errorf("Value() = %v, want 5", got)
+	}
+	if got := form.Slice(r, "tag", "", func(s string) (string, error) { return strings.ToUpper(s), nil }); !reflect.DeepEqual(got, []string{"GO", "RUST"}) {
+		t.Errorf("Slice() = %v, want [GO RUST]", got)
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	r := newMultipartRequest(t, map[string][]string{
+		"title": {"Report"},
+		"pages": {"12"},
+	})
+
+	if got := form.String(r, "title", ""); got != "Report" {
+		t.Errorf("String() = %q, want Report", got)
+	}
+	if got := form.Int(r, "pages", 0); got != 12 {
+		t.Errorf("Int() = %v, want 12", got)
+	}
+}
+
+func TestUnparseableBody(t *testing.T) {
+	r := httptest.NewRequest("POST", "/?page=2", strings.NewReader("--garbage"))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=missing")
+
+	if got := form.Int(r, "page", 1); got != 1 {
+		t.Errorf("Int() = %v, want default for an unparseable body", got)
+	}
+	if got := form.Values(r, form.DefaultMaxMemory); got == nil {
+		t.Error("Values() = nil, want empty values")
+	}
+
+	r = httptest.NewRequest("GET", "/?page=2", nil)
+	if got := form.Int(r, "page", 1); got != 1 {
+		t.Errorf("Int() = %v, want default for a request without a body", got)
+	}
+}
+
+func TestBind(t *testing.T) {
+	var signup struct {
+		Name  string `query:"name"`
+		Age   int    `query:"age"`
+		Terms bool   `query:"terms"`
+	}
+
+	r := newFormRequest("/signup", "name=Alice&age=30&terms=on")
+	if err := form.StrictBind(r, &signup); err != nil {
+		t.Fatalf("StrictBind() error = %v", err)
+	}
+	if signup.Name != "Alice" || signup.Age != 30 || !signup.Terms {
+		t.Errorf("StrictBind() = %+v", signup)
+	}
+
+	r = newFormRequest("/signup", "name=Alice&admin=1")
+	if err := form.StrictBind(r, &signup); err == nil {
+		t.Error("StrictBind() error = nil, want unknown field error")
+	}
+}
+
+func TestOrFail(t *testing.T) {
+	r := newFormRequest("/", "qty=abc")
+	w := httptest.NewRecorder()
+
+	if _, ok := form.IntOrFail(w, r, "qty"); ok {
+		t.Fatal("IntOrFail() ok = true, want false")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("IntOrFail() status = %d, want 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	if got, ok := form.ValueOrFail(w, newFormRequest("/", "qty=3"), "qty", strconv.Atoi); !ok || got != 3 {
+		t.Errorf("ValueOrFail() = %v, %v, want 3, true", got, ok)
+	}
+}
+
+func TestNewExtractor(t *testing.T) {
+	f := form.NewExtractor(query.Config{TrimSpace: true, CaseInsensitiveKeys: true}, 1<<20)
+	r := newFormRequest("/", "Qty=+7+")
+
+	if got := f.Int(r, "qty", 0); got != 7 {
+		t.Errorf("Extractor.Int() = %v, want 7", got)
+	}
+}
@@ -0,0 +1,291 @@
+package headers
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidAuth is returned for an Authorization or WWW-Authenticate value
+// that does not follow the syntax of RFC 9110, section 11.
+var ErrInvalidAuth = errors.New("headers: invalid authentication header")
+
+// Credentials is a parsed Authorization or Proxy-Authorization value: a scheme
+// followed by either a token68, such as a bearer token or Basic's base64
+// string, or a list of auth parameters.
+type Credentials struct {
+	Scheme string            // Scheme as sent; compare it with strings.EqualFold
+	Token  string            // The token68, "" if the credentials use parameters
+	Params map[string]string // Auth parameters by lower-cased name, nil if none
+}
+
+// ParseAuthorization parses an Authorization or Proxy-Authorization value
+// such as "Bearer mF_9.B5f-4.1JqM" or `Digest username="Mufasa", realm="x"`.
+// Quoted parameter values are unquoted. Returns ErrInvalidAuth if value is
+// not a single well-formed credential.
+func ParseAuthorization(value string) (Credentials, error) {
+	items, err := parseAuthList(value)
+	if err != nil || len(items) != 1 {
+		return Credentials{}, ErrInvalidAuth
+	}
+	return Credentials(items[0]), nil
+}
+
+// BearerToken returns the token of a request's "Authorization: Bearer ..."
+// header, as defined by RFC 6750. ok is false if the header is missing, uses
+// another scheme, or is malformed.
+//
+// Example:
+//
+//	token, ok := headers.BearerToken(r)
+//	if !ok {
+//	    w.Header().Set(headers.WWWAuthenticate, `Bearer realm="api"`)
+//	    w.WriteHeader(http.StatusUnauthorized)
+//	    return
+//	}
+func BearerToken(r *http.Request) (token string, ok bool) {
+	c, err := ParseAuthorization(r.Header.Get(Authorization))
+	if err != nil || !strings.EqualFold(c.Scheme, "Bearer") || c.Token == "" {
+		return "", false
+	}
+	return c.Token, true
+}
+
+// BasicAuthDecode returns the user ID and password of a request's
+// "Authorization: Basic ..." header, as defined by RFC 7617. Unlike
+// http.Request.BasicAuth it rejects credentials that are not valid UTF-8 or
+// contain control characters. ok is false if the header is missing, uses
+// another scheme, or is malformed.
+func BasicAuthDecode(r *http.Request) (user, password string, ok bool) {
+	c, err := ParseAuthorization(r.Header.Get(Authorization))
+	if err != nil || !strings.EqualFold(c.Scheme, "Basic") || c.Token == "" {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(c.Token)
+	if err != nil || !utf8.Valid(decoded) || strings.ContainsFunc(string(decoded), isControl) {
+		return "", "", false
+	}
+	user, password, ok = strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+	return user, password, true
+}
+
+// SecureCompare reports whether a and b are equal in time that depends on
+// neither their contents nor their lengths, for comparing secrets such as
+// tokens or passwords.
+func SecureCompare(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// CheckBasicAuth reports whether the request carries Basic credentials equal
+// to user and password. Both are compared with SecureCompare, and both
+// comparisons always run, so timing reveals neither which part was wrong nor
+// how much of it matched.
+//
+// Example:
+//
+//	if !headers.CheckBasicAuth(r, "admin", adminPassword) {
+//	    w.Header().Set(headers.WWWAuthenticate, `Basic realm="admin", charset="UTF-8"`)
+//	    w.WriteHeader(http.StatusUnauthorized)
+//	    return
+//	}
+func CheckBasicAuth(r *http.Request, user, password string) bool {
+	gotUser, gotPassword, ok := BasicAuthDecode(r)
+	userOK := SecureCompare(gotUser, user)
+	passwordOK := SecureCompare(gotPassword, password)
+	return ok && userOK && passwordOK
+}
+
+func isControl(c rune) bool {
+	return c < 0x20 || c == 0x7f
+}
+
+// authItem is a scheme with its token68 or parameters, the shape shared by
+// credentials and challenges.
+type authItem struct {
+	Scheme string
+	Token  string
+	Params map[string]string
+}
+
+// parseAuthList parses a comma-separated list of auth items, as found in
+// WWW-Authenticate. Credentials are a list of exactly one.
+func parseAuthList(s string) ([]authItem, error) {
+	var items []authItem
+	p := authParser{s: s}
+	for {
+		p.skipListSeparators()
+		if p.done() {
+			break
+		}
+		scheme := p.token()
+		if scheme == "" {
+			return nil, ErrInvalidAuth
+		}
+		item := authItem{Scheme: scheme}
+
+		if p.spaces() > 0 && !p.done() && p.peek() != ',' {
+			if tok, ok := p.token68(); ok {
+				item.Token = tok
+			} else if err := p.params(&item); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+
+		p.ows()
+		if !p.done() && p.peek() != ',' {
+			return nil, ErrInvalidAuth
+		}
+	}
+	if len(items) == 0 {
+		return nil, ErrInvalidAuth
+	}
+	return items, nil
+}
+
+// authParser is a cursor over an authentication header value.
+type authParser struct {
+	s string
+	i int
+}
+
+func (p *authParser) done() bool { return p.i >= len(p.s) }
+func (p *authParser) peek() byte { return p.s[p.i] }
+
+func (p *authParser) ows() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.i++
+	}
+}
+
+func (p *authParser) spaces() int {
+	start := p.i
+	p.ows()
+	return p.i - start
+}
+
+func (p *authParser) skipListSeparators() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == ',') {
+		p.i++
+	}
+}
+
+func (p *authParser) token() string {
+	start := p.i
+	for !p.done() && isTokenChar(p.peek()) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// token68 consumes a token68 if one is next and is followed by the end of
+// the item, leaving the cursor unchanged otherwise.
+func (p *authParser) token68() (string, bool) {
+	start := p.i
+	for !p.done() && isToken68Char(p.peek()) {
+		p.i++
+	}
+	if p.i == start {
+		return "", false
+	}
+	for !p.done() && p.peek() == '=' {
+		p.i++
+	}
+	tok := p.s[start:p.i]
+	p.ows()
+	if p.done() || p.peek() == ',' {
+		return tok, true
+	}
+	p.i = start
+	return "", false
+}
+
+// params consumes a list of auth parameters, stopping before a comma that
+// starts the next challenge.
+func (p *authParser) params(item *authItem) error {
+	item.Params = make(map[string]string)
+	for {
+		name := p.token()
+		p.ows()
+		if name == "" || p.done() || p.peek() != '=' {
+			return ErrInvalidAuth
+		}
+		p.i++
+		p.ows()
+
+		var value string
+		if !p.done() && p.peek() == '"' {
+			v, ok := p.quoted()
+			if !ok {
+				return ErrInvalidAuth
+			}
+			value = v
+		} else if value = p.token(); value == "" {
+			return ErrInvalidAuth
+		}
+		name = strings.ToLower(name)
+		if _, dup := item.Params[name]; dup {
+			return ErrInvalidAuth
+		}
+		item.Params[name] = value
+
+		// Another parameter follows if the next list element is name=...
+		save := p.i
+		p.ows()
+		if p.done() || p.peek() != ',' {
+			p.i = save
+			return nil
+		}
+		p.skipListSeparators()
+		next := p.i
+		if p.token() != "" {
+			p.ows()
+			if !p.done() && p.peek() == '=' {
+				p.i = next
+				continue
+			}
+		}
+		p.i = save
+		return nil
+	}
+}
+
+func (p *authParser) quoted() (string, bool) {
+	var b strings.Builder
+	for p.i++; !p.done(); p.i++ {
+		switch c := p.peek(); c {
+		case '"':
+			p.i++
+			return b.String(), true
+		case '\\':
+			p.i++
+			if p.done() {
+				return "", false
+			}
+			b.WriteByte(p.peek())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+func isTokenChar(c byte) bool {
+	return c > ' ' && c < 0x7f && strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) < 0
+}
+
+func isToken68Char(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~+/", c) >= 0
+}
@@ -0,0 +1,121 @@
+package headers_test
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParseAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  headers.Credentials
+		wantError bool
+	}{
+		{"bearer", "Bearer mF_9.B5f-4.1JqM", headers.Credentials{Scheme: "Bearer", Token: "mF_9.B5f-4.1JqM"}, false},
+		{"basic padding", "basic dXNlcjpwYXNz==", headers.Credentials{Scheme: "basic", Token: "dXNlcjpwYXNz=="}, false},
+		{"scheme only", "Negotiate", headers.Credentials{Scheme: "Negotiate"}, false},
+		{"params", `Digest username="Mufasa", realm="a, b", nc=00000001`, headers.Credentials{
+			Scheme: "Digest",
+			Params: map[string]string{"username": "Mufasa", "realm": "a, b", "nc": "00000001"},
+		}, false},
+		{"escaped quote", `Custom Key = "a \"b\""`, headers.Credentials{
+			Scheme: "Custom", Params: map[string]string{"key": `a "b"`},
+		}, false},
+		{"empty", "", headers.Credentials{}, true},
+		{"two credentials", "Bearer a, Basic b", headers.Credentials{}, true},
+		{"bad token", "Bearer a b", headers.Credentials{}, true},
+		{"unterminated", `Digest realm="x`, headers.Credentials{}, true},
+		{"duplicate param", "Digest a=1, a=2", headers.Credentials{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseAuthorization(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseAuthorization() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseAuthorization() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+		ok     bool
+	}{
+		{"Bearer abc.def", "abc.def", true},
+		{"bearer abc", "abc", true},
+		{"Basic abc", "", false},
+		{"Bearer", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", tt.header)
+			if token, ok := headers.BearerToken(r); token != tt.token || ok != tt.ok {
+				t.Errorf("BearerToken() = %q, %v, want %q, %v", token, ok, tt.token, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBasicAuthDecode(t *testing.T) {
+	encode := func(s string) string { return "Basic " + base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name     string
+		header   string
+		user     string
+		password string
+		ok       bool
+	}{
+		{"valid", encode("aladdin:open:sesame"), "aladdin", "open:sesame", true},
+		{"utf-8", encode("jürgen:pässwörd"), "jürgen", "pässwörd", true},
+		{"empty password", encode("user:"), "user", "", true},
+		{"no colon", encode("user"), "", "", false},
+		{"control character", encode("us\ner:x"), "", "", false},
+		{"invalid utf-8", encode("\xff:x"), "", "", false},
+		{"invalid base64", "Basic !!!", "", "", false},
+		{"bearer", "Bearer abc", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", tt.header)
+			user, password, ok := headers.BasicAuthDecode(r)
+			if user != tt.user || password != tt.password || ok != tt.ok {
+				t.Errorf("BasicAuthDecode() = %q, %q, %v, want %q, %q, %v", user, password, ok, tt.user, tt.password, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSecureCompare(t *testing.T) {
+	if !headers.SecureCompare("secret", "secret") || headers.SecureCompare("secret", "secreT") || headers.SecureCompare("a", "ab") {
+		t.Error("SecureCompare() returned a wrong result")
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("admin", "hunter2")
+	if !headers.CheckBasicAuth(r, "admin", "hunter2") {
+		t.Error("CheckBasicAuth() = false for matching credentials")
+	}
+	if headers.CheckBasicAuth(r, "admin", "hunter3") || headers.CheckBasicAuth(r, "root", "hunter2") {
+		t.Error("CheckBasicAuth() = true for wrong credentials")
+	}
+	if headers.CheckBasicAuth(httptest.NewRequest("GET", "/", nil), "", "") {
+		t.Error("CheckBasicAuth() = true without credentials")
+	}
+}
@@ -0,0 +1,140 @@
+package headers
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Error codes for the error parameter of a Bearer challenge, from RFC 6750,
+// section 3.1.
+const (
+	BearerInvalidRequest    = "invalid_request"
+	BearerInvalidToken      = "invalid_token"
+	BearerInsufficientScope = "insufficient_scope"
+)
+
+// Challenge is one authentication challenge from a WWW-Authenticate or
+// Proxy-Authenticate header: a scheme followed by either a token68 or a list
+// of auth parameters.
+type Challenge struct {
+	Scheme string            // Scheme as sent; compare it with strings.EqualFold
+	Token  string            // The token68, "" if the challenge uses parameters
+	Params map[string]string // Auth parameters by lower-cased name, nil if none
+}
+
+// BasicChallenge returns a Basic challenge for realm that asks for UTF-8
+// credentials, as described in RFC 7617, section 2.1.
+func BasicChallenge(realm string) Challenge {
+	return Challenge{Scheme: "Basic", Params: map[string]string{"realm": realm, "charset": "UTF-8"}}
+}
+
+// BearerChallenge returns a Bearer challenge for realm. Add the error,
+// error_description and scope parameters with With when the request carried a
+// token that was rejected.
+func BearerChallenge(realm string) Challenge {
+	return Challenge{Scheme: "Bearer", Params: map[string]string{"realm": realm}}
+}
+
+// Realm returns the realm parameter, or "" if there is none.
+func (c Challenge) Realm() string {
+	return c.Params["realm"]
+}
+
+// With returns a copy of c with the parameter name set to value.
+func (c Challenge) With(name, value string) Challenge {
+	params := make(map[string]string, len(c.Params)+1)
+	maps.Copy(params, c.Params)
+	params[strings.ToLower(name)] = value
+	c.Params = params
+	return c
+}
+
+// String formats c for a WWW-Authenticate header. The token68 is written if
+// set, and the parameters otherwise, realm first and the rest sorted by name.
+// Values are always quoted, with control characters replaced by "_":
+//
+//	headers.BearerChallenge("api").With("error", headers.BearerInvalidToken).String()
+//	// Bearer realm="api", error="invalid_token"
+func (c Challenge) String() string {
+	if c.Token != "" {
+		return c.Scheme + " " + c.Token
+	}
+	if len(c.Params) == 0 {
+		return c.Scheme
+	}
+
+	names := slices.Sorted(maps.Keys(c.Params))
+	if i := slices.Index(names, "realm"); i > 0 {
+		copy(names[1:i+1], names[:i])
+		names[0] = "realm"
+	}
+
+	var b strings.Builder
+	b.WriteString(c.Scheme)
+	for i, name := range names {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		writeQuotedContent(&b, c.Params[name])
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+func writeQuotedContent(b *strings.Builder, s string) {
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case isControl(c) && c != '\t':
+			b.WriteByte('_')
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+// FormatChallenges joins challenges into a single WWW-Authenticate value.
+// Servers may offer several schemes at once, and clients pick the one they
+// support:
+//
+//	w.Header().Set(headers.WWWAuthenticate, headers.FormatChallenges(
+//	    headers.BearerChallenge("api"),
+//	    headers.BasicChallenge("api"),
+//	))
+//	w.WriteHeader(http.StatusUnauthorized)
+func FormatChallenges(challenges ...Challenge) string {
+	parts := make([]string, len(challenges))
+	for i, c := range challenges {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseChallenges parses a WWW-Authenticate or Proxy-Authenticate value into
+// its challenges, in order. The commas separating challenges and those
+// separating one challenge's parameters are told apart as RFC 9110, section
+// 11.6.1 requires. A response may also send several header lines; join them
+// with ", " first:
+//
+//	value := strings.Join(resp.Header.Values(headers.WWWAuthenticate), ", ")
+//	challenges, err := headers.ParseChallenges(value)
+//
+// Returns ErrInvalidAuth if value is empty or malformed.
+func ParseChallenges(value string) ([]Challenge, error) {
+	items, err := parseAuthList(value)
+	if err != nil {
+		return nil, err
+	}
+	challenges := make([]Challenge, len(items))
+	for i, item := range items {
+		challenges[i] = Challenge(item)
+	}
+	return challenges, nil
+}
@@ -0,0 +1,130 @@
+package headers_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestChallengeString(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge headers.Challenge
+		expected  string
+	}{
+		{"basic", headers.BasicChallenge("admin"), `Basic realm="admin", charset="UTF-8"`},
+		{"bearer", headers.BearerChallenge("api"), `Bearer realm="api"`},
+		{
+			"bearer error",
+			headers.BearerChallenge("api").
+				With("error", headers.BearerInvalidToken).
+				With("Error_Description", "The access token expired"),
+			`Bearer realm="api", error="invalid_token", error_description="The access token expired"`,
+		},
+		{"escaped", headers.BearerChallenge(`a "b" \c` + "\r\n"), `Bearer realm="a \"b\" \\c__"`},
+		{"token68", headers.Challenge{Scheme: "Negotiate", Token: "YIIB=="}, "Negotiate YIIB=="},
+		{"scheme only", headers.Challenge{Scheme: "Negotiate"}, "Negotiate"},
+		{"no realm", headers.Challenge{Scheme: "Custom", Params: map[string]string{"b": "2", "a": "1"}}, `Custom a="1", b="2"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.challenge.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChallengeWithCopies(t *testing.T) {
+	base := headers.BearerChallenge("api")
+	_ = base.With("error", headers.BearerInvalidRequest)
+	if _, ok := base.Params["error"]; ok {
+		t.Error("With() modified the original challenge")
+	}
+}
+
+func TestFormatChallenges(t *testing.T) {
+	got := headers.FormatChallenges(headers.BearerChallenge("api"), headers.BasicChallenge("api"))
+	expected := `Bearer realm="api", Basic realm="api", charset="UTF-8"`
+	if got != expected {
+		t.Errorf("FormatChallenges() = %q, want %q", got, expected)
+	}
+	if got := headers.FormatChallenges(); got != "" {
+		t.Errorf("FormatChallenges() = %q, want empty", got)
+	}
+}
+
+func TestParseChallenges(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  []headers.Challenge
+		wantError bool
+	}{
+		{
+			name:  "several challenges",
+			value: `Newauth realm="apps", type=1, title="Login to \"apps\"", Basic realm="simple"`,
+			expected: []headers.Challenge{
+				{Scheme: "Newauth", Params: map[string]string{"realm": "apps", "type": "1", "title": `Login to "apps"`}},
+				{Scheme: "Basic", Params: map[string]string{"realm": "simple"}},
+			},
+		},
+		{
+			name:  "bearer error",
+			value: `Bearer realm="example", error="invalid_token", error_description="The access token expired"`,
+			expected: []headers.Challenge{{Scheme: "Bearer", Params: map[string]string{
+				"realm": "example", "error": "invalid_token", "error_description": "The access token expired",
+			}}},
+		},
+		{
+			name:  "token68 and bare schemes",
+			value: "Negotiate, NTLM abc=, Basic realm=x",
+			expected: []headers.Challenge{
+				{Scheme: "Negotiate"},
+				{Scheme: "NTLM", Token: "abc="},
+				{Scheme: "Basic", Params: map[string]string{"realm": "x"}},
+			},
+		},
+		{
+			name:  "empty list elements",
+			value: ` , Basic realm="a, b" ,, `,
+			expected: []headers.Challenge{
+				{Scheme: "Basic", Params: map[string]string{"realm": "a, b"}},
+			},
+		},
+		{name: "empty", value: "", wantError: true},
+		{name: "missing name", value: "Basic a=1, =2", wantError: true},
+		{name: "bad separator", value: `Basic realm="a" charset="b"`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseChallenges(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseChallenges() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseChallenges() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseChallengesRoundTrip(t *testing.T) {
+	challenges := []headers.Challenge{
+		headers.BearerChallenge(`a "quoted", realm`).With("scope", "read write"),
+		headers.BasicChallenge("x"),
+	}
+	got, err := headers.ParseChallenges(headers.FormatChallenges(challenges...))
+	if err != nil {
+		t.Fatalf("ParseChallenges() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, challenges) {
+		t.Errorf("ParseChallenges() = %+v, want %+v", got, challenges)
+	}
+	if got[0].Realm() != `a "quoted", realm` {
+		t.Errorf("Realm() = %q", got[0].Realm())
+	}
+}
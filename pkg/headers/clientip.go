@@ -0,0 +1,150 @@
+package headers
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustPolicy decides how far ClientIP believes the forwarding headers of a
+// request. The zero value trusts nothing, so ClientIP returns the address of
+// the peer.
+type TrustPolicy struct {
+	// Proxies lists the networks of trusted proxies. Walking back from the
+	// peer, ClientIP returns the first address outside these networks.
+	Proxies []netip.Prefix
+
+	// Hops, if positive, is the fixed number of proxies in front of the
+	// application, and replaces Proxies: ClientIP returns the address that
+	// many hops back from the application, whatever the proxies' addresses.
+	Hops int
+
+	// Header is the forwarding header the proxies set: Forwarded,
+	// X-Forwarded-For or X-Real-IP, X-Forwarded-For if empty. It must be one
+	// the proxies overwrite or append to, as a client can send any header
+	// and a proxy passes the others through untouched: behind a proxy that
+	// appends to X-Forwarded-For, a client-sent Forwarded header would name
+	// whatever address the client likes. Any other header, such as one
+	// specific to a CDN, is read as a comma-separated list like
+	// X-Forwarded-For.
+	Header string
+}
+
+// TrustProxies returns a TrustPolicy trusting the given networks, each a CIDR
+// prefix such as "10.0.0.0/8" or a single address. Returns the parse error of
+// the first invalid entry.
+func TrustProxies(cidrs ...string) (TrustPolicy, error) {
+	var policy TrustPolicy
+	for _, s := range cidrs {
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return TrustPolicy{}, err
+			}
+			addr = addr.Unmap()
+			policy.Proxies = append(policy.Proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return TrustPolicy{}, err
+		}
+		policy.Proxies = append(policy.Proxies, prefix.Masked())
+	}
+	return policy, nil
+}
+
+func (t TrustPolicy) trusts(addr netip.Addr) bool {
+	for _, prefix := range t.Proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that made the request, reading
+// forwarding headers only as far as trust allows. Forwarding headers are
+// plain request headers that any client can set, so trusting them blindly
+// lets clients spoof their address; ClientIP walks the chain of addresses
+// back from the peer and stops at the first one it cannot vouch for.
+//
+// If an entry in the chain is "unknown", obfuscated or malformed, the address
+// of the trusted proxy that reported it is returned. The result is the zero
+// netip.Addr only if r.RemoteAddr is not an IP address.
+//
+// Example:
+//
+//	// Behind a load balancer on 10.0.0.0/8 that sets X-Forwarded-For
+//	trust, _ := headers.TrustProxies("10.0.0.0/8")
+//	trust.Header = headers.XForwardedFor
+//
+//	ip := headers.ClientIP(r, trust)
+func ClientIP(r *http.Request, trust TrustPolicy) netip.Addr {
+	peer, ok := parseNode(r.RemoteAddr)
+	if !ok {
+		return netip.Addr{}
+	}
+	if trust.Hops <= 0 && !trust.trusts(peer) {
+		return peer
+	}
+	chain, ok := forwardingChain(r, trust.Header)
+	if !ok {
+		return peer
+	}
+
+	if trust.Hops > 0 {
+		addr := peer
+		for i := len(chain) - 1; i >= max(len(chain)-trust.Hops, 0); i-- {
+			next, ok := parseNode(chain[i])
+			if !ok {
+				break
+			}
+			addr = next
+		}
+		return addr
+	}
+
+	addr := peer
+	for i := len(chain) - 1; i >= 0 && trust.trusts(addr); i-- {
+		next, ok := parseNode(chain[i])
+		if !ok {
+			break
+		}
+		addr = next
+	}
+	return addr
+}
+
+// forwardingChain returns the node identifiers from the named forwarding
+// header, X-Forwarded-For if name is "", client first. ok is false if the
+// header is missing or malformed.
+func forwardingChain(r *http.Request, name string) (chain []string, ok bool) {
+	if name == "" {
+		name = XForwardedFor
+	}
+
+	values := r.Header.Values(name)
+	if len(values) == 0 {
+		return nil, false
+	}
+	switch http.CanonicalHeaderKey(name) {
+	case http.CanonicalHeaderKey(Forwarded):
+		elems, err := ParseForwarded(strings.Join(values, ", "))
+		if err != nil {
+			return nil, false
+		}
+		for _, e := range elems {
+			chain = append(chain, e.For)
+		}
+	case http.CanonicalHeaderKey(XRealIP):
+		chain = []string{strings.TrimSpace(values[0])}
+	default:
+		for _, v := range values {
+			for node := range strings.SplitSeq(v, ",") {
+				chain = append(chain, strings.TrimSpace(node))
+			}
+		}
+	}
+	return chain, true
+}
@@ -0,0 +1,188 @@
+package headers_test
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := headers.TrustProxies("10.0.0.0/8", "2001:db8::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xff := proxies
+	xff.Header = headers.XForwardedFor
+	forwarded := proxies
+	forwarded.Header = headers.Forwarded
+	realIP := proxies
+	realIP.Header = headers.XRealIP
+
+	tests := []struct {
+		name     string
+		remote   string
+		header   map[string]string
+		trust    headers.TrustPolicy
+		expected string
+	}{
+		{
+			name:     "zero policy ignores headers",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.XForwardedFor: "198.51.100.7"},
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "untrusted peer",
+			remote:   "203.0.113.9:1234",
+			header:   map[string]string{headers.XForwardedFor: "198.51.100.7"},
+			trust:    xff,
+			expected: "203.0.113.9",
+		},
+		{
+			name:     "spoofed entry skipped",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.XForwardedFor: "1.2.3.4, 198.51.100.7, 10.0.0.2"},
+			trust:    xff,
+			expected: "198.51.100.7",
+		},
+		{
+			name:     "all trusted",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.XForwardedFor: "10.0.0.3, 10.0.0.2"},
+			trust:    xff,
+			expected: "10.0.0.3",
+		},
+		{
+			name:     "garbage entry",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.XForwardedFor: "198.51.100.7, garbage, 10.0.0.2"},
+			trust:    xff,
+			expected: "10.0.0.2",
+		},
+		{
+			name:     "ipv6 peer",
+			remote:   "[2001:db8::1]:443",
+			header:   map[string]string{headers.XForwardedFor: "198.51.100.7"},
+			trust:    xff,
+			expected: "198.51.100.7",
+		},
+		{
+			name:   "configured header only",
+			remote: "10.0.0.1:1234",
+			header: map[string]string{
+				headers.Forwarded:     "for=1.2.3.4",
+				headers.XForwardedFor: "198.51.100.7",
+			},
+			trust:    xff,
+			expected: "198.51.100.7",
+		},
+		{
+			name:   "client-sent forwarded ignored by default",
+			remote: "10.0.0.1:1234",
+			header: map[string]string{
+				headers.Forwarded:     "for=1.2.3.4",
+				headers.XForwardedFor: "198.51.100.7",
+			},
+			trust:    proxies,
+			expected: "198.51.100.7",
+		},
+		{
+			name:     "default header missing",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.Forwarded: "for=1.2.3.4"},
+			trust:    proxies,
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "forwarded",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.Forwarded: `for=1.2.3.4, for="[2001:db8::9]:80";proto=https`},
+			trust:    forwarded,
+			expected: "2001:db8::9",
+		},
+		{
+			name:     "forwarded unknown",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.Forwarded: "for=unknown"},
+			trust:    forwarded,
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "malformed forwarded",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.Forwarded: "for=1.2.3.4;;=", headers.XForwardedFor: "198.51.100.7"},
+			trust:    forwarded,
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "x-real-ip",
+			remote:   "10.0.0.1:1234",
+			header:   map[string]string{headers.XRealIP: " 198.51.100.7 "},
+			trust:    realIP,
+			expected: "198.51.100.7",
+		},
+		{
+			name:     "hops",
+			remote:   "203.0.113.9:1234",
+			header:   map[string]string{headers.XForwardedFor: "1.2.3.4, 198.51.100.7, 192.0.2.1"},
+			trust:    headers.TrustPolicy{Hops: 2, Header: headers.XForwardedFor},
+			expected: "198.51.100.7",
+		},
+		{
+			name:     "hops beyond chain",
+			remote:   "203.0.113.9:1234",
+			header:   map[string]string{headers.XForwardedFor: "198.51.100.7"},
+			trust:    headers.TrustPolicy{Hops: 3},
+			expected: "198.51.100.7",
+		},
+		{
+			name:     "no header",
+			remote:   "10.0.0.1:1234",
+			trust:    proxies,
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "bad remote",
+			remote:   "@",
+			trust:    proxies,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for name, value := range tt.header {
+				r.Header.Set(name, value)
+			}
+
+			var expected netip.Addr
+			if tt.expected != "" {
+				expected = netip.MustParseAddr(tt.expected)
+			}
+			if got := headers.ClientIP(r, tt.trust); got != expected {
+				t.Errorf("ClientIP() = %v, want %v", got, expected)
+			}
+		})
+	}
+}
+
+func TestTrustProxies(t *testing.T) {
+	policy, err := headers.TrustProxies("10.1.2.3/8", "::ffff:192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.1/32")}
+	if len(policy.Proxies) != 2 || policy.Proxies[0] != expected[0] || policy.Proxies[1] != expected[1] {
+		t.Errorf("TrustProxies() = %v, want %v", policy.Proxies, expected)
+	}
+
+	for _, bad := range []string{"10.0.0.0/33", "proxy.internal"} {
+		if _, err := headers.TrustProxies(bad); err == nil {
+			t.Errorf("TrustProxies(%q) error = nil, want error", bad)
+		}
+	}
+}
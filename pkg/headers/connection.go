@@ -0,0 +1,103 @@
+package headers
+
+import (
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConnectionTokens returns the options of the Connection headers of h,
+// lower-cased and without duplicates: "close", "keep-alive", "upgrade", or
+// the names of other hop-by-hop headers the sender added.
+//
+// Example:
+//
+//	// Connection: Keep-Alive, Upgrade
+//	headers.ConnectionTokens(r.Header)  // ["keep-alive", "upgrade"]
+func ConnectionTokens(h http.Header) []string {
+	var tokens []string
+	seen := make(map[string]struct{})
+	for _, v := range h.Values(Connection) {
+		for token := range strings.SplitSeq(v, ",") {
+			token = strings.ToLower(textproto.TrimString(token))
+			if _, dup := seen[token]; token == "" || dup {
+				continue
+			}
+			seen[token] = struct{}{}
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// HasConnectionClose reports whether the Connection headers of h include
+// "close", with which the sender of a request or response announces that it
+// will close the connection after this message.
+func HasConnectionClose(h http.Header) bool {
+	return hasToken(h, Connection, "close")
+}
+
+// WantsClose reports whether the client of r expects the connection to be
+// closed after the response: it sent Connection: close, or it speaks
+// HTTP/1.0 without asking for keep-alive. HTTP/2 and later have no
+// connection options and never ask.
+//
+// net/http servers already close such connections and report it in r.Close;
+// WantsClose is for proxies and middleware that look at requests built
+// elsewhere.
+func WantsClose(r *http.Request) bool {
+	if r.ProtoMajor >= 2 {
+		return false
+	}
+	if HasConnectionClose(r.Header) {
+		return true
+	}
+	return !r.ProtoAtLeast(1, 1) && !hasToken(r.Header, Connection, "keep-alive")
+}
+
+// KeepAliveValue holds the parameters of a Keep-Alive header, which HTTP/1.1
+// peers send next to Connection: keep-alive to say how long they keep an idle
+// connection open and how many more requests they serve on it.
+type KeepAliveValue struct {
+	Timeout time.Duration // Idle timeout, in whole seconds; 0 if not given
+	Max     int           // Requests allowed before closing; 0 if not given
+}
+
+// ParseKeepAlive parses Keep-Alive header values such as "timeout=5,
+// max=1000". Parameter names are case-insensitive, unknown parameters are
+// ignored, and a parameter that is not a non-negative integer is left at 0,
+// so a missing or malformed header yields the zero value.
+func ParseKeepAlive(values ...string) KeepAliveValue {
+	var v KeepAliveValue
+	for _, value := range values {
+		for param := range strings.SplitSeq(value, ",") {
+			name, arg, _ := strings.Cut(param, "=")
+			n, err := strconv.ParseInt(strings.Trim(textproto.TrimString(arg), `"`), 10, 32)
+			if err != nil || n < 0 {
+				continue
+			}
+			switch strings.ToLower(textproto.TrimString(name)) {
+			case "timeout":
+				v.Timeout = time.Duration(n) * time.Second
+			case "max":
+				v.Max = int(n)
+			}
+		}
+	}
+	return v
+}
+
+// String formats v as a Keep-Alive header value, leaving out zero
+// parameters. The timeout is rounded down to whole seconds.
+func (v KeepAliveValue) String() string {
+	var params []string
+	if seconds := int64(v.Timeout / time.Second); seconds > 0 {
+		params = append(params, "timeout="+strconv.FormatInt(seconds, 10))
+	}
+	if v.Max > 0 {
+		params = append(params, "max="+strconv.Itoa(v.Max))
+	}
+	return strings.Join(params, ", ")
+}
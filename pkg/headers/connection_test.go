@@ -0,0 +1,137 @@
+package headers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestConnectionTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []string
+	}{
+		{"single", []string{"close"}, []string{"close"}},
+		{"list", []string{"Keep-Alive, Upgrade"}, []string{"keep-alive", "upgrade"}},
+		{"repeated headers", []string{"keep-alive", "X-Session-Hint"}, []string{"keep-alive", "x-session-hint"}},
+		{"duplicates", []string{"close, CLOSE", "Close"}, []string{"close"}},
+		{"empty elements", []string{" , upgrade ,"}, []string{"upgrade"}},
+		{"missing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{headers.Connection: tt.values}
+			if got := headers.ConnectionTokens(h); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ConnectionTokens() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasConnectionClose(t *testing.T) {
+	tests := []struct {
+		values   []string
+		expected bool
+	}{
+		{[]string{"close"}, true},
+		{[]string{"Upgrade, Close"}, true},
+		{[]string{"keep-alive", "close"}, true},
+		{[]string{"keep-alive"}, false},
+		{[]string{"closed"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		h := http.Header{headers.Connection: tt.values}
+		if got := headers.HasConnectionClose(h); got != tt.expected {
+			t.Errorf("HasConnectionClose(%q) = %v, want %v", tt.values, got, tt.expected)
+		}
+	}
+}
+
+func TestWantsClose(t *testing.T) {
+	tests := []struct {
+		name       string
+		proto      string
+		connection string
+		expected   bool
+	}{
+		{"HTTP/1.1", "HTTP/1.1", "", false},
+		{"HTTP/1.1 close", "HTTP/1.1", "close", true},
+		{"HTTP/1.1 keep-alive", "HTTP/1.1", "keep-alive", false},
+		{"HTTP/1.0", "HTTP/1.0", "", true},
+		{"HTTP/1.0 keep-alive", "HTTP/1.0", "Keep-Alive", false},
+		{"HTTP/1.0 keep-alive and close", "HTTP/1.0", "keep-alive, close", true},
+		{"HTTP/2", "HTTP/2.0", "close", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			var ok bool
+			r.ProtoMajor, r.ProtoMinor, ok = http.ParseHTTPVersion(tt.proto)
+			if !ok {
+				t.Fatalf("ParseHTTPVersion(%q) failed", tt.proto)
+			}
+			r.Proto = tt.proto
+			if tt.connection != "" {
+				r.Header.Set(headers.Connection, tt.connection)
+			}
+			if got := headers.WantsClose(r); got != tt.expected {
+				t.Errorf("WantsClose() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseKeepAlive(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected headers.KeepAliveValue
+	}{
+		{"both", []string{"timeout=5, max=1000"}, headers.KeepAliveValue{Timeout: 5 * time.Second, Max: 1000}},
+		{"timeout only", []string{"timeout=15"}, headers.KeepAliveValue{Timeout: 15 * time.Second}},
+		{"case and spacing", []string{" Max = 10 ,TIMEOUT=2"}, headers.KeepAliveValue{Timeout: 2 * time.Second, Max: 10}},
+		{"quoted", []string{`timeout="5"`}, headers.KeepAliveValue{Timeout: 5 * time.Second}},
+		{"repeated headers", []string{"timeout=5", "max=3"}, headers.KeepAliveValue{Timeout: 5 * time.Second, Max: 3}},
+		{"unknown ignored", []string{"timeout=5, foo=bar, 300"}, headers.KeepAliveValue{Timeout: 5 * time.Second}},
+		{"invalid numbers", []string{"timeout=-1, max=lots"}, headers.KeepAliveValue{}},
+		{"overflow", []string{"max=99999999999"}, headers.KeepAliveValue{}},
+		{"empty", []string{""}, headers.KeepAliveValue{}},
+		{"missing", nil, headers.KeepAliveValue{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headers.ParseKeepAlive(tt.values...); got != tt.expected {
+				t.Errorf("ParseKeepAlive(%q) = %+v, want %+v", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeepAliveValueString(t *testing.T) {
+	tests := []struct {
+		value    headers.KeepAliveValue
+		expected string
+	}{
+		{headers.KeepAliveValue{Timeout: 5 * time.Second, Max: 1000}, "timeout=5, max=1000"},
+		{headers.KeepAliveValue{Timeout: 5500 * time.Millisecond}, "timeout=5"},
+		{headers.KeepAliveValue{Max: 1}, "max=1"},
+		{headers.KeepAliveValue{Timeout: time.Millisecond}, ""},
+		{headers.KeepAliveValue{}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.expected {
+			t.Errorf("%+v.String() = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
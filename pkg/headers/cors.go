@@ -0,0 +1,130 @@
+package headers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsPreflight reports whether r is a CORS preflight request: an OPTIONS
+// request with both an Origin and an Access-Control-Request-Method header.
+// Browsers send one before a cross-origin request that is not "simple", and
+// it must be answered without running the handler of the actual request.
+func IsPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get(Origin) != "" &&
+		r.Header.Get(AccessControlRequestMethod) != ""
+}
+
+// IsCORS reports whether r is a cross-origin request: it has an Origin header
+// naming another host or port than the request's own Host, or the opaque
+// origin "null". Browsers also send Origin on same-origin POST requests, which
+// this tells apart.
+//
+// The scheme of the request is not compared, since TLS is often terminated by
+// a reverse proxy in front of the server; a request whose Host has no port is
+// assumed to use the default port of the Origin's scheme.
+func IsCORS(r *http.Request) bool {
+	origin := r.Header.Get(Origin)
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return true
+	}
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = schemePort(u.Scheme)
+	}
+	requestHost := &url.URL{Host: r.Host}
+	requestPort := requestHost.Port()
+	if requestPort == "" {
+		requestPort = schemePort(u.Scheme)
+	}
+	return !strings.EqualFold(host, requestHost.Hostname()) || port != requestPort
+}
+
+func schemePort(scheme string) string {
+	switch strings.ToLower(scheme) {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	}
+	return ""
+}
+
+// PreflightConfig is the answer to a CORS preflight request, written by
+// WritePreflight.
+type PreflightConfig struct {
+	// AllowOrigin is the origin allowed to make the request, usually the
+	// request's Origin, or "*" for any origin without credentials. Leave it
+	// empty to refuse the request.
+	AllowOrigin string
+
+	// AllowMethods lists the methods allowed, usually the one named by
+	// Access-Control-Request-Method.
+	AllowMethods []string
+
+	// AllowHeaders lists the request headers allowed, usually those named by
+	// Access-Control-Request-Headers.
+	AllowHeaders []string
+
+	// AllowCredentials lets the request carry cookies and HTTP authentication.
+	AllowCredentials bool
+
+	// MaxAge is how long the browser may cache this answer, in whole seconds.
+	// Zero leaves it to the browser.
+	MaxAge time.Duration
+
+	// AllowPrivateNetwork answers a Private Network Access preflight, one that
+	// carries Access-Control-Request-Private-Network: true. Only set it for
+	// those.
+	AllowPrivateNetwork bool
+}
+
+// WritePreflight answers a preflight request with 204 No Content and the
+// Access-Control-Allow-* headers of cfg, leaving out those that are empty. If
+// cfg.AllowOrigin is empty the request is refused: no Access-Control-Allow-*
+// header is written, and the browser blocks the actual request.
+//
+// The response always gets Origin and the Access-Control-Request-* headers
+// added to its Vary header with AddVary, since the answer depends on them.
+//
+// Example:
+//
+//	if headers.IsPreflight(r) {
+//	    cfg := headers.PreflightConfig{MaxAge: time.Hour}
+//	    if allowed(r.Header.Get(headers.Origin)) {
+//	        cfg.AllowOrigin = r.Header.Get(headers.Origin)
+//	        cfg.AllowMethods = []string{r.Header.Get(headers.AccessControlRequestMethod)}
+//	    }
+//	    headers.WritePreflight(w, cfg)
+//	    return
+//	}
+func WritePreflight(w http.ResponseWriter, cfg PreflightConfig) {
+	h := w.Header()
+	AddVary(h, Origin, AccessControlRequestMethod, AccessControlRequestHeaders, AccessControlRequestPrivateNetwork)
+
+	if cfg.AllowOrigin != "" {
+		h.Set(AccessControlAllowOrigin, cfg.AllowOrigin)
+		if cfg.AllowCredentials {
+			h.Set(AccessControlAllowCredentials, "true")
+		}
+		if len(cfg.AllowMethods) > 0 {
+			h.Set(AccessControlAllowMethods, strings.Join(cfg.AllowMethods, ", "))
+		}
+		if len(cfg.AllowHeaders) > 0 {
+			h.Set(AccessControlAllowHeaders, strings.Join(cfg.AllowHeaders, ", "))
+		}
+		if cfg.MaxAge >= time.Second {
+			h.Set(AccessControlMaxAge, strconv.FormatInt(int64(cfg.MaxAge/time.Second), 10))
+		}
+		if cfg.AllowPrivateNetwork {
+			h.Set(AccessControlAllowPrivateNetwork, "true")
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,134 @@
+package headers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestIsPreflight(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		header   http.Header
+		expected bool
+	}{
+		{"preflight", http.MethodOptions, http.Header{"Origin": {"https://a.example"}, "Access-Control-Request-Method": {"PUT"}}, true},
+		{"no request method", http.MethodOptions, http.Header{"Origin": {"https://a.example"}}, false},
+		{"no origin", http.MethodOptions, http.Header{"Access-Control-Request-Method": {"PUT"}}, false},
+		{"not OPTIONS", http.MethodPut, http.Header{"Origin": {"https://a.example"}, "Access-Control-Request-Method": {"PUT"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			r.Header = tt.header
+			if got := headers.IsPreflight(r); got != tt.expected {
+				t.Errorf("IsPreflight() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsCORS(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		origin   string
+		expected bool
+	}{
+		{"no origin", "example.com", "", false},
+		{"same origin", "example.com", "https://example.com", false},
+		{"same origin, case", "Example.COM", "https://example.com", false},
+		{"same origin, explicit port", "example.com:443", "https://example.com", false},
+		{"same origin, behind TLS proxy", "example.com", "http://example.com", false},
+		{"same origin, custom port", "localhost:8080", "http://localhost:8080", false},
+		{"other port", "localhost:8080", "http://localhost:3000", true},
+		{"other host", "api.example.com", "https://app.example.com", true},
+		{"null", "example.com", "null", true},
+		{"IPv6", "[::1]:8080", "http://[::1]:8080", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Host = tt.host
+			if tt.origin != "" {
+				r.Header.Set(headers.Origin, tt.origin)
+			}
+			if got := headers.IsCORS(r); got != tt.expected {
+				t.Errorf("IsCORS() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWritePreflight(t *testing.T) {
+	vary := "Origin, Access-Control-Request-Method, Access-Control-Request-Headers, Access-Control-Request-Private-Network"
+
+	tests := []struct {
+		name     string
+		cfg      headers.PreflightConfig
+		expected map[string]string
+	}{
+		{
+			name: "allowed",
+			cfg: headers.PreflightConfig{
+				AllowOrigin:         "https://a.example",
+				AllowMethods:        []string{"PUT", "DELETE"},
+				AllowHeaders:        []string{"content-type", "authorization"},
+				AllowCredentials:    true,
+				MaxAge:              10*time.Minute + 999*time.Millisecond,
+				AllowPrivateNetwork: true,
+			},
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:         "https://a.example",
+				headers.AccessControlAllowMethods:        "PUT, DELETE",
+				headers.AccessControlAllowHeaders:        "content-type, authorization",
+				headers.AccessControlAllowCredentials:    "true",
+				headers.AccessControlMaxAge:              "600",
+				headers.AccessControlAllowPrivateNetwork: "true",
+				headers.Vary:                             vary,
+			},
+		},
+		{
+			name: "minimal",
+			cfg:  headers.PreflightConfig{AllowOrigin: "*", MaxAge: time.Millisecond},
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:      "*",
+				headers.AccessControlAllowMethods:     "",
+				headers.AccessControlAllowHeaders:     "",
+				headers.AccessControlAllowCredentials: "",
+				headers.AccessControlMaxAge:           "",
+			},
+		},
+		{
+			name: "refused",
+			cfg:  headers.PreflightConfig{AllowMethods: []string{"PUT"}, AllowCredentials: true},
+			expected: map[string]string{
+				headers.AccessControlAllowOrigin:      "",
+				headers.AccessControlAllowMethods:     "",
+				headers.AccessControlAllowCredentials: "",
+				headers.Vary:                          vary,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			headers.WritePreflight(w, tt.cfg)
+			if w.Code != http.StatusNoContent {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			for name, want := range tt.expected {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
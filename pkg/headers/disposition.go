@@ -0,0 +1,129 @@
+package headers
+
+import (
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentDispositionAttachment returns a Content-Disposition value that makes
+// browsers download the response and save it as filename, such as
+// `attachment; filename="report.pdf"`. Non-ASCII names also get an RFC 5987
+// filename* parameter with the exact UTF-8 name, after a plain filename
+// parameter in which every non-ASCII character is replaced by "_" for clients
+// that do not understand it:
+//
+//	headers.ContentDispositionAttachment("Übersicht 2024.pdf")
+//	// attachment; filename="_bersicht 2024.pdf"; filename*=UTF-8''%C3%9Cbersicht%202024.pdf
+//
+// Quotes and backslashes are escaped, and control characters, which could
+// otherwise split the header, are replaced by "_". An empty filename returns
+// "attachment".
+func ContentDispositionAttachment(filename string) string {
+	return formatDisposition("attachment", filename)
+}
+
+// ContentDispositionInline is like ContentDispositionAttachment for a response
+// the browser should display, suggesting filename for when the user saves it.
+func ContentDispositionInline(filename string) string {
+	return formatDisposition("inline", filename)
+}
+
+func formatDisposition(dispType, filename string) string {
+	if filename == "" {
+		return dispType
+	}
+	filename = strings.ToValidUTF8(filename, "�")
+
+	var fallback strings.Builder
+	ascii := true
+	for _, c := range filename {
+		switch {
+		case c < 0x20 || c == 0x7f:
+			fallback.WriteByte('_')
+		case c >= utf8.RuneSelf:
+			fallback.WriteByte('_')
+			ascii = false
+		case c == '"' || c == '\\':
+			fallback.WriteByte('\\')
+			fallback.WriteRune(c)
+		default:
+			fallback.WriteRune(c)
+		}
+	}
+
+	value := dispType + `; filename="` + fallback.String() + `"`
+	if !ascii {
+		value += "; filename*=UTF-8''" + encodeExtValue(filename)
+	}
+	return value
+}
+
+// encodeExtValue percent-encodes s for an RFC 5987 ext-value, leaving only
+// attr-char unencoded.
+func encodeExtValue(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
+
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
+
+// Disposition is a parsed Content-Disposition value.
+type Disposition struct {
+	Type     string            // Lower-cased disposition type, such as "attachment" or "form-data"
+	Name     string            // The name parameter, the form field of a multipart part
+	Filename string            // The file name without any directory, "" if none was sent
+	Params   map[string]string // All parameters by lower-cased name, with filename* decoded into filename
+}
+
+// ParseContentDisposition parses a Content-Disposition value, as sent for the
+// parts of a multipart upload or received in a download. A UTF-8 filename*
+// parameter takes precedence over filename.
+//
+// Browsers may send a full client path as the file name, so Filename keeps
+// only the part after the last "/" or "\"; "." and ".." become "". The name is
+// still client input: check it before using it to build a path.
+//
+// Example:
+//
+//	cd, err := headers.ParseContentDisposition(part.Header.Get(headers.ContentDisposition))
+//	if err == nil && cd.Filename != "" {
+//	    // save the upload under a name derived from cd.Filename
+//	}
+func ParseContentDisposition(value string) (Disposition, error) {
+	dispType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return Disposition{}, err
+	}
+
+	filename := params["filename"]
+	if i := strings.LastIndexAny(filename, `/\`); i >= 0 {
+		filename = filename[i+1:]
+	}
+	if filename == "." || filename == ".." {
+		filename = ""
+	}
+	return Disposition{
+		Type:     dispType,
+		Name:     params["name"],
+		Filename: filename,
+		Params:   params,
+	}, nil
+}
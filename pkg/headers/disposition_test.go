@@ -0,0 +1,74 @@
+package headers_test
+
+import (
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestContentDispositionAttachment(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"", "attachment"},
+		{"report.pdf", `attachment; filename="report.pdf"`},
+		{`say "hi"\.txt`, `attachment; filename="say \"hi\"\\.txt"`},
+		{"a\r\nSet-Cookie: x", `attachment; filename="a__Set-Cookie: x"`},
+		{"Übersicht 2024.pdf", `attachment; filename="_bersicht 2024.pdf"; filename*=UTF-8''%C3%9Cbersicht%202024.pdf`},
+		{"日本.txt", `attachment; filename="__.txt"; filename*=UTF-8''%E6%97%A5%E6%9C%AC.txt`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := headers.ContentDispositionAttachment(tt.filename); got != tt.expected {
+				t.Errorf("ContentDispositionAttachment() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := headers.ContentDispositionInline("a.png"); got != `inline; filename="a.png"` {
+		t.Errorf("ContentDispositionInline() = %q", got)
+	}
+}
+
+func TestParseContentDisposition(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		dispType  string
+		field     string
+		filename  string
+		wantError bool
+	}{
+		{"form field", `form-data; name="avatar"; filename="me.png"`, "form-data", "avatar", "me.png", false},
+		{"extended wins", `attachment; filename="a.txt"; filename*=UTF-8''%C3%A9t%C3%A9.txt`, "attachment", "", "été.txt", false},
+		{"windows path", `form-data; name="f"; filename="C:\\Users\\x\\doc.txt"`, "form-data", "f", "doc.txt", false},
+		{"unix path", `attachment; filename="../../etc/passwd"`, "attachment", "", "passwd", false},
+		{"dot dot", `attachment; filename=".."`, "attachment", "", "", false},
+		{"no filename", `INLINE`, "inline", "", "", false},
+		{"malformed", `attachment; filename="unterminated`, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseContentDisposition(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseContentDisposition() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got.Type != tt.dispType || got.Name != tt.field || got.Filename != tt.filename {
+				t.Errorf("ParseContentDisposition() = %+v, want type %q name %q filename %q",
+					got, tt.dispType, tt.field, tt.filename)
+			}
+		})
+	}
+}
+
+func TestContentDispositionRoundTrip(t *testing.T) {
+	for _, name := range []string{"plain.txt", `q"uote.txt`, "Übersicht 2024.pdf", "emoji 🎉.png"} {
+		got, err := headers.ParseContentDisposition(headers.ContentDispositionAttachment(name))
+		if err != nil || got.Filename != name {
+			t.Errorf("round trip of %q = %q, %v", name, got.Filename, err)
+		}
+	}
+}
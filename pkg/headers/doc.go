@@ -52,6 +52,250 @@
 //   - Security: Security-related headers (CSP, HSTS, XFO, etc.)
 //   - WS: WebSocket headers (Sec-WebSocket-Key, Sec-WebSocket-Accept, etc.)
 //
+// # Header Metadata
+//
+// Every header constant is also described in a catalog, for gateways, linters
+// and other code that reasons about headers as data. Lookup finds a header by
+// name, case-insensitively, and reports its category, whether it is sent in
+// requests, responses or both, whether it is deprecated, and its defining
+// specification:
+//
+//	h, ok := headers.Lookup("content-type")
+//	// h.Name == "Content-Type", h.Usage == headers.UsageBoth,
+//	// h.Spec == "RFC 9110, Section 8.3"
+//
+//	for h := range headers.ByCategory(headers.CategorySecurity) {
+//	    // ...
+//	}
+//
+// All iterates over the whole catalog.
+//
+// # Range Requests
+//
+// ParseRange validates a Range header against the content size, handling
+// suffix, open-ended and multiple ranges, and tells an unsatisfiable request
+// (416) apart from one that must be ignored:
+//
+//	ranges, err := headers.ParseRange(r.Header.Get(headers.Range), size)
+//	if errors.Is(err, headers.ErrUnsatisfiableRange) {
+//	    w.Header().Set(headers.ContentRange, headers.FormatUnsatisfiedRange(size))
+//	    w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+//	    return
+//	}
+//	if err == nil && len(ranges) == 1 {
+//	    w.Header().Set(headers.ContentRange, ranges[0].ContentRange(size))
+//	    // ...
+//	}
+//
+// # Content-Disposition
+//
+// ContentDispositionAttachment and ContentDispositionInline build values with a
+// safely quoted filename and, for non-ASCII names, an RFC 5987 filename*:
+//
+//	w.Header().Set(headers.ContentDisposition, headers.ContentDispositionAttachment("Übersicht.pdf"))
+//
+// ParseContentDisposition reads them back, for instance from multipart
+// uploads, with any client directory stripped from the file name.
+//
+// # Media Types
+//
+// MediaType parses and formats Content-Type values, and Is compares a
+// request's Content-Type while ignoring parameters such as charset:
+//
+//	w.Header().Set(headers.ContentType, headers.JSONUTF8().String())
+//
+//	if !headers.Is(r, "application/json") {
+//	    w.WriteHeader(http.StatusUnsupportedMediaType)
+//	    return
+//	}
+//
+// # Authorization
+//
+// BearerToken and BasicAuthDecode extract credentials from the Authorization
+// header, and ParseAuthorization handles any other scheme. Compare secrets
+// with SecureCompare, or use CheckBasicAuth, so that response timing does not
+// leak them:
+//
+//	if !headers.CheckBasicAuth(r, "admin", adminPassword) {
+//	    w.Header().Set(headers.WWWAuthenticate, headers.BasicChallenge("admin").String())
+//	    w.WriteHeader(http.StatusUnauthorized)
+//	    return
+//	}
+//
+// Challenge builds the WWW-Authenticate side, and FormatChallenges and
+// ParseChallenges handle headers offering several schemes at once.
+//
+// # Client IP
+//
+// ClientIP finds the client address behind reverse proxies. Forwarding
+// headers can be sent by anyone, so it reads them only as far back as a
+// TrustPolicy allows, and only from the header the proxies overwrite or
+// append to, X-Forwarded-For unless TrustPolicy.Header says otherwise:
+//
+//	trust, err := headers.TrustProxies("10.0.0.0/8")
+//	trust.Header = headers.XForwardedFor
+//	ip := headers.ClientIP(r, trust)
+//
+// ParseForwarded reads RFC 7239 Forwarded headers directly.
+//
+// # Reverse Proxies
+//
+// SetXForwarded and AppendForwarded add the forwarding headers a reverse
+// proxy passes upstream, and ConvertXForwarded turns X-Forwarded-* headers
+// into a single Forwarded header:
+//
+//	headers.SetXForwarded(out.Header, in)
+//
+// StripHopByHop removes the headers that apply to a single connection, those
+// listed by HopByHop and any named in Connection, before a message is
+// forwarded.
+//
+// # Connection Management
+//
+// ConnectionTokens lists the options of a Connection header, WantsClose and
+// HasConnectionClose tell whether a connection is about to be closed, and
+// ParseKeepAlive reads the timeout and request limit of a Keep-Alive header:
+//
+//	if headers.WantsClose(r) {
+//	    // HTTP/1.0 without keep-alive, or Connection: close
+//	}
+//	ka := headers.ParseKeepAlive(resp.Header.Values(headers.KeepAlive)...)
+//	idle := ka.Timeout  // 0 if not given
+//
+// # Vary
+//
+// AddVary adds request header names to the Vary header of a response, for
+// middleware whose output depends on them, without listing any twice and
+// respecting "*". VaryFields lists them:
+//
+//	headers.AddVary(w.Header(), headers.AcceptEncoding)
+//	headers.VaryFields(w.Header())  // ["Origin", "Accept-Encoding"]
+//
+// # Origins
+//
+// An OriginMatcher checks Origin header values against allowed origins, exact
+// or with a wildcard subdomain label or port, for CORS and for the Origin
+// check of WebSocket handshakes. The opaque origin "null" only matches when
+// listed itself:
+//
+//	m, err := headers.NewOriginMatcher("https://app.example.com", "https://*.example.com", "http://localhost:*")
+//	if !m.Match(r.Header.Get(headers.Origin)) {
+//	    http.Error(w, "origin not allowed", http.StatusForbidden)
+//	}
+//
+// # CORS
+//
+// IsCORS and IsPreflight classify requests, and WritePreflight answers a
+// preflight with 204 No Content and the Access-Control-Allow-* headers of a
+// PreflightConfig, for frameworks that compose their own CORS handling:
+//
+//	if headers.IsPreflight(r) {
+//	    headers.WritePreflight(w, headers.PreflightConfig{
+//	        AllowOrigin:  r.Header.Get(headers.Origin),
+//	        AllowMethods: []string{http.MethodPut},
+//	    })
+//	    return
+//	}
+//
+// # Protocol Upgrades
+//
+// WantsUpgrade reports whether a request asks to switch to a protocol,
+// ParseUpgrade lists the protocols an Upgrade header offers, and
+// WriteSwitchingProtocols answers with 101 Switching Protocols, after which
+// the connection is hijacked:
+//
+//	if headers.WantsUpgrade(r, "h2c") {
+//	    headers.WriteSwitchingProtocols(w, "h2c", nil)
+//	    conn, rw, err := http.NewResponseController(w).Hijack()
+//	    ...
+//	}
+//
+// # WebSockets
+//
+// ValidateUpgrade checks a WebSocket opening handshake, NegotiateSubprotocol
+// picks the Sec-WebSocket-Protocol, and ComputeAccept answers the
+// Sec-WebSocket-Key, which is all a server needs for RFC 6455 handshakes
+// before taking over the connection. Clients use GenerateKey:
+//
+//	if err := headers.ValidateUpgrade(r); err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	extra := http.Header{}
+//	extra.Set(headers.SecWebSocketAccept, headers.ComputeAccept(r.Header.Get(headers.SecWebSocketKey)))
+//	if proto := headers.NegotiateSubprotocol(r, "chat.v2", "chat.v1"); proto != "" {
+//	    extra.Set(headers.SecWebSocketProtocol, proto)
+//	}
+//	headers.WriteSwitchingProtocols(w, "websocket", extra)
+//
+// # Preferences
+//
+// ParsePrefer reads the Prefer headers of RFC 7240, with return, wait,
+// respond-async and handling as fields, and SetPreferenceApplied tells the
+// client which ones the response honored:
+//
+//	prefs := headers.ParsePrefer(r.Header.Values(headers.Prefer)...)
+//	if prefs.Return == headers.PreferReturnMinimal {
+//	    headers.SetPreferenceApplied(w, headers.Preference{Name: "return", Value: prefs.Return})
+//	    w.WriteHeader(http.StatusNoContent)
+//	    return
+//	}
+//
+// # Retry-After
+//
+// SetRetryAfter writes the delay of a 429 or 503 response in whole seconds,
+// and ParseRetryAfter reads either form of the header back as a duration:
+//
+//	wait, err := headers.ParseRetryAfter(resp.Header.Get(headers.RetryAfter), time.Now())
+//
+// # Rate Limits
+//
+// SetRateLimit advertises the quota of a rate limited client with the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset fields, and
+// ParseRateLimit reads it back from any of the draft fields or the legacy
+// X-RateLimit-* ones, so that clients can slow down before they are refused:
+//
+//	if state, ok := headers.ParseRateLimit(resp.Header, time.Now()); ok && state.Exhausted() {
+//	    time.Sleep(state.Reset)
+//	}
+//
+// # Strict-Transport-Security
+//
+// HSTSValue formats Strict-Transport-Security values and checks the preload
+// list's requirements, and ParseHSTS reads them back for audits:
+//
+//	w.Header().Set(headers.StrictTransportSecurity, headers.HSTSValue{
+//	    MaxAge:            2 * 365 * 24 * time.Hour,
+//	    IncludeSubDomains: true,
+//	    Preload:           true,
+//	}.String())
+//
+// # Referrer-Policy and X-Frame-Options
+//
+// ReferrerPolicyValue and XFrameOptionsValue name the legal values of these
+// headers. SetReferrerPolicy and SetXFrameOptions reject anything else, such
+// as a repeated policy or the obsolete ALLOW-FROM, and ParseReferrerPolicy and
+// ParseXFrameOptions return what a browser applies for a received header:
+//
+//	err := headers.SetReferrerPolicy(w, headers.ReferrerPolicyStrictOriginWhenCrossOrigin)
+//	err = headers.SetXFrameOptions(w, headers.XFODeny)
+//
+// # Server-Timing
+//
+// ServerTimings collects metrics for the Server-Timing header, which browsers
+// show in their developer tools, and ParseServerTiming reads them back:
+//
+//	var timing headers.ServerTimings
+//	t := timing.Start("db", "Load products")
+//	products := store.List()
+//	t.Stop()
+//	timing.WriteTo(w.Header())
+//
+// # Structured Fields
+//
+// Headers defined as RFC 9651 Structured Field Values, such as Priority and
+// the Sec-CH-* client hints, are parsed and serialized by the sfv subpackage.
+//
 // # Header Values
 //
 // All header constant values match the official HTTP header specifications
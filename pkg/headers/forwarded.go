@@ -0,0 +1,142 @@
+package headers
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+)
+
+// ErrInvalidForwarded is returned for a Forwarded value that does not follow
+// the syntax of RFC 7239, section 4.
+var ErrInvalidForwarded = errors.New("headers: invalid Forwarded header")
+
+// ForwardedElement is the information one proxy added to a Forwarded header.
+// Values are unquoted, and unset parameters are "".
+type ForwardedElement struct {
+	For   string            // Node the proxy received the request from, such as "192.0.2.60" or "[2001:db8::1]:4711"
+	By    string            // Node the proxy received the request on
+	Host  string            // Host header the proxy received
+	Proto string            // Scheme the proxy received the request with, such as "https"
+	Ext   map[string]string // Other parameters by lower-cased name, nil if none
+}
+
+// ForAddr returns the IP address in For. ok is false if For is unset, the
+// identifier "unknown", or an obfuscated identifier such as "_hidden".
+func (e ForwardedElement) ForAddr() (addr netip.Addr, ok bool) {
+	return parseNode(e.For)
+}
+
+// ParseForwarded parses a Forwarded header value into its elements, the
+// client's first and the nearest proxy's last:
+//
+//	elems, err := headers.ParseForwarded(`for=192.0.2.60;proto=https, for="[2001:db8::17]:4711"`)
+//	// elems[0].For == "192.0.2.60", elems[0].Proto == "https"
+//	// elems[1].For == "[2001:db8::17]:4711"
+//
+// Parameter names are case-insensitive. A request may carry several header
+// lines; join them with ", " first. Returns ErrInvalidForwarded if value is
+// empty, malformed, or repeats a parameter within one element.
+//
+// The header is set by proxies but can be sent by anyone; use ClientIP to
+// find the client address without trusting what the client wrote.
+func ParseForwarded(value string) ([]ForwardedElement, error) {
+	var elems []ForwardedElement
+	p := authParser{s: value}
+	for {
+		p.skipListSeparators()
+		if p.done() {
+			break
+		}
+
+		var e ForwardedElement
+		for {
+			p.ows()
+			if p.done() || p.peek() == ',' {
+				break
+			}
+			if p.peek() == ';' {
+				p.i++
+				continue
+			}
+
+			name := strings.ToLower(p.token())
+			if name == "" || p.done() || p.peek() != '=' {
+				return nil, ErrInvalidForwarded
+			}
+			p.i++
+
+			var value string
+			if !p.done() && p.peek() == '"' {
+				v, ok := p.quoted()
+				if !ok {
+					return nil, ErrInvalidForwarded
+				}
+				value = v
+			} else if value = p.token(); value == "" {
+				return nil, ErrInvalidForwarded
+			}
+			if !e.set(name, value) {
+				return nil, ErrInvalidForwarded
+			}
+
+			p.ows()
+			if !p.done() && p.peek() != ';' && p.peek() != ',' {
+				return nil, ErrInvalidForwarded
+			}
+		}
+		elems = append(elems, e)
+	}
+	if len(elems) == 0 {
+		return nil, ErrInvalidForwarded
+	}
+	return elems, nil
+}
+
+// set stores a parameter, reporting false if it was already set.
+func (e *ForwardedElement) set(name, value string) bool {
+	var dst *string
+	switch name {
+	case "for":
+		dst = &e.For
+	case "by":
+		dst = &e.By
+	case "host":
+		dst = &e.Host
+	case "proto":
+		dst = &e.Proto
+	default:
+		if _, dup := e.Ext[name]; dup {
+			return false
+		}
+		if e.Ext == nil {
+			e.Ext = make(map[string]string)
+		}
+		e.Ext[name] = value
+		return true
+	}
+	if *dst != "" {
+		return false
+	}
+	*dst = value
+	return true
+}
+
+// parseNode returns the IP address of a node identifier as found in Forwarded
+// or X-Forwarded-For: an address with an optional port, IPv6 addresses being
+// bracketed when a port is present. IPv4-mapped IPv6 addresses are unmapped
+// and zones dropped, so the result compares cleanly against prefixes.
+func parseNode(s string) (netip.Addr, bool) {
+	host := s
+	if rest, ok := strings.CutPrefix(s, "["); ok {
+		if host, _, ok = strings.Cut(rest, "]"); !ok {
+			return netip.Addr{}, false
+		}
+	} else if strings.Count(s, ":") == 1 {
+		host, _, _ = strings.Cut(s, ":")
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
@@ -0,0 +1,88 @@
+package headers_test
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParseForwarded(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  []headers.ForwardedElement
+		wantError bool
+	}{
+		{
+			name:     "single",
+			value:    "for=192.0.2.60;proto=http;by=203.0.113.43",
+			expected: []headers.ForwardedElement{{For: "192.0.2.60", By: "203.0.113.43", Proto: "http"}},
+		},
+		{
+			name:  "several elements",
+			value: `For="[2001:db8:cafe::17]:4711", for=192.0.2.43;Host=example.com, for=unknown`,
+			expected: []headers.ForwardedElement{
+				{For: "[2001:db8:cafe::17]:4711"},
+				{For: "192.0.2.43", Host: "example.com"},
+				{For: "unknown"},
+			},
+		},
+		{
+			name:     "extension and empty pairs",
+			value:    `;for=_hidden;;secret="a\"b";`,
+			expected: []headers.ForwardedElement{{For: "_hidden", Ext: map[string]string{"secret": `a"b`}}},
+		},
+		{name: "empty", value: "", wantError: true},
+		{name: "missing value", value: "for=", wantError: true},
+		{name: "unquoted ipv6", value: "for=[2001:db8::1]", wantError: true},
+		{name: "duplicate", value: "for=a;for=b", wantError: true},
+		{name: "bad separator", value: "for=a proto=http", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseForwarded(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseForwarded() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseForwarded() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestForwardedElementForAddr(t *testing.T) {
+	tests := []struct {
+		node     string
+		expected string
+	}{
+		{"192.0.2.60", "192.0.2.60"},
+		{"192.0.2.60:8080", "192.0.2.60"},
+		{"[2001:db8::17]:4711", "2001:db8::17"},
+		{"[2001:db8::17]:_port", "2001:db8::17"},
+		{"[2001:db8::17]", "2001:db8::17"},
+		{"2001:db8::17", "2001:db8::17"},
+		{"::ffff:192.0.2.1", "192.0.2.1"},
+		{"unknown", ""},
+		{"_hidden", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.node, func(t *testing.T) {
+			addr, ok := headers.ForwardedElement{For: tt.node}.ForAddr()
+			if tt.expected == "" {
+				if ok {
+					t.Errorf("ForAddr() = %v, want not ok", addr)
+				}
+				return
+			}
+			if !ok || addr != netip.MustParseAddr(tt.expected) {
+				t.Errorf("ForAddr() = %v, %v, want %v", addr, ok, tt.expected)
+			}
+		})
+	}
+}
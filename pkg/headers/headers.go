@@ -51,6 +51,8 @@ const (
 	Connection = "Connection"
 	// KeepAlive controls how long a persistent connection should stay open.
 	KeepAlive = "Keep-Alive"
+	// ProxyConnection is a non-standard predecessor of Connection still sent by some clients to proxies.
+	ProxyConnection = "Proxy-Connection"
 
 	// Content Negotiation
 
@@ -89,6 +91,8 @@ const (
 	AccessControlAllowMethods = "Access-Control-Allow-Methods"
 	// AccessControlAllowOrigin indicates whether the response can be shared.
 	AccessControlAllowOrigin = "Access-Control-Allow-Origin"
+	// AccessControlAllowPrivateNetwork is used in response to a preflight request to allow a public site to access a server on a private network.
+	AccessControlAllowPrivateNetwork = "Access-Control-Allow-Private-Network"
 	// AccessControlExposeHeaders indicates which headers can be exposed as part of the response by listing their names.
 	AccessControlExposeHeaders = "Access-Control-Expose-Headers"
 	// AccessControlMaxAge indicates how long the results of a preflight request can be cached.
@@ -97,6 +101,8 @@ const (
 	AccessControlRequestHeaders = "Access-Control-Request-Headers"
 	// AccessControlRequestMethod is used when issuing a preflight request to let the server know which HTTP method will be used when the actual request is made.
 	AccessControlRequestMethod = "Access-Control-Request-Method"
+	// AccessControlRequestPrivateNetwork is sent in a preflight request when a public site accesses a server on a private network.
+	AccessControlRequestPrivateNetwork = "Access-Control-Request-Private-Network"
 	// Origin indicates where a fetch originates from.
 	Origin = "Origin"
 	// TimingAllowOrigin specifies origins that are allowed to see values of attributes retrieved via features of the Resource Timing API.
@@ -205,6 +211,8 @@ const (
 	UpgradeInsecureRequests = "Upgrade-Insecure-Requests"
 	// XContentTypeOptions disables MIME sniffing and forces browser to use the type given in Content-Type.
 	XContentTypeOptions = "X-Content-Type-Options"
+	// XCSRFToken carries the anti-CSRF token of a request made by a script, by convention.
+	XCSRFToken = "X-CSRF-Token"
 	// XFrameOptions indicates whether a browser should be allowed to render a page in a frame, iframe, embed or object.
 	XFrameOptions = "X-Frame-Options"
 	// XPermittedCrossDomainPolicies overrides cross-domain policy files.
@@ -269,8 +277,20 @@ const (
 	AltUsed = "Alt-Used"
 	// Date contains the date and time at which the message was originated.
 	Date = "Date"
+	// IdempotencyKey identifies a request so that the server can recognize retries of it and process it only once.
+	IdempotencyKey = "Idempotency-Key"
 	// Link provides a means for serializing one or more links in HTTP headers.
 	Link = "Link"
+	// RateLimit advertises the quota left to the client under a rate limit policy, and when it resets.
+	RateLimit = "RateLimit"
+	// RateLimitLimit advertises the request quota of the current rate limit window, as in early drafts of the RateLimit fields.
+	RateLimitLimit = "RateLimit-Limit"
+	// RateLimitPolicy advertises the rate limit policies of the server, with their quotas and windows.
+	RateLimitPolicy = "RateLimit-Policy"
+	// RateLimitRemaining advertises the quota left in the current rate limit window, as in early drafts of the RateLimit fields.
+	RateLimitRemaining = "RateLimit-Remaining"
+	// RateLimitReset advertises the number of seconds until the current rate limit window resets, as in early drafts of the RateLimit fields.
+	RateLimitReset = "RateLimit-Reset"
 	// RetryAfter indicates how long the user agent should wait before making a follow-up request.
 	RetryAfter = "Retry-After"
 	// ServerTiming communicates one or more metrics and descriptions for the given request-response cycle.
@@ -283,6 +303,10 @@ const (
 	ServiceWorkerNavigationPreload = "Service-Worker-Navigation-Preload"
 	// SourceMap links to a source map so that debuggers can step through original source code instead of generated or transformed code.
 	SourceMap = "SourceMap"
+	// Traceparent identifies the trace a request belongs to and the span of the caller, in the W3C Trace Context format.
+	Traceparent = "traceparent"
+	// Tracestate carries vendor-specific trace data alongside traceparent.
+	Tracestate = "tracestate"
 	// Upgrade can be used to upgrade an already established client/server connection to a different protocol.
 	Upgrade = "Upgrade"
 	// Priority provides a hint from about the priority of a particular resource request on a particular connection.
@@ -365,10 +389,34 @@ const (
 	XForwardedHost = "X-Forwarded-Host"
 	// XForwardedProto identifies the protocol (HTTP or HTTPS) that a client used to connect to your proxy or load balancer.
 	XForwardedProto = "X-Forwarded-Proto"
+	// XForwardedPort identifies the port that a client used to connect to your proxy or load balancer.
+	XForwardedPort = "X-Forwarded-Port"
+	// XRealIP carries the client IP address as seen by a single reverse proxy.
+	XRealIP = "X-Real-IP"
 	// XDNSPrefetchControl controls DNS prefetching.
 	XDNSPrefetchControl = "X-DNS-Prefetch-Control"
 	// XRobotsTag indicates how a web page is to be indexed within public search engine results.
 	XRobotsTag = "X-Robots-Tag"
+	// XRequestID carries an identifier of the request, logged by every service it passes through to correlate their logs.
+	XRequestID = "X-Request-ID"
+	// B3 propagates the trace, span and sampling decision of a request in the single-header Zipkin B3 format.
+	B3 = "b3"
+	// XB3TraceID carries the trace ID of a request in the multi-header Zipkin B3 format.
+	XB3TraceID = "X-B3-TraceId"
+	// XB3SpanID carries the span ID of the caller in the multi-header Zipkin B3 format.
+	XB3SpanID = "X-B3-SpanId"
+	// XB3ParentSpanID carries the parent of the caller's span in the multi-header Zipkin B3 format.
+	XB3ParentSpanID = "X-B3-ParentSpanId"
+	// XB3Sampled carries the sampling decision, 1 or 0, in the multi-header Zipkin B3 format.
+	XB3Sampled = "X-B3-Sampled"
+	// XB3Flags set to 1 requests debug tracing in the multi-header Zipkin B3 format.
+	XB3Flags = "X-B3-Flags"
+	// XRateLimitLimit is the request quota of the current rate limit window, the predecessor of RateLimit-Limit.
+	XRateLimitLimit = "X-RateLimit-Limit"
+	// XRateLimitRemaining is the quota left in the current rate limit window, the predecessor of RateLimit-Remaining.
+	XRateLimitRemaining = "X-RateLimit-Remaining"
+	// XRateLimitReset is when the current rate limit window resets, as a Unix time or a number of seconds depending on the server.
+	XRateLimitReset = "X-RateLimit-Reset"
 
 	// Deprecated
 
@@ -445,16 +493,18 @@ type corsHeaders struct{}
 
 var CORS = corsHeaders{}
 
-func (corsHeaders) AllowCredentials() string  { return AccessControlAllowCredentials }
-func (corsHeaders) AllowHeaders() string      { return AccessControlAllowHeaders }
-func (corsHeaders) AllowMethods() string      { return AccessControlAllowMethods }
-func (corsHeaders) AllowOrigin() string       { return AccessControlAllowOrigin }
-func (corsHeaders) ExposeHeaders() string     { return AccessControlExposeHeaders }
-func (corsHeaders) MaxAge() string            { return AccessControlMaxAge }
-func (corsHeaders) RequestHeaders() string    { return AccessControlRequestHeaders }
-func (corsHeaders) RequestMethod() string     { return AccessControlRequestMethod }
-func (corsHeaders) Origin() string            { return Origin }
-func (corsHeaders) TimingAllowOrigin() string { return TimingAllowOrigin }
+func (corsHeaders) AllowCredentials() string      { return AccessControlAllowCredentials }
+func (corsHeaders) AllowHeaders() string          { return AccessControlAllowHeaders }
+func (corsHeaders) AllowMethods() string          { return AccessControlAllowMethods }
+func (corsHeaders) AllowOrigin() string           { return AccessControlAllowOrigin }
+func (corsHeaders) AllowPrivateNetwork() string   { return AccessControlAllowPrivateNetwork }
+func (corsHeaders) ExposeHeaders() string         { return AccessControlExposeHeaders }
+func (corsHeaders) MaxAge() string                { return AccessControlMaxAge }
+func (corsHeaders) RequestHeaders() string        { return AccessControlRequestHeaders }
+func (corsHeaders) RequestMethod() string         { return AccessControlRequestMethod }
+func (corsHeaders) RequestPrivateNetwork() string { return AccessControlRequestPrivateNetwork }
+func (corsHeaders) Origin() string                { return Origin }
+func (corsHeaders) TimingAllowOrigin() string     { return TimingAllowOrigin }
 
 // Content provides content-related headers
 type contentHeaders struct{}
@@ -513,6 +563,7 @@ var Security = securityHeaders{}
 
 func (securityHeaders) CSP() string                     { return ContentSecurityPolicy }
 func (securityHeaders) CSPReportOnly() string           { return ContentSecurityPolicyReportOnly }
+func (securityHeaders) CSRFToken() string               { return XCSRFToken }
 func (securityHeaders) COEP() string                    { return CrossOriginEmbedderPolicy }
 func (securityHeaders) COOP() string                    { return CrossOriginOpenerPolicy }
 func (securityHeaders) CORP() string                    { return CrossOriginResourcePolicy }
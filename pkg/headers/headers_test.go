@@ -55,10 +55,12 @@ func TestAllGroupedHeaders(t *testing.T) {
 		{"CORS.AllowHeaders", headers.CORS.AllowHeaders(), "Access-Control-Allow-Headers"},
 		{"CORS.AllowMethods", headers.CORS.AllowMethods(), "Access-Control-Allow-Methods"},
 		{"CORS.AllowOrigin", headers.CORS.AllowOrigin(), "Access-Control-Allow-Origin"},
+		{"CORS.AllowPrivateNetwork", headers.CORS.AllowPrivateNetwork(), "Access-Control-Allow-Private-Network"},
 		{"CORS.ExposeHeaders", headers.CORS.ExposeHeaders(), "Access-Control-Expose-Headers"},
 		{"CORS.MaxAge", headers.CORS.MaxAge(), "Access-Control-Max-Age"},
 		{"CORS.RequestHeaders", headers.CORS.RequestHeaders(), "Access-Control-Request-Headers"},
 		{"CORS.RequestMethod", headers.CORS.RequestMethod(), "Access-Control-Request-Method"},
+		{"CORS.RequestPrivateNetwork", headers.CORS.RequestPrivateNetwork(), "Access-Control-Request-Private-Network"},
 		{"CORS.Origin", headers.CORS.Origin(), "Origin"},
 		{"CORS.TimingAllowOrigin", headers.CORS.TimingAllowOrigin(), "Timing-Allow-Origin"},
 
@@ -95,6 +97,7 @@ func TestAllGroupedHeaders(t *testing.T) {
 		// Security
 		{"Security.CSP", headers.Security.CSP(), "Content-Security-Policy"},
 		{"Security.CSPReportOnly", headers.Security.CSPReportOnly(), "Content-Security-Policy-Report-Only"},
+		{"Security.CSRFToken", headers.Security.CSRFToken(), "X-CSRF-Token"},
 		{"Security.COEP", headers.Security.COEP(), "Cross-Origin-Embedder-Policy"},
 		{"Security.COOP", headers.Security.COOP(), "Cross-Origin-Opener-Policy"},
 		{"Security.CORP", headers.Security.CORP(), "Cross-Origin-Resource-Policy"},
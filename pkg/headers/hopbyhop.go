@@ -0,0 +1,47 @@
+package headers
+
+import (
+	"net/http"
+	"slices"
+)
+
+// hopByHop lists the headers that describe a single connection rather than
+// the message, from RFC 9110, section 7.6.1, and RFC 2616, section 13.5.1.
+var hopByHop = []string{
+	Connection,
+	KeepAlive,
+	ProxyConnection,
+	ProxyAuthenticate,
+	ProxyAuthorization,
+	TE,
+	Trailer,
+	TransferEncoding,
+	Upgrade,
+}
+
+// HopByHop returns the names of the headers that apply only to a single
+// connection and must not be forwarded by proxies: Connection, Keep-Alive,
+// Proxy-Connection, Proxy-Authenticate, Proxy-Authorization, TE, Trailer,
+// Transfer-Encoding and Upgrade. The slice is a copy and may be modified.
+func HopByHop() []string {
+	return slices.Clone(hopByHop)
+}
+
+// StripHopByHop removes the hop-by-hop headers from h, both those listed by
+// HopByHop and any the sender named in its Connection header, as a proxy must
+// before forwarding a request or response:
+//
+//	// Connection: close, X-Session-Hint
+//	headers.StripHopByHop(out.Header)
+//	// Connection and X-Session-Hint are gone
+//
+// A proxy that supports protocol upgrades, such as WebSockets, has to set
+// Connection and Upgrade again on the outgoing request itself.
+func StripHopByHop(h http.Header) {
+	for _, name := range ConnectionTokens(h) {
+		h.Del(name)
+	}
+	for _, name := range hopByHop {
+		h.Del(name)
+	}
+}
@@ -0,0 +1,41 @@
+package headers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestHopByHop(t *testing.T) {
+	got := headers.HopByHop()
+	if len(got) != 9 {
+		t.Fatalf("HopByHop() = %v, want 9 headers", got)
+	}
+	got[0] = "X-Changed"
+	if headers.HopByHop()[0] != headers.Connection {
+		t.Error("HopByHop() returned a shared slice")
+	}
+}
+
+func TestStripHopByHop(t *testing.T) {
+	h := http.Header{}
+	h.Add(headers.Connection, "close, X-Session-Hint")
+	h.Add(headers.Connection, " x-debug ,")
+	h.Set("X-Session-Hint", "a")
+	h.Set("X-Debug", "b")
+	h.Set(headers.KeepAlive, "timeout=5")
+	h.Set(headers.ProxyConnection, "keep-alive")
+	h.Set(headers.ProxyAuthorization, "Basic abc")
+	h.Set(headers.TE, "trailers")
+	h.Set(headers.TransferEncoding, "chunked")
+	h.Set(headers.Upgrade, "websocket")
+	h.Set(headers.ContentType, "text/plain")
+	h.Set(headers.Authorization, "Bearer abc")
+
+	headers.StripHopByHop(h)
+
+	if len(h) != 2 || h.Get(headers.ContentType) != "text/plain" || h.Get(headers.Authorization) != "Bearer abc" {
+		t.Errorf("StripHopByHop() left %v", h)
+	}
+}
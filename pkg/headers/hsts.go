@@ -0,0 +1,121 @@
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidHSTS is returned for a Strict-Transport-Security value that
+	// does not follow RFC 6797, section 6.1.
+	ErrInvalidHSTS = errors.New("headers: invalid Strict-Transport-Security header")
+
+	// ErrHSTSPreload is returned by HSTSValue.Validate when Preload is set but
+	// the value does not meet the requirements of the browsers' preload list.
+	ErrHSTSPreload = errors.New("headers: HSTS value not eligible for preloading")
+)
+
+// HSTSPreloadMinAge is the shortest max-age accepted by the HSTS preload list
+// at https://hstspreload.org: one year.
+const HSTSPreloadMinAge = 31536000 * time.Second
+
+// HSTSValue is a Strict-Transport-Security header value.
+type HSTSValue struct {
+	MaxAge            time.Duration // How long browsers remember the policy; 0 removes it
+	IncludeSubDomains bool          // Whether the policy covers all subdomains
+	Preload           bool          // Whether the site asks to be on the preload list
+}
+
+// Validate reports whether v can be sent as is. A value with Preload set must
+// have a MaxAge of at least HSTSPreloadMinAge and IncludeSubDomains set, or
+// the preload list rejects the site; Validate returns an error wrapping
+// ErrHSTSPreload describing the first requirement missed.
+func (v HSTSValue) Validate() error {
+	if !v.Preload {
+		return nil
+	}
+	if v.MaxAge < HSTSPreloadMinAge {
+		return fmt.Errorf("%w: max-age must be at least %d seconds", ErrHSTSPreload, int64(HSTSPreloadMinAge/time.Second))
+	}
+	if !v.IncludeSubDomains {
+		return fmt.Errorf("%w: includeSubDomains is required", ErrHSTSPreload)
+	}
+	return nil
+}
+
+// String formats v for the Strict-Transport-Security header, such as
+// "max-age=63072000; includeSubDomains; preload". MaxAge is written in whole
+// seconds, negative values as 0. The preload directive is only written if v
+// passes Validate, so a value that would be rejected from the preload list
+// never claims to qualify:
+//
+//	w.Header().Set(headers.StrictTransportSecurity, headers.HSTSValue{
+//	    MaxAge:            2 * 365 * 24 * time.Hour,
+//	    IncludeSubDomains: true,
+//	    Preload:           true,
+//	}.String())
+func (v HSTSValue) String() string {
+	value := "max-age=" + strconv.FormatInt(int64(max(v.MaxAge, 0)/time.Second), 10)
+	if v.IncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+	if v.Preload && v.Validate() == nil {
+		value += "; preload"
+	}
+	return value
+}
+
+// ParseHSTS parses a Strict-Transport-Security value, for instance to audit
+// the policy a site sends. Directive names are case-insensitive, unknown
+// directives are ignored, and a max-age too long for a time.Duration is
+// capped at its maximum. Returns ErrInvalidHSTS if max-age is missing or
+// invalid, or if a directive appears twice.
+func ParseHSTS(value string) (HSTSValue, error) {
+	var v HSTSValue
+	seen := make(map[string]bool)
+	for directive := range strings.SplitSeq(value, ";") {
+		name, arg, hasArg := strings.Cut(directive, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			if hasArg {
+				return HSTSValue{}, ErrInvalidHSTS
+			}
+			continue
+		}
+		if seen[name] {
+			return HSTSValue{}, ErrInvalidHSTS
+		}
+		seen[name] = true
+
+		switch name {
+		case "max-age":
+			arg = strings.TrimSpace(arg)
+			if unquoted, ok := strings.CutPrefix(arg, `"`); ok {
+				if arg, ok = strings.CutSuffix(unquoted, `"`); !ok {
+					return HSTSValue{}, ErrInvalidHSTS
+				}
+			}
+			if arg == "" || strings.Trim(arg, "0123456789") != "" {
+				return HSTSValue{}, ErrInvalidHSTS
+			}
+			seconds, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || seconds > int64(math.MaxInt64/time.Second) {
+				v.MaxAge = time.Duration(math.MaxInt64)
+			} else {
+				v.MaxAge = time.Duration(seconds) * time.Second
+			}
+		case "includesubdomains":
+			v.IncludeSubDomains = true
+		case "preload":
+			v.Preload = true
+		}
+	}
+	if !seen["max-age"] {
+		return HSTSValue{}, ErrInvalidHSTS
+	}
+	return v, nil
+}
@@ -0,0 +1,72 @@
+package headers_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestHSTSValueString(t *testing.T) {
+	year := 365 * 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		value    headers.HSTSValue
+		expected string
+		err      error
+	}{
+		{"max-age only", headers.HSTSValue{MaxAge: time.Hour}, "max-age=3600", nil},
+		{"subdomains", headers.HSTSValue{MaxAge: 90 * time.Second, IncludeSubDomains: true}, "max-age=90; includeSubDomains", nil},
+		{"preload", headers.HSTSValue{MaxAge: 2 * year, IncludeSubDomains: true, Preload: true}, "max-age=63072000; includeSubDomains; preload", nil},
+		{"preload minimum", headers.HSTSValue{MaxAge: headers.HSTSPreloadMinAge, IncludeSubDomains: true, Preload: true}, "max-age=31536000; includeSubDomains; preload", nil},
+		{"preload too short", headers.HSTSValue{MaxAge: year - time.Second, IncludeSubDomains: true, Preload: true}, "max-age=31535999; includeSubDomains", headers.ErrHSTSPreload},
+		{"preload without subdomains", headers.HSTSValue{MaxAge: year, Preload: true}, "max-age=31536000", headers.ErrHSTSPreload},
+		{"removal", headers.HSTSValue{MaxAge: -time.Hour}, "max-age=0", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.value.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+			if err := tt.value.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseHSTS(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  headers.HSTSValue
+		wantError bool
+	}{
+		{"full", "max-age=63072000; includeSubDomains; preload", headers.HSTSValue{MaxAge: 63072000 * time.Second, IncludeSubDomains: true, Preload: true}, false},
+		{"case and spacing", ` INCLUDESUBDOMAINS ;MAX-AGE = "300";`, headers.HSTSValue{MaxAge: 300 * time.Second, IncludeSubDomains: true}, false},
+		{"unknown directive", "max-age=0; report-uri=x", headers.HSTSValue{}, false},
+		{"huge", "max-age=99999999999999999999", headers.HSTSValue{MaxAge: time.Duration(math.MaxInt64)}, false},
+		{"missing max-age", "includeSubDomains", headers.HSTSValue{}, true},
+		{"empty max-age", "max-age=", headers.HSTSValue{}, true},
+		{"negative", "max-age=-1", headers.HSTSValue{}, true},
+		{"unterminated quote", `max-age="1`, headers.HSTSValue{}, true},
+		{"duplicate", "max-age=1; max-age=2", headers.HSTSValue{}, true},
+		{"empty", "", headers.HSTSValue{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseHSTS(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseHSTS() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseHSTS() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package headers
+
+import (
+	"errors"
+	"maps"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidMediaType is returned by ParseMediaType for a value that is not a
+// media type.
+var ErrInvalidMediaType = errors.New("headers: invalid media type")
+
+// MediaType is a media type such as text/html;charset=utf-8, as used in
+// Content-Type. Type and Subtype are lower-case; parameter names are
+// lower-case and values are kept as sent.
+type MediaType struct {
+	Type    string
+	Subtype string
+	Params  map[string]string
+}
+
+// ParseMediaType parses a Content-Type style value using mime.ParseMediaType,
+// so quoted and RFC 2231 encoded parameters are handled. Wildcards are
+// rejected; use package negotiate for Accept ranges.
+//
+// Example:
+//
+//	mt, err := headers.ParseMediaType(r.Header.Get(headers.ContentType))
+//	if err == nil && mt.Essence() == "text/csv" {
+//	    charset := mt.Charset()
+//	}
+func ParseMediaType(s string) (MediaType, error) {
+	v, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return MediaType{}, err
+	}
+	typ, subtype, ok := strings.Cut(v, "/")
+	if !ok || typ == "*" || subtype == "*" {
+		return MediaType{}, ErrInvalidMediaType
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+	return MediaType{Type: typ, Subtype: subtype, Params: params}, nil
+}
+
+// Essence returns the type and subtype without parameters, such as
+// "application/json".
+func (m MediaType) Essence() string {
+	return m.Type + "/" + m.Subtype
+}
+
+// Charset returns the charset parameter lower-cased, or "" if there is none.
+func (m MediaType) Charset() string {
+	return strings.ToLower(m.Params["charset"])
+}
+
+// With returns a copy of m with the parameter name set to value.
+func (m MediaType) With(name, value string) MediaType {
+	params := make(map[string]string, len(m.Params)+1)
+	maps.Copy(params, m.Params)
+	params[strings.ToLower(name)] = value
+	m.Params = params
+	return m
+}
+
+// String formats m for a Content-Type header using mime.FormatMediaType, with
+// parameters sorted by name and quoted where needed. It returns "" if m is not
+// a valid media type.
+func (m MediaType) String() string {
+	return mime.FormatMediaType(m.Essence(), m.Params)
+}
+
+// Media types returned by the constructors below, which copy them.
+var (
+	mediaJSON           = MediaType{Type: "application", Subtype: "json"}
+	mediaText           = MediaType{Type: "text", Subtype: "plain"}
+	mediaHTML           = MediaType{Type: "text", Subtype: "html"}
+	mediaFormURLEncoded = MediaType{Type: "application", Subtype: "x-www-form-urlencoded"}
+	mediaOctetStream    = MediaType{Type: "application", Subtype: "octet-stream"}
+	mediaProblemJSON    = MediaType{Type: "application", Subtype: "problem+json"}
+)
+
+// JSON returns application/json.
+func JSON() MediaType { return mediaJSON }
+
+// JSONUTF8 returns application/json; charset=utf-8.
+func JSONUTF8() MediaType { return mediaJSON.With("charset", "utf-8") }
+
+// TextUTF8 returns text/plain; charset=utf-8.
+func TextUTF8() MediaType { return mediaText.With("charset", "utf-8") }
+
+// HTMLUTF8 returns text/html; charset=utf-8.
+func HTMLUTF8() MediaType { return mediaHTML.With("charset", "utf-8") }
+
+// FormURLEncoded returns application/x-www-form-urlencoded.
+func FormURLEncoded() MediaType { return mediaFormURLEncoded }
+
+// OctetStream returns application/octet-stream.
+func OctetStream() MediaType { return mediaOctetStream }
+
+// ProblemJSON returns application/problem+json, the RFC 9457 error format.
+func ProblemJSON() MediaType { return mediaProblemJSON }
+
+// Is reports whether the Content-Type of r has one of the given essences,
+// ignoring parameters and case, so "application/json; charset=UTF-8" is
+// "application/json". An entry such as "text/*" matches any subtype. It is
+// false if the request has no valid Content-Type.
+//
+// Example:
+//
+//	if !headers.Is(r, "application/json") {
+//	    w.WriteHeader(http.StatusUnsupportedMediaType)
+//	    return
+//	}
+func Is(r *http.Request, mediaTypes ...string) bool {
+	mt, err := ParseMediaType(r.Header.Get(ContentType))
+	if err != nil {
+		return false
+	}
+	for _, want := range mediaTypes {
+		typ, subtype, _ := strings.Cut(strings.ToLower(strings.TrimSpace(want)), "/")
+		if typ == mt.Type && (subtype == mt.Subtype || subtype == "*") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package headers_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParseMediaType(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  headers.MediaType
+		wantError bool
+	}{
+		{"application/json", headers.MediaType{Type: "application", Subtype: "json"}, false},
+		{`Text/HTML; Charset="UTF-8"`, headers.MediaType{
+			Type: "text", Subtype: "html", Params: map[string]string{"charset": "UTF-8"},
+		}, false},
+		{"multipart/form-data; boundary=abc", headers.MediaType{
+			Type: "multipart", Subtype: "form-data", Params: map[string]string{"boundary": "abc"},
+		}, false},
+		{"text/*", headers.MediaType{}, true},
+		{"json", headers.MediaType{}, true},
+		{"", headers.MediaType{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := headers.ParseMediaType(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseMediaType() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseMediaType() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMediaTypeFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      headers.MediaType
+		expected string
+	}{
+		{"JSON", headers.JSON(), "application/json"},
+		{"JSONUTF8", headers.JSONUTF8(), "application/json; charset=utf-8"},
+		{"TextUTF8", headers.TextUTF8(), "text/plain; charset=utf-8"},
+		{"HTMLUTF8", headers.HTMLUTF8(), "text/html; charset=utf-8"},
+		{"FormURLEncoded", headers.FormURLEncoded(), "application/x-www-form-urlencoded"},
+		{"OctetStream", headers.OctetStream(), "application/octet-stream"},
+		{"ProblemJSON", headers.ProblemJSON(), "application/problem+json"},
+		{"quoted parameter", headers.TextUTF8().With("Format", "a b"), `text/plain; charset=utf-8; format="a b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	base := headers.JSON()
+	if withCharset := base.With("charset", "utf-8"); base.Params != nil || withCharset.Charset() != "utf-8" {
+		t.Error("With() modified the receiver")
+	}
+}
+
+func TestMediaTypeAccessors(t *testing.T) {
+	mt, _ := headers.ParseMediaType("text/csv; charset=ISO-8859-1")
+	if mt.Essence() != "text/csv" || mt.Charset() != "iso-8859-1" {
+		t.Errorf("Essence() = %q, Charset() = %q", mt.Essence(), mt.Charset())
+	}
+}
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		contentType string
+		types       []string
+		expected    bool
+	}{
+		{"application/json", []string{"application/json"}, true},
+		{"Application/JSON; charset=UTF-8", []string{"application/json"}, true},
+		{"application/json-patch+json", []string{"application/json"}, false},
+		{"text/csv", []string{"application/json", "text/*"}, true},
+		{"", []string{"application/json"}, false},
+		{"garbage", []string{"garbage"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", nil)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			if got := headers.Is(r, tt.types...); got != tt.expected {
+				t.Errorf("Is(%v) = %v, want %v", tt.types, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidOrigin is returned by NewOriginMatcher for a pattern that is not
+// a serialized origin or one of the wildcard forms it accepts.
+var ErrInvalidOrigin = errors.New("headers: invalid origin pattern")
+
+// OriginMatcher checks Origin header values against a list of allowed
+// origins, for CORS and for the Origin check of WebSocket handshakes. Create
+// one with NewOriginMatcher; it is safe for concurrent use.
+type OriginMatcher struct {
+	patterns  []originPattern
+	any       bool
+	allowNull bool
+}
+
+// originPattern is a parsed pattern. An empty port means any port.
+type originPattern struct {
+	scheme    string
+	host      string // Without the "*." of a subdomain pattern
+	port      string
+	subdomain bool
+}
+
+// NewOriginMatcher returns a matcher for the given patterns:
+//
+//   - "https://app.example.com" matches that origin only. Schemes and hosts
+//     are compared case-insensitively, and a default port may be left out or
+//     written: https://app.example.com:443 is the same origin.
+//   - "https://*.example.com" matches every subdomain of example.com, at any
+//     depth, but not example.com itself.
+//   - "http://localhost:*" matches any port, which helps with development
+//     servers.
+//   - "*" matches every origin except "null".
+//   - "null" matches the opaque origin browsers send from sandboxed frames,
+//     file: URLs and some redirects. Any page can produce it, so only allow it
+//     knowingly; "*" does not include it.
+//
+// The scheme is always enforced: http://app.example.com does not match
+// https://app.example.com. Returns an error wrapping ErrInvalidOrigin for a
+// pattern with a path, user info, or a wildcard elsewhere than as a whole
+// leading label or port.
+func NewOriginMatcher(patterns ...string) (*OriginMatcher, error) {
+	m := &OriginMatcher{}
+	for _, pattern := range patterns {
+		switch pattern {
+		case "*":
+			m.any = true
+			continue
+		case "null":
+			m.allowNull = true
+			continue
+		}
+		p, ok := parseOriginPattern(pattern, true)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidOrigin, pattern)
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m, nil
+}
+
+// parseOriginPattern parses "scheme://host[:port]", accepting the "*." host
+// prefix and ":*" port if wildcards is set.
+func parseOriginPattern(s string, wildcards bool) (originPattern, bool) {
+	scheme, hostPort, ok := strings.Cut(strings.ToLower(s), "://")
+	if !ok || scheme == "" || hostPort == "" {
+		return originPattern{}, false
+	}
+	p := originPattern{scheme: scheme}
+	anyPort := false
+	if wildcards {
+		hostPort, p.subdomain = strings.CutPrefix(hostPort, "*.")
+		hostPort, anyPort = strings.CutSuffix(hostPort, ":*")
+	}
+	u, err := url.Parse(scheme + "://" + hostPort)
+	if err != nil || u.Host != hostPort || u.Hostname() == "" || strings.Contains(u.Host, "*") ||
+		strings.HasSuffix(u.Host, ":") {
+		return originPattern{}, false
+	}
+	p.host = u.Hostname()
+	if !anyPort {
+		p.port = u.Port()
+		if p.port == "" {
+			p.port = schemePort(scheme)
+		}
+		if p.port == "" {
+			// A scheme without a default port, such as chrome-extension:
+			// only matches origins that also have none.
+			p.port = "-"
+		}
+	}
+	return p, true
+}
+
+// Match reports whether origin, an Origin header value, is allowed. An empty
+// origin, sent by clients other than browsers, never matches: decide
+// separately whether to accept requests without one.
+func (m *OriginMatcher) Match(origin string) bool {
+	if origin == "null" {
+		return m.allowNull
+	}
+	o, ok := parseOriginPattern(origin, false)
+	if !ok {
+		return false
+	}
+	if m.any {
+		return true
+	}
+	for _, p := range m.patterns {
+		if p.matches(o) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p originPattern) matches(o originPattern) bool {
+	if p.scheme != o.scheme || p.port != "" && p.port != o.port {
+		return false
+	}
+	if p.subdomain {
+		return strings.HasSuffix(o.host, "."+p.host)
+	}
+	return p.host == o.host
+}
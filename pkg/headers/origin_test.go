@@ -0,0 +1,84 @@
+package headers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestNewOriginMatcherInvalid(t *testing.T) {
+	for _, pattern := range []string{
+		"",
+		"example.com",
+		"*.example.com",
+		"https://",
+		"https://example.com/",
+		"https://example.com/path",
+		"https://user@example.com",
+		"https://a.*.example.com",
+		"https://*example.com",
+		"https://example.*",
+		"https://example.com:",
+		"https://example.com:8*",
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			m, err := headers.NewOriginMatcher(pattern)
+			if !errors.Is(err, headers.ErrInvalidOrigin) || m != nil {
+				t.Errorf("NewOriginMatcher(%q) = %v, %v, want nil, %v", pattern, m, err, headers.ErrInvalidOrigin)
+			}
+		})
+	}
+}
+
+func TestOriginMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		origin   string
+		expected bool
+	}{
+		{"exact", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"case-insensitive", []string{"https://App.Example.com"}, "HTTPS://app.example.COM", true},
+		{"other host", []string{"https://app.example.com"}, "https://api.example.com", false},
+		{"scheme enforced", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"default port written", []string{"https://app.example.com:443"}, "https://app.example.com", true},
+		{"default port sent", []string{"https://app.example.com"}, "https://app.example.com:443", true},
+		{"other port", []string{"https://app.example.com"}, "https://app.example.com:8443", false},
+		{"explicit port", []string{"http://localhost:3000"}, "http://localhost:3000", true},
+		{"explicit port mismatch", []string{"http://localhost:3000"}, "http://localhost:3001", false},
+		{"any port", []string{"http://localhost:*"}, "http://localhost:5173", true},
+		{"any port default", []string{"http://localhost:*"}, "http://localhost", true},
+		{"any port other host", []string{"http://localhost:*"}, "http://127.0.0.1:5173", false},
+		{"subdomain", []string{"https://*.example.com"}, "https://app.example.com", true},
+		{"nested subdomain", []string{"https://*.example.com"}, "https://a.b.example.com", true},
+		{"subdomain excludes apex", []string{"https://*.example.com"}, "https://example.com", false},
+		{"subdomain suffix trick", []string{"https://*.example.com"}, "https://evilexample.com", false},
+		{"subdomain lookalike", []string{"https://*.example.com"}, "https://example.com.evil.net", false},
+		{"IPv6", []string{"http://[::1]:8080"}, "http://[::1]:8080", true},
+		{"scheme without default port", []string{"chrome-extension://abcdef"}, "chrome-extension://abcdef", true},
+		{"several patterns", []string{"https://a.example", "https://b.example"}, "https://b.example", true},
+		{"wildcard", []string{"*"}, "https://anything.example", true},
+		{"wildcard excludes null", []string{"*"}, "null", false},
+		{"null not listed", []string{"https://app.example.com"}, "null", false},
+		{"null allowed", []string{"null"}, "null", true},
+		{"null pattern only", []string{"null"}, "https://app.example.com", false},
+		{"empty origin", []string{"*"}, "", false},
+		{"malformed origin", []string{"*"}, "app.example.com", false},
+		{"origin with path", []string{"https://app.example.com"}, "https://app.example.com/", false},
+		{"origin with wildcard", []string{"https://*.example.com"}, "https://*.example.com", false},
+		{"no patterns", nil, "https://app.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := headers.NewOriginMatcher(tt.patterns...)
+			if err != nil {
+				t.Fatalf("NewOriginMatcher(%q) error = %v", tt.patterns, err)
+			}
+			if got := m.Match(tt.origin); got != tt.expected {
+				t.Errorf("Match(%q) = %v, want %v", tt.origin, got, tt.expected)
+			}
+		})
+	}
+}
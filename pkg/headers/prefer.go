@@ -0,0 +1,195 @@
+package headers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values of the return and handling preferences of RFC 7240.
+const (
+	PreferReturnMinimal        = "minimal"
+	PreferReturnRepresentation = "representation"
+	PreferHandlingStrict       = "strict"
+	PreferHandlingLenient      = "lenient"
+)
+
+// Preference is one preference of a Prefer header, or one applied preference
+// of a Preference-Applied header.
+type Preference struct {
+	Name   string            // Lower case, such as "return"
+	Value  string            // Unquoted, "" if none
+	Params map[string]string // Lower-case names, nil if none; not sent in Preference-Applied
+}
+
+// String formats p as written in a Preference-Applied header, "name" or
+// "name=value", quoting the value if needed.
+func (p Preference) String() string {
+	if p.Value == "" {
+		return p.Name
+	}
+	return p.Name + "=" + quoteIfNeeded(p.Value)
+}
+
+// Preferences holds the preferences of the Prefer headers of a request, as
+// specified by RFC 7240.
+type Preferences struct {
+	Return       string        // PreferReturnMinimal, PreferReturnRepresentation or ""
+	Wait         time.Duration // How long the client is willing to wait, in whole seconds; 0 if not given
+	RespondAsync bool          // The client prefers 202 Accepted to waiting
+	Handling     string        // PreferHandlingStrict, PreferHandlingLenient or ""
+	Other        []Preference  // Preferences not listed above, in order
+}
+
+// ParsePrefer parses Prefer header values such as
+// "return=minimal, wait=10, respond-async". Preference names are
+// case-insensitive, and only the first instance of a preference counts, as
+// RFC 7240, section 2, requires. Malformed preferences, and return or
+// handling values other than those defined, are ignored, so a missing header
+// yields the zero value.
+//
+// Example:
+//
+//	prefs := headers.ParsePrefer(r.Header.Values(headers.Prefer)...)
+//	if prefs.RespondAsync {
+//	    id := jobs.Start(r)
+//	    headers.SetPreferenceApplied(w, headers.Preference{Name: "respond-async"})
+//	    w.Header().Set(headers.Location, "/jobs/"+id)
+//	    w.WriteHeader(http.StatusAccepted)
+//	    return
+//	}
+func ParsePrefer(values ...string) Preferences {
+	var prefs Preferences
+	seen := make(map[string]bool)
+	for _, value := range values {
+		p := authParser{s: value}
+		for {
+			p.skipListSeparators()
+			if p.done() {
+				break
+			}
+			pref, ok := p.preference()
+			if !ok {
+				p.skipElement()
+				continue
+			}
+			if !seen[pref.Name] {
+				seen[pref.Name] = true
+				prefs.set(pref)
+			}
+		}
+	}
+	return prefs
+}
+
+// set stores a preference seen for the first time.
+func (prefs *Preferences) set(pref Preference) {
+	value := strings.ToLower(pref.Value)
+	switch pref.Name {
+	case "return":
+		if value == PreferReturnMinimal || value == PreferReturnRepresentation {
+			prefs.Return = value
+		}
+	case "wait":
+		if seconds, err := strconv.ParseUint(value, 10, 64); err == nil {
+			prefs.Wait = time.Duration(min(seconds, uint64(math.MaxInt64/time.Second))) * time.Second
+		}
+	case "respond-async":
+		prefs.RespondAsync = true
+	case "handling":
+		if value == PreferHandlingStrict || value == PreferHandlingLenient {
+			prefs.Handling = value
+		}
+	default:
+		prefs.Other = append(prefs.Other, pref)
+	}
+}
+
+// preference parses one preference with its parameters, up to the next
+// comma or the end.
+func (p *authParser) preference() (Preference, bool) {
+	var pref Preference
+	var ok bool
+	if pref.Name, pref.Value, ok = p.preferenceParam(); !ok {
+		return Preference{}, false
+	}
+	for {
+		p.ows()
+		if p.done() || p.peek() == ',' {
+			return pref, true
+		}
+		if p.peek() != ';' {
+			return Preference{}, false
+		}
+		p.i++
+		p.ows()
+		if p.done() || p.peek() == ',' || p.peek() == ';' {
+			continue
+		}
+		name, value, ok := p.preferenceParam()
+		if !ok {
+			return Preference{}, false
+		}
+		if pref.Params == nil {
+			pref.Params = make(map[string]string)
+		}
+		if _, dup := pref.Params[name]; !dup {
+			pref.Params[name] = value
+		}
+	}
+}
+
+// preferenceParam parses token [ BWS "=" BWS word ], lower-casing the token.
+func (p *authParser) preferenceParam() (name, value string, ok bool) {
+	name = strings.ToLower(p.token())
+	if name == "" {
+		return "", "", false
+	}
+	p.ows()
+	if p.done() || p.peek() != '=' {
+		return name, "", true
+	}
+	p.i++
+	p.ows()
+	if !p.done() && p.peek() == '"' {
+		value, ok = p.quoted()
+		return name, value, ok
+	}
+	return name, p.token(), true
+}
+
+// skipElement moves past the rest of a malformed list element, up to the
+// next comma outside a quoted string.
+func (p *authParser) skipElement() {
+	for !p.done() && p.peek() != ',' {
+		if p.peek() == '"' {
+			p.quoted()
+			continue
+		}
+		p.i++
+	}
+}
+
+// SetPreferenceApplied sets the Preference-Applied header of w to the
+// preferences the server honored, and adds Prefer to its Vary header, as
+// responses that honor preferences depend on them. Parameters are not sent,
+// and an empty list only sets Vary.
+//
+// Example:
+//
+//	headers.SetPreferenceApplied(w, headers.Preference{Name: "return", Value: headers.PreferReturnMinimal})
+//	w.WriteHeader(http.StatusNoContent)
+func SetPreferenceApplied(w http.ResponseWriter, applied ...Preference) {
+	h := w.Header()
+	AddVary(h, Prefer)
+	if len(applied) == 0 {
+		return
+	}
+	names := make([]string, len(applied))
+	for i, pref := range applied {
+		names[i] = pref.String()
+	}
+	h.Set(PreferenceApplied, strings.Join(names, ", "))
+}
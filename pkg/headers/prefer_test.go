@@ -0,0 +1,129 @@
+package headers_test
+
+import (
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParsePrefer(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected headers.Preferences
+	}{
+		{
+			name:     "return",
+			values:   []string{"return=minimal"},
+			expected: headers.Preferences{Return: headers.PreferReturnMinimal},
+		},
+		{
+			name:   "all defined",
+			values: []string{"return=representation, wait=10, respond-async, handling=lenient"},
+			expected: headers.Preferences{
+				Return:       headers.PreferReturnRepresentation,
+				Wait:         10 * time.Second,
+				RespondAsync: true,
+				Handling:     headers.PreferHandlingLenient,
+			},
+		},
+		{
+			name:     "repeated headers",
+			values:   []string{"respond-async", "wait=100"},
+			expected: headers.Preferences{RespondAsync: true, Wait: 100 * time.Second},
+		},
+		{
+			name:     "case and spacing",
+			values:   []string{" RETURN = Minimal ,Handling=STRICT"},
+			expected: headers.Preferences{Return: headers.PreferReturnMinimal, Handling: headers.PreferHandlingStrict},
+		},
+		{
+			name:     "quoted",
+			values:   []string{`return="minimal", wait="5"`},
+			expected: headers.Preferences{Return: headers.PreferReturnMinimal, Wait: 5 * time.Second},
+		},
+		{
+			name:     "first wins",
+			values:   []string{"return=minimal, return=representation", "return=representation"},
+			expected: headers.Preferences{Return: headers.PreferReturnMinimal},
+		},
+		{
+			name:     "invalid value of first instance",
+			values:   []string{"return=everything, return=minimal, wait=-1, wait=5"},
+			expected: headers.Preferences{},
+		},
+		{
+			name:     "huge wait",
+			values:   []string{"wait=99999999999999999999"},
+			expected: headers.Preferences{},
+		},
+		{
+			name:     "capped wait",
+			values:   []string{"wait=9999999999999"},
+			expected: headers.Preferences{Wait: time.Duration(math.MaxInt64/time.Second) * time.Second},
+		},
+		{
+			name:   "other preferences",
+			values: []string{`foo; bar="a, b";baz, lang=en;;q=1, respond-async`},
+			expected: headers.Preferences{
+				RespondAsync: true,
+				Other: []headers.Preference{
+					{Name: "foo", Params: map[string]string{"bar": "a, b", "baz": ""}},
+					{Name: "lang", Value: "en", Params: map[string]string{"q": "1"}},
+				},
+			},
+		},
+		{
+			name:     "malformed skipped",
+			values:   []string{`=x, "quoted, with comma", wait=1 2, return=minimal`},
+			expected: headers.Preferences{Return: headers.PreferReturnMinimal},
+		},
+		{
+			name:     "unterminated quote",
+			values:   []string{`return="minimal`},
+			expected: headers.Preferences{},
+		},
+		{"empty", []string{""}, headers.Preferences{}},
+		{"missing", nil, headers.Preferences{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headers.ParsePrefer(tt.values...); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParsePrefer(%q) = %+v, want %+v", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetPreferenceApplied(t *testing.T) {
+	tests := []struct {
+		name     string
+		applied  []headers.Preference
+		expected string
+	}{
+		{"one", []headers.Preference{{Name: "return", Value: headers.PreferReturnMinimal}}, "return=minimal"},
+		{"several", []headers.Preference{{Name: "respond-async"}, {Name: "wait", Value: "10"}}, "respond-async, wait=10"},
+		{"quoted value", []headers.Preference{{Name: "foo", Value: "a b"}}, `foo="a b"`},
+		{"params dropped", []headers.Preference{{Name: "foo", Params: map[string]string{"bar": "1"}}}, "foo"},
+		{"none", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set(headers.Vary, "Accept")
+			headers.SetPreferenceApplied(w, tt.applied...)
+			if got := w.Header().Get(headers.PreferenceApplied); got != tt.expected {
+				t.Errorf("Preference-Applied = %q, want %q", got, tt.expected)
+			}
+			if got := headers.VaryFields(w.Header()); !reflect.DeepEqual(got, []string{"Accept", "Prefer"}) {
+				t.Errorf("Vary = %q, want Accept and Prefer", got)
+			}
+		})
+	}
+}
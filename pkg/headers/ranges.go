@@ -0,0 +1,163 @@
+package headers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// maxRanges bounds the number of ranges ParseRange accepts in one header, so a
+// request cannot ask for thousands of tiny or overlapping parts.
+const maxRanges = 100
+
+var (
+	// ErrInvalidRange is returned by ParseRange for a Range header that is
+	// malformed or uses a unit other than bytes. RFC 9110 requires such a header
+	// to be ignored: respond 200 OK with the full content.
+	ErrInvalidRange = errors.New("headers: invalid range")
+
+	// ErrUnsatisfiableRange is returned by ParseRange when no requested range
+	// overlaps the content. Respond 416 Range Not Satisfiable with a
+	// Content-Range header from FormatUnsatisfiedRange.
+	ErrUnsatisfiableRange = errors.New("headers: range not satisfiable")
+)
+
+// ByteRange is a validated range of bytes, with both ends inclusive, as in
+// Content-Range.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// Length returns the number of bytes in the range.
+func (r ByteRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ContentRange formats r for the Content-Range header of a 206 Partial Content
+// response for content of the given size. See FormatContentRange.
+func (r ByteRange) ContentRange(size int64) string {
+	return FormatContentRange(r.Start, r.End, size)
+}
+
+// ParseRange parses a Range header value such as "bytes=0-499", "bytes=500-",
+// "bytes=-500" or "bytes=0-99,200-299" against content of size bytes, and
+// returns the requested ranges in header order, clamped to the content.
+// Ranges that start beyond the content are dropped.
+//
+// An empty header returns nil and no error: serve the full content. A
+// malformed header, one with another unit, or one with more than 100 ranges
+// returns ErrInvalidRange, which should also be answered with the full
+// content. If no range overlaps the content, ErrUnsatisfiableRange is returned
+// and the response should be 416 Range Not Satisfiable.
+//
+// Example:
+//
+//	ranges, err := headers.ParseRange(r.Header.Get(headers.Range), size)
+//	switch {
+//	case errors.Is(err, headers.ErrUnsatisfiableRange):
+//	    w.Header().Set(headers.ContentRange, headers.FormatUnsatisfiedRange(size))
+//	    w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+//	case err == nil && len(ranges) == 1:
+//	    w.Header().Set(headers.ContentRange, ranges[0].ContentRange(size))
+//	    w.WriteHeader(http.StatusPartialContent)
+//	    // copy ranges[0].Length() bytes from ranges[0].Start
+//	default:
+//	    // serve the full content
+//	}
+func ParseRange(header string, size int64) ([]ByteRange, error) {
+	header = strings.Trim(header, " \t")
+	if header == "" {
+		return nil, nil
+	}
+	unit, set, ok := strings.Cut(header, "=")
+	if !ok || !strings.EqualFold(strings.TrimRight(unit, " \t"), "bytes") {
+		return nil, ErrInvalidRange
+	}
+
+	specs := strings.Split(set, ",")
+	if len(specs) > maxRanges {
+		return nil, ErrInvalidRange
+	}
+
+	var ranges []ByteRange
+	valid := false
+	for _, spec := range specs {
+		spec = strings.Trim(spec, " \t")
+		if spec == "" {
+			continue // empty list elements are allowed
+		}
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, ErrInvalidRange
+		}
+
+		if first == "" {
+			// Suffix range: the last n bytes.
+			n, ok := parseRangeInt(last)
+			if !ok {
+				return nil, ErrInvalidRange
+			}
+			valid = true
+			if n == 0 || size <= 0 {
+				continue
+			}
+			ranges = append(ranges, ByteRange{Start: max(size-n, 0), End: size - 1})
+			continue
+		}
+
+		start, ok := parseRangeInt(first)
+		if !ok {
+			return nil, ErrInvalidRange
+		}
+		end := int64(-1)
+		if last != "" {
+			if end, ok = parseRangeInt(last); !ok || end < start {
+				return nil, ErrInvalidRange
+			}
+		}
+		valid = true
+		if start >= size {
+			continue
+		}
+		if end < 0 || end >= size {
+			end = size - 1
+		}
+		ranges = append(ranges, ByteRange{Start: start, End: end})
+	}
+
+	switch {
+	case !valid:
+		return nil, ErrInvalidRange
+	case len(ranges) == 0:
+		return nil, ErrUnsatisfiableRange
+	}
+	return ranges, nil
+}
+
+// FormatContentRange formats the Content-Range header value for the bytes
+// start through end, inclusive, of content of the given size, such as
+// "bytes 0-499/1234". A negative size is written as "*", for content whose
+// length is not known yet.
+func FormatContentRange(start, end, size int64) string {
+	total := "*"
+	if size >= 0 {
+		total = strconv.FormatInt(size, 10)
+	}
+	return "bytes " + strconv.FormatInt(start, 10) + "-" + strconv.FormatInt(end, 10) + "/" + total
+}
+
+// FormatUnsatisfiedRange formats the Content-Range header value of a 416 Range
+// Not Satisfiable response for content of the given size, such as "bytes */1234".
+func FormatUnsatisfiedRange(size int64) string {
+	return "bytes */" + strconv.FormatInt(size, 10)
+}
+
+// parseRangeInt parses a non-empty string of ASCII digits.
+func parseRangeInt(s string) (int64, bool) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
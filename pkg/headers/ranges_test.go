@@ -0,0 +1,71 @@
+package headers_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		size     int64
+		expected []headers.ByteRange
+		err      error
+	}{
+		{"empty", "", 1000, nil, nil},
+		{"first bytes", "bytes=0-499", 1000, []headers.ByteRange{{0, 499}}, nil},
+		{"open ended", "bytes=500-", 1000, []headers.ByteRange{{500, 999}}, nil},
+		{"suffix", "bytes=-200", 1000, []headers.ByteRange{{800, 999}}, nil},
+		{"suffix larger than content", "bytes=-2000", 1000, []headers.ByteRange{{0, 999}}, nil},
+		{"end clamped", "bytes=900-5000", 1000, []headers.ByteRange{{900, 999}}, nil},
+		{"multiple", "bytes=0-99, 200-299,,-1", 1000, []headers.ByteRange{{0, 99}, {200, 299}, {999, 999}}, nil},
+		{"unit case", "Bytes=0-0", 10, []headers.ByteRange{{0, 0}}, nil},
+		{"unsatisfiable dropped", "bytes=0-9,5000-6000", 1000, []headers.ByteRange{{0, 9}}, nil},
+		{"unsatisfiable", "bytes=1000-", 1000, nil, headers.ErrUnsatisfiableRange},
+		{"zero suffix", "bytes=-0", 1000, nil, headers.ErrUnsatisfiableRange},
+		{"empty content", "bytes=0-", 0, nil, headers.ErrUnsatisfiableRange},
+		{"other unit", "items=0-9", 1000, nil, headers.ErrInvalidRange},
+		{"no equals", "bytes 0-9", 1000, nil, headers.ErrInvalidRange},
+		{"reversed", "bytes=500-100", 1000, nil, headers.ErrInvalidRange},
+		{"no dash", "bytes=100", 1000, nil, headers.ErrInvalidRange},
+		{"signed", "bytes=+1-5", 1000, nil, headers.ErrInvalidRange},
+		{"dash only", "bytes=-", 1000, nil, headers.ErrInvalidRange},
+		{"no ranges", "bytes=,", 1000, nil, headers.ErrInvalidRange},
+		{"overflow", "bytes=0-99999999999999999999", 1000, nil, headers.ErrInvalidRange},
+		{"too many", "bytes=" + strings.Repeat("0-0,", 101), 1000, nil, headers.ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseRange(tt.header, tt.size)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseRange() error = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseRange() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatContentRange(t *testing.T) {
+	if got := headers.FormatContentRange(0, 499, 1234); got != "bytes 0-499/1234" {
+		t.Errorf("FormatContentRange() = %q", got)
+	}
+	if got := headers.FormatContentRange(0, 499, -1); got != "bytes 0-499/*" {
+		t.Errorf("FormatContentRange(unknown size) = %q", got)
+	}
+	if got := headers.FormatUnsatisfiedRange(1234); got != "bytes */1234" {
+		t.Errorf("FormatUnsatisfiedRange() = %q", got)
+	}
+
+	r := headers.ByteRange{Start: 500, End: 999}
+	if r.Length() != 500 || r.ContentRange(1000) != "bytes 500-999/1000" {
+		t.Errorf("ByteRange = %d bytes, %q", r.Length(), r.ContentRange(1000))
+	}
+}
@@ -0,0 +1,191 @@
+package headers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// RateLimitState is the rate limit a server advertises in a response, for
+// clients to pace their requests.
+type RateLimitState struct {
+	Policy    string        // Name of the policy, with the RateLimit field only
+	Limit     int64         // Quota of the window, 0 if not advertised
+	Remaining int64         // Quota left in the window
+	Reset     time.Duration // Time until the window resets, 0 if not advertised
+}
+
+// Exhausted reports whether the quota is used up, in which case the client
+// should wait for s.Reset before sending another request.
+func (s RateLimitState) Exhausted() bool {
+	return s.Remaining <= 0
+}
+
+// SetRateLimit sets the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers of w, structured field integers as in the IETF
+// RateLimit fields draft: the quota of the current window, what is left of
+// it, and the seconds until it resets at resetAt, rounded up like
+// SetRetryAfter. Negative counts are written as 0.
+//
+// Example:
+//
+//	allowed, remaining, resetAt := limiter.Take(clientIP)
+//	headers.SetRateLimit(w, 100, remaining, resetAt)
+//	if !allowed {
+//	    headers.SetRetryAfter(w, time.Until(resetAt))
+//	    http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+//	    return
+//	}
+func SetRateLimit(w http.ResponseWriter, limit, remaining int64, resetAt time.Time) {
+	h := w.Header()
+	h.Set(RateLimitLimit, strconv.FormatInt(max(limit, 0), 10))
+	h.Set(RateLimitRemaining, strconv.FormatInt(max(remaining, 0), 10))
+	h.Set(RateLimitReset, strconv.FormatInt(ceilSeconds(time.Until(resetAt)), 10))
+}
+
+// unixResetThreshold tells Unix times from numbers of seconds in
+// X-RateLimit-Reset: no window lasts 30 years, and no reset is due before
+// 2001.
+const unixResetThreshold = 1_000_000_000
+
+// ParseRateLimit returns the rate limit advertised by the headers of a
+// response, read from the first of these that is present and well formed:
+//
+//   - the RateLimit field of the current IETF draft, such as
+//     `"default";r=50;t=30`, with the quota of its policy in
+//     RateLimit-Policy; of several policies, the one with the least quota
+//     left counts
+//   - the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset fields of
+//     earlier drafts
+//   - the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+//     fields many APIs still send, whose reset is taken as a Unix time if it
+//     is large enough to be one, and as a number of seconds otherwise
+//
+// Resets are counted from now. ok is false if no remaining quota is found.
+//
+// Example:
+//
+//	if state, ok := headers.ParseRateLimit(resp.Header, time.Now()); ok && state.Exhausted() {
+//	    time.Sleep(state.Reset)
+//	}
+func ParseRateLimit(h http.Header, now time.Time) (state RateLimitState, ok bool) {
+	if state, ok = parseRateLimitField(h); ok {
+		return state, true
+	}
+	if state, ok = parseRateLimitFields(h, RateLimitLimit, RateLimitRemaining, RateLimitReset, now, false); ok {
+		return state, true
+	}
+	return parseRateLimitFields(h, XRateLimitLimit, XRateLimitRemaining, XRateLimitReset, now, true)
+}
+
+// parseRateLimitField reads the RateLimit and RateLimit-Policy fields.
+func parseRateLimitField(h http.Header) (RateLimitState, bool) {
+	var state RateLimitState
+	found := false
+	for _, item := range listItems(h.Values(RateLimit)) {
+		name, ok := item.Value.(string)
+		remaining, ok2 := nonNegativeInteger(item.Params.Get("r"))
+		if !ok || !ok2 || found && remaining >= state.Remaining {
+			continue
+		}
+		state = RateLimitState{Policy: name, Remaining: remaining}
+		if t, ok := nonNegativeInteger(item.Params.Get("t")); ok {
+			state.Reset = secondsDuration(t)
+		}
+		found = true
+	}
+	if !found {
+		return RateLimitState{}, false
+	}
+	for _, item := range listItems(h.Values(RateLimitPolicy)) {
+		if name, ok := item.Value.(string); ok && name == state.Policy {
+			if q, ok := nonNegativeInteger(item.Params.Get("q")); ok {
+				state.Limit = q
+			}
+			break
+		}
+	}
+	return state, true
+}
+
+// parseRateLimitFields reads a set of separate limit, remaining and reset
+// fields.
+func parseRateLimitFields(h http.Header, limit, remaining, reset string, now time.Time, unixReset bool) (RateLimitState, bool) {
+	var state RateLimitState
+	var ok bool
+	if state.Remaining, ok = firstInteger(h.Values(remaining)); !ok {
+		return RateLimitState{}, false
+	}
+	state.Limit, _ = firstInteger(h.Values(limit))
+	if n, ok := firstInteger(h.Values(reset)); ok {
+		if unixReset && n >= unixResetThreshold {
+			state.Reset = max(time.Unix(n, 0).Sub(now), 0)
+		} else {
+			state.Reset = secondsDuration(n)
+		}
+	}
+	return state, true
+}
+
+// firstInteger returns the first member of a list field if it is a
+// non-negative integer, such as 100 in "100, 100;w=60", the form the earlier
+// drafts allowed for RateLimit-Limit.
+func firstInteger(values []string) (int64, bool) {
+	items := listItems(values)
+	if len(items) == 0 {
+		return 0, false
+	}
+	return nonNegativeInteger(items[0].Value, true)
+}
+
+// listItems parses values as a structured field list, keeping only its
+// items. It returns nil if the list is malformed.
+func listItems(values []string) []sfv.Item {
+	if len(values) == 0 {
+		return nil
+	}
+	list, err := sfv.ParseList(strings.Join(values, ", "))
+	if err != nil {
+		return nil
+	}
+	var items []sfv.Item
+	for _, m := range list {
+		if item, ok := m.(sfv.Item); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// nonNegativeInteger returns v if it is a non-negative integer. It takes the
+// results of sfv.Params.Get.
+func nonNegativeInteger(v any, ok bool) (int64, bool) {
+	n, isInt := v.(int64)
+	if !ok || !isInt || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// secondsDuration converts a number of seconds to a duration, capped at the
+// longest one.
+func secondsDuration(seconds int64) time.Duration {
+	if seconds > int64(math.MaxInt64/time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ceilSeconds returns d in whole seconds, rounded up so that clients never
+// retry early. Negative durations yield 0.
+func ceilSeconds(d time.Duration) int64 {
+	seconds := int64(max(d, 0) / time.Second)
+	if d > 0 && d%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
@@ -0,0 +1,152 @@
+package headers_test
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestSetRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int64
+		remaining int64
+		reset     time.Duration
+		expected  [3]string
+	}{
+		{"quota left", 100, 42, 30 * time.Second, [3]string{"100", "42", "30"}},
+		{"rounded up", 100, 0, 1500 * time.Millisecond, [3]string{"100", "0", "2"}},
+		{"negative", -1, -5, -time.Minute, [3]string{"0", "0", "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			headers.SetRateLimit(w, tt.limit, tt.remaining, time.Now().Add(tt.reset))
+			got := [3]string{
+				w.Header().Get(headers.RateLimitLimit),
+				w.Header().Get(headers.RateLimitRemaining),
+				w.Header().Get(headers.RateLimitReset),
+			}
+			if got != tt.expected {
+				t.Errorf("SetRateLimit() headers = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected headers.RateLimitState
+		ok       bool
+	}{
+		{
+			name: "rate limit field",
+			header: http.Header{
+				headers.RateLimit:       {`"default";r=50;t=30`},
+				headers.RateLimitPolicy: {`"burst";q=10;w=1, "default";q=100;w=60`},
+			},
+			expected: headers.RateLimitState{Policy: "default", Limit: 100, Remaining: 50, Reset: 30 * time.Second},
+			ok:       true,
+		},
+		{
+			name: "least quota left of several policies",
+			header: http.Header{
+				headers.RateLimit: {`"day";r=900;t=3600`, `"minute";r=3;t=20`},
+			},
+			expected: headers.RateLimitState{Policy: "minute", Remaining: 3, Reset: 20 * time.Second},
+			ok:       true,
+		},
+		{
+			name: "separate fields",
+			header: http.Header{
+				headers.RateLimitLimit:     {"100, 100;w=60"},
+				headers.RateLimitRemaining: {"0"},
+				headers.RateLimitReset:     {"45"},
+			},
+			expected: headers.RateLimitState{Limit: 100, Remaining: 0, Reset: 45 * time.Second},
+			ok:       true,
+		},
+		{
+			name: "malformed rate limit field falls back",
+			header: http.Header{
+				headers.RateLimit:          {`"default";t=30`},
+				headers.RateLimitRemaining: {"7"},
+			},
+			expected: headers.RateLimitState{Remaining: 7},
+			ok:       true,
+		},
+		{
+			name: "legacy unix reset",
+			header: http.Header{
+				headers.XRateLimitLimit:     {"5000"},
+				headers.XRateLimitRemaining: {"4999"},
+				headers.XRateLimitReset:     {"1709294460"},
+			},
+			expected: headers.RateLimitState{Limit: 5000, Remaining: 4999, Reset: time.Minute},
+			ok:       true,
+		},
+		{
+			name: "legacy seconds reset",
+			header: http.Header{
+				headers.XRateLimitRemaining: {"10"},
+				headers.XRateLimitReset:     {"90"},
+			},
+			expected: headers.RateLimitState{Remaining: 10, Reset: 90 * time.Second},
+			ok:       true,
+		},
+		{
+			name: "legacy reset in the past",
+			header: http.Header{
+				headers.XRateLimitRemaining: {"0"},
+				headers.XRateLimitReset:     {"1709290000"},
+			},
+			expected: headers.RateLimitState{},
+			ok:       true,
+		},
+		{
+			name: "huge reset",
+			header: http.Header{
+				headers.RateLimitRemaining: {"1"},
+				headers.RateLimitReset:     {"999999999999999"},
+			},
+			expected: headers.RateLimitState{Remaining: 1, Reset: time.Duration(math.MaxInt64)},
+			ok:       true,
+		},
+		{"negative remaining", http.Header{headers.RateLimitRemaining: {"-1"}}, headers.RateLimitState{}, false},
+		{"garbage", http.Header{headers.XRateLimitRemaining: {"lots"}}, headers.RateLimitState{}, false},
+		{"none", http.Header{}, headers.RateLimitState{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := make(http.Header)
+			for name, values := range tt.header { // canonicalizes names such as RateLimit
+				for _, v := range values {
+					h.Add(name, v)
+				}
+			}
+			got, ok := headers.ParseRateLimit(h, now)
+			if ok != tt.ok || got != tt.expected {
+				t.Errorf("ParseRateLimit() = %+v, %v, want %+v, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRateLimitStateExhausted(t *testing.T) {
+	if !(headers.RateLimitState{Remaining: 0}).Exhausted() {
+		t.Error("Exhausted() = false with no quota left, want true")
+	}
+	if (headers.RateLimitState{Remaining: 1}).Exhausted() {
+		t.Error("Exhausted() = true with quota left, want false")
+	}
+}
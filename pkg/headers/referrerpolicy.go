@@ -0,0 +1,89 @@
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidReferrerPolicy is returned for a Referrer-Policy value that is not
+// one of the ReferrerPolicyValue constants, or for a list that repeats one.
+var ErrInvalidReferrerPolicy = errors.New("headers: invalid Referrer-Policy header")
+
+// ReferrerPolicyValue is a policy of the Referrer-Policy header, from the W3C
+// Referrer Policy specification, section 3.
+type ReferrerPolicyValue string
+
+// Referrer policies, from most to least restrictive as a rough guide.
+// ReferrerPolicyStrictOriginWhenCrossOrigin is the browsers' default and a
+// good choice for most sites.
+const (
+	ReferrerPolicyNoReferrer                  ReferrerPolicyValue = "no-referrer"
+	ReferrerPolicySameOrigin                  ReferrerPolicyValue = "same-origin"
+	ReferrerPolicyStrictOrigin                ReferrerPolicyValue = "strict-origin"
+	ReferrerPolicyOrigin                      ReferrerPolicyValue = "origin"
+	ReferrerPolicyStrictOriginWhenCrossOrigin ReferrerPolicyValue = "strict-origin-when-cross-origin"
+	ReferrerPolicyOriginWhenCrossOrigin       ReferrerPolicyValue = "origin-when-cross-origin"
+	ReferrerPolicyNoReferrerWhenDowngrade     ReferrerPolicyValue = "no-referrer-when-downgrade"
+	ReferrerPolicyUnsafeURL                   ReferrerPolicyValue = "unsafe-url"
+)
+
+var referrerPolicies = []ReferrerPolicyValue{
+	ReferrerPolicyNoReferrer, ReferrerPolicySameOrigin, ReferrerPolicyStrictOrigin,
+	ReferrerPolicyOrigin, ReferrerPolicyStrictOriginWhenCrossOrigin,
+	ReferrerPolicyOriginWhenCrossOrigin, ReferrerPolicyNoReferrerWhenDowngrade,
+	ReferrerPolicyUnsafeURL,
+}
+
+// Valid reports whether v is one of the ReferrerPolicyValue constants.
+func (v ReferrerPolicyValue) Valid() bool {
+	return slices.Contains(referrerPolicies, v)
+}
+
+// SetReferrerPolicy sets the Referrer-Policy header of w. Several policies
+// form a fallback list: browsers apply the last one they support, so newer
+// policies go last, after the ones older browsers should use instead:
+//
+//	err := headers.SetReferrerPolicy(w, headers.ReferrerPolicyNoReferrer,
+//	    headers.ReferrerPolicyStrictOriginWhenCrossOrigin)
+//
+// Returns an error wrapping ErrInvalidReferrerPolicy, and leaves w unchanged,
+// if no policy is given, if one is not a ReferrerPolicyValue constant, or if
+// one is repeated.
+func SetReferrerPolicy(w http.ResponseWriter, policies ...ReferrerPolicyValue) error {
+	if len(policies) == 0 {
+		return fmt.Errorf("%w: no policy", ErrInvalidReferrerPolicy)
+	}
+	names := make([]string, len(policies))
+	for i, v := range policies {
+		if !v.Valid() {
+			return fmt.Errorf("%w: unknown policy %q", ErrInvalidReferrerPolicy, string(v))
+		}
+		if slices.Contains(policies[:i], v) {
+			return fmt.Errorf("%w: %q listed twice", ErrInvalidReferrerPolicy, string(v))
+		}
+		names[i] = string(v)
+	}
+	w.Header().Set(ReferrerPolicy, strings.Join(names, ", "))
+	return nil
+}
+
+// ParseReferrerPolicy returns the policy a browser applies for a
+// Referrer-Policy value: the last policy in the comma-separated list that it
+// recognizes, compared case-insensitively. Repeated headers are joined into
+// one list first. Returns "" if there is none, in which case the browser
+// falls back to its default, ReferrerPolicyStrictOriginWhenCrossOrigin.
+func ParseReferrerPolicy(values ...string) ReferrerPolicyValue {
+	var policy ReferrerPolicyValue
+	for _, value := range values {
+		for token := range strings.SplitSeq(value, ",") {
+			v := ReferrerPolicyValue(strings.ToLower(strings.TrimSpace(token)))
+			if v.Valid() {
+				policy = v
+			}
+		}
+	}
+	return policy
+}
@@ -0,0 +1,62 @@
+package headers_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestSetReferrerPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []headers.ReferrerPolicyValue
+		expected string
+		err      error
+	}{
+		{"single", []headers.ReferrerPolicyValue{headers.ReferrerPolicyStrictOriginWhenCrossOrigin}, "strict-origin-when-cross-origin", nil},
+		{"fallback list", []headers.ReferrerPolicyValue{headers.ReferrerPolicyNoReferrer, headers.ReferrerPolicyStrictOrigin}, "no-referrer, strict-origin", nil},
+		{"none", nil, "", headers.ErrInvalidReferrerPolicy},
+		{"unknown", []headers.ReferrerPolicyValue{"never"}, "", headers.ErrInvalidReferrerPolicy},
+		{"wrong case", []headers.ReferrerPolicyValue{"No-Referrer"}, "", headers.ErrInvalidReferrerPolicy},
+		{"repeated", []headers.ReferrerPolicyValue{headers.ReferrerPolicyOrigin, headers.ReferrerPolicyOrigin}, "", headers.ErrInvalidReferrerPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := headers.SetReferrerPolicy(w, tt.policies...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SetReferrerPolicy() error = %v, want %v", err, tt.err)
+			}
+			if got := w.Header().Get(headers.ReferrerPolicy); got != tt.expected {
+				t.Errorf("Referrer-Policy = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseReferrerPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected headers.ReferrerPolicyValue
+	}{
+		{"single", []string{"no-referrer"}, headers.ReferrerPolicyNoReferrer},
+		{"last wins", []string{"no-referrer, strict-origin-when-cross-origin"}, headers.ReferrerPolicyStrictOriginWhenCrossOrigin},
+		{"unknown skipped", []string{"origin, future-policy"}, headers.ReferrerPolicyOrigin},
+		{"case and spacing", []string{"  Unsafe-URL "}, headers.ReferrerPolicyUnsafeURL},
+		{"repeated headers", []string{"origin", "same-origin"}, headers.ReferrerPolicySameOrigin},
+		{"empty", []string{""}, ""},
+		{"missing", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headers.ParseReferrerPolicy(tt.values...); got != tt.expected {
+				t.Errorf("ParseReferrerPolicy(%q) = %q, want %q", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
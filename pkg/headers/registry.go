@@ -0,0 +1,326 @@
+package headers
+
+import (
+	"iter"
+	"strings"
+)
+
+// Category groups related headers, following the sections of the MDN header
+// reference.
+type Category string
+
+// Header categories.
+const (
+	CategoryAuthentication  Category = "Authentication"
+	CategoryCaching         Category = "Caching"
+	CategoryConditionals    Category = "Conditionals"
+	CategoryConnection      Category = "Connection Management"
+	CategoryNegotiation     Category = "Content Negotiation"
+	CategoryControls        Category = "Controls"
+	CategoryCookies         Category = "Cookies"
+	CategoryCORS            Category = "CORS"
+	CategoryDownloads       Category = "Downloads"
+	CategoryDigests         Category = "Integrity Digests"
+	CategoryBody            Category = "Message Body Information"
+	CategoryPreferences     Category = "Preferences"
+	CategoryProxies         Category = "Proxies"
+	CategoryRanges          Category = "Range Requests"
+	CategoryRedirects       Category = "Redirects"
+	CategoryRequestContext  Category = "Request Context"
+	CategoryResponseContext Category = "Response Context"
+	CategorySecurity        Category = "Security"
+	CategoryFetchMetadata   Category = "Fetch Metadata"
+	CategoryStorageAccess   Category = "Fetch Storage Access"
+	CategoryReporting       Category = "Reporting"
+	CategoryTransfer        Category = "Transfer Coding"
+	CategoryWebSockets      Category = "WebSockets"
+	CategoryClientHints     Category = "Client Hints"
+	CategoryDictionary      Category = "Compression Dictionary Transport"
+	CategoryPrivacy         Category = "Privacy"
+	CategoryNonStandard     Category = "Non-standard"
+	CategoryOther           Category = "Other"
+)
+
+// Usage tells whether a header is sent in requests, responses, or both.
+type Usage uint8
+
+// Header usages. UsageBoth is UsageRequest|UsageResponse, so a usage can be
+// tested with u&UsageRequest != 0.
+const (
+	UsageRequest Usage = 1 << iota
+	UsageResponse
+	UsageBoth = UsageRequest | UsageResponse
+)
+
+// String returns "request", "response" or "both".
+func (u Usage) String() string {
+	switch u {
+	case UsageRequest:
+		return "request"
+	case UsageResponse:
+		return "response"
+	case UsageBoth:
+		return "both"
+	}
+	return "unknown"
+}
+
+// Header describes a header in the catalog.
+type Header struct {
+	Name       string   // Documented form of the name, as in the constants, such as "ETag"
+	Category   Category // Group the header belongs to
+	Usage      Usage    // Whether the header is sent in requests, responses or both
+	Deprecated bool     // Whether the header is deprecated or obsolete
+	Spec       string   // Defining specification, such as "RFC 9110, Section 8.3"; "" if none
+}
+
+// catalog lists every header constant of the package, in declaration order.
+var catalog = []Header{
+	{Authorization, CategoryAuthentication, UsageRequest, false, "RFC 9110, Section 11.6.2"},
+	{ProxyAuthorization, CategoryAuthentication, UsageRequest, false, "RFC 9110, Section 11.7.2"},
+	{WWWAuthenticate, CategoryAuthentication, UsageResponse, false, "RFC 9110, Section 11.6.1"},
+	{ProxyAuthenticate, CategoryAuthentication, UsageResponse, false, "RFC 9110, Section 11.7.1"},
+
+	{Age, CategoryCaching, UsageResponse, false, "RFC 9111, Section 5.1"},
+	{CacheControl, CategoryCaching, UsageBoth, false, "RFC 9111, Section 5.2"},
+	{ClearSiteData, CategoryCaching, UsageResponse, false, "W3C Clear Site Data"},
+	{Expires, CategoryCaching, UsageResponse, false, "RFC 9111, Section 5.3"},
+	{NoVarySearch, CategoryCaching, UsageResponse, false, "WICG No-Vary-Search"},
+
+	{ETag, CategoryConditionals, UsageResponse, false, "RFC 9110, Section 8.8.3"},
+	{IfMatch, CategoryConditionals, UsageRequest, false, "RFC 9110, Section 13.1.1"},
+	{IfNoneMatch, CategoryConditionals, UsageRequest, false, "RFC 9110, Section 13.1.2"},
+	{IfModifiedSince, CategoryConditionals, UsageRequest, false, "RFC 9110, Section 13.1.3"},
+	{IfUnmodifiedSince, CategoryConditionals, UsageRequest, false, "RFC 9110, Section 13.1.4"},
+	{LastModified, CategoryConditionals, UsageResponse, false, "RFC 9110, Section 8.8.2"},
+	{Vary, CategoryConditionals, UsageResponse, false, "RFC 9110, Section 12.5.5"},
+
+	{Connection, CategoryConnection, UsageBoth, false, "RFC 9110, Section 7.6.1"},
+	{KeepAlive, CategoryConnection, UsageBoth, false, "RFC 2068, Section 19.7.1.1"},
+	{ProxyConnection, CategoryConnection, UsageRequest, true, ""},
+
+	{Accept, CategoryNegotiation, UsageRequest, false, "RFC 9110, Section 12.5.1"},
+	{AcceptEncoding, CategoryNegotiation, UsageRequest, false, "RFC 9110, Section 12.5.3"},
+	{AcceptLanguage, CategoryNegotiation, UsageRequest, false, "RFC 9110, Section 12.5.4"},
+	{AcceptPatch, CategoryNegotiation, UsageResponse, false, "RFC 5789, Section 3.1"},
+	{AcceptPost, CategoryNegotiation, UsageResponse, false, "W3C Linked Data Platform 1.0"},
+
+	{Expect, CategoryControls, UsageRequest, false, "RFC 9110, Section 10.1.1"},
+	{MaxForwards, CategoryControls, UsageRequest, false, "RFC 9110, Section 7.6.2"},
+
+	{Cookie, CategoryCookies, UsageRequest, false, "RFC 6265, Section 5.4"},
+	{SetCookie, CategoryCookies, UsageResponse, false, "RFC 6265, Section 4.1"},
+
+	{AccessControlAllowCredentials, CategoryCORS, UsageResponse, false, "WHATWG Fetch"},
+	{AccessControlAllowHeaders, CategoryCORS, UsageResponse, false, "WHATWG Fetch"},
+	{AccessControlAllowMethods, CategoryCORS, UsageResponse, false, "WHATWG Fetch"},
+	{AccessControlAllowOrigin, CategoryCORS, UsageResponse, false, "WHATWG Fetch"},
+	{AccessControlAllowPrivateNetwork, CategoryCORS, UsageResponse, false, "WICG Private Network Access"},
+	{AccessControlExposeHeaders, CategoryCORS, UsageResponse, false, "WHATWG Fetch"},
+	{AccessControlMaxAge, CategoryCORS, UsageResponse, false, "WHATWG Fetch"},
+	{AccessControlRequestHeaders, CategoryCORS, UsageRequest, false, "WHATWG Fetch"},
+	{AccessControlRequestMethod, CategoryCORS, UsageRequest, false, "WHATWG Fetch"},
+	{AccessControlRequestPrivateNetwork, CategoryCORS, UsageRequest, false, "WICG Private Network Access"},
+	{Origin, CategoryCORS, UsageRequest, false, "RFC 6454, Section 7"},
+	{TimingAllowOrigin, CategoryCORS, UsageResponse, false, "W3C Resource Timing"},
+
+	{ContentDisposition, CategoryDownloads, UsageResponse, false, "RFC 6266"},
+
+	{ContentDigest, CategoryDigests, UsageBoth, false, "RFC 9530, Section 2"},
+	{ReprDigest, CategoryDigests, UsageBoth, false, "RFC 9530, Section 3"},
+	{WantContentDigest, CategoryDigests, UsageBoth, false, "RFC 9530, Section 4"},
+	{WantReprDigest, CategoryDigests, UsageBoth, false, "RFC 9530, Section 4"},
+
+	{ContentEncoding, CategoryBody, UsageBoth, false, "RFC 9110, Section 8.4"},
+	{ContentLanguage, CategoryBody, UsageBoth, false, "RFC 9110, Section 8.5"},
+	{ContentLength, CategoryBody, UsageBoth, false, "RFC 9110, Section 8.6"},
+	{ContentLocation, CategoryBody, UsageBoth, false, "RFC 9110, Section 8.7"},
+	{ContentType, CategoryBody, UsageBoth, false, "RFC 9110, Section 8.3"},
+
+	{Prefer, CategoryPreferences, UsageRequest, false, "RFC 7240, Section 2"},
+	{PreferenceApplied, CategoryPreferences, UsageResponse, false, "RFC 7240, Section 3"},
+
+	{Forwarded, CategoryProxies, UsageRequest, false, "RFC 7239, Section 4"},
+	{Via, CategoryProxies, UsageBoth, false, "RFC 9110, Section 7.6.3"},
+
+	{AcceptRanges, CategoryRanges, UsageResponse, false, "RFC 9110, Section 14.3"},
+	{ContentRange, CategoryRanges, UsageResponse, false, "RFC 9110, Section 14.4"},
+	{IfRange, CategoryRanges, UsageRequest, false, "RFC 9110, Section 13.1.5"},
+	{Range, CategoryRanges, UsageRequest, false, "RFC 9110, Section 14.2"},
+
+	{Location, CategoryRedirects, UsageResponse, false, "RFC 9110, Section 10.2.2"},
+	{Refresh, CategoryRedirects, UsageResponse, false, "WHATWG HTML"},
+
+	{From, CategoryRequestContext, UsageRequest, false, "RFC 9110, Section 10.1.2"},
+	{Host, CategoryRequestContext, UsageRequest, false, "RFC 9110, Section 7.2"},
+	{Referer, CategoryRequestContext, UsageRequest, false, "RFC 9110, Section 10.1.3"},
+	{ReferrerPolicy, CategoryRequestContext, UsageResponse, false, "W3C Referrer Policy"},
+	{UserAgent, CategoryRequestContext, UsageRequest, false, "RFC 9110, Section 10.1.5"},
+
+	{Allow, CategoryResponseContext, UsageResponse, false, "RFC 9110, Section 10.2.1"},
+	{Server, CategoryResponseContext, UsageResponse, false, "RFC 9110, Section 10.2.4"},
+
+	{ContentSecurityPolicy, CategorySecurity, UsageResponse, false, "W3C Content Security Policy Level 3"},
+	{ContentSecurityPolicyReportOnly, CategorySecurity, UsageResponse, false, "W3C Content Security Policy Level 3"},
+	{CrossOriginEmbedderPolicy, CategorySecurity, UsageResponse, false, "WHATWG HTML"},
+	{CrossOriginOpenerPolicy, CategorySecurity, UsageResponse, false, "WHATWG HTML"},
+	{CrossOriginResourcePolicy, CategorySecurity, UsageResponse, false, "WHATWG Fetch"},
+	{PermissionsPolicy, CategorySecurity, UsageResponse, false, "W3C Permissions Policy"},
+	{ReportingEndpoints, CategoryReporting, UsageResponse, false, "W3C Reporting API"},
+	{StrictTransportSecurity, CategorySecurity, UsageResponse, false, "RFC 6797"},
+	{UpgradeInsecureRequests, CategorySecurity, UsageRequest, false, "W3C Upgrade Insecure Requests"},
+	{XContentTypeOptions, CategorySecurity, UsageResponse, false, "WHATWG Fetch"},
+	{XCSRFToken, CategorySecurity, UsageRequest, false, ""},
+	{XFrameOptions, CategorySecurity, UsageResponse, false, "WHATWG HTML"},
+	{XPermittedCrossDomainPolicies, CategorySecurity, UsageResponse, false, ""},
+	{XPoweredBy, CategorySecurity, UsageResponse, false, ""},
+	{XXSSProtection, CategorySecurity, UsageResponse, true, ""},
+
+	{SecFetchDest, CategoryFetchMetadata, UsageRequest, false, "W3C Fetch Metadata Request Headers"},
+	{SecFetchMode, CategoryFetchMetadata, UsageRequest, false, "W3C Fetch Metadata Request Headers"},
+	{SecFetchSite, CategoryFetchMetadata, UsageRequest, false, "W3C Fetch Metadata Request Headers"},
+	{SecFetchUser, CategoryFetchMetadata, UsageRequest, false, "W3C Fetch Metadata Request Headers"},
+	{SecPurpose, CategoryFetchMetadata, UsageRequest, false, "WHATWG Fetch"},
+
+	{SecFetchStorageAccess, CategoryStorageAccess, UsageRequest, false, "WICG Storage Access Headers"},
+	{ActivateStorageAccess, CategoryStorageAccess, UsageResponse, false, "WICG Storage Access Headers"},
+
+	{ReportTo, CategoryReporting, UsageResponse, true, "W3C Reporting API (2018 draft)"},
+
+	{TE, CategoryTransfer, UsageRequest, false, "RFC 9110, Section 10.1.4"},
+	{Trailer, CategoryTransfer, UsageBoth, false, "RFC 9110, Section 6.6.2"},
+	{TransferEncoding, CategoryTransfer, UsageBoth, false, "RFC 9112, Section 6.1"},
+
+	{SecWebSocketAccept, CategoryWebSockets, UsageResponse, false, "RFC 6455, Section 11.3.3"},
+	{SecWebSocketExtensions, CategoryWebSockets, UsageBoth, false, "RFC 6455, Section 11.3.2"},
+	{SecWebSocketKey, CategoryWebSockets, UsageRequest, false, "RFC 6455, Section 11.3.1"},
+	{SecWebSocketProtocol, CategoryWebSockets, UsageBoth, false, "RFC 6455, Section 11.3.4"},
+	{SecWebSocketVersion, CategoryWebSockets, UsageBoth, false, "RFC 6455, Section 11.3.5"},
+
+	{AltSvc, CategoryOther, UsageResponse, false, "RFC 7838, Section 3"},
+	{AltUsed, CategoryOther, UsageRequest, false, "RFC 7838, Section 5"},
+	{Date, CategoryOther, UsageBoth, false, "RFC 9110, Section 6.6.1"},
+	{IdempotencyKey, CategoryOther, UsageRequest, false, "IETF The Idempotency-Key HTTP Header Field (draft)"},
+	{Link, CategoryOther, UsageBoth, false, "RFC 8288, Section 3"},
+	{RateLimit, CategoryOther, UsageResponse, false, "IETF RateLimit header fields for HTTP (draft)"},
+	{RateLimitLimit, CategoryOther, UsageResponse, false, "IETF RateLimit header fields for HTTP (draft)"},
+	{RateLimitPolicy, CategoryOther, UsageResponse, false, "IETF RateLimit header fields for HTTP (draft)"},
+	{RateLimitRemaining, CategoryOther, UsageResponse, false, "IETF RateLimit header fields for HTTP (draft)"},
+	{RateLimitReset, CategoryOther, UsageResponse, false, "IETF RateLimit header fields for HTTP (draft)"},
+	{RetryAfter, CategoryOther, UsageResponse, false, "RFC 9110, Section 10.2.3"},
+	{ServerTiming, CategoryOther, UsageResponse, false, "W3C Server Timing"},
+	{ServiceWorker, CategoryOther, UsageRequest, false, "W3C Service Workers"},
+	{ServiceWorkerAllowed, CategoryOther, UsageResponse, false, "W3C Service Workers"},
+	{ServiceWorkerNavigationPreload, CategoryOther, UsageRequest, false, "W3C Service Workers"},
+	{SourceMap, CategoryOther, UsageResponse, false, "ECMA-426 Source Map Format"},
+	{Traceparent, CategoryOther, UsageRequest, false, "W3C Trace Context"},
+	{Tracestate, CategoryOther, UsageRequest, false, "W3C Trace Context"},
+	{Upgrade, CategoryOther, UsageBoth, false, "RFC 9110, Section 7.8"},
+	{Priority, CategoryOther, UsageBoth, false, "RFC 9218, Section 5"},
+
+	{AcceptCH, CategoryClientHints, UsageResponse, false, "RFC 8942, Section 3.1"},
+	{CriticalCH, CategoryClientHints, UsageResponse, false, "IETF Client Hint Reliability (draft)"},
+	{SecCHUA, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAArch, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUABitness, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAFormFactors, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAFullVersion, CategoryClientHints, UsageRequest, true, "WICG User-Agent Client Hints"},
+	{SecCHUAFullVersionList, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAMobile, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAModel, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAPlatform, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAPlatformVersion, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHUAWoW64, CategoryClientHints, UsageRequest, false, "WICG User-Agent Client Hints"},
+	{SecCHPrefersColorScheme, CategoryClientHints, UsageRequest, false, "WICG User Preference Media Features Client Hints"},
+	{SecCHPrefersReducedMotion, CategoryClientHints, UsageRequest, false, "WICG User Preference Media Features Client Hints"},
+	{SecCHPrefersReducedTransparency, CategoryClientHints, UsageRequest, false, "WICG User Preference Media Features Client Hints"},
+	{SecCHDeviceMemory, CategoryClientHints, UsageRequest, false, "W3C Device Memory"},
+	{SecCHDPR, CategoryClientHints, UsageRequest, false, "WICG Responsive Image Client Hints"},
+	{SecCHViewportHeight, CategoryClientHints, UsageRequest, false, "WICG Responsive Image Client Hints"},
+	{SecCHViewportWidth, CategoryClientHints, UsageRequest, false, "WICG Responsive Image Client Hints"},
+	{Downlink, CategoryClientHints, UsageRequest, false, "WICG Network Information API"},
+	{ECT, CategoryClientHints, UsageRequest, false, "WICG Network Information API"},
+	{RTT, CategoryClientHints, UsageRequest, false, "WICG Network Information API"},
+	{SaveData, CategoryClientHints, UsageRequest, false, "WICG Save Data API"},
+
+	{AvailableDictionary, CategoryDictionary, UsageRequest, false, "IETF Compression Dictionary Transport"},
+	{DictionaryID, CategoryDictionary, UsageRequest, false, "IETF Compression Dictionary Transport"},
+	{UseAsDictionary, CategoryDictionary, UsageResponse, false, "IETF Compression Dictionary Transport"},
+
+	{DNT, CategoryPrivacy, UsageRequest, true, "W3C Tracking Preference Expression"},
+	{Tk, CategoryPrivacy, UsageResponse, true, "W3C Tracking Preference Expression"},
+	{SecGPC, CategoryPrivacy, UsageRequest, false, "Global Privacy Control"},
+
+	{XForwardedFor, CategoryNonStandard, UsageRequest, false, ""},
+	{XForwardedHost, CategoryNonStandard, UsageRequest, false, ""},
+	{XForwardedProto, CategoryNonStandard, UsageRequest, false, ""},
+	{XForwardedPort, CategoryNonStandard, UsageRequest, false, ""},
+	{XRealIP, CategoryNonStandard, UsageRequest, false, ""},
+	{XDNSPrefetchControl, CategoryNonStandard, UsageResponse, false, ""},
+	{XRobotsTag, CategoryNonStandard, UsageResponse, false, ""},
+	{XRequestID, CategoryNonStandard, UsageBoth, false, ""},
+	{B3, CategoryNonStandard, UsageRequest, false, ""},
+	{XB3TraceID, CategoryNonStandard, UsageRequest, false, ""},
+	{XB3SpanID, CategoryNonStandard, UsageRequest, false, ""},
+	{XB3ParentSpanID, CategoryNonStandard, UsageRequest, false, ""},
+	{XB3Sampled, CategoryNonStandard, UsageRequest, false, ""},
+	{XB3Flags, CategoryNonStandard, UsageRequest, false, ""},
+	{XRateLimitLimit, CategoryNonStandard, UsageResponse, false, ""},
+	{XRateLimitRemaining, CategoryNonStandard, UsageResponse, false, ""},
+	{XRateLimitReset, CategoryNonStandard, UsageResponse, false, ""},
+
+	{Pragma, CategoryCaching, UsageBoth, true, "RFC 9111, Section 5.4"},
+	{Warning, CategoryCaching, UsageBoth, true, "RFC 9111, Section 5.5"},
+}
+
+// byName indexes catalog by lower-cased name.
+var byName = func() map[string]Header {
+	m := make(map[string]Header, len(catalog))
+	for _, h := range catalog {
+		m[strings.ToLower(h.Name)] = h
+	}
+	return m
+}()
+
+// Lookup returns the catalog entry for the header with the given name. Names
+// are matched case-insensitively, so "content-type" finds Content-Type. ok is
+// false for headers not in the catalog.
+//
+// Example:
+//
+//	if h, ok := headers.Lookup("X-XSS-Protection"); ok && h.Deprecated {
+//	    log.Printf("%s is deprecated", h.Name)
+//	}
+func Lookup(name string) (Header, bool) {
+	h, ok := byName[strings.ToLower(name)]
+	return h, ok
+}
+
+// All returns an iterator over every header in the catalog, in the order the
+// constants are declared.
+func All() iter.Seq[Header] {
+	return func(yield func(Header) bool) {
+		for _, h := range catalog {
+			if !yield(h) {
+				return
+			}
+		}
+	}
+}
+
+// ByCategory returns an iterator over the headers in category cat.
+//
+// Example:
+//
+//	for h := range headers.ByCategory(headers.CategoryCORS) {
+//	    fmt.Println(h.Name, h.Usage)
+//	}
+func ByCategory(cat Category) iter.Seq[Header] {
+	return func(yield func(Header) bool) {
+		for _, h := range catalog {
+			if h.Category == cat && !yield(h) {
+				return
+			}
+		}
+	}
+}
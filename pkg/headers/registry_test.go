@@ -0,0 +1,102 @@
+package headers_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected headers.Header
+	}{
+		{"Content-Type", headers.Header{
+			Name: "Content-Type", Category: headers.CategoryBody, Usage: headers.UsageBoth, Spec: "RFC 9110, Section 8.3",
+		}},
+		{"etag", headers.Header{
+			Name: "ETag", Category: headers.CategoryConditionals, Usage: headers.UsageResponse, Spec: "RFC 9110, Section 8.8.3",
+		}},
+		{"X-XSS-Protection", headers.Header{
+			Name: "X-XSS-Protection", Category: headers.CategorySecurity, Usage: headers.UsageResponse, Deprecated: true,
+		}},
+		{"ACCESS-CONTROL-REQUEST-METHOD", headers.Header{
+			Name: "Access-Control-Request-Method", Category: headers.CategoryCORS, Usage: headers.UsageRequest, Spec: "WHATWG Fetch",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := headers.Lookup(tt.name)
+			if !ok || got != tt.expected {
+				t.Errorf("Lookup(%q) = %+v, %v, want %+v, true", tt.name, got, ok, tt.expected)
+			}
+		})
+	}
+
+	if _, ok := headers.Lookup("X-Unknown"); ok {
+		t.Error("Lookup(\"X-Unknown\") ok = true, want false")
+	}
+}
+
+func TestAllCoversConstants(t *testing.T) {
+	// Every header constant must be in the catalog, exactly once.
+	file, err := parser.ParseFile(token.NewFileSet(), "headers.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var constants []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if decl, ok := n.(*ast.GenDecl); ok && decl.Tok == token.CONST {
+			for _, spec := range decl.Specs {
+				for _, v := range spec.(*ast.ValueSpec).Values {
+					name, _ := strconv.Unquote(v.(*ast.BasicLit).Value)
+					constants = append(constants, name)
+				}
+			}
+		}
+		return true
+	})
+
+	var names []string
+	for h := range headers.All() {
+		names = append(names, h.Name)
+	}
+	if !slices.Equal(names, constants) {
+		t.Errorf("All() = %v, want the header constants %v", names, constants)
+	}
+}
+
+func TestByCategory(t *testing.T) {
+	var got []string
+	for h := range headers.ByCategory(headers.CategoryPreferences) {
+		got = append(got, h.Name)
+	}
+	if want := []string{"Prefer", "Preference-Applied"}; !slices.Equal(got, want) {
+		t.Errorf("ByCategory(CategoryPreferences) = %v, want %v", got, want)
+	}
+
+	for h := range headers.All() {
+		if h.Category == "" || h.Usage.String() == "unknown" {
+			t.Errorf("%s has category %q and usage %v", h.Name, h.Category, h.Usage)
+		}
+	}
+}
+
+func TestUsageString(t *testing.T) {
+	for u, want := range map[headers.Usage]string{
+		headers.UsageRequest:  "request",
+		headers.UsageResponse: "response",
+		headers.UsageBoth:     "both",
+		0:                     "unknown",
+	} {
+		if got := u.String(); got != want {
+			t.Errorf("Usage(%d).String() = %q, want %q", u, got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package headers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRetryAfter is returned for a Retry-After value that is neither
+// delta-seconds nor an HTTP-date.
+var ErrInvalidRetryAfter = errors.New("headers: invalid Retry-After header")
+
+// ParseRetryAfter returns how long to wait according to a Retry-After value,
+// which is either a number of seconds, "120", or an HTTP-date,
+// "Fri, 31 Dec 1999 23:59:59 GMT", counted from now. A date in the past
+// yields 0, and delays too long for a time.Duration are capped at its
+// maximum. Returns ErrInvalidRetryAfter if value is in neither form.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusTooManyRequests {
+//	    wait, err := headers.ParseRetryAfter(resp.Header.Get(headers.RetryAfter), time.Now())
+//	    if err != nil {
+//	        wait = backoff.Next()
+//	    }
+//	    time.Sleep(wait)
+//	}
+func ParseRetryAfter(value string, now time.Time) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, ErrInvalidRetryAfter
+	}
+
+	if strings.Trim(value, "0123456789") == "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Duration(math.MaxInt64), nil
+		}
+		return secondsDuration(seconds), nil
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, ErrInvalidRetryAfter
+	}
+	return max(date.Sub(now), 0), nil
+}
+
+// SetRetryAfter sets the Retry-After header of a 429 or 503 response to d in
+// whole seconds, rounded up so that clients never retry early. Negative
+// durations are written as 0.
+func SetRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set(RetryAfter, strconv.FormatInt(ceilSeconds(d), 10))
+}
@@ -0,0 +1,69 @@
+package headers_test
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		value     string
+		expected  time.Duration
+		wantError bool
+	}{
+		{"seconds", "120", 2 * time.Minute, false},
+		{"zero", "0", 0, false},
+		{"whitespace", " 5 ", 5 * time.Second, false},
+		{"huge", "99999999999999999999", time.Duration(math.MaxInt64), false},
+		{"http date", "Fri, 01 Mar 2024 12:01:30 GMT", 90 * time.Second, false},
+		{"rfc 850 date", "Friday, 01-Mar-24 12:00:10 GMT", 10 * time.Second, false},
+		{"past date", "Fri, 01 Mar 2024 11:00:00 GMT", 0, false},
+		{"negative", "-5", 0, true},
+		{"fraction", "1.5", 0, true},
+		{"empty", "", 0, true},
+		{"garbage", "soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseRetryAfter(tt.value, now)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseRetryAfter() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseRetryAfter() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{30 * time.Second, "30"},
+		{1500 * time.Millisecond, "2"},
+		{time.Nanosecond, "1"},
+		{0, "0"},
+		{-time.Minute, "0"},
+		{time.Duration(math.MaxInt64), "9223372037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			headers.SetRetryAfter(w, tt.d)
+			if got := w.Header().Get(headers.RetryAfter); got != tt.expected {
+				t.Errorf("Retry-After = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
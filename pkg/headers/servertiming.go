@@ -0,0 +1,217 @@
+package headers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidServerTiming is returned for a Server-Timing value that does not
+// follow the syntax of the W3C Server Timing specification.
+var ErrInvalidServerTiming = errors.New("headers: invalid Server-Timing header")
+
+// Metric is a single Server-Timing metric, such as `db;dur=53.2;desc="Query"`.
+type Metric struct {
+	Name        string        // Metric name, a token such as "db" or "cache"
+	Duration    time.Duration // Time taken; zero is left out of the header
+	Description string        // Human-readable description, "" if none
+}
+
+// String formats m as a Server-Timing metric. The duration is written in
+// milliseconds with up to three decimals, and the description is quoted when
+// it is not a token.
+func (m Metric) String() string {
+	var b strings.Builder
+	b.WriteString(m.Name)
+	if m.Duration != 0 {
+		ms := math.Round(float64(m.Duration)/float64(time.Microsecond)) / 1000
+		b.WriteString(";dur=")
+		b.WriteString(strconv.FormatFloat(ms, 'f', -1, 64))
+	}
+	if m.Description != "" {
+		b.WriteString(";desc=")
+		b.WriteString(quoteIfNeeded(m.Description))
+	}
+	return b.String()
+}
+
+// ServerTimings collects the metrics of one response for the Server-Timing
+// header, which browsers show in their developer tools. The zero value is
+// ready to use, and methods may be called from several goroutines.
+//
+// Example:
+//
+//	var timing headers.ServerTimings
+//
+//	t := timing.Start("db", "Load products")
+//	products := store.List()
+//	t.Stop()
+//
+//	timing.Add("cache", 0, "miss")
+//	timing.WriteTo(w.Header())
+//	// Server-Timing: db;dur=12.345;desc="Load products", cache;desc=miss
+type ServerTimings struct {
+	mu      sync.Mutex
+	metrics []Metric
+}
+
+// Add records a metric. Metrics whose name is not a token are dropped when
+// the header is written.
+func (s *ServerTimings) Add(name string, d time.Duration, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = append(s.metrics, Metric{Name: name, Duration: d, Description: description})
+}
+
+// Start starts timing a block of code as the named metric. The metric is
+// recorded when the returned Timer is stopped:
+//
+//	defer timing.Start("render", "").Stop()
+func (s *ServerTimings) Start(name, description string) *Timer {
+	return &Timer{timing: s, name: name, description: description, start: time.Now()}
+}
+
+// Metrics returns a copy of the metrics recorded so far, in order.
+func (s *ServerTimings) Metrics() []Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Metric(nil), s.metrics...)
+}
+
+// String formats the recorded metrics as a Server-Timing value.
+func (s *ServerTimings) String() string {
+	var parts []string
+	for _, m := range s.Metrics() {
+		if isToken(m.Name) {
+			parts = append(parts, m.String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteTo sets the Server-Timing header in h to the recorded metrics, or
+// leaves h unchanged if there are none. Call it before the response header is
+// written; metrics recorded afterwards are lost.
+func (s *ServerTimings) WriteTo(h http.Header) {
+	if value := s.String(); value != "" {
+		h.Set(ServerTiming, value)
+	}
+}
+
+// Timer measures a block of code and records it as a metric. Create one with
+// ServerTimings.Start.
+type Timer struct {
+	timing      *ServerTimings
+	name        string
+	description string
+	start       time.Time
+	once        sync.Once
+	elapsed     time.Duration
+}
+
+// Stop records the time elapsed since the timer was started and returns it.
+// Only the first call records a metric; later calls return the same duration.
+func (t *Timer) Stop() time.Duration {
+	t.once.Do(func() {
+		t.elapsed = time.Since(t.start)
+		t.timing.Add(t.name, t.elapsed, t.description)
+	})
+	return t.elapsed
+}
+
+// ParseServerTiming parses a Server-Timing value into its metrics, for clients
+// and tests that inspect what a server reported. Parameter names are
+// case-insensitive, the first occurrence of a parameter wins, and parameters
+// other than dur and desc are ignored. A dur that is not a number is treated
+// as absent. Returns ErrInvalidServerTiming if value is empty or malformed.
+func ParseServerTiming(value string) ([]Metric, error) {
+	var metrics []Metric
+	p := authParser{s: value}
+	for {
+		p.skipListSeparators()
+		if p.done() {
+			break
+		}
+
+		m := Metric{Name: p.token()}
+		if m.Name == "" {
+			return nil, ErrInvalidServerTiming
+		}
+		seen := make(map[string]bool)
+		for {
+			p.ows()
+			if p.done() || p.peek() == ',' {
+				break
+			}
+			if p.peek() != ';' {
+				return nil, ErrInvalidServerTiming
+			}
+			p.i++
+			p.ows()
+
+			name := strings.ToLower(p.token())
+			if name == "" {
+				return nil, ErrInvalidServerTiming
+			}
+			p.ows()
+			var value string
+			if !p.done() && p.peek() == '=' {
+				p.i++
+				p.ows()
+				if !p.done() && p.peek() == '"' {
+					v, ok := p.quoted()
+					if !ok {
+						return nil, ErrInvalidServerTiming
+					}
+					value = v
+				} else {
+					value = p.token()
+				}
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			switch name {
+			case "dur":
+				if ms, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(ms, 0) && !math.IsNaN(ms) {
+					m.Duration = time.Duration(ms * float64(time.Millisecond))
+				}
+			case "desc":
+				m.Description = value
+			}
+		}
+		metrics = append(metrics, m)
+	}
+	if len(metrics) == 0 {
+		return nil, ErrInvalidServerTiming
+	}
+	return metrics, nil
+}
+
+// quoteIfNeeded returns value as is if it is a token, and as a quoted-string
+// otherwise.
+func quoteIfNeeded(value string) string {
+	if isToken(value) {
+		return value
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	writeQuotedContent(&b, value)
+	b.WriteByte('"')
+	return b.String()
+}
+
+func isToken(s string) bool {
+	for i := range len(s) {
+		if !isTokenChar(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
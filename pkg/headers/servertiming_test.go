@@ -0,0 +1,126 @@
+package headers_test
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestMetricString(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   headers.Metric
+		expected string
+	}{
+		{"duration", headers.Metric{Name: "db", Duration: 53200 * time.Microsecond}, "db;dur=53.2"},
+		{"rounded", headers.Metric{Name: "db", Duration: 1234567 * time.Nanosecond}, "db;dur=1.235"},
+		{"token description", headers.Metric{Name: "cache", Description: "miss"}, "cache;desc=miss"},
+		{"quoted description", headers.Metric{Name: "app", Duration: time.Second, Description: `Render "home"`}, `app;dur=1000;desc="Render \"home\""`},
+		{"name only", headers.Metric{Name: "miss"}, "miss"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServerTimings(t *testing.T) {
+	var timing headers.ServerTimings
+	h := http.Header{}
+	timing.WriteTo(h)
+	if _, ok := h[headers.ServerTiming]; ok {
+		t.Error("WriteTo() set an empty header")
+	}
+
+	timing.Add("db", 12*time.Millisecond, "Query")
+	timing.Add("bad name", time.Millisecond, "")
+	timing.Add("cache", 0, "hit")
+	timing.WriteTo(h)
+
+	if got, expected := h.Get(headers.ServerTiming), "db;dur=12;desc=Query, cache;desc=hit"; got != expected {
+		t.Errorf("Server-Timing = %q, want %q", got, expected)
+	}
+	if got := len(timing.Metrics()); got != 3 {
+		t.Errorf("len(Metrics()) = %d, want 3", got)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	var timing headers.ServerTimings
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timer := timing.Start("work", "")
+			time.Sleep(time.Millisecond)
+			first := timer.Stop()
+			if first < time.Millisecond || timer.Stop() != first {
+				t.Errorf("Stop() = %v, then a different value", first)
+			}
+		}()
+	}
+	wg.Wait()
+
+	metrics := timing.Metrics()
+	if len(metrics) != 4 {
+		t.Fatalf("Metrics() = %v, want 4 metrics", metrics)
+	}
+	if !strings.HasPrefix(timing.String(), "work;dur=") {
+		t.Errorf("String() = %q", timing.String())
+	}
+}
+
+func TestParseServerTiming(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  []headers.Metric
+		wantError bool
+	}{
+		{
+			name:  "several metrics",
+			value: `db;dur=53.2;desc="Query, \"users\"", cache;desc=hit, total;dur=120`,
+			expected: []headers.Metric{
+				{Name: "db", Duration: 53200 * time.Microsecond, Description: `Query, "users"`},
+				{Name: "cache", Description: "hit"},
+				{Name: "total", Duration: 120 * time.Millisecond},
+			},
+		},
+		{
+			name:  "lenient parameters",
+			value: "app ; DUR = 5 ; dur=9 ; desc ; extra=1, miss",
+			expected: []headers.Metric{
+				{Name: "app", Duration: 5 * time.Millisecond},
+				{Name: "miss"},
+			},
+		},
+		{name: "bad duration", value: "db;dur=fast", expected: []headers.Metric{{Name: "db"}}},
+		{name: "empty", value: "", wantError: true},
+		{name: "bad separator", value: "db dur=1", wantError: true},
+		{name: "missing parameter", value: "db;", wantError: true},
+		{name: "unterminated", value: `db;desc="x`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseServerTiming(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseServerTiming() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseServerTiming() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package sfv parses and serializes Structured Field Values for HTTP, as
+// defined by RFC 9651 (which obsoletes RFC 8941). Headers such as Priority,
+// Accept-CH, the Sec-CH-* client hints and Signature-Input are structured
+// fields, so they can be read and written without ad-hoc string handling.
+//
+// A structured field is an Item, a List or a Dictionary, chosen by the header's
+// specification rather than by its content, so each has its own parse and
+// format function:
+//
+//	// Priority: u=1, i
+//	dict, err := sfv.ParseDictionary(r.Header.Get("Priority"))
+//	if m, ok := dict.Get("u"); ok {
+//	    urgency, _ := m.(sfv.Item).Value.(int64)
+//	}
+//
+//	// Sec-CH-UA-Platform: "macOS"
+//	item, err := sfv.ParseItem(r.Header.Get("Sec-CH-UA-Platform"))
+//
+// # Bare Items
+//
+// Item values and parameter values are bare items, held in an any with one of
+// the following dynamic types:
+//
+//	Integer         int64 (int is also accepted when formatting)
+//	Decimal         float64
+//	String          string
+//	Token           Token
+//	Byte Sequence   []byte
+//	Boolean         bool
+//	Date            time.Time
+//	Display String  DisplayString
+//
+// Parameters and dictionaries keep the order they were sent in, as the
+// specification requires, so they are slices of key/value pairs with a Get
+// method rather than maps.
+//
+// # Multiple Header Lines
+//
+// Lists and dictionaries may be split over several header lines. Join them
+// with ", " before parsing:
+//
+//	list, err := sfv.ParseList(strings.Join(r.Header.Values("Accept-CH"), ", "))
+package sfv
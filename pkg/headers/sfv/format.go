@@ -0,0 +1,238 @@
+package sfv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxInteger = 999_999_999_999_999
+
+// FormatItem serializes an item, such as Item{Value: Token("gzip")} to
+// "gzip". Returns an error wrapping ErrValue if the item holds a value that
+// cannot be serialized.
+func FormatItem(item Item) (string, error) {
+	var b strings.Builder
+	if err := writeItem(&b, item); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// FormatList serializes a list, separating members with ", ". Returns an
+// error wrapping ErrValue if a member cannot be serialized.
+func FormatList(list List) (string, error) {
+	var b strings.Builder
+	for i, m := range list {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if err := writeMember(&b, m); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// FormatDictionary serializes a dictionary, separating members with ", ".
+// Members whose value is the boolean true are written as their key alone,
+// followed by any parameters. Returns an error wrapping ErrValue if a key or
+// member cannot be serialized.
+func FormatDictionary(dict Dictionary) (string, error) {
+	var b strings.Builder
+	for i, m := range dict {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if err := writeKey(&b, m.Key); err != nil {
+			return "", err
+		}
+		if item, ok := m.Value.(Item); ok && item.Value == true {
+			if err := writeParams(&b, item.Params); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte('=')
+		if err := writeMember(&b, m.Value); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrValue}, args...)...)
+}
+
+func writeMember(b *strings.Builder, m Member) error {
+	switch m := m.(type) {
+	case Item:
+		return writeItem(b, m)
+	case InnerList:
+		return writeInnerList(b, m)
+	}
+	return invalid("unsupported member %T", m)
+}
+
+func writeInnerList(b *strings.Builder, list InnerList) error {
+	b.WriteByte('(')
+	for i, item := range list.Items {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if err := writeItem(b, item); err != nil {
+			return err
+		}
+	}
+	b.WriteByte(')')
+	return writeParams(b, list.Params)
+}
+
+func writeItem(b *strings.Builder, item Item) error {
+	if err := writeBareItem(b, item.Value); err != nil {
+		return err
+	}
+	return writeParams(b, item.Params)
+}
+
+func writeParams(b *strings.Builder, params Params) error {
+	for _, p := range params {
+		b.WriteByte(';')
+		if err := writeKey(b, p.Key); err != nil {
+			return err
+		}
+		if p.Value != true {
+			b.WriteByte('=')
+			if err := writeBareItem(b, p.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeKey(b *strings.Builder, key string) error {
+	if key == "" || !isKeyStart(key[0]) {
+		return invalid("key %q", key)
+	}
+	for i := 1; i < len(key); i++ {
+		if !isKeyChar(key[i]) {
+			return invalid("key %q", key)
+		}
+	}
+	b.WriteString(key)
+	return nil
+}
+
+func writeBareItem(b *strings.Builder, value any) error {
+	switch v := value.(type) {
+	case int:
+		return writeInteger(b, int64(v))
+	case int64:
+		return writeInteger(b, v)
+	case float64:
+		return writeDecimal(b, v)
+	case string:
+		return writeString(b, v)
+	case Token:
+		return writeToken(b, v)
+	case []byte:
+		b.WriteByte(':')
+		b.WriteString(base64.StdEncoding.EncodeToString(v))
+		b.WriteByte(':')
+	case bool:
+		if v {
+			b.WriteString("?1")
+		} else {
+			b.WriteString("?0")
+		}
+	case time.Time:
+		b.WriteByte('@')
+		return writeInteger(b, v.Unix())
+	case DisplayString:
+		return writeDisplayString(b, v)
+	default:
+		return invalid("unsupported bare item %T", value)
+	}
+	return nil
+}
+
+func writeInteger(b *strings.Builder, n int64) error {
+	if n < -maxInteger || n > maxInteger {
+		return invalid("integer %d out of range", n)
+	}
+	b.WriteString(strconv.FormatInt(n, 10))
+	return nil
+}
+
+// writeDecimal rounds f to three fractional digits, ties to even, as RFC
+// 9651, section 4.1.5 requires.
+func writeDecimal(b *strings.Builder, f float64) error {
+	rounded := math.RoundToEven(f*1000) / 1000
+	if math.IsNaN(rounded) || math.Abs(rounded) >= 1e12 {
+		return invalid("decimal %v out of range", f)
+	}
+	if rounded == 0 {
+		rounded = 0 // drop the sign of -0
+	}
+	s := strconv.FormatFloat(rounded, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	b.WriteString(s)
+	return nil
+}
+
+func writeString(b *strings.Builder, s string) error {
+	b.WriteByte('"')
+	for i := range len(s) {
+		c := s[i]
+		if c < 0x20 || c > 0x7e {
+			return invalid("string contains %q; use DisplayString for non-ASCII text", c)
+		}
+		if c == '"' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('"')
+	return nil
+}
+
+func writeToken(b *strings.Builder, t Token) error {
+	if t == "" || (t[0] != '*' && !isAlpha(t[0])) {
+		return invalid("token %q", string(t))
+	}
+	for i := 1; i < len(t); i++ {
+		if !isTokenChar(t[i]) {
+			return invalid("token %q", string(t))
+		}
+	}
+	b.WriteString(string(t))
+	return nil
+}
+
+func writeDisplayString(b *strings.Builder, s DisplayString) error {
+	if !utf8.ValidString(string(s)) {
+		return invalid("display string is not valid UTF-8")
+	}
+	const hex = "0123456789abcdef"
+	b.WriteString(`%"`)
+	for i := range len(s) {
+		c := s[i]
+		if c == '%' || c == '"' || c < 0x20 || c > 0x7e {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+			continue
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('"')
+	return nil
+}
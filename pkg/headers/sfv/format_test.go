@@ -0,0 +1,138 @@
+package sfv_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+func TestFormatItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     sfv.Item
+		expected string
+	}{
+		{"int", sfv.Item{Value: 42}, "42"},
+		{"int64", sfv.Item{Value: int64(-7)}, "-7"},
+		{"decimal", sfv.Item{Value: 1.5}, "1.5"},
+		{"whole decimal", sfv.Item{Value: 2.0}, "2.0"},
+		{"rounded decimal", sfv.Item{Value: 0.0025}, "0.002"},
+		{"negative zero", sfv.Item{Value: math.Copysign(0, -1)}, "0.0"},
+		{"string", sfv.Item{Value: `say "hi" \o/`}, `"say \"hi\" \\o/"`},
+		{"token", sfv.Item{Value: sfv.Token("text/html")}, "text/html"},
+		{"bytes", sfv.Item{Value: []byte("hi")}, ":aGk=:"},
+		{"true", sfv.Item{Value: true}, "?1"},
+		{"false", sfv.Item{Value: false}, "?0"},
+		{"date", sfv.Item{Value: time.Unix(1659578233, 0)}, "@1659578233"},
+		{"display string", sfv.Item{Value: sfv.DisplayString(`füü "%"`)}, `%"f%c3%bc%c3%bc %22%25%22"`},
+		{
+			"params",
+			sfv.Item{Value: sfv.Token("gzip"), Params: sfv.Params{{Key: "q", Value: 0.5}, {Key: "fresh", Value: true}, {Key: "old", Value: false}}},
+			"gzip;q=0.5;fresh;old=?0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sfv.FormatItem(tt.item)
+			if err != nil {
+				t.Fatalf("FormatItem() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FormatItem() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatItemInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		item sfv.Item
+	}{
+		{"integer too large", sfv.Item{Value: int64(1_000_000_000_000_000)}},
+		{"decimal too large", sfv.Item{Value: 1e12}},
+		{"nan", sfv.Item{Value: math.NaN()}},
+		{"non-ascii string", sfv.Item{Value: "fü"}},
+		{"control in string", sfv.Item{Value: "a\nb"}},
+		{"bad token", sfv.Item{Value: sfv.Token("1abc")}},
+		{"empty token", sfv.Item{Value: sfv.Token("")}},
+		{"invalid display string", sfv.Item{Value: sfv.DisplayString("\xff")}},
+		{"unsupported type", sfv.Item{Value: uint8(1)}},
+		{"bad key", sfv.Item{Value: 1, Params: sfv.Params{{Key: "Q", Value: 1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := sfv.FormatItem(tt.item); !errors.Is(err, sfv.ErrValue) {
+				t.Errorf("FormatItem() = %q, %v, want ErrValue", got, err)
+			}
+		})
+	}
+}
+
+func TestFormatList(t *testing.T) {
+	list := sfv.List{
+		sfv.Item{Value: sfv.Token("sugar")},
+		sfv.InnerList{Items: []sfv.Item{{Value: "foo"}, {Value: "bar"}}, Params: sfv.Params{{Key: "lvl", Value: 5}}},
+		sfv.InnerList{},
+	}
+	got, err := sfv.FormatList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `sugar, ("foo" "bar");lvl=5, ()`; got != expected {
+		t.Errorf("FormatList() = %q, want %q", got, expected)
+	}
+
+	if _, err := sfv.FormatList(sfv.List{nil}); !errors.Is(err, sfv.ErrValue) {
+		t.Errorf("FormatList(nil member) error = %v, want ErrValue", err)
+	}
+}
+
+func TestFormatDictionary(t *testing.T) {
+	dict := sfv.Dictionary{
+		{Key: "u", Value: sfv.Item{Value: 1}},
+		{Key: "i", Value: sfv.Item{Value: true}},
+		{Key: "a", Value: sfv.Item{Value: true, Params: sfv.Params{{Key: "p", Value: 2}}}},
+		{Key: "b", Value: sfv.Item{Value: false}},
+		{Key: "l", Value: sfv.InnerList{Items: []sfv.Item{{Value: 1}, {Value: 2}}}},
+	}
+	got, err := sfv.FormatDictionary(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "u=1, i, a;p=2, b=?0, l=(1 2)"; got != expected {
+		t.Errorf("FormatDictionary() = %q, want %q", got, expected)
+	}
+
+	if _, err := sfv.FormatDictionary(sfv.Dictionary{{Key: "Bad", Value: sfv.Item{Value: 1}}}); !errors.Is(err, sfv.ErrValue) {
+		t.Errorf("FormatDictionary(bad key) error = %v, want ErrValue", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	values := []string{
+		`sugar, tea;sweet, ("a" 1.5 @0 :aGk=:);x=%"%c3%a9"`,
+		"u=1, i, a=(1 2);x, b=?0;p",
+	}
+	for _, value := range values {
+		list, err := sfv.ParseList(value)
+		if err == nil {
+			if got, err := sfv.FormatList(list); err != nil || got != value {
+				t.Errorf("FormatList(ParseList(%q)) = %q, %v", value, got, err)
+			}
+			continue
+		}
+		dict, err := sfv.ParseDictionary(value)
+		if err != nil {
+			t.Fatalf("ParseDictionary(%q) error = %v", value, err)
+		}
+		if got, err := sfv.FormatDictionary(dict); err != nil || got != value {
+			t.Errorf("FormatDictionary(ParseDictionary(%q)) = %q, %v", value, got, err)
+		}
+	}
+}
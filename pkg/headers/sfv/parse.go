@@ -0,0 +1,406 @@
+package sfv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ParseItem parses a structured field item, such as `"macOS"` or `5;q=0.5`.
+// Returns an error wrapping ErrSyntax if value is not a valid item.
+func ParseItem(value string) (Item, error) {
+	p := parser{s: strings.TrimLeft(value, " ")}
+	item, err := p.item()
+	if err != nil {
+		return Item{}, err
+	}
+	return item, p.end()
+}
+
+// ParseList parses a structured field list, such as `sugar, tea;sweet, (rum
+// milk)`. An empty value is an empty list. Returns an error wrapping
+// ErrSyntax if value is not a valid list.
+func ParseList(value string) (List, error) {
+	p := parser{s: strings.TrimLeft(value, " ")}
+	var list List
+	for !p.done() {
+		m, err := p.member()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+		if err := p.nextMember(); err != nil {
+			return nil, err
+		}
+	}
+	return list, p.end()
+}
+
+// ParseDictionary parses a structured field dictionary, such as `u=1, i`. A
+// key sent more than once keeps its first position and its last value. An
+// empty value is an empty dictionary. Returns an error wrapping ErrSyntax if
+// value is not a valid dictionary.
+func ParseDictionary(value string) (Dictionary, error) {
+	p := parser{s: strings.TrimLeft(value, " ")}
+	var dict Dictionary
+	index := make(map[string]int)
+	for !p.done() {
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		var m Member
+		if !p.done() && p.peek() == '=' {
+			p.i++
+			if m, err = p.member(); err != nil {
+				return nil, err
+			}
+		} else {
+			params, err := p.params()
+			if err != nil {
+				return nil, err
+			}
+			m = Item{Value: true, Params: params}
+		}
+		dict.set(key, m, index)
+		if err := p.nextMember(); err != nil {
+			return nil, err
+		}
+	}
+	return dict, p.end()
+}
+
+// parser is a cursor over a structured field value, following the parsing
+// algorithms of RFC 9651, section 4.2.
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) done() bool { return p.i >= len(p.s) }
+func (p *parser) peek() byte { return p.s[p.i] }
+
+func (p *parser) fail(what string) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrSyntax, what, p.i)
+}
+
+func (p *parser) skipSP() {
+	for !p.done() && p.peek() == ' ' {
+		p.i++
+	}
+}
+
+func (p *parser) skipOWS() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.i++
+	}
+}
+
+// end checks that only trailing spaces remain.
+func (p *parser) end() error {
+	p.skipSP()
+	if !p.done() {
+		return p.fail("unexpected character")
+	}
+	return nil
+}
+
+// nextMember consumes the separator after a list or dictionary member,
+// leaving the cursor at the next member or the end.
+func (p *parser) nextMember() error {
+	p.skipOWS()
+	if p.done() {
+		return nil
+	}
+	if p.peek() != ',' {
+		return p.fail("expected comma")
+	}
+	p.i++
+	p.skipOWS()
+	if p.done() {
+		return p.fail("trailing comma")
+	}
+	return nil
+}
+
+func (p *parser) member() (Member, error) {
+	if !p.done() && p.peek() == '(' {
+		return p.innerList()
+	}
+	return p.item()
+}
+
+func (p *parser) innerList() (InnerList, error) {
+	p.i++ // (
+	var list InnerList
+	for {
+		p.skipSP()
+		if p.done() {
+			return InnerList{}, p.fail("unterminated inner list")
+		}
+		if p.peek() == ')' {
+			p.i++
+			params, err := p.params()
+			if err != nil {
+				return InnerList{}, err
+			}
+			list.Params = params
+			return list, nil
+		}
+		item, err := p.item()
+		if err != nil {
+			return InnerList{}, err
+		}
+		list.Items = append(list.Items, item)
+		if !p.done() && p.peek() != ' ' && p.peek() != ')' {
+			return InnerList{}, p.fail("expected space or )")
+		}
+	}
+}
+
+func (p *parser) item() (Item, error) {
+	value, err := p.bareItem()
+	if err != nil {
+		return Item{}, err
+	}
+	params, err := p.params()
+	if err != nil {
+		return Item{}, err
+	}
+	return Item{Value: value, Params: params}, nil
+}
+
+func (p *parser) params() (Params, error) {
+	var params Params
+	var index map[string]int
+	for !p.done() && p.peek() == ';' {
+		p.i++
+		p.skipSP()
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		var value any = true
+		if !p.done() && p.peek() == '=' {
+			p.i++
+			if value, err = p.bareItem(); err != nil {
+				return nil, err
+			}
+		}
+		if index == nil {
+			index = make(map[string]int)
+		}
+		params.set(key, value, index)
+	}
+	return params, nil
+}
+
+func (p *parser) key() (string, error) {
+	if p.done() || !isKeyStart(p.peek()) {
+		return "", p.fail("expected key")
+	}
+	start := p.i
+	for !p.done() && isKeyChar(p.peek()) {
+		p.i++
+	}
+	return p.s[start:p.i], nil
+}
+
+func (p *parser) bareItem() (any, error) {
+	if p.done() {
+		return nil, p.fail("expected item")
+	}
+	switch c := p.peek(); {
+	case c == '-' || isDigit(c):
+		return p.number()
+	case c == '"':
+		return p.string()
+	case c == '*' || isAlpha(c):
+		return p.token(), nil
+	case c == ':':
+		return p.byteSequence()
+	case c == '?':
+		return p.boolean()
+	case c == '@':
+		return p.date()
+	case c == '%':
+		return p.displayString()
+	}
+	return nil, p.fail("unexpected character")
+}
+
+// number parses an Integer or Decimal, returning int64 or float64.
+func (p *parser) number() (any, error) {
+	start := p.i
+	if !p.done() && p.peek() == '-' {
+		p.i++
+	}
+	digitsStart := p.i
+	if p.done() || !isDigit(p.peek()) {
+		return nil, p.fail("expected digit")
+	}
+
+	dot := -1
+	for !p.done() {
+		c := p.peek()
+		if c == '.' && dot < 0 {
+			if p.i-digitsStart > 12 {
+				return nil, p.fail("decimal too long")
+			}
+			dot = p.i
+		} else if !isDigit(c) {
+			break
+		}
+		p.i++
+		if dot < 0 && p.i-digitsStart > 15 {
+			return nil, p.fail("integer too long")
+		}
+		if dot >= 0 && p.i-digitsStart > 16 {
+			return nil, p.fail("decimal too long")
+		}
+	}
+
+	num := p.s[start:p.i]
+	if dot < 0 {
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return nil, p.fail("invalid integer")
+		}
+		return n, nil
+	}
+	if frac := p.i - dot - 1; frac < 1 || frac > 3 {
+		return nil, p.fail("invalid decimal")
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return nil, p.fail("invalid decimal")
+	}
+	return f, nil
+}
+
+func (p *parser) string() (string, error) {
+	var b strings.Builder
+	for p.i++; !p.done(); p.i++ {
+		switch c := p.peek(); {
+		case c == '"':
+			p.i++
+			return b.String(), nil
+		case c == '\\':
+			p.i++
+			if p.done() || (p.peek() != '"' && p.peek() != '\\') {
+				return "", p.fail("invalid escape")
+			}
+			b.WriteByte(p.peek())
+		case c < 0x20 || c > 0x7e:
+			return "", p.fail("invalid string character")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.fail("unterminated string")
+}
+
+func (p *parser) token() Token {
+	start := p.i
+	p.i++
+	for !p.done() && isTokenChar(p.peek()) {
+		p.i++
+	}
+	return Token(p.s[start:p.i])
+}
+
+func (p *parser) byteSequence() ([]byte, error) {
+	p.i++ // :
+	end := strings.IndexByte(p.s[p.i:], ':')
+	if end < 0 {
+		return nil, p.fail("unterminated byte sequence")
+	}
+	encoded := p.s[p.i : p.i+end]
+	if strings.Trim(encoded, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=") != "" {
+		return nil, p.fail("invalid byte sequence")
+	}
+	// RFC 9651, section 4.2.7: parsers should not fail on missing padding.
+	decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, p.fail("invalid byte sequence")
+	}
+	p.i += end + 1
+	return decoded, nil
+}
+
+func (p *parser) boolean() (bool, error) {
+	p.i++ // ?
+	if !p.done() {
+		switch p.peek() {
+		case '1':
+			p.i++
+			return true, nil
+		case '0':
+			p.i++
+			return false, nil
+		}
+	}
+	return false, p.fail("invalid boolean")
+}
+
+func (p *parser) date() (time.Time, error) {
+	p.i++ // @
+	n, err := p.number()
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, ok := n.(int64)
+	if !ok {
+		return time.Time{}, p.fail("invalid date")
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+func (p *parser) displayString() (DisplayString, error) {
+	p.i++ // %
+	if p.done() || p.peek() != '"' {
+		return "", p.fail(`expected "`)
+	}
+	var b []byte
+	for p.i++; !p.done(); p.i++ {
+		switch c := p.peek(); {
+		case c < 0x20 || c > 0x7e:
+			return "", p.fail("invalid display string character")
+		case c == '%':
+			if p.i+2 >= len(p.s) || !isLowerHex(p.s[p.i+1]) || !isLowerHex(p.s[p.i+2]) {
+				return "", p.fail("invalid percent encoding")
+			}
+			n, _ := strconv.ParseUint(p.s[p.i+1:p.i+3], 16, 8)
+			b = append(b, byte(n))
+			p.i += 2
+		case c == '"':
+			p.i++
+			if !utf8.Valid(b) {
+				return "", p.fail("invalid UTF-8 in display string")
+			}
+			return DisplayString(b), nil
+		default:
+			b = append(b, c)
+		}
+	}
+	return "", p.fail("unterminated display string")
+}
+
+func isDigit(c byte) bool    { return '0' <= c && c <= '9' }
+func isAlpha(c byte) bool    { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' }
+func isLowerHex(c byte) bool { return isDigit(c) || 'a' <= c && c <= 'f' }
+
+func isKeyStart(c byte) bool { return 'a' <= c && c <= 'z' || c == '*' }
+
+func isKeyChar(c byte) bool {
+	return isKeyStart(c) || isDigit(c) || c == '_' || c == '-' || c == '.'
+}
+
+// isTokenChar reports whether c may follow the first character of a token:
+// a tchar, ':' or '/'.
+func isTokenChar(c byte) bool {
+	return isAlpha(c) || isDigit(c) || strings.IndexByte("!#$%&'*+-.^_`|~:/", c) >= 0
+}
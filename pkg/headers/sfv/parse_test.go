@@ -0,0 +1,214 @@
+package sfv_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+func TestParseItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected sfv.Item
+	}{
+		{"integer", "42", sfv.Item{Value: int64(42)}},
+		{"negative integer", "-999999999999999", sfv.Item{Value: int64(-999999999999999)}},
+		{"decimal", "4.5", sfv.Item{Value: 4.5}},
+		{"negative decimal", "-0.125", sfv.Item{Value: -0.125}},
+		{"string", `"hello \"world\" \\"`, sfv.Item{Value: `hello "world" \`}},
+		{"token", "*foo/bar:baz", sfv.Item{Value: sfv.Token("*foo/bar:baz")}},
+		{"byte sequence", ":cHJldGVuZCB0aGlzIGlzIGJpbmFyeSBjb250ZW50Lg==:", sfv.Item{Value: []byte("pretend this is binary content.")}},
+		{"unpadded byte sequence", ":YQ:", sfv.Item{Value: []byte("a")}},
+		{"empty byte sequence", "::", sfv.Item{Value: []byte{}}},
+		{"true", "?1", sfv.Item{Value: true}},
+		{"false", "?0", sfv.Item{Value: false}},
+		{"date", "@1659578233", sfv.Item{Value: time.Unix(1659578233, 0).UTC()}},
+		{"display string", `%"f%c3%bc%c3%bc"`, sfv.Item{Value: sfv.DisplayString("füü")}},
+		{"surrounding spaces", "  1  ", sfv.Item{Value: int64(1)}},
+		{
+			"parameters",
+			"text/html;charset=utf-8;q=0.9;fresh",
+			sfv.Item{Value: sfv.Token("text/html"), Params: sfv.Params{
+				{Key: "charset", Value: sfv.Token("utf-8")},
+				{Key: "q", Value: 0.9},
+				{Key: "fresh", Value: true},
+			}},
+		},
+		{
+			"duplicate parameter",
+			"1;a=1;b=2;a=3",
+			sfv.Item{Value: int64(1), Params: sfv.Params{{Key: "a", Value: int64(3)}, {Key: "b", Value: int64(2)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sfv.ParseItem(tt.value)
+			if err != nil {
+				t.Fatalf("ParseItem(%q) error = %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseItem(%q) = %#v, want %#v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseItemInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"1 2",
+		"1,2",
+		"1234567890123456",
+		"1234567890123.0",
+		"1.2345",
+		"1.",
+		"-",
+		"-a",
+		`"unterminated`,
+		`"bad \n escape"`,
+		"\"tab\there\"",
+		":not base64!:",
+		":YQ",
+		":Y=Q:",
+		":Y:",
+		"?2",
+		"?",
+		"@1.5",
+		"@",
+		"@-",
+		`%"F%C3%BC"`,
+		`%"%ff"`,
+		"1;A=1",
+		"1;a=",
+		"Ñ",
+		"\t1",
+	}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			if got, err := sfv.ParseItem(value); !errors.Is(err, sfv.ErrSyntax) {
+				t.Errorf("ParseItem(%q) = %#v, %v, want ErrSyntax", value, got, err)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected sfv.List
+	}{
+		{"empty", "", nil},
+		{
+			"tokens",
+			"sugar, tea,\trum",
+			sfv.List{
+				sfv.Item{Value: sfv.Token("sugar")},
+				sfv.Item{Value: sfv.Token("tea")},
+				sfv.Item{Value: sfv.Token("rum")},
+			},
+		},
+		{
+			"inner lists",
+			`("foo" "bar");lvl=5, ("baz"), ( ), (1 2;a)`,
+			sfv.List{
+				sfv.InnerList{Items: []sfv.Item{{Value: "foo"}, {Value: "bar"}}, Params: sfv.Params{{Key: "lvl", Value: int64(5)}}},
+				sfv.InnerList{Items: []sfv.Item{{Value: "baz"}}},
+				sfv.InnerList{},
+				sfv.InnerList{Items: []sfv.Item{{Value: int64(1)}, {Value: int64(2), Params: sfv.Params{{Key: "a", Value: true}}}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sfv.ParseList(tt.value)
+			if err != nil {
+				t.Fatalf("ParseList(%q) error = %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseList(%q) = %#v, want %#v", tt.value, got, tt.expected)
+			}
+		})
+	}
+
+	for _, value := range []string{"a,", "a, ,b", "a b", "(a", "(a,b)", "(a)b", ",a"} {
+		if _, err := sfv.ParseList(value); !errors.Is(err, sfv.ErrSyntax) {
+			t.Errorf("ParseList(%q) error = %v, want ErrSyntax", value, err)
+		}
+	}
+}
+
+func TestParseDictionary(t *testing.T) {
+	got, err := sfv.ParseDictionary(`u=1, i, a=(1 2);x, b=?0;p, u=3`)
+	if err != nil {
+		t.Fatalf("ParseDictionary() error = %v", err)
+	}
+	expected := sfv.Dictionary{
+		{Key: "u", Value: sfv.Item{Value: int64(3)}},
+		{Key: "i", Value: sfv.Item{Value: true}},
+		{Key: "a", Value: sfv.InnerList{Items: []sfv.Item{{Value: int64(1)}, {Value: int64(2)}}, Params: sfv.Params{{Key: "x", Value: true}}}},
+		{Key: "b", Value: sfv.Item{Value: false, Params: sfv.Params{{Key: "p", Value: true}}}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseDictionary() = %#v, want %#v", got, expected)
+	}
+
+	if m, ok := got.Get("i"); !ok || m.(sfv.Item).Value != true {
+		t.Errorf("Get(i) = %#v, %v", m, ok)
+	}
+	if _, ok := got.Get("missing"); ok {
+		t.Error("Get(missing) ok = true")
+	}
+
+	for _, value := range []string{"A=1", "a=", "a=@", "sha-256=@", "a=1,", "a=1 b=2", "1=a"} {
+		if _, err := sfv.ParseDictionary(value); !errors.Is(err, sfv.ErrSyntax) {
+			t.Errorf("ParseDictionary(%q) error = %v, want ErrSyntax", value, err)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		`1;a=2`, `"text";q=0.5`, `:YWJj:`, `?1`, `@1659578233`, `%"f%c3%bc"`, `(a b);x, c`,
+		`sha-256=:X48E9q=:, sha-512=:YQ:`, `@`, `a=@`, `-`, `(`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if item, err := sfv.ParseItem(value); err == nil {
+			formatted, err := sfv.FormatItem(item)
+			if err != nil {
+				t.Fatalf("FormatItem(ParseItem(%q)) error = %v", value, err)
+			}
+			if again, err := sfv.ParseItem(formatted); err != nil || !reflect.DeepEqual(again, item) {
+				t.Fatalf("ParseItem(%q) = %#v, %v, want %#v", formatted, again, err, item)
+			}
+		}
+		if list, err := sfv.ParseList(value); err == nil {
+			formatted, err := sfv.FormatList(list)
+			if err != nil {
+				t.Fatalf("FormatList(ParseList(%q)) error = %v", value, err)
+			}
+			if again, err := sfv.ParseList(formatted); err != nil || !reflect.DeepEqual(again, list) {
+				t.Fatalf("ParseList(%q) = %#v, %v, want %#v", formatted, again, err, list)
+			}
+		}
+		if dict, err := sfv.ParseDictionary(value); err == nil {
+			formatted, err := sfv.FormatDictionary(dict)
+			if err != nil {
+				t.Fatalf("FormatDictionary(ParseDictionary(%q)) error = %v", value, err)
+			}
+			if again, err := sfv.ParseDictionary(formatted); err != nil || !reflect.DeepEqual(again, dict) {
+				t.Fatalf("ParseDictionary(%q) = %#v, %v, want %#v", formatted, again, err, dict)
+			}
+		}
+	})
+}
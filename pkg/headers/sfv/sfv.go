@@ -0,0 +1,106 @@
+package sfv
+
+import "errors"
+
+var (
+	// ErrSyntax is returned by the parse functions for input that is not a
+	// valid structured field of the requested kind.
+	ErrSyntax = errors.New("sfv: invalid syntax")
+
+	// ErrValue is returned by the format functions for values that cannot be
+	// serialized, such as an out-of-range integer or an invalid token.
+	ErrValue = errors.New("sfv: invalid value")
+)
+
+// Token is a short textual word, such as the gzip in `gzip;q=1`. It is kept
+// apart from string so that the two serialize differently: Token("gzip") is
+// written bare, and "gzip" in quotes.
+type Token string
+
+// DisplayString is a Unicode string, written percent-encoded as %"...".
+// Plain strings are limited to printable ASCII.
+type DisplayString string
+
+// Param is a single parameter of an item or inner list.
+type Param struct {
+	Key   string
+	Value any // A bare item; true for a parameter sent without a value
+}
+
+// Params are the parameters of an item or inner list, in order.
+type Params []Param
+
+// Get returns the value of the parameter named key.
+func (p Params) Get(key string) (value any, ok bool) {
+	for _, param := range p {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the value of key in place if present, and appends it
+// otherwise. index maps the keys of p to their positions, so that parsing
+// stays linear in the number of parameters.
+func (p *Params) set(key string, value any, index map[string]int) {
+	if i, ok := index[key]; ok {
+		(*p)[i].Value = value
+		return
+	}
+	index[key] = len(*p)
+	*p = append(*p, Param{Key: key, Value: value})
+}
+
+// Member is a member of a List or Dictionary: an Item or an InnerList.
+type Member interface {
+	member()
+}
+
+// Item is a bare item with parameters.
+type Item struct {
+	Value  any
+	Params Params
+}
+
+// InnerList is a parenthesized list of items with parameters of its own.
+type InnerList struct {
+	Items  []Item
+	Params Params
+}
+
+func (Item) member()      {}
+func (InnerList) member() {}
+
+// List is a structured field list.
+type List []Member
+
+// DictMember is a single key and member of a Dictionary.
+type DictMember struct {
+	Key   string
+	Value Member
+}
+
+// Dictionary is a structured field dictionary, in order.
+type Dictionary []DictMember
+
+// Get returns the member named key.
+func (d Dictionary) Get(key string) (value Member, ok bool) {
+	for _, m := range d {
+		if m.Key == key {
+			return m.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the member named key in place if present, and appends it
+// otherwise. index maps the keys of d to their positions, as for Params.set.
+func (d *Dictionary) set(key string, value Member, index map[string]int) {
+	if i, ok := index[key]; ok {
+		(*d)[i].Value = value
+		return
+	}
+	index[key] = len(*d)
+	*d = append(*d, DictMember{Key: key, Value: value})
+}
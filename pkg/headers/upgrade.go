@@ -0,0 +1,94 @@
+package headers
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// UpgradeOffer is one protocol of an Upgrade header, such as websocket or
+// HTTP/2.0.
+type UpgradeOffer struct {
+	Name    string // Protocol name, such as "websocket" or "HTTP"
+	Version string // Protocol version, such as "2.0", or ""
+}
+
+// String returns the offer as written in an Upgrade header, "name/version" or
+// "name".
+func (o UpgradeOffer) String() string {
+	if o.Version == "" {
+		return o.Name
+	}
+	return o.Name + "/" + o.Version
+}
+
+// ParseUpgrade parses Upgrade header values, comma-separated lists of
+// protocols in the client's order of preference, as specified by RFC 9110,
+// section 7.8. Invalid elements are skipped.
+//
+// Example:
+//
+//	headers.ParseUpgrade("h2c, websocket, HTTP/2.0")
+//	// [{h2c } {websocket } {HTTP 2.0}]
+func ParseUpgrade(values ...string) []UpgradeOffer {
+	var offers []UpgradeOffer
+	for _, v := range values {
+		for element := range strings.SplitSeq(v, ",") {
+			name, version, hasVersion := strings.Cut(textproto.TrimString(element), "/")
+			if !isToken(name) || hasVersion && !isToken(version) {
+				continue
+			}
+			offers = append(offers, UpgradeOffer{Name: name, Version: version})
+		}
+	}
+	return offers
+}
+
+// WantsUpgrade reports whether r asks to switch to protocol: its Connection
+// header includes "upgrade", and its Upgrade header offers protocol. Protocol
+// names are compared case-insensitively; a protocol without a version, such
+// as "websocket", matches any version offered, while "HTTP/2.0" only matches
+// that version.
+//
+// Example:
+//
+//	if headers.WantsUpgrade(r, "websocket") {
+//	    serveWebSocket(w, r)
+//	    return
+//	}
+func WantsUpgrade(r *http.Request, protocol string) bool {
+	if !hasToken(r.Header, Connection, "upgrade") {
+		return false
+	}
+	name, version, hasVersion := strings.Cut(protocol, "/")
+	for _, offer := range ParseUpgrade(r.Header.Values(Upgrade)...) {
+		if strings.EqualFold(offer.Name, name) && (!hasVersion || offer.Version == version) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteSwitchingProtocols answers an upgrade request with 101 Switching
+// Protocols, Connection: Upgrade and Upgrade: protocol, plus the headers of
+// extra, such as Sec-WebSocket-Accept, which replace any of the same name
+// already set on w.
+//
+// With net/http, hijack the connection right after: the response is written
+// to it then, and the new protocol takes over.
+//
+// Example:
+//
+//	headers.WriteSwitchingProtocols(w, "websocket", http.Header{
+//	    headers.SecWebSocketAccept: {headers.ComputeAccept(key)},
+//	})
+//	conn, rw, err := http.NewResponseController(w).Hijack()
+func WriteSwitchingProtocols(w http.ResponseWriter, protocol string, extra http.Header) {
+	h := w.Header()
+	h.Set(Connection, "Upgrade")
+	h.Set(Upgrade, protocol)
+	for name, values := range extra {
+		h[textproto.CanonicalMIMEHeaderKey(name)] = append([]string(nil), values...)
+	}
+	w.WriteHeader(http.StatusSwitchingProtocols)
+}
@@ -0,0 +1,138 @@
+package headers_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestParseUpgrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []headers.UpgradeOffer
+	}{
+		{"single", []string{"websocket"}, []headers.UpgradeOffer{{Name: "websocket"}}},
+		{"versions", []string{"HTTP/2.0, SHTTP/1.3"}, []headers.UpgradeOffer{{Name: "HTTP", Version: "2.0"}, {Name: "SHTTP", Version: "1.3"}}},
+		{"repeated headers", []string{"h2c", " websocket "}, []headers.UpgradeOffer{{Name: "h2c"}, {Name: "websocket"}}},
+		{"invalid skipped", []string{"web socket, , /1, HTTP/, a/b/c, IRC/6.9"}, []headers.UpgradeOffer{{Name: "IRC", Version: "6.9"}}},
+		{"empty", []string{""}, nil},
+		{"missing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headers.ParseUpgrade(tt.values...); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseUpgrade(%q) = %v, want %v", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpgradeOfferString(t *testing.T) {
+	if got := (headers.UpgradeOffer{Name: "HTTP", Version: "2.0"}).String(); got != "HTTP/2.0" {
+		t.Errorf("String() = %q, want %q", got, "HTTP/2.0")
+	}
+	if got := (headers.UpgradeOffer{Name: "websocket"}).String(); got != "websocket" {
+		t.Errorf("String() = %q, want %q", got, "websocket")
+	}
+}
+
+func TestWantsUpgrade(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection string
+		upgrade    string
+		protocol   string
+		expected   bool
+	}{
+		{"websocket", "Upgrade", "websocket", "websocket", true},
+		{"case-insensitive", "keep-alive, UPGRADE", "WebSocket", "websocket", true},
+		{"among offers", "Upgrade, HTTP2-Settings", "h2c, websocket", "h2c", true},
+		{"any version", "Upgrade", "HTTP/2.0", "HTTP", true},
+		{"exact version", "Upgrade", "HTTP/2.0", "HTTP/2.0", true},
+		{"other version", "Upgrade", "HTTP/3.0", "HTTP/2.0", false},
+		{"not offered", "Upgrade", "h2c", "websocket", false},
+		{"no connection token", "keep-alive", "websocket", "websocket", false},
+		{"no upgrade header", "Upgrade", "", "websocket", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(headers.Connection, tt.connection)
+			if tt.upgrade != "" {
+				r.Header.Set(headers.Upgrade, tt.upgrade)
+			}
+			if got := headers.WantsUpgrade(r, tt.protocol); got != tt.expected {
+				t.Errorf("WantsUpgrade(%q) = %v, want %v", tt.protocol, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteSwitchingProtocols(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(headers.Connection, "close")
+	headers.WriteSwitchingProtocols(w, "websocket", http.Header{
+		"sec-websocket-accept": {"s3pPLMBiTxaQ9kYGzzhZRbK+xOo="},
+	})
+
+	if w.Code != http.StatusSwitchingProtocols {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusSwitchingProtocols)
+	}
+	for name, want := range map[string]string{
+		headers.Connection:         "Upgrade",
+		headers.Upgrade:            "websocket",
+		headers.SecWebSocketAccept: "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=",
+	} {
+		if got := w.Header().Values(name); len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWriteSwitchingProtocolsHijack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !headers.WantsUpgrade(r, "echo") {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		headers.WriteSwitchingProtocols(w, "echo", nil)
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error = %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		line, _ := rw.ReadString('\n')
+		_, _ = rw.WriteString(line)
+		_ = rw.Flush()
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n"))
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("ReadResponse() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get(headers.Upgrade) != "echo" {
+		t.Fatalf("response = %d, Upgrade %q", resp.StatusCode, resp.Header.Get(headers.Upgrade))
+	}
+	_, _ = conn.Write([]byte("hello\n"))
+	if line, _ := br.ReadString('\n'); line != "hello\n" {
+		t.Errorf("echo = %q, want %q", line, "hello\n")
+	}
+}
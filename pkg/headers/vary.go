@@ -0,0 +1,75 @@
+package headers
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// VaryFields returns the request header names listed by the Vary headers of
+// h, without duplicates, in the order they first appear. Names are compared
+// case-insensitively and returned as written. A response that varies on
+// everything, with a "*" member, yields just "*".
+//
+// Example:
+//
+//	// Vary: Accept-Encoding, origin
+//	// Vary: Origin
+//	headers.VaryFields(h)  // ["Accept-Encoding", "origin"]
+func VaryFields(h http.Header) []string {
+	var fields []string
+	for _, v := range h.Values(Vary) {
+		for field := range strings.SplitSeq(v, ",") {
+			field = textproto.TrimString(field)
+			if field == "*" {
+				return []string{"*"}
+			}
+			if field != "" && !containsFold(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// AddVary adds the request header names fields to the Vary header of h, for
+// middleware whose response depends on them. Names already listed, in any
+// case, are left out, and the others are added as one more Vary line, so
+// that existing lines are kept as they are.
+//
+// A response that varies on "*" cannot be matched by caches at all, so
+// nothing is added to it, and adding "*" replaces the Vary header with just
+// "*".
+//
+// Example:
+//
+//	headers.AddVary(w.Header(), headers.Origin, headers.AcceptEncoding)
+func AddVary(h http.Header, fields ...string) {
+	existing := VaryFields(h)
+	if len(existing) == 1 && existing[0] == "*" {
+		return
+	}
+	var added []string
+	for _, field := range fields {
+		field = textproto.TrimString(field)
+		if field == "*" {
+			h.Set(Vary, "*")
+			return
+		}
+		if field != "" && !containsFold(existing, field) && !containsFold(added, field) {
+			added = append(added, field)
+		}
+	}
+	if len(added) > 0 {
+		h.Add(Vary, strings.Join(added, ", "))
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, element := range list {
+		if strings.EqualFold(element, s) {
+			return true
+		}
+	}
+	return false
+}
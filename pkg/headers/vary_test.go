@@ -0,0 +1,67 @@
+package headers_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestVaryFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []string
+	}{
+		{"single", []string{"Accept-Encoding"}, []string{"Accept-Encoding"}},
+		{"list", []string{"Accept-Encoding, Origin"}, []string{"Accept-Encoding", "Origin"}},
+		{"repeated headers", []string{"Accept-Encoding", "Origin"}, []string{"Accept-Encoding", "Origin"}},
+		{"duplicates", []string{"Accept-Encoding, origin", "Origin, ACCEPT-ENCODING"}, []string{"Accept-Encoding", "origin"}},
+		{"empty elements", []string{" , Origin,,"}, []string{"Origin"}},
+		{"star", []string{"Origin", "*"}, []string{"*"}},
+		{"missing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{headers.Vary: tt.values}
+			if got := headers.VaryFields(h); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("VaryFields() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		fields   []string
+		expected []string
+	}{
+		{"empty", nil, []string{headers.Origin}, []string{"Origin"}},
+		{"several", nil, []string{headers.Origin, headers.AcceptEncoding}, []string{"Origin, Accept-Encoding"}},
+		{"appended", []string{"Accept-Encoding"}, []string{headers.Origin}, []string{"Accept-Encoding", "Origin"}},
+		{"already listed", []string{"accept-encoding, origin"}, []string{headers.Origin, headers.AcceptEncoding}, []string{"accept-encoding, origin"}},
+		{"some listed", []string{"Origin"}, []string{headers.Origin, headers.Accept}, []string{"Origin", "Accept"}},
+		{"duplicate arguments", nil, []string{headers.Origin, "origin"}, []string{"Origin"}},
+		{"empty names", nil, []string{"", " "}, nil},
+		{"add star", []string{"Origin"}, []string{headers.Accept, "*"}, []string{"*"}},
+		{"existing star", []string{"*"}, []string{headers.Origin}, []string{"*"}},
+		{"none", []string{"Origin"}, nil, []string{"Origin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.existing != nil {
+				h[headers.Vary] = tt.existing
+			}
+			headers.AddVary(h, tt.fields...)
+			if got := h.Values(headers.Vary); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Vary = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
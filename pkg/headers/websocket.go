@@ -0,0 +1,129 @@
+package headers
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+var (
+	// ErrInvalidWebSocketHandshake is returned by ValidateUpgrade for a
+	// request that is not a valid WebSocket opening handshake. Answer it with
+	// 400 Bad Request.
+	ErrInvalidWebSocketHandshake = errors.New("headers: invalid WebSocket handshake")
+
+	// ErrUnsupportedWebSocketVersion is returned by ValidateUpgrade for a
+	// handshake asking for another protocol version than WebSocketVersion.
+	// Answer it with 426 Upgrade Required and a Sec-WebSocket-Version header
+	// naming WebSocketVersion, so that the client can retry.
+	ErrUnsupportedWebSocketVersion = errors.New("headers: unsupported WebSocket version")
+)
+
+// WebSocketVersion is the Sec-WebSocket-Version of RFC 6455, the only one in
+// use.
+const WebSocketVersion = "13"
+
+// websocketGUID is appended to the key to compute Sec-WebSocket-Accept, from
+// RFC 6455, section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ComputeAccept returns the Sec-WebSocket-Accept value answering the
+// Sec-WebSocket-Key key: the base64-encoded SHA-1 of the key followed by the
+// GUID of RFC 6455. Servers send it in their 101 response, and clients check
+// it.
+func ComputeAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// GenerateKey returns a new Sec-WebSocket-Key for a client handshake: 16
+// random bytes, base64-encoded.
+func GenerateKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // never fails, see crypto/rand.Read
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// ValidateUpgrade checks that r is a WebSocket opening handshake, as
+// specified by RFC 6455, section 4.2.1: a GET request over HTTP/1.1 or later
+// whose Connection header includes "upgrade", whose Upgrade header includes
+// "websocket", with Sec-WebSocket-Version 13 and a Sec-WebSocket-Key of 16
+// base64-encoded bytes.
+//
+// Returns an error wrapping ErrUnsupportedWebSocketVersion for another
+// version, and one wrapping ErrInvalidWebSocketHandshake otherwise. The
+// Origin header is not checked; use an OriginMatcher for that.
+//
+// Example:
+//
+//	if err := headers.ValidateUpgrade(r); err != nil {
+//	    if errors.Is(err, headers.ErrUnsupportedWebSocketVersion) {
+//	        w.Header().Set(headers.SecWebSocketVersion, headers.WebSocketVersion)
+//	        http.Error(w, err.Error(), http.StatusUpgradeRequired)
+//	        return
+//	    }
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+func ValidateUpgrade(r *http.Request) error {
+	switch {
+	case r.Method != http.MethodGet:
+		return fmt.Errorf("%w: method %s", ErrInvalidWebSocketHandshake, r.Method)
+	case !r.ProtoAtLeast(1, 1):
+		return fmt.Errorf("%w: %s", ErrInvalidWebSocketHandshake, r.Proto)
+	case !hasToken(r.Header, Connection, "upgrade"):
+		return fmt.Errorf("%w: Connection does not include upgrade", ErrInvalidWebSocketHandshake)
+	case !hasToken(r.Header, Upgrade, "websocket"):
+		return fmt.Errorf("%w: Upgrade does not include websocket", ErrInvalidWebSocketHandshake)
+	}
+	if version := r.Header.Get(SecWebSocketVersion); version != WebSocketVersion {
+		return fmt.Errorf("%w: %q", ErrUnsupportedWebSocketVersion, version)
+	}
+	key, err := base64.StdEncoding.DecodeString(r.Header.Get(SecWebSocketKey))
+	if err != nil || len(key) != 16 {
+		return fmt.Errorf("%w: invalid Sec-WebSocket-Key", ErrInvalidWebSocketHandshake)
+	}
+	return nil
+}
+
+// NegotiateSubprotocol returns the subprotocol to answer in
+// Sec-WebSocket-Protocol: the first one the client offers, in its order of
+// preference, that is also in supported. Names are compared
+// case-sensitively. Returns "" if there is none in common, in which case the
+// response must not carry Sec-WebSocket-Protocol.
+//
+// Example:
+//
+//	// Sec-WebSocket-Protocol: graphql-transport-ws, graphql-ws
+//	proto := headers.NegotiateSubprotocol(r, "graphql-ws")  // "graphql-ws"
+func NegotiateSubprotocol(r *http.Request, supported ...string) string {
+	for _, v := range r.Header.Values(SecWebSocketProtocol) {
+		for offered := range strings.SplitSeq(v, ",") {
+			offered = textproto.TrimString(offered)
+			for _, s := range supported {
+				if offered != "" && offered == s {
+					return s
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// hasToken reports whether a comma-separated list header of h includes token,
+// compared case-insensitively.
+func hasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for element := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(textproto.TrimString(element), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package headers_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestComputeAccept(t *testing.T) {
+	// RFC 6455, section 1.3.
+	if got := headers.ComputeAccept("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("ComputeAccept() = %q, want %q", got, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	key := headers.GenerateKey()
+	b, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(b) != 16 {
+		t.Errorf("GenerateKey() = %q, want 16 base64-encoded bytes", key)
+	}
+	if headers.GenerateKey() == key {
+		t.Error("GenerateKey() returned the same key twice")
+	}
+}
+
+// handshake returns a valid WebSocket opening handshake.
+func handshake() *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/chat", nil)
+	r.Header.Set(headers.Connection, "Upgrade")
+	r.Header.Set(headers.Upgrade, "websocket")
+	r.Header.Set(headers.SecWebSocketVersion, "13")
+	r.Header.Set(headers.SecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+	return r
+}
+
+func TestValidateUpgrade(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *http.Request)
+		err    error
+	}{
+		{"valid", func(*http.Request) {}, nil},
+		{"connection list", func(r *http.Request) { r.Header.Set(headers.Connection, "keep-alive, Upgrade") }, nil},
+		{"upgrade list", func(r *http.Request) { r.Header.Set(headers.Upgrade, "h2c, WebSocket") }, nil},
+		{"method", func(r *http.Request) { r.Method = http.MethodPost }, headers.ErrInvalidWebSocketHandshake},
+		{"HTTP/1.0", func(r *http.Request) { r.Proto, r.ProtoMajor, r.ProtoMinor = "HTTP/1.0", 1, 0 }, headers.ErrInvalidWebSocketHandshake},
+		{"no connection", func(r *http.Request) { r.Header.Del(headers.Connection) }, headers.ErrInvalidWebSocketHandshake},
+		{"connection without upgrade", func(r *http.Request) { r.Header.Set(headers.Connection, "keep-alive") }, headers.ErrInvalidWebSocketHandshake},
+		{"no upgrade", func(r *http.Request) { r.Header.Del(headers.Upgrade) }, headers.ErrInvalidWebSocketHandshake},
+		{"other upgrade", func(r *http.Request) { r.Header.Set(headers.Upgrade, "h2c") }, headers.ErrInvalidWebSocketHandshake},
+		{"no version", func(r *http.Request) { r.Header.Del(headers.SecWebSocketVersion) }, headers.ErrUnsupportedWebSocketVersion},
+		{"old version", func(r *http.Request) { r.Header.Set(headers.SecWebSocketVersion, "8") }, headers.ErrUnsupportedWebSocketVersion},
+		{"no key", func(r *http.Request) { r.Header.Del(headers.SecWebSocketKey) }, headers.ErrInvalidWebSocketHandshake},
+		{"short key", func(r *http.Request) { r.Header.Set(headers.SecWebSocketKey, "c2hvcnQ=") }, headers.ErrInvalidWebSocketHandshake},
+		{"malformed key", func(r *http.Request) { r.Header.Set(headers.SecWebSocketKey, "not base64!") }, headers.ErrInvalidWebSocketHandshake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := handshake()
+			tt.modify(r)
+			if err := headers.ValidateUpgrade(r); !errors.Is(err, tt.err) {
+				t.Errorf("ValidateUpgrade() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNegotiateSubprotocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		offered   []string
+		supported []string
+		expected  string
+	}{
+		{"client preference", []string{"graphql-transport-ws, graphql-ws"}, []string{"graphql-ws", "graphql-transport-ws"}, "graphql-transport-ws"},
+		{"repeated headers", []string{"mqtt", "chat"}, []string{"chat"}, "chat"},
+		{"case-sensitive", []string{"Chat"}, []string{"chat"}, ""},
+		{"none in common", []string{"v1.proto"}, []string{"v2.proto"}, ""},
+		{"empty elements", []string{" , chat"}, []string{"", "chat"}, "chat"},
+		{"not offered", nil, []string{"chat"}, ""},
+		{"none supported", []string{"chat"}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := handshake()
+			for _, v := range tt.offered {
+				r.Header.Add(headers.SecWebSocketProtocol, v)
+			}
+			if got := headers.NegotiateSubprotocol(r, tt.supported...); got != tt.expected {
+				t.Errorf("NegotiateSubprotocol() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package headers
+
+import (
+	"maps"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// String formats e as a Forwarded element, such as
+// `for="[2001:db8::17]:4711";host=example.com;proto=https`. Parameters are
+// written in the order for, by, host, proto, then extensions sorted by name,
+// and unset ones are left out. Bare IPv6 addresses in For and By are
+// bracketed, and values that are not tokens are quoted.
+func (e ForwardedElement) String() string {
+	var pairs []string
+	add := func(name, value string) {
+		if value != "" {
+			pairs = append(pairs, name+"="+forwardedValue(value))
+		}
+	}
+	add("for", bracketNode(e.For))
+	add("by", bracketNode(e.By))
+	add("host", e.Host)
+	add("proto", e.Proto)
+	for _, name := range slices.Sorted(maps.Keys(e.Ext)) {
+		add(name, e.Ext[name])
+	}
+	return strings.Join(pairs, ";")
+}
+
+// FormatForwarded joins elements into a single Forwarded value.
+func FormatForwarded(elems ...ForwardedElement) string {
+	parts := make([]string, len(elems))
+	for i, e := range elems {
+		parts[i] = e.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func bracketNode(node string) string {
+	if addr, err := netip.ParseAddr(node); err == nil && addr.Is6() {
+		return "[" + node + "]"
+	}
+	return node
+}
+
+func forwardedValue(value string) string {
+	for i := range len(value) {
+		if !isTokenChar(value[i]) {
+			var b strings.Builder
+			b.WriteByte('"')
+			writeQuotedContent(&b, value)
+			b.WriteByte('"')
+			return b.String()
+		}
+	}
+	return value
+}
+
+// AppendXForwardedFor adds addr to the end of the X-Forwarded-For header,
+// merging any existing lines into one comma-separated value.
+func AppendXForwardedFor(h http.Header, addr netip.Addr) {
+	appendList(h, XForwardedFor, addr.String())
+}
+
+// SetXForwarded sets the X-Forwarded-* headers of out, the header of a request
+// a reverse proxy sends upstream, from in, the request the proxy received:
+//
+//   - X-Forwarded-For gets the address of in's peer appended
+//   - X-Forwarded-Host is set to in.Host
+//   - X-Forwarded-Proto is set to "https" or "http", depending on in.TLS
+//   - X-Forwarded-Port is set to the port in in.Host, or the default port
+//
+// Any X-Forwarded-For already in out is kept and appended to, so delete it
+// first when the peer is not a trusted proxy.
+//
+// Example:
+//
+//	proxy := &httputil.ReverseProxy{
+//	    Rewrite: func(pr *httputil.ProxyRequest) {
+//	        pr.SetURL(upstream)
+//	        headers.SetXForwarded(pr.Out.Header, pr.In)
+//	    },
+//	}
+func SetXForwarded(out http.Header, in *http.Request) {
+	if peer, ok := parseNode(in.RemoteAddr); ok {
+		AppendXForwardedFor(out, peer)
+	}
+	out.Set(XForwardedHost, in.Host)
+	out.Set(XForwardedProto, requestScheme(in))
+	out.Set(XForwardedPort, requestPort(in))
+}
+
+// AppendForwarded adds an element describing the hop from in's peer to the
+// Forwarded header of out, merging any existing lines into one value. It is
+// the RFC 7239 counterpart of SetXForwarded. The peer is written as "unknown"
+// if in.RemoteAddr is not an IP address.
+func AppendForwarded(out http.Header, in *http.Request) {
+	e := ForwardedElement{For: "unknown", Host: in.Host, Proto: requestScheme(in)}
+	if peer, ok := parseNode(in.RemoteAddr); ok {
+		e.For = peer.String()
+	}
+	appendList(out, Forwarded, e.String())
+}
+
+// ConvertXForwarded replaces the X-Forwarded-* headers in h with an
+// equivalent Forwarded header, replacing any Forwarded header already there.
+// Each X-Forwarded-For address becomes an element, and X-Forwarded-Host and
+// X-Forwarded-Proto, which the first proxy set, are added to the first one.
+// X-Forwarded-Port has no Forwarded equivalent and is dropped.
+func ConvertXForwarded(h http.Header) {
+	var elems []ForwardedElement
+	for _, v := range h.Values(XForwardedFor) {
+		for node := range strings.SplitSeq(v, ",") {
+			if node = strings.TrimSpace(node); node != "" {
+				elems = append(elems, ForwardedElement{For: node})
+			}
+		}
+	}
+
+	host, proto := firstListElement(h.Get(XForwardedHost)), firstListElement(h.Get(XForwardedProto))
+	if len(elems) == 0 && (host != "" || proto != "") {
+		elems = append(elems, ForwardedElement{})
+	}
+	if len(elems) > 0 {
+		elems[0].Host = host
+		elems[0].Proto = proto
+		h.Set(Forwarded, FormatForwarded(elems...))
+	}
+
+	for _, name := range []string{XForwardedFor, XForwardedHost, XForwardedProto, XForwardedPort} {
+		h.Del(name)
+	}
+}
+
+func appendList(h http.Header, name, value string) {
+	if prior := h.Values(name); len(prior) > 0 {
+		value = strings.Join(prior, ", ") + ", " + value
+	}
+	h.Set(name, value)
+}
+
+func firstListElement(s string) string {
+	first, _, _ := strings.Cut(s, ",")
+	return strings.TrimSpace(first)
+}
+
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func requestPort(r *http.Request) string {
+	if i := strings.LastIndexByte(r.Host, ':'); i > strings.LastIndexByte(r.Host, ']') {
+		return r.Host[i+1:]
+	}
+	if r.TLS != nil {
+		return "443"
+	}
+	return "80"
+}
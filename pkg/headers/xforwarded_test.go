@@ -0,0 +1,178 @@
+package headers_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestForwardedElementString(t *testing.T) {
+	tests := []struct {
+		name     string
+		elem     headers.ForwardedElement
+		expected string
+	}{
+		{"ipv4", headers.ForwardedElement{For: "192.0.2.60", Proto: "http", By: "203.0.113.43"}, "for=192.0.2.60;by=203.0.113.43;proto=http"},
+		{"bare ipv6", headers.ForwardedElement{For: "2001:db8::17"}, `for="[2001:db8::17]"`},
+		{"bracketed ipv6", headers.ForwardedElement{For: "[2001:db8::17]:4711"}, `for="[2001:db8::17]:4711"`},
+		{"host with port", headers.ForwardedElement{For: "unknown", Host: "example.com:8080"}, `for=unknown;host="example.com:8080"`},
+		{"extensions", headers.ForwardedElement{For: "_x", Ext: map[string]string{"z": "1", "a": `"q"`}}, `for=_x;a="\"q\"";z=1`},
+		{"empty", headers.ForwardedElement{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.elem.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatForwardedRoundTrip(t *testing.T) {
+	value := headers.FormatForwarded(
+		headers.ForwardedElement{For: "2001:db8::17", Host: "example.com:8443", Proto: "https"},
+		headers.ForwardedElement{For: "10.0.0.1"},
+	)
+	elems, err := headers.ParseForwarded(value)
+	if err != nil {
+		t.Fatalf("ParseForwarded(%q) error = %v", value, err)
+	}
+	if len(elems) != 2 || elems[0].Host != "example.com:8443" || elems[1].For != "10.0.0.1" {
+		t.Fatalf("ParseForwarded(%q) = %+v", value, elems)
+	}
+	if addr, ok := elems[0].ForAddr(); !ok || addr != netip.MustParseAddr("2001:db8::17") {
+		t.Errorf("ForAddr() = %v, %v", addr, ok)
+	}
+}
+
+func TestAppendXForwardedFor(t *testing.T) {
+	h := http.Header{}
+	h.Add(headers.XForwardedFor, "192.0.2.1")
+	h.Add(headers.XForwardedFor, "192.0.2.2, 192.0.2.3")
+	headers.AppendXForwardedFor(h, netip.MustParseAddr("2001:db8::1"))
+
+	expected := []string{"192.0.2.1, 192.0.2.2, 192.0.2.3, 2001:db8::1"}
+	if got := h.Values(headers.XForwardedFor); len(got) != 1 || got[0] != expected[0] {
+		t.Errorf("X-Forwarded-For = %q, want %q", got, expected)
+	}
+}
+
+func TestSetXForwarded(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		remote   string
+		tls      bool
+		prior    string
+		expected map[string]string
+	}{
+		{
+			name:   "http default port",
+			host:   "example.com",
+			remote: "192.0.2.1:5555",
+			expected: map[string]string{
+				headers.XForwardedFor: "192.0.2.1", headers.XForwardedHost: "example.com",
+				headers.XForwardedProto: "http", headers.XForwardedPort: "80",
+			},
+		},
+		{
+			name:   "https explicit port and chain",
+			host:   "[2001:db8::1]:8443",
+			remote: "[2001:db8::2]:5555",
+			tls:    true,
+			prior:  "198.51.100.7",
+			expected: map[string]string{
+				headers.XForwardedFor: "198.51.100.7, 2001:db8::2", headers.XForwardedHost: "[2001:db8::1]:8443",
+				headers.XForwardedProto: "https", headers.XForwardedPort: "8443",
+			},
+		},
+		{
+			name:   "https default port",
+			host:   "example.com",
+			remote: "@",
+			tls:    true,
+			expected: map[string]string{
+				headers.XForwardedFor: "", headers.XForwardedHost: "example.com",
+				headers.XForwardedProto: "https", headers.XForwardedPort: "443",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := httptest.NewRequest("GET", "/", nil)
+			in.Host = tt.host
+			in.RemoteAddr = tt.remote
+			if tt.tls {
+				in.TLS = &tls.ConnectionState{}
+			}
+			out := http.Header{}
+			if tt.prior != "" {
+				out.Set(headers.XForwardedFor, tt.prior)
+			}
+
+			headers.SetXForwarded(out, in)
+			for name, want := range tt.expected {
+				if got := out.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendForwarded(t *testing.T) {
+	in := httptest.NewRequest("GET", "/", nil)
+	in.Host = "example.com"
+	in.RemoteAddr = "[2001:db8::2]:5555"
+	in.TLS = &tls.ConnectionState{}
+
+	out := http.Header{}
+	out.Set(headers.Forwarded, "for=198.51.100.7")
+	headers.AppendForwarded(out, in)
+
+	expected := `for=198.51.100.7, for="[2001:db8::2]";host=example.com;proto=https`
+	if got := out.Get(headers.Forwarded); got != expected {
+		t.Errorf("Forwarded = %q, want %q", got, expected)
+	}
+
+	in.RemoteAddr = "@"
+	out = http.Header{}
+	headers.AppendForwarded(out, in)
+	if got := out.Get(headers.Forwarded); got != "for=unknown;host=example.com;proto=https" {
+		t.Errorf("Forwarded = %q", got)
+	}
+}
+
+func TestConvertXForwarded(t *testing.T) {
+	h := http.Header{}
+	h.Set(headers.XForwardedFor, "192.0.2.1, 2001:db8::2")
+	h.Set(headers.XForwardedHost, "example.com")
+	h.Set(headers.XForwardedProto, "https, http")
+	h.Set(headers.XForwardedPort, "443")
+	h.Set(headers.Forwarded, "for=stale")
+
+	headers.ConvertXForwarded(h)
+
+	expected := `for=192.0.2.1;host=example.com;proto=https, for="[2001:db8::2]"`
+	if got := h.Get(headers.Forwarded); got != expected {
+		t.Errorf("Forwarded = %q, want %q", got, expected)
+	}
+	for _, name := range []string{headers.XForwardedFor, headers.XForwardedHost, headers.XForwardedProto, headers.XForwardedPort} {
+		if _, ok := h[http.CanonicalHeaderKey(name)]; ok {
+			t.Errorf("%s was not removed", name)
+		}
+	}
+
+	h = http.Header{}
+	h.Set(headers.XForwardedProto, "https")
+	headers.ConvertXForwarded(h)
+	if got := h.Get(headers.Forwarded); got != "proto=https" {
+		t.Errorf("Forwarded = %q, want %q", got, "proto=https")
+	}
+}
@@ -0,0 +1,70 @@
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidXFrameOptions is returned for an X-Frame-Options value other than
+// DENY or SAMEORIGIN, or for conflicting values.
+var ErrInvalidXFrameOptions = errors.New("headers: invalid X-Frame-Options header")
+
+// XFrameOptionsValue is a value of the X-Frame-Options header, as defined by
+// the WHATWG HTML standard, section 7.1.2.
+type XFrameOptionsValue string
+
+// X-Frame-Options values. ALLOW-FROM is obsolete and ignored by browsers; use
+// the frame-ancestors directive of Content-Security-Policy to allow specific
+// origins instead.
+const (
+	XFODeny       XFrameOptionsValue = "DENY"       // The page cannot be framed at all
+	XFOSameOrigin XFrameOptionsValue = "SAMEORIGIN" // Only pages of the same origin can frame it
+)
+
+// Valid reports whether v is XFODeny or XFOSameOrigin.
+func (v XFrameOptionsValue) Valid() bool {
+	return v == XFODeny || v == XFOSameOrigin
+}
+
+// SetXFrameOptions sets the X-Frame-Options header of w. Returns an error
+// wrapping ErrInvalidXFrameOptions, and leaves w unchanged, if v is not
+// XFODeny or XFOSameOrigin; ALLOW-FROM in particular is rejected, since
+// browsers ignore the header entirely when it carries that value.
+func SetXFrameOptions(w http.ResponseWriter, v XFrameOptionsValue) error {
+	if !v.Valid() {
+		if strings.HasPrefix(strings.ToUpper(string(v)), "ALLOW-FROM") {
+			return fmt.Errorf("%w: ALLOW-FROM is obsolete, use CSP frame-ancestors", ErrInvalidXFrameOptions)
+		}
+		return fmt.Errorf("%w: %q", ErrInvalidXFrameOptions, string(v))
+	}
+	w.Header().Set(XFrameOptions, string(v))
+	return nil
+}
+
+// ParseXFrameOptions returns the value a browser applies for the given
+// X-Frame-Options header values, following the HTML standard: values are
+// comma-separated lists compared case-insensitively, and repeating the same
+// value is allowed. Returns ErrInvalidXFrameOptions if the header is missing,
+// holds an unknown value, or mixes DENY and SAMEORIGIN, all of which browsers
+// treat as no protection at all.
+func ParseXFrameOptions(values ...string) (XFrameOptionsValue, error) {
+	var result XFrameOptionsValue
+	for _, value := range values {
+		for token := range strings.SplitSeq(value, ",") {
+			v := XFrameOptionsValue(strings.ToUpper(strings.TrimSpace(token)))
+			if !v.Valid() {
+				return "", fmt.Errorf("%w: %q", ErrInvalidXFrameOptions, strings.TrimSpace(token))
+			}
+			if result != "" && v != result {
+				return "", fmt.Errorf("%w: conflicting values", ErrInvalidXFrameOptions)
+			}
+			result = v
+		}
+	}
+	if result == "" {
+		return "", ErrInvalidXFrameOptions
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package headers_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+func TestSetXFrameOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    headers.XFrameOptionsValue
+		expected string
+		err      error
+	}{
+		{"deny", headers.XFODeny, "DENY", nil},
+		{"sameorigin", headers.XFOSameOrigin, "SAMEORIGIN", nil},
+		{"allow-from", "ALLOW-FROM https://example.com", "", headers.ErrInvalidXFrameOptions},
+		{"lower case", "deny", "", headers.ErrInvalidXFrameOptions},
+		{"empty", "", "", headers.ErrInvalidXFrameOptions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := headers.SetXFrameOptions(w, tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SetXFrameOptions() error = %v, want %v", err, tt.err)
+			}
+			if got := w.Header().Get(headers.XFrameOptions); got != tt.expected {
+				t.Errorf("X-Frame-Options = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseXFrameOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		expected  headers.XFrameOptionsValue
+		wantError bool
+	}{
+		{"deny", []string{"DENY"}, headers.XFODeny, false},
+		{"case and spacing", []string{" sameOrigin "}, headers.XFOSameOrigin, false},
+		{"repeated", []string{"deny, DENY", "Deny"}, headers.XFODeny, false},
+		{"conflicting", []string{"DENY, SAMEORIGIN"}, "", true},
+		{"conflicting headers", []string{"SAMEORIGIN", "DENY"}, "", true},
+		{"allow-from", []string{"ALLOW-FROM https://example.com"}, "", true},
+		{"empty", []string{""}, "", true},
+		{"missing", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headers.ParseXFrameOptions(tt.values...)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseXFrameOptions() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil && !errors.Is(err, headers.ErrInvalidXFrameOptions) {
+				t.Errorf("ParseXFrameOptions() error = %v, want %v", err, headers.ErrInvalidXFrameOptions)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseXFrameOptions() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
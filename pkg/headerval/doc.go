@@ -0,0 +1,15 @@
+// Package headerval provides typed getters for request headers with the
+// fail-safe semantics of package query: if a header is missing, empty, or
+// cannot be parsed, the provided default value is returned.
+//
+// Header names are matched case-insensitively and combine naturally with the
+// constants of package headers:
+//
+//	size  := headerval.Int(r, headers.ContentLength, -1)
+//	since := headerval.Time(r, headers.IfModifiedSince, time.Time{})
+//	types := headerval.CSV(r, headers.Accept)
+//
+// Single-value getters read the first line of a header sent several times.
+// CSV combines all lines into one list, as RFC 9110 allows for list-based
+// fields.
+package headerval
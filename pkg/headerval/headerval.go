@@ -0,0 +1,148 @@
+package headerval
+
+import (
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+// std is a query.Extractor reading request headers. Header names are
+// canonicalized before lookup, so key[] merging does not apply.
+var std = query.NewExtractor(query.Config{
+	Source:               headerSource,
+	DisableArrayBrackets: true,
+	TrueValues:           []string{"true", "1", "yes", "on", "y", "?1"},
+	FalseValues:          []string{"false", "0", "no", "off", "n", "?0"},
+})
+
+func headerSource(r *http.Request) url.Values {
+	return url.Values(r.Header)
+}
+
+// Value extracts a header and converts it using the provided parser. If the
+// header is sent on several lines, the first is used. Returns defaultValue if
+// the header is missing, empty, or cannot be parsed.
+//
+// Example:
+//
+//	ip := headerval.Value(r, "X-Real-Ip", netip.Addr{}, netip.ParseAddr)
+func Value[T any](r *http.Request, name string, defaultValue T, parser query.Parser[T]) T {
+	return query.ValueWith(std, r, textproto.CanonicalMIMEHeaderKey(name), defaultValue, parser)
+}
+
+// String extracts a header value.
+// Returns defaultValue if the header is missing or empty.
+func String(r *http.Request, name string, defaultValue string) string {
+	return std.String(r, textproto.CanonicalMIMEHeaderKey(name), defaultValue)
+}
+
+// Int extracts an integer header value.
+// Returns defaultValue if the header is missing, empty, or not an int.
+//
+// Example:
+//
+//	size := headerval.Int(r, headers.ContentLength, -1)
+func Int(r *http.Request, name string, defaultValue int) int {
+	return std.Int(r, textproto.CanonicalMIMEHeaderKey(name), defaultValue)
+}
+
+// Int64 extracts an int64 header value.
+// Returns defaultValue if the header is missing, empty, or not an int64.
+func Int64(r *http.Request, name string, defaultValue int64) int64 {
+	return std.Int64(r, textproto.CanonicalMIMEHeaderKey(name), defaultValue)
+}
+
+// Float64 extracts a float64 header value, such as the Downlink client hint.
+// Returns defaultValue if the header is missing, empty, or not a float64.
+func Float64(r *http.Request, name string, defaultValue float64) float64 {
+	return std.Float64(r, textproto.CanonicalMIMEHeaderKey(name), defaultValue)
+}
+
+// Bool extracts a boolean header value. In addition to the vocabulary of
+// query.Bool, the structured field booleans "?1" and "?0" are recognized, as
+// sent in Sec-CH-UA-Mobile or Sec-Fetch-User.
+// Returns defaultValue if the header is missing, empty, or not recognized.
+func Bool(r *http.Request, name string, defaultValue bool) bool {
+	return std.Bool(r, textproto.CanonicalMIMEHeaderKey(name), defaultValue)
+}
+
+// Time extracts an HTTP-date header value, accepting the three formats of
+// RFC 9110 via http.ParseTime. The result is in UTC.
+// Returns defaultValue if the header is missing, empty, or not a valid date.
+//
+// Example:
+//
+//	since := headerval.Time(r, headers.IfModifiedSince, time.Time{})
+//	if !since.IsZero() && !modified.After(since) {
+//	    w.WriteHeader(http.StatusNotModified)
+//	    return
+//	}
+func Time(r *http.Request, name string, defaultValue time.Time) time.Time {
+	return Value(r, name, defaultValue, http.ParseTime)
+}
+
+// Seconds extracts a delta-seconds header value, as used by Retry-After,
+// Access-Control-Max-Age or Keep-Alive, as a time.Duration. Returns
+// defaultValue if the header is missing, empty, negative, or not an integer.
+func Seconds(r *http.Request, name string, defaultValue time.Duration) time.Duration {
+	return Value(r, name, defaultValue, func(s string) (time.Duration, error) {
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * time.Second, nil
+	})
+}
+
+// CSV returns the elements of a comma-separated list header, such as Accept or
+// Cache-Control, combined across all lines the header was sent on. Elements
+// are trimmed and empty ones dropped; commas inside quoted strings do not
+// split. Returns an empty slice if the header is missing.
+//
+// Example:
+//
+//	// Accept: text/html, application/json;q=0.9
+//	types := headerval.CSV(r, headers.Accept)  // []string{"text/html", "application/json;q=0.9"}
+func CSV(r *http.Request, name string) []string {
+	result := []string{}
+	for _, line := range r.Header.Values(name) {
+		result = appendCSV(result, line)
+	}
+	return result
+}
+
+// Has reports whether the header is present, even if empty.
+func Has(r *http.Request, name string) bool {
+	return std.Has(r, textproto.CanonicalMIMEHeaderKey(name))
+}
+
+// appendCSV appends the list elements of line to dst.
+func appendCSV(dst []string, line string) []string {
+	inQuote, escaped, start := false, false, 0
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case escaped:
+			escaped = false
+		case inQuote && c == '\\':
+			escaped = true
+		case c == '"':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			dst = appendElement(dst, line[start:i])
+			start = i + 1
+		}
+	}
+	return appendElement(dst, line[start:])
+}
+
+func appendElement(dst []string, s string) []string {
+	if s = strings.Trim(s, " \t"); s != "" {
+		dst = append(dst, s)
+	}
+	return dst
+}
@@ -0,0 +1,117 @@
+package headerval_test
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headerval"
+)
+
+func TestExtractors(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(headers.ContentLength, "1024")
+	r.Header.Set(headers.IfModifiedSince, "Sun, 06 Nov 1994 08:49:37 GMT")
+	r.Header.Set(headers.RetryAfter, "120")
+	r.Header.Set("Downlink", "1.7")
+	r.Header.Set("Sec-CH-UA-Mobile", "?1")
+	r.Header.Set("Save-Data", "on")
+	r.Header.Set("X-Real-IP", "203.0.113.7")
+	r.Header.Set("X-Empty", "")
+	r.Header.Add("X-Count", "1")
+	r.Header.Add("X-Count", "2")
+
+	if got := headerval.Int(r, headers.ContentLength, -1); got != 1024 {
+		t.Errorf("Int() = %v, want 1024", got)
+	}
+	if got := headerval.Int64(r, "content-length", -1); got != 1024 {
+		t.Errorf("Int64() = %v, want 1024 for a lowercase name", got)
+	}
+	if got := headerval.Int(r, "X-Count", 0); got != 1 {
+		t.Errorf("Int(X-Count) = %v, want the first line", got)
+	}
+	if got := headerval.Int(r, "X-Real-IP", -1); got != -1 {
+		t.Errorf("Int(invalid) = %v, want default", got)
+	}
+	if got := headerval.Float64(r, "Downlink", 0); got != 1.7 {
+		t.Errorf("Float64() = %v, want 1.7", got)
+	}
+	if !headerval.Bool(r, "Sec-CH-UA-Mobile", false) || !headerval.Bool(r, "Save-Data", false) {
+		t.Error("Bool() = false, want true for ?1 and on")
+	}
+	if got := headerval.Seconds(r, headers.RetryAfter, 0); got != 2*time.Minute {
+		t.Errorf("Seconds() = %v, want 2m", got)
+	}
+	want := time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)
+	if got := headerval.Time(r, headers.IfModifiedSince, time.Time{}); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v", got, want)
+	}
+	if got := headerval.Value(r, "x-real-ip", netip.Addr{}, netip.ParseAddr); got != netip.MustParseAddr("203.0.113.7") {
+		t.Errorf("Value() = %v, want 203.0.113.7", got)
+	}
+	if got := headerval.String(r, "X-Empty", "none"); got != "none" {
+		t.Errorf("String(empty) = %q, want default", got)
+	}
+	if !headerval.Has(r, "x-empty") || headerval.Has(r, "X-Missing") {
+		t.Error("Has() should report present headers, even empty ones")
+	}
+}
+
+func TestTimeFormats(t *testing.T) {
+	want := time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)
+	for _, value := range []string{
+		"Sun, 06 Nov 1994 08:49:37 GMT",
+		"Sunday, 06-Nov-94 08:49:37 GMT",
+		"Sun Nov  6 08:49:37 1994",
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(headers.IfModifiedSince, value)
+		if got := headerval.Time(r, headers.IfModifiedSince, time.Time{}); !got.Equal(want) {
+			t.Errorf("Time(%q) = %v, want %v", value, got, want)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(headers.IfModifiedSince, "2024-01-01")
+	if got := headerval.Time(r, headers.IfModifiedSince, time.Time{}); !got.IsZero() {
+		t.Errorf("Time(invalid) = %v, want default", got)
+	}
+}
+
+func TestSecondsRejectsNegative(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(headers.RetryAfter, "-5")
+	if got := headerval.Seconds(r, headers.RetryAfter, time.Second); got != time.Second {
+		t.Errorf("Seconds() = %v, want default", got)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{"single line", []string{"text/html, application/json;q=0.9"}, []string{"text/html", "application/json;q=0.9"}},
+		{"multiple lines", []string{"gzip", "br, zstd"}, []string{"gzip", "br", "zstd"}},
+		{"empty elements", []string{" , a,, b ,"}, []string{"a", "b"}},
+		{"quoted comma", []string{`no-cache="Set-Cookie, X-Foo", max-age=0`}, []string{`no-cache="Set-Cookie, X-Foo"`, "max-age=0"}},
+		{"escaped quote", []string{`a="x\", y", b`}, []string{`a="x\", y"`, "b"}},
+		{"missing", nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			for _, line := range tt.lines {
+				r.Header.Add(headers.Accept, line)
+			}
+			if got := headerval.CSV(r, "accept"); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CSV() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
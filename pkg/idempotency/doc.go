@@ -0,0 +1,19 @@
+// Package idempotency makes retries of POST and PATCH requests safe with the
+// Idempotency-Key header: a client sends a unique key with a request, and if
+// it has to retry, say after a timeout, the server replays the response of
+// the first attempt instead of charging a card twice.
+//
+// Middleware stores responses through a Store, which can be shared by
+// several servers, and replays them for requests repeating a key within a
+// TTL. MemoryStore is a Store for a single server and for tests:
+//
+//	handler, err := idempotency.Middleware(api, idempotency.Config{
+//	    Store:    idempotency.NewMemoryStore(),
+//	    TTL:      24 * time.Hour,
+//	    Required: true,
+//	})
+//
+// A client retries with the same key and the same request:
+//
+//	Idempotency-Key: "8e03978e-40d5-43e8-bc93-6894a57f9324"
+package idempotency
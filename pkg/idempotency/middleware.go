@@ -0,0 +1,265 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// ErrInvalidConfig is returned by Middleware for a Config with a negative
+// TTL or an invalid method.
+var ErrInvalidConfig = errors.New("idempotency: invalid configuration")
+
+// ReplayedHeader is set to "true" on replayed responses, so that clients can
+// tell them from fresh ones.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// MaxKeyLength is the longest Idempotency-Key accepted, in bytes.
+const MaxKeyLength = 255
+
+// Defaults used for the zero fields of Config.
+const (
+	DefaultTTL     = 24 * time.Hour
+	DefaultLockTTL = time.Minute
+)
+
+// Config configures Middleware.
+type Config struct {
+	// Store keeps the responses. Defaults to a new MemoryStore.
+	Store Store
+
+	// TTL is how long a response is replayed for retries of its request.
+	// Defaults to DefaultTTL.
+	TTL time.Duration
+
+	// LockTTL is how long a request being processed holds its key, after
+	// which a retry is processed again even if the first attempt has not
+	// finished, for instance because its server crashed. Defaults to
+	// DefaultLockTTL; set it above the longest time a request may take.
+	LockTTL time.Duration
+
+	// Methods lists the methods the middleware applies to. Defaults to POST
+	// and PATCH, the methods that are not idempotent.
+	Methods []string
+
+	// Required rejects requests without an Idempotency-Key with 400 Bad
+	// Request. Otherwise they are passed to the handler as they are.
+	Required bool
+
+	// Scope, if set, returns the owner of a request, such as the
+	// authenticated user, so that keys chosen by different clients never
+	// collide.
+	Scope func(r *http.Request) string
+}
+
+// Middleware makes retries of requests with an Idempotency-Key safe: the
+// first request with a key is processed and its response stored, and
+// requests repeating the key within c.TTL get the stored response replayed,
+// marked with ReplayedHeader, without running next again.
+//
+// Following the IETF Idempotency-Key draft, a request is answered with:
+//
+//   - 400 Bad Request if its key is malformed, or missing when c.Required is
+//     set
+//   - 409 Conflict while a request with the same key is being processed
+//   - 422 Unprocessable Content if its key was used for a request with
+//     another method, URL or body
+//
+// Responses with a 5xx status are not stored, so that the request can be
+// retried. The request body is read into memory to fingerprint it; limit its
+// size beforehand, with http.MaxBytesReader for instance. Returns an error
+// wrapping ErrInvalidConfig if c is invalid.
+//
+// Example:
+//
+//	handler, err := idempotency.Middleware(payments, idempotency.Config{
+//	    Store:    redisStore,
+//	    Required: true,
+//	    Scope:    func(r *http.Request) string { return userID(r) },
+//	})
+func Middleware(next http.Handler, c Config) (http.Handler, error) {
+	if c.TTL < 0 || c.LockTTL < 0 {
+		return nil, fmt.Errorf("%w: negative TTL", ErrInvalidConfig)
+	}
+	for _, m := range c.Methods {
+		if m == "" || strings.ContainsAny(m, " \t,") {
+			return nil, fmt.Errorf("%w: invalid method %q", ErrInvalidConfig, m)
+		}
+	}
+	if c.Store == nil {
+		c.Store = NewMemoryStore()
+	}
+	if c.TTL == 0 {
+		c.TTL = DefaultTTL
+	}
+	if c.LockTTL == 0 {
+		c.LockTTL = DefaultLockTTL
+	}
+	if len(c.Methods) == 0 {
+		c.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(c.Methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		values := r.Header.Values(headers.IdempotencyKey)
+		if len(values) == 0 {
+			if c.Required {
+				http.Error(w, "missing Idempotency-Key header", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, ok := parseKey(values)
+		if !ok {
+			http.Error(w, "invalid Idempotency-Key header", http.StatusBadRequest)
+			return
+		}
+		if c.Scope != nil {
+			key = c.Scope(r) + "\x00" + key
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "cannot read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fp := fingerprint(r, body)
+
+		ctx := r.Context()
+		stored, err := c.Store.Begin(ctx, key, c.LockTTL)
+		switch {
+		case errors.Is(err, ErrInProgress):
+			http.Error(w, "a request with this Idempotency-Key is being processed", http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		case stored != nil:
+			if stored.Fingerprint != fp {
+				http.Error(w, "Idempotency-Key was used for another request", http.StatusUnprocessableEntity)
+				return
+			}
+			replay(w, stored)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if !completed {
+				_ = c.Store.Abort(context.WithoutCancel(ctx), key)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		if resp := rec.response(); resp.Status < 500 {
+			resp.Fingerprint = fp
+			completed = c.Store.Complete(context.WithoutCancel(ctx), key, resp, c.TTL) == nil
+		}
+	}), nil
+}
+
+// parseKey returns the key of the Idempotency-Key header values: a single
+// structured field string, as the draft specifies, or else a bare value of
+// visible ASCII, as many clients send UUIDs.
+func parseKey(values []string) (string, bool) {
+	if len(values) != 1 {
+		return "", false
+	}
+	key := strings.TrimSpace(values[0])
+	if item, err := sfv.ParseItem(key); err == nil {
+		if s, ok := item.Value.(string); ok && len(item.Params) == 0 {
+			key = s
+		}
+	}
+	if key == "" || len(key) > MaxKeyLength {
+		return "", false
+	}
+	for i := range len(key) {
+		if key[i] <= ' ' || key[i] >= 0x7f {
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// fingerprint identifies a request by its method, URL and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay writes a stored response.
+func replay(w http.ResponseWriter, resp *Response) {
+	h := w.Header()
+	for name, values := range resp.Header {
+		h[name] = slices.Clone(values)
+	}
+	h.Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
+
+// recorder passes a response through while keeping a copy of it.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recorder) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+		w.header = w.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *recorder) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *recorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// response returns the recorded response.
+func (w *recorder) response() *Response {
+	if w.status == 0 {
+		w.status = http.StatusOK
+		w.header = w.Header().Clone()
+	}
+	return &Response{Status: w.status, Header: w.header, Body: w.body.Bytes()}
+}
@@ -0,0 +1,237 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/idempotency"
+)
+
+// counter is a handler that answers with the number of times it ran.
+type counter struct {
+	calls  int
+	status int
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.calls++
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("X-Call", fmt.Sprint(c.calls))
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+	_, _ = fmt.Fprintf(w, "call %d: %s", c.calls, body)
+}
+
+func newMiddleware(t *testing.T, next http.Handler, c idempotency.Config) http.Handler {
+	t.Helper()
+	h, err := idempotency.Middleware(next, c)
+	if err != nil {
+		t.Fatalf("Middleware() error = %v", err)
+	}
+	return h
+}
+
+func do(h http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/payments", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(headers.IdempotencyKey, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddlewareReplay(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	h := newMiddleware(t, next, idempotency.Config{})
+
+	first := do(h, http.MethodPost, `"8e03978e-40d5-43e8-bc93-6894a57f9324"`, "pay 10")
+	if first.Code != http.StatusCreated || first.Body.String() != "call 1: pay 10" {
+		t.Fatalf("first response = %d %q", first.Code, first.Body.String())
+	}
+	if got := first.Header().Get(idempotency.ReplayedHeader); got != "" {
+		t.Errorf("first response %s = %q, want none", idempotency.ReplayedHeader, got)
+	}
+
+	// The same key, bare rather than quoted, is the same key.
+	retry := do(h, http.MethodPost, "8e03978e-40d5-43e8-bc93-6894a57f9324", "pay 10")
+	if next.calls != 1 {
+		t.Errorf("handler ran %d times, want 1", next.calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != "call 1: pay 10" {
+		t.Errorf("replayed response = %d %q, want %d %q", retry.Code, retry.Body.String(), http.StatusCreated, "call 1: pay 10")
+	}
+	if got := retry.Header().Get("X-Call"); got != "1" {
+		t.Errorf("replayed X-Call = %q, want %q", got, "1")
+	}
+	if got := retry.Header().Get(idempotency.ReplayedHeader); got != "true" {
+		t.Errorf("replayed %s = %q, want %q", idempotency.ReplayedHeader, got, "true")
+	}
+
+	// Another key is another request.
+	if other := do(h, http.MethodPost, "other", "pay 10"); other.Body.String() != "call 2: pay 10" {
+		t.Errorf("response for another key = %q, want %q", other.Body.String(), "call 2: pay 10")
+	}
+}
+
+func TestMiddlewareRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		required bool
+		key      string
+		expected int
+	}{
+		{"missing key when required", true, "", http.StatusBadRequest},
+		{"missing key", false, "", http.StatusOK},
+		{"empty string", false, `""`, http.StatusBadRequest},
+		{"space", false, "a b", http.StatusBadRequest},
+		{"non-ASCII", false, "clé", http.StatusBadRequest},
+		{"too long", false, strings.Repeat("k", idempotency.MaxKeyLength+1), http.StatusBadRequest},
+		{"longest", false, strings.Repeat("k", idempotency.MaxKeyLength), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newMiddleware(t, &counter{}, idempotency.Config{Required: tt.required})
+			if got := do(h, http.MethodPost, tt.key, "").Code; got != tt.expected {
+				t.Errorf("status = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMiddlewareMultipleKeys(t *testing.T) {
+	h := newMiddleware(t, &counter{}, idempotency.Config{})
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Add(headers.IdempotencyKey, "a")
+	r.Header.Add(headers.IdempotencyKey, "b")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMiddlewareMismatch(t *testing.T) {
+	next := &counter{}
+	h := newMiddleware(t, next, idempotency.Config{})
+
+	do(h, http.MethodPost, "k", "pay 10")
+	if got := do(h, http.MethodPost, "k", "pay 20").Code; got != http.StatusUnprocessableEntity {
+		t.Errorf("status for another body = %d, want %d", got, http.StatusUnprocessableEntity)
+	}
+	if got := do(h, http.MethodPatch, "k", "pay 10").Code; got != http.StatusUnprocessableEntity {
+		t.Errorf("status for another method = %d, want %d", got, http.StatusUnprocessableEntity)
+	}
+	if next.calls != 1 {
+		t.Errorf("handler ran %d times, want 1", next.calls)
+	}
+}
+
+func TestMiddlewareInProgress(t *testing.T) {
+	var h http.Handler
+	var inner *httptest.ResponseRecorder
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// A retry arrives while the first attempt is still running.
+		inner = do(h, http.MethodPost, "k", "")
+		w.WriteHeader(http.StatusOK)
+	})
+	h = newMiddleware(t, next, idempotency.Config{})
+
+	if got := do(h, http.MethodPost, "k", "").Code; got != http.StatusOK {
+		t.Errorf("first status = %d, want %d", got, http.StatusOK)
+	}
+	if inner.Code != http.StatusConflict {
+		t.Errorf("concurrent status = %d, want %d", inner.Code, http.StatusConflict)
+	}
+}
+
+func TestMiddlewareServerError(t *testing.T) {
+	next := &counter{status: http.StatusServiceUnavailable}
+	h := newMiddleware(t, next, idempotency.Config{})
+
+	do(h, http.MethodPost, "k", "")
+	next.status = http.StatusOK
+	if got := do(h, http.MethodPost, "k", "").Body.String(); got != "call 2: " {
+		t.Errorf("retry after 5xx = %q, want %q", got, "call 2: ")
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	h := newMiddleware(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), idempotency.Config{Store: store})
+
+	func() {
+		defer func() { _ = recover() }()
+		do(h, http.MethodPost, "k", "")
+	}()
+	if resp, err := store.Begin(context.Background(), "k", time.Minute); resp != nil || err != nil {
+		t.Errorf("Begin() after panic = %v, %v, want the key released", resp, err)
+	}
+}
+
+func TestMiddlewareMethods(t *testing.T) {
+	next := &counter{}
+	h := newMiddleware(t, next, idempotency.Config{Required: true})
+
+	do(h, http.MethodGet, "k", "")
+	do(h, http.MethodGet, "k", "")
+	if next.calls != 2 {
+		t.Errorf("handler ran %d times for GET, want 2", next.calls)
+	}
+
+	h = newMiddleware(t, next, idempotency.Config{Methods: []string{http.MethodPut}})
+	do(h, http.MethodPut, "k", "")
+	do(h, http.MethodPut, "k", "")
+	if next.calls != 3 {
+		t.Errorf("handler ran %d times, want 3", next.calls)
+	}
+}
+
+func TestMiddlewareScope(t *testing.T) {
+	next := &counter{}
+	h := newMiddleware(t, next, idempotency.Config{
+		Scope: func(r *http.Request) string { return r.Header.Get("X-User") },
+	})
+
+	for _, user := range []string{"alice", "bob", "alice"} {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set(headers.IdempotencyKey, "k")
+		r.Header.Set("X-User", user)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if next.calls != 2 {
+		t.Errorf("handler ran %d times, want 2", next.calls)
+	}
+}
+
+func TestMiddlewareInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		c    idempotency.Config
+	}{
+		{"negative TTL", idempotency.Config{TTL: -time.Second}},
+		{"negative lock TTL", idempotency.Config{LockTTL: -time.Second}},
+		{"empty method", idempotency.Config{Methods: []string{""}}},
+		{"method list", idempotency.Config{Methods: []string{"POST, PUT"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := idempotency.Middleware(&counter{}, tt.c); !errors.Is(err, idempotency.ErrInvalidConfig) {
+				t.Errorf("Middleware() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
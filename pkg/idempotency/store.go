@@ -0,0 +1,109 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrInProgress is returned by Store.Begin when another request with the
+// same key is still being processed.
+var ErrInProgress = errors.New("idempotency: request in progress")
+
+// Response is a response stored for replay.
+type Response struct {
+	Status      int
+	Header      http.Header
+	Body        []byte
+	Fingerprint string // Identifies the request that produced it
+}
+
+// Store keeps the responses of requests by idempotency key. Implementations
+// must be safe for concurrent use, and atomic across servers if several
+// share the store, for instance with Redis SET NX.
+type Store interface {
+	// Begin claims key for a new request, for at most ttl. If a response is
+	// already stored under key, Begin returns it instead and claims nothing.
+	// If another request holds the claim, it returns ErrInProgress.
+	Begin(ctx context.Context, key string, ttl time.Duration) (*Response, error)
+
+	// Complete stores resp under key for ttl and releases the claim.
+	Complete(ctx context.Context, key string, resp *Response, ttl time.Duration) error
+
+	// Abort releases the claim on key without storing a response, so that
+	// the request can be retried.
+	Abort(ctx context.Context, key string) error
+}
+
+// MemoryStore is a Store keeping responses in memory, for a single server or
+// for tests. Expired entries are swept from time to time as keys are
+// claimed, at a constant amortized cost per request. Create one with
+// NewMemoryStore.
+type MemoryStore struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	untilSweep int // Calls to Begin left before the next sweep
+}
+
+// minSweepInterval is the fewest calls to Begin between two sweeps. Between
+// larger ones, there are as many calls as entries left by the last sweep.
+const minSweepInterval = 64
+
+type memoryEntry struct {
+	resp    *Response // nil while in progress
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), untilSweep: minSweepInterval}
+}
+
+// Begin implements Store.
+func (s *MemoryStore) Begin(_ context.Context, key string, ttl time.Duration) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.untilSweep--
+	if s.untilSweep <= 0 {
+		s.sweep(now)
+	}
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		if e.resp == nil {
+			return nil, ErrInProgress
+		}
+		return e.resp, nil
+	}
+	s.entries[key] = memoryEntry{expires: now.Add(ttl)}
+	return nil, nil
+}
+
+// sweep removes the entries expired at now.
+func (s *MemoryStore) sweep(now time.Time) {
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.untilSweep = max(len(s.entries), minSweepInterval)
+}
+
+// Complete implements Store.
+func (s *MemoryStore) Complete(_ context.Context, key string, resp *Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryEntry{resp: resp, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+// Abort implements Store.
+func (s *MemoryStore) Abort(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
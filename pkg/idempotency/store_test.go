@@ -0,0 +1,83 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/idempotency"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := idempotency.NewMemoryStore()
+
+	resp, err := s.Begin(ctx, "k", time.Minute)
+	if resp != nil || err != nil {
+		t.Fatalf("Begin() = %v, %v, want nil, nil", resp, err)
+	}
+	if _, err := s.Begin(ctx, "k", time.Minute); !errors.Is(err, idempotency.ErrInProgress) {
+		t.Fatalf("second Begin() error = %v, want ErrInProgress", err)
+	}
+
+	stored := &idempotency.Response{Status: http.StatusCreated, Body: []byte("ok"), Fingerprint: "fp"}
+	if err := s.Complete(ctx, "k", stored, time.Minute); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	resp, err = s.Begin(ctx, "k", time.Minute)
+	if err != nil || resp != stored {
+		t.Fatalf("Begin() after Complete = %v, %v, want stored response", resp, err)
+	}
+
+	// Abort leaves stored responses alone.
+	if err := s.Abort(ctx, "k"); err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+	if resp, _ := s.Begin(ctx, "k", time.Minute); resp != stored {
+		t.Errorf("Begin() after Abort of a stored key = %v, want stored response", resp)
+	}
+}
+
+func TestMemoryStoreAbort(t *testing.T) {
+	ctx := context.Background()
+	s := idempotency.NewMemoryStore()
+
+	_, _ = s.Begin(ctx, "k", time.Minute)
+	if err := s.Abort(ctx, "k"); err != nil {
+		t.Fatalf("Abort() error = %v", err)
+	}
+	if resp, err := s.Begin(ctx, "k", time.Minute); resp != nil || err != nil {
+		t.Errorf("Begin() after Abort = %v, %v, want nil, nil", resp, err)
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	s := idempotency.NewMemoryStore()
+
+	// A claim that has expired no longer blocks the key.
+	_, _ = s.Begin(ctx, "claimed", 0)
+	if resp, err := s.Begin(ctx, "claimed", time.Minute); resp != nil || err != nil {
+		t.Errorf("Begin() after expired claim = %v, %v, want nil, nil", resp, err)
+	}
+
+	// Nor do claims expired across sweeps.
+	for i := range 200 {
+		_, _ = s.Begin(ctx, fmt.Sprint("many", i), 0)
+	}
+	for i := range 200 {
+		if _, err := s.Begin(ctx, fmt.Sprint("many", i), time.Minute); err != nil {
+			t.Fatalf("Begin() after expired claim %d error = %v", i, err)
+		}
+	}
+
+	// Nor is an expired response replayed.
+	_, _ = s.Begin(ctx, "done", time.Minute)
+	_ = s.Complete(ctx, "done", &idempotency.Response{Status: http.StatusOK}, -time.Second)
+	if resp, err := s.Begin(ctx, "done", time.Minute); resp != nil || err != nil {
+		t.Errorf("Begin() after expired response = %v, %v, want nil, nil", resp, err)
+	}
+}
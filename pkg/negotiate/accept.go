@@ -0,0 +1,205 @@
+package negotiate
+
+import (
+	"cmp"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// MediaRange is one element of an Accept header, such as text/html;level=1;q=0.7.
+type MediaRange struct {
+	Type    string            // Lower-cased type, "*" for */*
+	Subtype string            // Lower-cased subtype, "*" for type/* and */*
+	Params  map[string]string // Media type parameters by lower-cased name, nil if none
+	Q       float64           // Quality, from 0 to 1
+}
+
+// String formats r as it would appear in an Accept header, with parameters
+// sorted by name and q omitted when it is 1.
+func (r MediaRange) String() string {
+	var b strings.Builder
+	b.WriteString(r.Type + "/" + r.Subtype)
+	for _, name := range slices.Sorted(maps.Keys(r.Params)) {
+		b.WriteString(";" + name + "=" + quoteIfNeeded(r.Params[name]))
+	}
+	if r.Q != 1 {
+		b.WriteString(";q=" + strconv.FormatFloat(r.Q, 'f', -1, 64))
+	}
+	return b.String()
+}
+
+// Matches reports whether the media type mediaType, such as "text/html" or
+// "text/html;charset=utf-8", falls within r. Wildcards match any type or
+// subtype, and every parameter of r must be present in mediaType with the same
+// value; parameter values are compared case-insensitively. The quality of r
+// is not considered.
+func (r MediaRange) Matches(mediaType string) bool {
+	t, ok := parseMediaType(mediaType, false)
+	return ok && specificity(r, t) >= 0
+}
+
+// ParseAccept parses an Accept header value into its media ranges, ordered by
+// precedence: highest quality first and, among equal qualities, the more
+// specific range first; otherwise the header order is kept. Elements that are
+// not valid media ranges, or have an invalid q, are skipped. Accept extension
+// parameters, those following q, are dropped. Returns an empty slice if no
+// element is valid.
+//
+// Example:
+//
+//	ranges := negotiate.ParseAccept("text/*, application/json;q=0.9, text/html")
+//	// text/html, text/*, application/json;q=0.9
+func ParseAccept(accept string) []MediaRange {
+	ranges := parseRanges(accept)
+	slices.SortStableFunc(ranges, func(a, b MediaRange) int {
+		if c := cmp.Compare(b.Q, a.Q); c != 0 {
+			return c
+		}
+		return cmp.Compare(rangeSpecificity(b), rangeSpecificity(a))
+	})
+	return ranges
+}
+
+// Negotiate returns the entry of offered that best satisfies the Accept header
+// value accept, as written in offered, or "" if the client accepts none of
+// them. Offers are media types such as "application/json"; offers that are not
+// valid media types, or contain wildcards, are never chosen. An empty accept,
+// or one with no valid media range, accepts anything, so the first valid offer
+// is returned. See the package documentation for the precedence rules.
+//
+// Example:
+//
+//	ct := negotiate.Negotiate([]string{"application/json", "text/html"},
+//	    "text/html;q=0.9, application/*;q=0.5")
+//	// ct == "text/html"
+func Negotiate(offered []string, accept string) string {
+	ranges := parseRanges(accept)
+
+	best, bestQ, bestSpec := "", 0.0, -1
+	for _, offer := range offered {
+		t, ok := parseMediaType(offer, false)
+		if !ok {
+			continue
+		}
+		if len(ranges) == 0 {
+			return offer
+		}
+
+		q, spec := 0.0, -1
+		for _, r := range ranges {
+			if s := specificity(r, t); s > spec {
+				q, spec = r.Q, s
+			}
+		}
+		if spec >= 0 && q > 0 && (q > bestQ || (q == bestQ && spec > bestSpec)) {
+			best, bestQ, bestSpec = offer, q, spec
+		}
+	}
+	return best
+}
+
+// Preferred returns the entry of offered that best satisfies the request's
+// Accept header, combined across all lines it was sent on, or "" if the
+// client accepts none of them. See Negotiate.
+//
+// Example:
+//
+//	// Accept: text/html, application/json;q=0.9
+//	ct := negotiate.Preferred(r, "application/json", "text/html")  // "text/html"
+func Preferred(r *http.Request, offered ...string) string {
+	return Negotiate(offered, strings.Join(r.Header.Values(headers.Accept), ","))
+}
+
+func parseRanges(accept string) []MediaRange {
+	ranges := []MediaRange{}
+	for _, elem := range splitList(accept) {
+		if r, ok := parseMediaType(elem, true); ok {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// parseMediaType parses a media type or, if isRange, a media range with an
+// optional weight. Type, subtype and parameter names are lower-cased.
+func parseMediaType(s string, isRange bool) (MediaRange, bool) {
+	typ, rest := s, ""
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		typ, rest = s[:i], s[i:]
+	}
+	mainType, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(typ)), "/")
+	if !ok || !isToken(mainType) || !isToken(subtype) {
+		return MediaRange{}, false
+	}
+	if (mainType == "*" && subtype != "*") || (!isRange && subtype == "*") {
+		return MediaRange{}, false
+	}
+
+	params, ok := parseParams(rest)
+	if !ok {
+		return MediaRange{}, false
+	}
+
+	r := MediaRange{Type: mainType, Subtype: subtype, Q: 1}
+	for _, p := range params {
+		if isRange && p.name == "q" {
+			q, ok := parseQuality(p.value)
+			if !ok {
+				return MediaRange{}, false
+			}
+			r.Q = float64(q) / 1000
+			break // the rest are accept extensions
+		}
+		if r.Params == nil {
+			r.Params = make(map[string]string)
+		}
+		r.Params[p.name] = p.value
+	}
+	return r, true
+}
+
+// specificity returns how specifically r matches the media type t, higher
+// being more specific, or -1 if it does not match.
+func specificity(r, t MediaRange) int {
+	switch {
+	case r.Type == "*":
+		return 0
+	case r.Type != t.Type:
+		return -1
+	case r.Subtype == "*":
+		return 1
+	case r.Subtype != t.Subtype:
+		return -1
+	}
+	for name, value := range r.Params {
+		if v, ok := t.Params[name]; !ok || !strings.EqualFold(v, value) {
+			return -1
+		}
+	}
+	return 2 + len(r.Params)
+}
+
+// rangeSpecificity ranks r among other ranges, as specificity would for a
+// media type that r matches.
+func rangeSpecificity(r MediaRange) int {
+	switch {
+	case r.Type == "*":
+		return 0
+	case r.Subtype == "*":
+		return 1
+	}
+	return 2 + len(r.Params)
+}
+
+// quoteIfNeeded returns s unchanged if it is a token, or as a quoted-string.
+func quoteIfNeeded(s string) string {
+	if isToken(s) {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
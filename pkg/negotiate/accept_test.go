@@ -0,0 +1,97 @@
+package negotiate_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/negotiate"
+)
+
+func TestParseAccept(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected []string
+	}{
+		{"empty", "", []string{}},
+		{"ordered by quality", "text/plain;q=0.5, application/json, text/html;q=0.8",
+			[]string{"application/json", "text/html;q=0.8", "text/plain;q=0.5"}},
+		{"specific before wildcard", "*/*, text/*, text/html;level=1, text/html",
+			[]string{"text/html;level=1", "text/html", "text/*", "*/*"}},
+		{"case and whitespace", " Text/HTML ; Charset=UTF-8 ; q=0.7 ",
+			[]string{"text/html;charset=UTF-8;q=0.7"}},
+		{"quoted parameter", `text/plain;format="flowed, fixed"`,
+			[]string{`text/plain;format="flowed, fixed"`}},
+		{"accept extensions dropped", "text/html;q=0.5;ext=1", []string{"text/html;q=0.5"}},
+		{"invalid skipped", "text, */html, text/html;q=2, text/html;q=0.1234, json/, a/b;c, image/png",
+			[]string{"image/png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := negotiate.ParseAccept(tt.accept)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ParseAccept() = %v, want %v", got, tt.expected)
+			}
+			for i, r := range got {
+				if r.String() != tt.expected[i] {
+					t.Errorf("ParseAccept()[%d] = %s, want %s", i, r, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		offered  []string
+		accept   string
+		expected string
+	}{
+		{"no accept", []string{"application/json", "text/html"}, "", "application/json"},
+		{"invalid accept", []string{"application/json"}, "garbage", "application/json"},
+		{"exact", []string{"application/json", "text/html"}, "text/html", "text/html"},
+		{"quality", []string{"application/json", "text/html"}, "text/html;q=0.5, application/json;q=0.9", "application/json"},
+		{"subtype wildcard", []string{"application/json", "text/csv"}, "text/*", "text/csv"},
+		{"explicit beats wildcard", []string{"text/html", "application/json"}, "*/*, application/json", "application/json"},
+		{"server order on tie", []string{"text/html", "application/json"}, "*/*", "text/html"},
+		{"excluded", []string{"text/html"}, "text/html;q=0, */*", ""},
+		{"most specific range wins", []string{"text/plain"}, "text/*;q=0, */*;q=1", ""},
+		{"nothing acceptable", []string{"text/html"}, "application/json", ""},
+		{"case-insensitive", []string{"Application/JSON"}, "application/json", "Application/JSON"},
+		{"parameters", []string{"text/html", "text/html;level=1"}, "text/html;level=1, text/html;q=0.5", "text/html;level=1"},
+		{"parameter mismatch", []string{"text/html;level=2"}, "text/html;level=1", ""},
+		{"invalid offers skipped", []string{"*/*", "text", "text/html"}, "", "text/html"},
+		{"RFC 9110 example", []string{"image/jpeg", "text/plain", "text/html"},
+			"text/*;q=0.3, text/html;q=0.7, text/html;level=1, text/html;level=2;q=0.4, */*;q=0.5", "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiate.Negotiate(tt.offered, tt.accept); got != tt.expected {
+				t.Errorf("Negotiate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMediaRangeMatches(t *testing.T) {
+	r := negotiate.ParseAccept("text/html;charset=utf-8")[0]
+	if !r.Matches("text/html; charset=UTF-8; level=1") {
+		t.Error("Matches() = false for a matching type with extra parameters")
+	}
+	if r.Matches("text/html") || r.Matches("text/plain;charset=utf-8") || r.Matches("not a type") {
+		t.Error("Matches() = true for a non-matching type")
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Add("Accept", "application/xml;q=0.8")
+	r.Header.Add("Accept", "application/json;q=0.9")
+
+	if got := negotiate.Preferred(r, "application/xml", "application/json"); got != "application/json" {
+		t.Errorf("Preferred() = %q, want %q", got, "application/json")
+	}
+}
@@ -0,0 +1,46 @@
+// Package negotiate implements HTTP proactive content negotiation as described
+// in RFC 9110, section 12: parsing the Accept family of request headers, with
+// their quality values and wildcards, and choosing the best of the
+// representations a handler can produce.
+//
+// Preferred reads the Accept header of a request and returns the best of the
+// offered media types, or "" if the client accepts none of them:
+//
+//	switch negotiate.Preferred(r, "application/json", "text/html") {
+//	case "text/html":
+//	    renderHTML(w, data)
+//	case "application/json":
+//	    json.NewEncoder(w).Encode(data)
+//	default:
+//	    w.WriteHeader(http.StatusNotAcceptable)
+//	}
+//
+// Negotiate does the same for an Accept value held as a string, and
+// ParseAccept exposes the parsed media ranges.
+//
+// PreferredEncoding chooses a content coding from Accept-Encoding, honoring
+// q=0 exclusions, "*" and identity, for compression middleware:
+//
+//	switch negotiate.PreferredEncoding(r, "zstd", "br", "gzip") {
+//	case negotiate.Identity:
+//	    // send uncompressed
+//	case "":
+//	    w.WriteHeader(http.StatusNotAcceptable)
+//	default:
+//	    // compress with the returned coding
+//	}
+//
+// # Precedence
+//
+// Each offer is given the quality of the most specific media range that
+// matches it: a range with parameters beats a plain type/subtype, which beats
+// type/*, which beats */*. So with
+//
+//	Accept: text/*;q=0.3, text/html;q=0.7, text/html;level=1, */*;q=0.5
+//
+// text/html;level=1 has quality 1, text/html 0.7, text/plain 0.3 and
+// image/jpeg 0.5. The offer with the highest quality wins; ties go to the
+// offer matched by the more specific range, then to the earlier offer. Offers
+// with quality 0 are never chosen. A request without an Accept header accepts
+// anything, so the first offer is returned.
+package negotiate
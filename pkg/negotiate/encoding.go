@@ -0,0 +1,172 @@
+package negotiate
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// Identity is the content coding that leaves content unchanged.
+const Identity = "identity"
+
+// Coding is one element of an Accept-Encoding header, such as gzip;q=0.8.
+type Coding struct {
+	Name string  // Lower-cased coding name, "*" for any coding
+	Q    float64 // Quality, from 0 to 1
+}
+
+// String formats c as it would appear in an Accept-Encoding header, with q
+// omitted when it is 1.
+func (c Coding) String() string {
+	if c.Q == 1 {
+		return c.Name
+	}
+	return c.Name + ";q=" + strconv.FormatFloat(c.Q, 'f', -1, 64)
+}
+
+// ParseAcceptEncoding parses an Accept-Encoding header value into its codings,
+// highest quality first and otherwise in header order. Names are lower-cased,
+// and "x-gzip" and "x-compress" are read as "gzip" and "compress". Elements
+// that are not valid, or have an invalid q, are skipped. Returns an empty
+// slice if no element is valid.
+//
+// Example:
+//
+//	codings := negotiate.ParseAcceptEncoding("gzip;q=0.8, br, identity;q=0")
+//	// br, gzip;q=0.8, identity;q=0
+func ParseAcceptEncoding(acceptEncoding string) []Coding {
+	codings := parseCodings(acceptEncoding)
+	slices.SortStableFunc(codings, func(a, b Coding) int {
+		return cmp.Compare(b.Q, a.Q)
+	})
+	return codings
+}
+
+// NegotiateEncoding returns the entry of supported, a list of content codings
+// such as "br", "zstd" and "gzip" in the server's order of preference, that
+// best satisfies the Accept-Encoding header value acceptEncoding, as written in
+// supported. If none is acceptable it returns Identity, meaning the content
+// should be sent unencoded, or "" if the client has excluded identity too and
+// a 406 Not Acceptable response is in order.
+//
+// A coding listed with q=0 is never chosen, and "*" stands for every coding
+// not listed. Among codings of equal quality the server's order decides, and
+// an encoding is preferred to identity. Identity is acceptable unless it is
+// excluded by "identity;q=0", or by "*;q=0" without an identity entry.
+//
+// Example:
+//
+//	enc := negotiate.NegotiateEncoding("gzip, deflate, br;q=0.9", "zstd", "br", "gzip")
+//	// enc == "gzip"
+func NegotiateEncoding(acceptEncoding string, supported ...string) string {
+	codings := parseCodings(acceptEncoding)
+
+	quality := func(name string) (float64, bool) {
+		star, hasStar := 0.0, false
+		for _, c := range codings {
+			switch c.Name {
+			case name:
+				return c.Q, true
+			case "*":
+				star, hasStar = c.Q, true
+			}
+		}
+		return star, hasStar
+	}
+
+	best, bestQ := "", 0.0
+	for _, s := range supported {
+		name := canonicalCoding(s)
+		if name == Identity {
+			continue
+		}
+		if q, ok := quality(name); ok && q > bestQ {
+			best, bestQ = s, q
+		}
+	}
+
+	identityQ, listed := quality(Identity)
+	switch {
+	case listed && identityQ > bestQ:
+		return Identity
+	case best != "":
+		return best
+	case !listed || identityQ > 0:
+		return Identity
+	}
+	return ""
+}
+
+// PreferredEncoding returns the entry of supported that best satisfies the
+// request's Accept-Encoding header, Identity if content should be sent
+// unencoded, or "" if no acceptable coding is supported. See
+// NegotiateEncoding.
+//
+// A request without an Accept-Encoding header gets Identity: RFC 9110 allows
+// any coding then, but clients that cannot decode compressed content often
+// omit the header.
+//
+// Example:
+//
+//	switch negotiate.PreferredEncoding(r, "br", "gzip") {
+//	case "br":
+//	    // wrap w in a brotli writer
+//	case "gzip":
+//	    // wrap w in a gzip writer
+//	case "":
+//	    w.WriteHeader(http.StatusNotAcceptable)
+//	    return
+//	}
+func PreferredEncoding(r *http.Request, supported ...string) string {
+	values := r.Header.Values(headers.AcceptEncoding)
+	if len(values) == 0 {
+		return Identity
+	}
+	return NegotiateEncoding(strings.Join(values, ","), supported...)
+}
+
+func parseCodings(acceptEncoding string) []Coding {
+	codings := []Coding{}
+	for _, elem := range splitList(acceptEncoding) {
+		name, rest := elem, ""
+		if i := strings.IndexByte(elem, ';'); i >= 0 {
+			name, rest = elem[:i], elem[i:]
+		}
+		name = strings.TrimRight(name, " \t")
+		params, ok := parseParams(rest)
+		if !isToken(name) || !ok {
+			continue
+		}
+
+		c := Coding{Name: canonicalCoding(name), Q: 1}
+		for _, p := range params {
+			if p.name == "q" {
+				q, valid := parseQuality(p.value)
+				ok = valid
+				c.Q = float64(q) / 1000
+				break
+			}
+		}
+		if ok {
+			codings = append(codings, c)
+		}
+	}
+	return codings
+}
+
+// canonicalCoding lower-cases name and maps the "x-" aliases RFC 9110
+// requires recipients to treat as equivalent.
+func canonicalCoding(name string) string {
+	name = strings.ToLower(name)
+	switch name {
+	case "x-gzip":
+		return "gzip"
+	case "x-compress":
+		return "compress"
+	}
+	return name
+}
@@ -0,0 +1,82 @@
+package negotiate_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/negotiate"
+)
+
+func TestParseAcceptEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected []string
+	}{
+		{"empty", "", []string{}},
+		{"ordered by quality", "gzip;q=0.8, BR, identity;q=0", []string{"br", "gzip;q=0.8", "identity;q=0"}},
+		{"aliases", "x-gzip, x-compress;q=0.5", []string{"gzip", "compress;q=0.5"}},
+		{"invalid skipped", "gzip;q=2, br;q, zstd;q=0.5, a b, *", []string{"*", "zstd;q=0.5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := negotiate.ParseAcceptEncoding(tt.header)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ParseAcceptEncoding() = %v, want %v", got, tt.expected)
+			}
+			for i, c := range got {
+				if c.String() != tt.expected[i] {
+					t.Errorf("ParseAcceptEncoding()[%d] = %s, want %s", i, c, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{"zstd", "br", "gzip"}
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"empty value", "", "identity"},
+		{"single", "gzip", "gzip"},
+		{"server order on tie", "gzip, br", "br"},
+		{"quality", "gzip, deflate, br;q=0.9", "gzip"},
+		{"alias", "x-gzip", "gzip"},
+		{"case-insensitive", "GZIP", "gzip"},
+		{"wildcard", "*", "zstd"},
+		{"wildcard with exclusion", "*, zstd;q=0", "br"},
+		{"excluded", "gzip;q=0", "identity"},
+		{"unsupported", "deflate", "identity"},
+		{"identity preferred", "gzip;q=0.5, identity", "identity"},
+		{"encoding before identity on tie", "gzip, identity", "gzip"},
+		{"identity excluded", "deflate, identity;q=0", ""},
+		{"everything excluded", "*;q=0", ""},
+		{"identity kept by explicit entry", "*;q=0, identity", "identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiate.NegotiateEncoding(tt.header, supported...); got != tt.expected {
+				t.Errorf("NegotiateEncoding(%q) = %q, want %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreferredEncoding(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if got := negotiate.PreferredEncoding(r, "gzip"); got != negotiate.Identity {
+		t.Errorf("PreferredEncoding() = %q without a header, want identity", got)
+	}
+
+	r.Header.Add("Accept-Encoding", "gzip;q=0.5")
+	r.Header.Add("Accept-Encoding", "br")
+	if got := negotiate.PreferredEncoding(r, "gzip", "br"); got != "br" {
+		t.Errorf("PreferredEncoding() = %q, want br", got)
+	}
+}
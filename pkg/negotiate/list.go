@@ -0,0 +1,143 @@
+package negotiate
+
+import "strings"
+
+// splitList returns the trimmed, non-empty elements of a comma-separated list
+// header value. Commas inside quoted strings do not split.
+func splitList(s string) []string {
+	var result []string
+	inQuote, escaped, start := false, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case escaped:
+			escaped = false
+		case inQuote && c == '\\':
+			escaped = true
+		case c == '"':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			result = appendElement(result, s[start:i])
+			start = i + 1
+		}
+	}
+	return appendElement(result, s[start:])
+}
+
+func appendElement(dst []string, s string) []string {
+	if s = strings.Trim(s, " \t"); s != "" {
+		dst = append(dst, s)
+	}
+	return dst
+}
+
+// param is one name=value parameter of a list element.
+type param struct {
+	name, value string
+}
+
+// parseParams parses the ";"-separated parameters following a list element,
+// such as `; q=0.5; charset="utf-8"`. Names are lower-cased and quoted values
+// unquoted. ok is false if a parameter is malformed.
+func parseParams(s string) (params []param, ok bool) {
+	for s = trimOWS(s); s != ""; s = trimOWS(s) {
+		if s[0] != ';' {
+			return nil, false
+		}
+		s = trimOWS(s[1:])
+		if s == "" || s[0] == ';' {
+			continue // empty parameter, as in "text/html;;level=1"
+		}
+
+		i := strings.IndexByte(s, '=')
+		if i <= 0 || !isToken(strings.TrimRight(s[:i], " \t")) {
+			return nil, false
+		}
+		name := strings.ToLower(strings.TrimRight(s[:i], " \t"))
+		s = trimOWS(s[i+1:])
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			v, rest, ok := unquote(s)
+			if !ok {
+				return nil, false
+			}
+			value, s = v, rest
+		} else {
+			end := strings.IndexAny(s, "; \t")
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+			if !isToken(value) {
+				return nil, false
+			}
+		}
+		params = append(params, param{name, value})
+	}
+	return params, true
+}
+
+// unquote parses the quoted-string at the start of s and returns its value and
+// the rest of s.
+func unquote(s string) (value, rest string, ok bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return b.String(), s[i+1:], true
+		case '\\':
+			if i+1 == len(s) {
+				return "", "", false
+			}
+			i++
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", false
+}
+
+func trimOWS(s string) string {
+	return strings.TrimLeft(s, " \t")
+}
+
+// isToken reports whether s is a non-empty RFC 9110 token.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// parseQuality parses an RFC 9110 weight: "0" or "1", optionally followed by
+// a point and up to three digits, and at most 1. The result is in thousandths.
+func parseQuality(s string) (int, bool) {
+	if s == "" || len(s) > 5 || (s[0] != '0' && s[0] != '1') {
+		return 0, false
+	}
+	q := int(s[0]-'0') * 1000
+	if len(s) > 1 {
+		if s[1] != '.' {
+			return 0, false
+		}
+		scale := 100
+		for i := 2; i < len(s); i++ {
+			if s[i] < '0' || s[i] > '9' {
+				return 0, false
+			}
+			q += int(s[i]-'0') * scale
+			scale /= 10
+		}
+	}
+	if q > 1000 {
+		return 0, false
+	}
+	return q, true
+}
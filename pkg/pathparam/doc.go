@@ -0,0 +1,29 @@
+// Package pathparam provides typed access to the path wildcards matched by
+// http.ServeMux, such as {id} in "GET /users/{id}", wrapping r.PathValue with
+// the fail-safe semantics of package query.
+//
+// # Overview
+//
+// Each extractor returns the provided default if the wildcard is missing,
+// empty, or fails to parse or validate:
+//
+//	mux.HandleFunc("GET /users/{id}/posts/{slug}", func(w http.ResponseWriter, r *http.Request) {
+//	    id   := pathparam.Int64(r, "id", 0)
+//	    slug := pathparam.Slug(r, "slug", "")
+//	})
+//
+// # Required Parameters
+//
+// Path wildcards usually identify the resource, so the OrFail variants are the
+// common choice. They write a 400 Bad Request response using
+// query.DefaultErrorHandler and report ok = false, so the handler only has to
+// return:
+//
+//	id, ok := pathparam.UUIDOrFail(w, r, "id")
+//	if !ok {
+//	    return
+//	}
+//
+// The error passed to the handler is a *query.ParamError wrapping
+// query.ErrMissing or the parser's error.
+package pathparam
@@ -0,0 +1,172 @@
+package pathparam
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+var (
+	// ErrInvalidUUID is reported by UUIDOrFail for values that are not UUIDs.
+	ErrInvalidUUID = errors.New("invalid UUID")
+
+	// ErrInvalidSlug is reported by SlugOrFail for values that are not slugs.
+	ErrInvalidSlug = errors.New("invalid slug")
+)
+
+// Value extracts a path wildcard and converts it using the provided parser.
+// Returns defaultValue if the wildcard is missing, empty, or cannot be parsed.
+//
+// Example:
+//
+//	// Route: GET /orders/{status}
+//	status := pathparam.Value(r, "status", StatusAny, ParseStatus)
+func Value[T any](r *http.Request, key string, defaultValue T, parser query.Parser[T]) T {
+	val := r.PathValue(key)
+	if val == "" {
+		return defaultValue
+	}
+
+	parsed, err := parser(val)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+// ValueOrFail extracts a required path wildcard and converts it using parser.
+// If the wildcard is missing, empty or invalid, a 400 Bad Request response is
+// written and ok is false, in which case the handler should return immediately.
+func ValueOrFail[T any](w http.ResponseWriter, r *http.Request, key string, parser query.Parser[T]) (T, bool) {
+	var zero T
+
+	val := r.PathValue(key)
+	if val == "" {
+		query.DefaultErrorHandler(w, r, &query.ParamError{Key: key, Err: query.ErrMissing})
+		return zero, false
+	}
+
+	parsed, err := parser(val)
+	if err != nil {
+		query.DefaultErrorHandler(w, r, &query.ParamError{Key: key, Value: val, Err: err})
+		return zero, false
+	}
+	return parsed, true
+}
+
+// String extracts a path wildcard as a string.
+// Returns defaultValue if the wildcard is missing or empty.
+func String(r *http.Request, key string, defaultValue string) string {
+	if val := r.PathValue(key); val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// Int extracts an integer path wildcard.
+// Returns defaultValue if the wildcard is missing, empty, or not an int.
+func Int(r *http.Request, key string, defaultValue int) int {
+	return Value(r, key, defaultValue, strconv.Atoi)
+}
+
+// Int64 extracts an int64 path wildcard, the usual type of database IDs.
+// Returns defaultValue if the wildcard is missing, empty, or not an int64.
+func Int64(r *http.Request, key string, defaultValue int64) int64 {
+	return Value(r, key, defaultValue, parseInt64)
+}
+
+// UUID extracts a path wildcard in the canonical 8-4-4-4-12 hexadecimal UUID
+// form, returned in lowercase. Returns defaultValue if the wildcard is missing,
+// empty, or not a UUID.
+//
+// Example:
+//
+//	// Route: GET /files/{id}, URL: /files/3F2504E0-4F89-11D3-9A0C-0305E82C3301
+//	id := pathparam.UUID(r, "id", "")  // "3f2504e0-4f89-11d3-9a0c-0305e82c3301"
+func UUID(r *http.Request, key string, defaultValue string) string {
+	return Value(r, key, defaultValue, parseUUID)
+}
+
+// Slug extracts a URL slug such as "hello-world-2024": lowercase ASCII letters
+// and digits separated by single hyphens. Returns defaultValue if the wildcard
+// is missing, empty, or not a slug.
+func Slug(r *http.Request, key string, defaultValue string) string {
+	return Value(r, key, defaultValue, parseSlug)
+}
+
+// StringOrFail extracts a required path wildcard. See ValueOrFail.
+func StringOrFail(w http.ResponseWriter, r *http.Request, key string) (string, bool) {
+	return ValueOrFail(w, r, key, func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// IntOrFail extracts a required integer path wildcard. See ValueOrFail.
+//
+// Example:
+//
+//	// Route: GET /users/{id}
+//	id, ok := pathparam.IntOrFail(w, r, "id")
+//	if !ok {
+//	    return  // 400 Bad Request already written
+//	}
+func IntOrFail(w http.ResponseWriter, r *http.Request, key string) (int, bool) {
+	return ValueOrFail(w, r, key, strconv.Atoi)
+}
+
+// Int64OrFail extracts a required int64 path wildcard. See ValueOrFail.
+func Int64OrFail(w http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	return ValueOrFail(w, r, key, parseInt64)
+}
+
+// UUIDOrFail extracts a required UUID path wildcard. See UUID and ValueOrFail.
+func UUIDOrFail(w http.ResponseWriter, r *http.Request, key string) (string, bool) {
+	return ValueOrFail(w, r, key, parseUUID)
+}
+
+// SlugOrFail extracts a required slug path wildcard. See Slug and ValueOrFail.
+func SlugOrFail(w http.ResponseWriter, r *http.Request, key string) (string, bool) {
+	return ValueOrFail(w, r, key, parseSlug)
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// parseUUID validates the canonical textual UUID form and lowercases it.
+func parseUUID(s string) (string, error) {
+	if len(s) != 36 {
+		return "", ErrInvalidUUID
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return "", ErrInvalidUUID
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return "", ErrInvalidUUID
+			}
+		}
+	}
+	return strings.ToLower(s), nil
+}
+
+// parseSlug accepts lowercase ASCII letters and digits separated by single
+// hyphens, with no leading or trailing hyphen.
+func parseSlug(s string) (string, error) {
+	if s == "" || s[0] == '-' || s[len(s)-1] == '-' || strings.Contains(s, "--") {
+		return "", ErrInvalidSlug
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+			return "", ErrInvalidSlug
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,155 @@
+package pathparam_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/pathparam"
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+// serve routes target through a ServeMux with the given pattern, so path
+// values are populated exactly as in production, and returns the handler's
+// request.
+func serve(t *testing.T, pattern, target string) *http.Request {
+	t.Helper()
+	var got *http.Request
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, func(_ http.ResponseWriter, r *http.Request) {
+		got = r
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+	if got == nil {
+		t.Fatalf("pattern %q did not match %q", pattern, target)
+	}
+	return got
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected int
+	}{
+		{"valid", "/users/42", 42},
+		{"negative", "/users/-1", -1},
+		{"invalid", "/users/abc", 7},
+		{"overflow", "/users/99999999999999999999", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := serve(t, "GET /users/{id}", tt.target)
+			if got := pathparam.Int(r, "id", 7); got != tt.expected {
+				t.Errorf("Int() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractors(t *testing.T) {
+	r := serve(t, "GET /files/{id}/{slug}/{n}/{rest...}", "/files/3F2504E0-4F89-11D3-9A0C-0305E82C3301/hello-world-2024/9000000000/a/b")
+
+	if got := pathparam.UUID(r, "id", ""); got != "3f2504e0-4f89-11d3-9a0c-0305e82c3301" {
+		t.Errorf("UUID() = %q, want lowercase UUID", got)
+	}
+	if got := pathparam.Slug(r, "slug", ""); got != "hello-world-2024" {
+		t.Errorf("Slug() = %q, want hello-world-2024", got)
+	}
+	if got := pathparam.Int64(r, "n", 0); got != 9000000000 {
+		t.Errorf("Int64() = %v, want 9000000000", got)
+	}
+	if got := pathparam.String(r, "rest", ""); got != "a/b" {
+		t.Errorf("String() = %q, want a/b", got)
+	}
+	if got := pathparam.String(r, "missing", "none"); got != "none" {
+		t.Errorf("String(missing) = %q, want default", got)
+	}
+	if got := pathparam.Value(r, "slug", 0, func(s string) (int, error) { return len(s), nil }); got != 16 {
+		t.Errorf("Value() = %v, want 16", got)
+	}
+}
+
+func TestUUIDAndSlugValidation(t *testing.T) {
+	uuids := map[string]bool{
+		"3f2504e0-4f89-11d3-9a0c-0305e82c3301":   true,
+		"3f2504e04f8911d39a0c0305e82c3301":       false,
+		"3f2504e0-4f89-11d3-9a0c-0305e82c330":    false,
+		"3f2504e0-4f89-11d3-9a0c-0305e82c330g":   false,
+		"{3f2504e0-4f89-11d3-9a0c-0305e82c3301}": false,
+		"3f2504e0_4f89-11d3-9a0c-0305e82c3301":   false,
+	}
+	for value, valid := range uuids {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.SetPathValue("id", value)
+		if got := pathparam.UUID(r, "id", "") != ""; got != valid {
+			t.Errorf("UUID(%q) valid = %v, want %v", value, got, valid)
+		}
+	}
+
+	slugs := map[string]bool{
+		"hello":        true,
+		"hello-world":  true,
+		"2024-recap":   true,
+		"Hello":        false,
+		"-hello":       false,
+		"hello-":       false,
+		"hello--world": false,
+		"hello_world":  false,
+		"héllo":        false,
+	}
+	for value, valid := range slugs {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.SetPathValue("slug", value)
+		if got := pathparam.Slug(r, "slug", "") != ""; got != valid {
+			t.Errorf("Slug(%q) valid = %v, want %v", value, got, valid)
+		}
+	}
+}
+
+func TestOrFail(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{"valid", "42", nil},
+		{"invalid", "abc", strconv.ErrSyntax},
+		{"missing", "", query.ErrMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.SetPathValue("id", tt.value)
+			w := httptest.NewRecorder()
+
+			got, ok := pathparam.IntOrFail(w, r, "id")
+			if tt.wantErr == nil {
+				if !ok || got != 42 || w.Code != http.StatusOK {
+					t.Errorf("IntOrFail() = %v, %v, status %d, want 42, true, 200", got, ok, w.Code)
+				}
+				return
+			}
+			if ok || w.Code != http.StatusBadRequest {
+				t.Errorf("IntOrFail() ok = %v, status %d, want false, 400", ok, w.Code)
+			}
+			if body := w.Body.String(); !strings.Contains(body, tt.wantErr.Error()) {
+				t.Errorf("IntOrFail() body = %q, want it to mention %q", body, tt.wantErr)
+			}
+		})
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.SetPathValue("id", "nope")
+	w := httptest.NewRecorder()
+	if _, ok := pathparam.UUIDOrFail(w, r, "id"); ok {
+		t.Fatal("UUIDOrFail() ok = true, want false")
+	}
+	if body := w.Body.String(); !strings.Contains(body, pathparam.ErrInvalidUUID.Error()) {
+		t.Errorf("UUIDOrFail() body = %q, want it to mention %q", body, pathparam.ErrInvalidUUID)
+	}
+}
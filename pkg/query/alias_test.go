@@ -0,0 +1,98 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExtractorAliases(t *testing.T) {
+	e := NewExtractor(Config{Aliases: map[string][]string{"per_page": {"limit", "page_size"}}})
+
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{"own name", "/?per_page=10", 10},
+		{"first alias", "/?limit=20", 20},
+		{"second alias", "/?page_size=30", 30},
+		{"own name wins", "/?page_size=30&per_page=10&limit=20", 10},
+		{"alias order", "/?page_size=30&limit=20", 20},
+		{"bracketed alias", "/?limit[]=40", 40},
+		{"missing", "/", 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := e.Int(r, "per_page", 25); got != tt.expected {
+				t.Errorf("Extractor.Int() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+
+	r := httptest.NewRequest("GET", "/?limit=5&page_size=6", nil)
+	if got := e.Ints(r, "per_page", 0); !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("Extractor.Ints() = %v, want [5]", got)
+	}
+	if unknown := e.AllowKeys(r, "per_page"); len(unknown) != 0 {
+		t.Errorf("Extractor.AllowKeys() = %v, want aliases allowed", unknown)
+	}
+}
+
+func TestExtractorAliasesBind(t *testing.T) {
+	e := NewExtractor(Config{Aliases: map[string][]string{"per_page": {"limit"}}})
+
+	var params struct {
+		PerPage int `query:"per_page"`
+	}
+	r := httptest.NewRequest("GET", "/?limit=50", nil)
+	if err := e.StrictBind(r, &params); err != nil || params.PerPage != 50 {
+		t.Errorf("Extractor.StrictBind() = %d, %v, want 50", params.PerPage, err)
+	}
+}
+
+func TestBindAliasTag(t *testing.T) {
+	type params struct {
+		PerPage int `query:"per_page,alias=limit page_size,default=25"`
+		Filter  struct {
+			Status string `query:"status,alias=state"`
+		} `query:"filter"`
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		perPage  int
+		status   string
+		wantKeys []string
+	}{
+		{"own names", "/?per_page=10&filter.status=open", 10, "open", nil},
+		{"aliases", "/?page_size=30&filter[state]=closed", 30, "closed", nil},
+		{"precedence", "/?page_size=30&limit=20", 20, "", nil},
+		{"default", "/", 25, "", nil},
+		{"unknown", "/?size=5", 25, "", []string{"size"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			var got params
+			err := StrictBind(r, &got)
+
+			var unknownErr *UnknownKeysError
+			if errors.As(err, &unknownErr) {
+				if !reflect.DeepEqual(unknownErr.Keys, tt.wantKeys) {
+					t.Errorf("StrictBind() unknown keys = %v, want %v", unknownErr.Keys, tt.wantKeys)
+				}
+			} else if err != nil || tt.wantKeys != nil {
+				t.Errorf("StrictBind() error = %v, want unknown keys %v", err, tt.wantKeys)
+			}
+			if got.PerPage != tt.perPage || got.Filter.Status != tt.status {
+				t.Errorf("StrictBind() = %+v, want per_page %d and status %q", got, tt.perPage, tt.status)
+			}
+		})
+	}
+}
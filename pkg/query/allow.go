@@ -0,0 +1,69 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// UnknownKeysError is returned by StrictBind when the query string contains
+// parameters that do not map to any field.
+type UnknownKeysError struct {
+	Keys []string // Unexpected parameter names, sorted
+}
+
+func (e *UnknownKeysError) Error() string {
+	return fmt.Sprintf("query: unknown parameters: %s", strings.Join(e.Keys, ", "))
+}
+
+// AllowKeys returns the query parameters that are not in the allowed list,
+// sorted by name, so strict APIs can reject typos such as ?pgae=2 instead of
+// silently ignoring them. Bracketed forms of an allowed key (id[] or
+// filter[status]) count as that key. Returns an empty slice if every parameter
+// is allowed.
+//
+// Example:
+//
+//	// URL: /products?page=2&pgae=3&limit=10
+//	if unknown := query.AllowKeys(r, "page", "limit", "sort"); len(unknown) > 0 {
+//	    http.Error(w, "unknown parameters: "+strings.Join(unknown, ", "), http.StatusBadRequest)
+//	    return
+//	}
+func AllowKeys(r *http.Request, keys ...string) []string {
+	return std.AllowKeys(r, keys...)
+}
+
+// unknownKeys returns the sorted keys of values that are not in allowed, either
+// directly or through their bracket base name. With fold, names are compared
+// case-insensitively.
+func unknownKeys(values url.Values, allowed []string, fold bool) []string {
+	set := make(map[string]struct{}, len(allowed))
+	for _, k := range allowed {
+		if fold {
+			k = strings.ToLower(k)
+		}
+		set[k] = struct{}{}
+	}
+	isAllowed := func(k string) bool {
+		if fold {
+			k = strings.ToLower(k)
+		}
+		_, ok := set[k]
+		return ok
+	}
+
+	unknown := []string{}
+	for k := range values {
+		if isAllowed(k) {
+			continue
+		}
+		if base, path, ok := splitBracketKey(k); ok && len(path) > 0 && isAllowed(base) {
+			continue
+		}
+		unknown = append(unknown, k)
+	}
+	slices.Sort(unknown)
+	return unknown
+}
@@ -0,0 +1,35 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAllowKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		allowed  []string
+		expected []string
+	}{
+		{"all allowed", "/?page=2&limit=10", []string{"page", "limit", "sort"}, []string{}},
+		{"typo", "/?pgae=2&limit=10", []string{"page", "limit"}, []string{"pgae"}},
+		{"sorted", "/?zeta=1&alpha=2&page=1", []string{"page"}, []string{"alpha", "zeta"}},
+		{"array brackets", "/?id[]=1&id[]=2", []string{"id"}, []string{}},
+		{"map brackets", "/?filter[status]=active", []string{"filter"}, []string{}},
+		{"bracket of unknown key", "/?fitler[status]=active", []string{"filter"}, []string{"fitler[status]"}},
+		{"no params", "/", []string{"page"}, []string{}},
+		{"nothing allowed", "/?page=1", nil, []string{"page"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := AllowKeys(r, tt.allowed...)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("AllowKeys() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,533 @@
+package query
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBindTarget is returned by Bind when dst is not a non-nil pointer to a struct.
+var ErrInvalidBindTarget = errors.New("query: Bind destination must be a non-nil pointer to a struct")
+
+// FieldError describes a struct field that could not be bound.
+type FieldError struct {
+	Field string // Go field name
+	Key   string // Query parameter name
+	Value string // Raw value that failed, if any
+	Err   error  // Underlying error
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("query: field %s (%s): %v", e.Field, e.Key, e.Err)
+	}
+	return fmt.Sprintf("query: field %s (%s=%q): %v", e.Field, e.Key, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BindErrors collects the FieldError of every field that could not be bound.
+type BindErrors []*FieldError
+
+func (e BindErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual field errors, so errors.Is and errors.As can
+// inspect them.
+func (e BindErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Bind populates the exported fields of the struct pointed to by dst from the
+// query string. Each field is read from the parameter named by its `query` tag,
+// or from its Go field name when untagged; a tag of "-" skips the field.
+//
+// Supported field types are string, bool, all integer and float kinds,
+// time.Time (parsed with DefaultTimeLayouts), time.Duration, types implementing
+// encoding.TextUnmarshaler, types registered with RegisterType, pointers to
+// these, and slices of these (filled from repeated parameters).
+//
+// Fields of other struct types, or pointers to them, are bound recursively from
+// dotted or bracketed parameter names, so a Price field tagged `query:"price"`
+// containing a Min field tagged `query:"min"` is read from ?price.min=10 or
+// ?price[min]=10; a nil pointer is only allocated when one of its fields is
+// bound. Untagged embedded structs are flattened into the parent.
+//
+// Slices of structs are bound from indexed groups such as
+// ?items[0][sku]=A1&items[0][qty]=2 or ?items.0.sku=A1. Groups are ordered by
+// index and compacted, so sparse indexes leave no gaps, and groups in which no
+// field is bound are dropped. At most 1000 groups, or Config.MaxValueCount if
+// lower, are accepted per slice.
+//
+// Fields whose parameter is missing or empty are left untouched, so values set
+// before calling Bind act as defaults. Fields whose value cannot be parsed are
+// also left untouched and reported in a BindErrors error; all other fields are
+// still bound.
+//
+// Options after the name in a `query` tag add defaults, validation and aliases:
+//
+//   - default=V binds V when the parameter is missing or empty
+//   - required reports ErrMissing when the parameter is missing or empty
+//   - min=N and max=N bound numbers, durations and times by value (N is parsed
+//     like the field) and strings by length in characters
+//   - oneof=a b c accepts only the listed values, separated by spaces
+//   - alias=a b reads the field from the listed names, in order, when its own
+//     name is not present
+//
+// Rules apply to each element of a slice. A value that breaks a rule leaves the
+// field untouched and is reported as a FieldError wrapping a *RuleError.
+//
+// Example:
+//
+//	type ListParams struct {
+//	    Page   int      `query:"page,default=1,min=1"`
+//	    Limit  int      `query:"limit,default=25,min=1,max=100"`
+//	    Order  string   `query:"order,oneof=asc desc"`
+//	    Tags   []string `query:"tag"`
+//	    Active *bool    `query:"active"`
+//	}
+//
+//	var params ListParams
+//	if err := query.Bind(r, &params); err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+func Bind(r *http.Request, dst any) error {
+	return std.Bind(r, dst)
+}
+
+// StrictBind is like Bind, but additionally rejects query parameters that do not
+// map to any field, returning an *UnknownKeysError. When there are also field
+// errors, both are returned joined together.
+func StrictBind(r *http.Request, dst any) error {
+	return std.StrictBind(r, dst)
+}
+
+// bind binds values into dst and returns the parameter names it consulted.
+func (e *Extractor) bind(values url.Values, dst any) ([]string, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidBindTarget
+	}
+
+	b := &binder{e: e, values: values}
+	b.bindStruct(rv.Elem(), nil)
+	if len(b.errs) > 0 {
+		return b.used, b.errs
+	}
+	return b.used, nil
+}
+
+func (e *Extractor) strictBind(values url.Values, dst any) error {
+	used, err := e.bind(values, dst)
+	if errors.Is(err, ErrInvalidBindTarget) {
+		return err
+	}
+
+	if unknown := unknownKeys(values, e.withAliases(used), e.cfg.CaseInsensitiveKeys); len(unknown) > 0 {
+		return errors.Join(err, &UnknownKeysError{Keys: unknown})
+	}
+	return err
+}
+
+// maxBindGroups bounds how many elements a slice of structs receives from
+// indexed parameters such as items[0][sku], unless Config.MaxValueCount is lower.
+const maxBindGroups = 1000
+
+type binder struct {
+	e      *Extractor
+	values url.Values
+	used   []string
+	errs   BindErrors
+	bound  int // number of fields set so far, to detect empty nested structs
+}
+
+// bindStruct binds the fields of v. path holds the names of the enclosing
+// struct fields, and any slice indexes, that prefix every parameter name.
+func (b *binder) bindStruct(v reflect.Value, path []string) {
+	if len(path) > maxNestingDepth {
+		return
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key := fieldKey(field)
+		if key == "" {
+			continue
+		}
+
+		// Untagged embedded structs are flattened, as in encoding/json.
+		if field.Anonymous && !hasTagName(field) && isNestedStruct(indirectType(field.Type)) {
+			if field.IsExported() || field.Type.Kind() == reflect.Struct {
+				b.bindNested(v.Field(i), path)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := childPath(path, key)
+		switch {
+		case isNestedStruct(indirectType(field.Type)):
+			b.bindNested(v.Field(i), fieldPath)
+		case field.Type.Kind() == reflect.Slice && isNestedStruct(field.Type.Elem()):
+			b.bindGroups(v.Field(i), fieldPath, field.Name)
+		default:
+			b.bindField(v.Field(i), field, fieldPath)
+		}
+	}
+}
+
+// bindField binds a field holding a single value or a slice of values, applying
+// the default and validation rules from its tag.
+func (b *binder) bindField(fv reflect.Value, field reflect.StructField, path []string) {
+	rules, err := parseRules(field)
+	if err != nil {
+		b.errs = append(b.errs, &FieldError{Field: field.Name, Key: dottedKey(path), Err: err})
+		return
+	}
+	key, vals, err := b.lookupAliased(path, rules.aliases)
+	if err != nil {
+		b.errs = append(b.errs, &FieldError{Field: field.Name, Key: key, Err: err})
+		return
+	}
+
+	sent := len(vals) > 0 && (field.Type.Kind() == reflect.Slice || vals[0] != "")
+	switch {
+	case sent:
+	case rules.hasDefault:
+		vals = []string{rules.def}
+	case rules.required:
+		b.errs = append(b.errs, &FieldError{Field: field.Name, Key: key, Err: ErrMissing})
+		return
+	default:
+		return
+	}
+
+	if field.Type.Kind() == reflect.Slice {
+		vals = b.e.capItems(vals)
+	}
+	parsed := reflect.New(field.Type).Elem()
+	if bad, err := b.e.setField(parsed, vals); err != nil {
+		b.errs = append(b.errs, &FieldError{Field: field.Name, Key: key, Value: bad, Err: err})
+		return
+	}
+	if bad, err := b.e.validate(parsed, vals, rules); err != nil {
+		b.errs = append(b.errs, &FieldError{Field: field.Name, Key: key, Value: bad, Err: err})
+		return
+	}
+
+	fv.Set(parsed)
+	if sent {
+		b.bound++
+	}
+}
+
+// bindNested binds a struct or pointer-to-struct field. A nil pointer is only
+// allocated when at least one of the struct's fields is bound.
+func (b *binder) bindNested(fv reflect.Value, path []string) {
+	if fv.Kind() != reflect.Pointer {
+		b.bindStruct(fv, path)
+		return
+	}
+	if !fv.IsNil() {
+		b.bindStruct(fv.Elem(), path)
+		return
+	}
+
+	elem := reflect.New(fv.Type().Elem())
+	before := b.bound
+	b.bindStruct(elem.Elem(), path)
+	if b.bound > before {
+		fv.Set(elem)
+	}
+}
+
+// bindGroups binds a slice of structs from indexed parameters. Indexes are
+// compacted in ascending order, so items[0] and items[5] produce two elements,
+// and groups in which no field is bound are dropped.
+func (b *binder) bindGroups(fv reflect.Value, path []string, fieldName string) {
+	indices := b.groupIndices(path)
+	if len(indices) == 0 {
+		return
+	}
+
+	limit := maxBindGroups
+	if n := b.e.cfg.MaxValueCount; n > 0 && n < limit {
+		limit = n
+	}
+	if len(indices) > limit {
+		b.errs = append(b.errs, &FieldError{Field: fieldName, Key: dottedKey(path), Err: ErrTooManyValues})
+		return
+	}
+
+	slice := reflect.MakeSlice(fv.Type(), 0, len(indices))
+	for _, idx := range indices {
+		elem := reflect.New(fv.Type().Elem()).Elem()
+		before := b.bound
+		b.bindStruct(elem, childPath(path, strconv.Itoa(idx)))
+		if b.bound > before {
+			slice = reflect.Append(slice, elem)
+		}
+	}
+	if slice.Len() > 0 {
+		fv.Set(slice)
+	}
+}
+
+// groupIndices returns the sorted, distinct indexes used in parameter names of
+// the form path[i]... or path.i.... Indexes with leading zeros are ignored.
+func (b *binder) groupIndices(path []string) []int {
+	prefixes := [2]string{dottedKey(path) + ".", bracketKey(path) + "["}
+	terminators := [2]byte{'.', ']'}
+
+	seen := make(map[int]struct{})
+	for k := range b.values {
+		for form, prefix := range prefixes {
+			if len(k) <= len(prefix) || !b.keyEqual(k[:len(prefix)], prefix) {
+				continue
+			}
+			rest := k[len(prefix):]
+			end := strings.IndexByte(rest, terminators[form])
+			if end <= 0 || end > 6 || !isDigits(rest[:end]) || (end > 1 && rest[0] == '0') {
+				continue
+			}
+			idx, _ := strconv.Atoi(rest[:end])
+			seen[idx] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// lookup returns the values for the field at path, trying the dotted name
+// (address.city) before the bracketed one (address[city]), along with the
+// name the values were found under.
+// lookupAliased is lookup for path and then, while nothing is found, for path
+// with its last segment replaced by each alias in turn. Every alias is looked
+// up, so that all of them count as used for StrictBind.
+func (b *binder) lookupAliased(path []string, aliases []string) (string, []string, error) {
+	key, vals, err := b.lookup(path)
+	found := len(vals) > 0 || err != nil
+	for _, alias := range aliases {
+		aliasKey, aliasVals, aliasErr := b.lookup(childPath(path[:len(path)-1], alias))
+		if !found && (len(aliasVals) > 0 || aliasErr != nil) {
+			key, vals, err, found = aliasKey, aliasVals, aliasErr, true
+		}
+	}
+	return key, vals, err
+}
+
+func (b *binder) lookup(path []string) (string, []string, error) {
+	key := dottedKey(path)
+	b.used = append(b.used, key)
+	vals, err := b.e.lookupErr(b.values, key)
+	if len(path) == 1 || len(vals) > 0 || err != nil {
+		return key, vals, err
+	}
+
+	alt := bracketKey(path)
+	b.used = append(b.used, alt)
+	if altVals, altErr := b.e.lookupErr(b.values, alt); len(altVals) > 0 || altErr != nil {
+		return alt, altVals, altErr
+	}
+	return key, vals, err
+}
+
+func (b *binder) keyEqual(a, c string) bool {
+	if b.e.cfg.CaseInsensitiveKeys {
+		return strings.EqualFold(a, c)
+	}
+	return a == c
+}
+
+// childPath returns path extended by name, without sharing path's backing array.
+func childPath(path []string, name string) []string {
+	return append(path[:len(path):len(path)], name)
+}
+
+// dottedKey joins path as a.b.c.
+func dottedKey(path []string) string {
+	return strings.Join(path, ".")
+}
+
+// bracketKey joins path as a[b][c].
+func bracketKey(path []string) string {
+	if len(path) == 1 {
+		return path[0]
+	}
+	return path[0] + "[" + strings.Join(path[1:], "][") + "]"
+}
+
+// isNestedStruct reports whether fields of type t are bound field by field
+// from dotted or bracketed parameter names rather than parsed from a single
+// value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return false
+	}
+	_, registered := lookupType(t)
+	return !registered
+}
+
+// indirectType returns the element type of pointer types, and t otherwise.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// hasTagName reports whether field has a `query` tag with an explicit name.
+func hasTagName(field reflect.StructField) bool {
+	tag, _ := field.Tag.Lookup("query")
+	name, _, _ := strings.Cut(tag, ",")
+	return name != ""
+}
+
+// fieldKey returns the parameter name for field, or "" if it should be skipped.
+func fieldKey(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("query")
+	if !ok {
+		return field.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// setField stores vals in fv. On failure fv is left untouched and the offending
+// raw value is returned along with the error.
+func (e *Extractor) setField(fv reflect.Value, vals []string) (string, error) {
+	switch {
+	case fv.Kind() == reflect.Slice:
+		elemType := fv.Type().Elem()
+		slice := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			parsed, err := e.parseScalar(elemType, val)
+			if err != nil {
+				return val, err
+			}
+			slice.Index(i).Set(parsed)
+		}
+		fv.Set(slice)
+		return "", nil
+
+	case fv.Kind() == reflect.Pointer:
+		if vals[0] == "" {
+			return "", nil
+		}
+		parsed, err := e.parseScalar(fv.Type().Elem(), vals[0])
+		if err != nil {
+			return vals[0], err
+		}
+		ptr := reflect.New(fv.Type().Elem())
+		ptr.Elem().Set(parsed)
+		fv.Set(ptr)
+		return "", nil
+
+	default:
+		if vals[0] == "" {
+			return "", nil
+		}
+		parsed, err := e.parseScalar(fv.Type(), vals[0])
+		if err != nil {
+			return vals[0], err
+		}
+		fv.Set(parsed)
+		return "", nil
+	}
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// parseScalar parses s into a new value of type t.
+func (e *Extractor) parseScalar(t reflect.Type, s string) (reflect.Value, error) {
+	if entry, ok := lookupType(t); ok {
+		return entry.parse(s)
+	}
+
+	v := reflect.New(t).Elem()
+	switch t {
+	case timeType:
+		parsed, _, ok := parseTime(s, nil)
+		if !ok {
+			return v, strconv.ErrSyntax
+		}
+		v.Set(reflect.ValueOf(parsed))
+		return v, nil
+	case durationType:
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(int64(parsed))
+		return v, nil
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		return v, err
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		parsed, err := e.parseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(e.normalizeNumber(s), 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(e.normalizeNumber(s), 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := e.parseFloat(s, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(parsed)
+	default:
+		return v, fmt.Errorf("unsupported field type %s", t)
+	}
+	return v, nil
+}
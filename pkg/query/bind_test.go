@@ -0,0 +1,284 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type bindParams struct {
+	Page     int           `query:"page"`
+	Limit    uint8         `query:"limit"`
+	Ratio    float64       `query:"ratio"`
+	Active   bool          `query:"active"`
+	Sort     string        `query:"sort"`
+	Tags     []string      `query:"tag"`
+	IDs      []int64       `query:"id"`
+	Since    time.Time     `query:"since"`
+	Timeout  time.Duration `query:"timeout"`
+	Archived *bool         `query:"archived"`
+	Level    level         `query:"level"`
+	Levels   []level       `query:"levels"`
+	Name     string
+	Skipped  string `query:"-"`
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected bindParams
+	}{
+		{"defaults kept", "/", bindParams{Page: 1, Sort: "name"}},
+		{"scalars", "/?page=3&limit=50&ratio=0.5&active=yes&sort=price",
+			bindParams{Page: 3, Limit: 50, Ratio: 0.5, Active: true, Sort: "price"}},
+		{"slices", "/?tag=go&tag=rust&id[]=1&id[]=2",
+			bindParams{Page: 1, Sort: "name", Tags: []string{"go", "rust"}, IDs: []int64{1, 2}}},
+		{"time and duration", "/?since=2024-01-02&timeout=1m30s",
+			bindParams{Page: 1, Sort: "name", Since: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Timeout: 90 * time.Second}},
+		{"pointer", "/?archived=false", bindParams{Page: 1, Sort: "name", Archived: ptr(false)}},
+		{"text unmarshaler", "/?level=warn&levels=info&levels=error",
+			bindParams{Page: 1, Sort: "name", Level: levelWarn, Levels: []level{levelInfo, levelError}}},
+		{"empty value keeps default", "/?page=&sort=", bindParams{Page: 1, Sort: "name"}},
+		{"untagged uses field name", "/?Name=gopher", bindParams{Page: 1, Sort: "name", Name: "gopher"}},
+		{"skipped field", "/?Skipped=x&internal=y", bindParams{Page: 1, Sort: "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := bindParams{Page: 1, Sort: "name"}
+			if err := Bind(r, &got); err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Bind() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?page=abc&limit=300&sort=price&id=1&id=x&level=loud", nil)
+	got := bindParams{Page: 1, IDs: []int64{9}}
+
+	err := Bind(r, &got)
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("Bind() error = %v, want BindErrors", err)
+	}
+	if len(bindErrs) != 4 {
+		t.Fatalf("Bind() returned %d field errors, want 4: %v", len(bindErrs), err)
+	}
+
+	wantKeys := []string{"page", "limit", "id", "level"}
+	wantValues := []string{"abc", "300", "x", "loud"}
+	for i, fe := range bindErrs {
+		if fe.Key != wantKeys[i] || fe.Value != wantValues[i] {
+			t.Errorf("error %d = %s=%q, want %s=%q", i, fe.Key, fe.Value, wantKeys[i], wantValues[i])
+		}
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Bind() error should wrap strconv.ErrSyntax")
+	}
+
+	// Invalid fields keep their defaults, valid ones are still bound.
+	if got.Page != 1 || !reflect.DeepEqual(got.IDs, []int64{9}) || got.Sort != "price" {
+		t.Errorf("Bind() = %+v, want invalid fields untouched and sort bound", got)
+	}
+}
+
+func TestBindInvalidTarget(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?page=1", nil)
+
+	var params bindParams
+	var nilPtr *bindParams
+	n := 0
+	for _, dst := range []any{params, nilPtr, &n, nil} {
+		if err := Bind(r, dst); !errors.Is(err, ErrInvalidBindTarget) {
+			t.Errorf("Bind(%T) error = %v, want ErrInvalidBindTarget", dst, err)
+		}
+	}
+}
+
+func TestStrictBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		wantUnknown []string
+		wantField   bool
+	}{
+		{"all known", "/?page=2&tag=go&id[]=1", nil, false},
+		{"typo", "/?pgae=2&limit=10", []string{"pgae"}, false},
+		{"skipped field is unknown", "/?Skipped=x", []string{"Skipped"}, false},
+		{"unknown and invalid", "/?page=abc&extra=1", []string{"extra"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			var params bindParams
+			err := StrictBind(r, &params)
+
+			var unknownErr *UnknownKeysError
+			if errors.As(err, &unknownErr) {
+				if !reflect.DeepEqual(unknownErr.Keys, tt.wantUnknown) {
+					t.Errorf("StrictBind() unknown keys = %v, want %v", unknownErr.Keys, tt.wantUnknown)
+				}
+			} else if tt.wantUnknown != nil {
+				t.Errorf("StrictBind() error = %v, want UnknownKeysError", err)
+			}
+
+			var bindErrs BindErrors
+			if errors.As(err, &bindErrs) != tt.wantField {
+				t.Errorf("StrictBind() error = %v, want field errors %v", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestBindLimits(t *testing.T) {
+	e := NewExtractor(Config{MaxValueLength: 5, MaxValueCount: 2})
+	r := httptest.NewRequest("GET", "/?sort=toolongvalue&tag=a&tag=b&tag=c&page=2", nil)
+
+	got := bindParams{Sort: "name"}
+	err := e.Bind(r, &got)
+
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) || len(bindErrs) != 2 {
+		t.Fatalf("Extractor.Bind() error = %v, want 2 field errors", err)
+	}
+	if !errors.Is(err, ErrValueTooLong) || !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("Extractor.Bind() error = %v, want ErrValueTooLong and ErrTooManyValues", err)
+	}
+	if got.Page != 2 || got.Sort != "name" || got.Tags != nil {
+		t.Errorf("Extractor.Bind() = %+v, want limited fields untouched", got)
+	}
+}
+
+type bindPaging struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type bindAddress struct {
+	City string `query:"city"`
+	Zip  string `query:"zip"`
+}
+
+type bindItem struct {
+	SKU string `query:"sku"`
+	Qty int    `query:"qty"`
+}
+
+type bindFilter struct {
+	bindPaging
+	Address  bindAddress  `query:"address"`
+	Shipping *bindAddress `query:"shipping"`
+	Items    []bindItem   `query:"items"`
+}
+
+func TestBindNested(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected bindFilter
+	}{
+		{"embedded struct is flattened", "/?page=2&limit=10",
+			bindFilter{bindPaging: bindPaging{Page: 2, Limit: 10}}},
+		{"dotted nested", "/?address.city=Berlin&address.zip=10115",
+			bindFilter{Address: bindAddress{City: "Berlin", Zip: "10115"}}},
+		{"bracketed nested", "/?address[city]=Berlin&address[zip]=10115",
+			bindFilter{Address: bindAddress{City: "Berlin", Zip: "10115"}}},
+		{"dotted wins over bracketed", "/?address.city=Berlin&address[city]=Paris",
+			bindFilter{Address: bindAddress{City: "Berlin"}}},
+		{"pointer allocated when bound", "/?shipping[city]=Oslo",
+			bindFilter{Shipping: &bindAddress{City: "Oslo"}}},
+		{"pointer nil when nothing bound", "/?shipping[city]=", bindFilter{}},
+		{"bracketed groups", "/?items[0][sku]=A1&items[0][qty]=2&items[1][sku]=B2",
+			bindFilter{Items: []bindItem{{SKU: "A1", Qty: 2}, {SKU: "B2"}}}},
+		{"dotted groups", "/?items.0.sku=A1&items.1.sku=B2&items.1.qty=3",
+			bindFilter{Items: []bindItem{{SKU: "A1"}, {SKU: "B2", Qty: 3}}}},
+		{"sparse groups are compacted in order", "/?items[7][sku]=C3&items[2][sku]=B2&items[10][sku]=D4",
+			bindFilter{Items: []bindItem{{SKU: "B2"}, {SKU: "C3"}, {SKU: "D4"}}}},
+		{"empty groups dropped", "/?items[0][sku]=&items[1][sku]=B2",
+			bindFilter{Items: []bindItem{{SKU: "B2"}}}},
+		{"leading zero index ignored", "/?items[01][sku]=A1", bindFilter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			var got bindFilter
+			if err := Bind(r, &got); err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Bind() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindNestedErrors(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?items[0][sku]=A1&items[1][qty]=many", nil)
+
+	var got bindFilter
+	err := Bind(r, &got)
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) || len(bindErrs) != 1 {
+		t.Fatalf("Bind() error = %v, want 1 field error", err)
+	}
+	if fe := bindErrs[0]; fe.Field != "Qty" || fe.Key != "items[1][qty]" || fe.Value != "many" {
+		t.Errorf("Bind() field error = %+v, want Qty at items[1][qty]", fe)
+	}
+	if !reflect.DeepEqual(got.Items, []bindItem{{SKU: "A1"}}) {
+		t.Errorf("Bind() items = %+v, want only the valid group", got.Items)
+	}
+}
+
+func TestBindGroupLimit(t *testing.T) {
+	e := NewExtractor(Config{MaxValueCount: 2})
+	r := httptest.NewRequest("GET", "/?items[0][sku]=A&items[1][sku]=B&items[2][sku]=C", nil)
+
+	var got bindFilter
+	err := e.Bind(r, &got)
+	if !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("Extractor.Bind() error = %v, want ErrTooManyValues", err)
+	}
+	if got.Items != nil {
+		t.Errorf("Extractor.Bind() items = %+v, want nil", got.Items)
+	}
+}
+
+func TestStrictBindNested(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		wantUnknown []string
+	}{
+		{"all known", "/?page=1&address.city=Berlin&shipping[zip]=0150&items[0][sku]=A1&items.1.qty=2", nil},
+		{"unknown nested field", "/?address[zzz]=1&items[0][color]=red", []string{"address[zzz]", "items[0][color]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			var params bindFilter
+			err := StrictBind(r, &params)
+
+			var unknownErr *UnknownKeysError
+			if errors.As(err, &unknownErr) {
+				if !reflect.DeepEqual(unknownErr.Keys, tt.wantUnknown) {
+					t.Errorf("StrictBind() unknown keys = %v, want %v", unknownErr.Keys, tt.wantUnknown)
+				}
+			} else if tt.wantUnknown != nil || err != nil {
+				t.Errorf("StrictBind() error = %v, want unknown keys %v", err, tt.wantUnknown)
+			}
+		})
+	}
+}
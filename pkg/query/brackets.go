@@ -0,0 +1,163 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// maxNestingDepth bounds how many bracket segments are honored in a single key,
+// so a crafted key such as a[b][c][d]... cannot build arbitrarily deep trees.
+const maxNestingDepth = 10
+
+// Map extracts PHP/Rails-style bracketed parameters into a map keyed by the
+// bracketed name. Only single-level keys are included; use Nested for deeper
+// structures. If a key appears multiple times, the first value is used.
+// Returns an empty map if no matching parameters are present.
+//
+// Example: For URL "?filter[status]=active&filter[type]=post&page=2"
+//
+//	filter := query.Map(r, "filter")  // map[string]string{"status": "active", "type": "post"}
+func Map(r *http.Request, key string) map[string]string {
+	return MapOf(r, key, "", func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// MapOf extracts single-level bracketed parameters and converts their values
+// using the provided parser. If a value cannot be parsed, defaultValue is used
+// for that entry. Returns an empty map if no matching parameters are present.
+//
+// Example:
+//
+//	// URL: /stock?qty[apples]=3&qty[pears]=x
+//	qty := query.MapOf(r, "qty", 0, strconv.Atoi)  // map[string]int{"apples": 3, "pears": 0}
+func MapOf[T any](r *http.Request, key string, defaultValue T, parser Parser[T]) map[string]T {
+	parser = resolveParser(parser)
+	result := make(map[string]T)
+	for k, vals := range r.URL.Query() {
+		base, path, ok := splitBracketKey(k)
+		if !ok || base != key || len(path) != 1 || path[0] == "" || len(vals) == 0 {
+			continue
+		}
+
+		parsed, err := parser(vals[0])
+		if err != nil {
+			result[path[0]] = defaultValue
+		} else {
+			result[path[0]] = parsed
+		}
+	}
+	return result
+}
+
+// Nested extracts bracketed parameters of any depth into a tree of
+// map[string]any. Leaves are strings (the first value of the parameter), or
+// []string for keys ending in "[]". When a key is used both as a leaf and as a
+// parent (filter[a]=1&filter[a][b]=2), the nested form wins.
+// Keys nested more than ten levels deep are ignored.
+// Returns an empty map if no matching parameters are present.
+//
+// Example: For URL "?filter[price][min]=10&filter[price][max]=50&filter[tag][]=a&filter[tag][]=b"
+//
+//	filter := query.Nested(r, "filter")
+//	// map[string]any{
+//	//     "price": map[string]any{"min": "10", "max": "50"},
+//	//     "tag":   []string{"a", "b"},
+//	// }
+func Nested(r *http.Request, key string) map[string]any {
+	return nestedFrom(r.URL.Query(), key)
+}
+
+func nestedFrom(values url.Values, key string) map[string]any {
+	// Sorting guarantees parents are visited before their children, which
+	// makes conflict resolution deterministic.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	result := make(map[string]any)
+	for _, k := range keys {
+		base, path, ok := splitBracketKey(k)
+		if !ok || base != key || len(path) == 0 || len(values[k]) == 0 {
+			continue
+		}
+
+		var leaf any = values[k][0]
+		if path[len(path)-1] == "" {
+			path = path[:len(path)-1]
+			leaf = values[k]
+			if len(path) == 0 {
+				continue
+			}
+		}
+		if slices.Contains(path, "") {
+			continue
+		}
+
+		setPath(result, path, leaf)
+	}
+	return result
+}
+
+// setPath stores leaf at path inside tree, creating intermediate maps as needed.
+// Intermediate leaves are replaced by maps; existing maps are never replaced by leaves.
+func setPath(tree map[string]any, path []string, leaf any) {
+	node := tree
+	for _, segment := range path[:len(path)-1] {
+		child, ok := node[segment].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			node[segment] = child
+		}
+		node = child
+	}
+
+	last := path[len(path)-1]
+	if _, isMap := node[last].(map[string]any); isMap {
+		return
+	}
+	node[last] = leaf
+}
+
+// splitBracketKey splits a key such as "filter[price][min]" into its base name
+// and bracketed path ("filter", ["price", "min"]). A trailing "[]" yields an
+// empty final segment. Keys without brackets return an empty path. ok is false
+// for malformed keys (unbalanced brackets or text between segments) and for
+// keys deeper than maxNestingDepth.
+func splitBracketKey(key string) (base string, path []string, ok bool) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 {
+		if strings.Contains(key, "]") {
+			return "", nil, false
+		}
+		return key, nil, true
+	}
+	if open == 0 {
+		return "", nil, false
+	}
+
+	base, rest := key[:open], key[open:]
+	for rest != "" {
+		if rest[0] != '[' {
+			return "", nil, false
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return "", nil, false
+		}
+		segment := rest[1:end]
+		if strings.Contains(segment, "[") {
+			return "", nil, false
+		}
+		path = append(path, segment)
+		if len(path) > maxNestingDepth {
+			return "", nil, false
+		}
+		rest = rest[end+1:]
+	}
+	return base, path, true
+}
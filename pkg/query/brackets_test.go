@@ -0,0 +1,144 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected map[string]string
+	}{
+		{
+			"single level",
+			"/?filter[status]=active&filter[type]=post&page=2",
+			map[string]string{"status": "active", "type": "post"},
+		},
+		{
+			"encoded brackets",
+			"/?filter%5Bstatus%5D=active",
+			map[string]string{"status": "active"},
+		},
+		{
+			"first value wins",
+			"/?filter[status]=active&filter[status]=archived",
+			map[string]string{"status": "active"},
+		},
+		{
+			"nested and array keys ignored",
+			"/?filter[price][min]=10&filter[tag][]=a&filter[]=x&filter[status]=active",
+			map[string]string{"status": "active"},
+		},
+		{
+			"other prefixes ignored",
+			"/?filters[status]=active&xfilter[a]=1&filter=plain",
+			map[string]string{},
+		},
+		{
+			"missing",
+			"/?page=1",
+			map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Map(r, "filter")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Map() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMapOf(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?qty[apples]=3&qty[pears]=x&qty[kiwis]=-1", nil)
+	got := MapOf(r, "qty", 0, strconv.Atoi)
+	expected := map[string]int{"apples": 3, "pears": 0, "kiwis": -1}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("MapOf() = %v, want %v", got, expected)
+	}
+}
+
+func TestNested(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected map[string]any
+	}{
+		{
+			"nested levels",
+			"/?filter[price][min]=10&filter[price][max]=50&filter[status]=active",
+			map[string]any{
+				"price":  map[string]any{"min": "10", "max": "50"},
+				"status": "active",
+			},
+		},
+		{
+			"array leaves",
+			"/?filter[tag][]=a&filter[tag][]=b",
+			map[string]any{"tag": []string{"a", "b"}},
+		},
+		{
+			"nested form wins over leaf",
+			"/?filter[a]=1&filter[a][b]=2",
+			map[string]any{"a": map[string]any{"b": "2"}},
+		},
+		{
+			"malformed keys ignored",
+			"/?filter[a=1&filter[b]x[c]=2&filter[][d]=3&filter[ok]=4",
+			map[string]any{"ok": "4"},
+		},
+		{
+			"missing",
+			"/?page=1",
+			map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Nested(r, "filter")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Nested() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitBracketKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		base     string
+		path     []string
+		expected bool
+	}{
+		{"plain", "plain", nil, true},
+		{"filter[status]", "filter", []string{"status"}, true},
+		{"filter[price][min]", "filter", []string{"price", "min"}, true},
+		{"ids[]", "ids", []string{""}, true},
+		{"[status]", "", nil, false},
+		{"filter[status", "", nil, false},
+		{"filter]", "", nil, false},
+		{"filter[a]b", "", nil, false},
+		{"filter[a[b]]", "", nil, false},
+		{"deep" + strings.Repeat("[x]", maxNestingDepth+1), "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			base, path, ok := splitBracketKey(tt.key)
+			if base != tt.base || !reflect.DeepEqual(path, tt.path) || ok != tt.expected {
+				t.Errorf("splitBracketKey(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.key, base, path, ok, tt.base, tt.path, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package query
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Builder constructs query strings with the same type safety the extractors
+// provide when reading them. Setters return the Builder so calls can be chained.
+// The zero value is ready to use.
+//
+// Example:
+//
+//	// Link to the next page while preserving the current filters
+//	next := *r.URL
+//	query.BuilderFrom(r.URL.Query()).
+//	    SetInt("page", page+1).
+//	    Apply(&next)
+type Builder struct {
+	values url.Values
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{values: url.Values{}}
+}
+
+// BuilderFrom returns a Builder initialized with a copy of values.
+func BuilderFrom(values url.Values) *Builder {
+	b := NewBuilder()
+	for k, v := range values {
+		b.values[k] = append([]string(nil), v...)
+	}
+	return b
+}
+
+// Set sets key to the string value, replacing any existing values.
+func (b *Builder) Set(key, value string) *Builder {
+	b.init()
+	b.values.Set(key, value)
+	return b
+}
+
+// Add appends a string value to key.
+func (b *Builder) Add(key, value string) *Builder {
+	b.init()
+	b.values.Add(key, value)
+	return b
+}
+
+// SetValue sets key to value formatted with the formatter registered for its
+// type with RegisterType, its MarshalText method, or fmt.Sprint, in that order.
+func (b *Builder) SetValue(key string, value any) *Builder {
+	return b.Set(key, formatValue(value))
+}
+
+// SetInt sets key to the decimal form of value.
+func (b *Builder) SetInt(key string, value int) *Builder {
+	return b.Set(key, strconv.Itoa(value))
+}
+
+// SetInt64 sets key to the decimal form of value.
+func (b *Builder) SetInt64(key string, value int64) *Builder {
+	return b.Set(key, strconv.FormatInt(value, 10))
+}
+
+// SetFloat64 sets key to the shortest decimal form that parses back to value.
+func (b *Builder) SetFloat64(key string, value float64) *Builder {
+	return b.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// SetBool sets key to "true" or "false".
+func (b *Builder) SetBool(key string, value bool) *Builder {
+	return b.Set(key, strconv.FormatBool(value))
+}
+
+// SetTime sets key to value formatted with layout, such as time.RFC3339 or
+// time.DateOnly.
+func (b *Builder) SetTime(key string, value time.Time, layout string) *Builder {
+	return b.Set(key, value.Format(layout))
+}
+
+// SetStrings sets key to the given values, replacing any existing values.
+// Passing no values removes the key.
+func (b *Builder) SetStrings(key string, values ...string) *Builder {
+	b.init()
+	if len(values) == 0 {
+		b.values.Del(key)
+		return b
+	}
+	b.values[key] = append([]string(nil), values...)
+	return b
+}
+
+// SetInts sets key to the decimal form of each value, replacing any existing values.
+// Passing no values removes the key.
+func (b *Builder) SetInts(key string, values ...int) *Builder {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = strconv.Itoa(v)
+	}
+	return b.SetStrings(key, strs...)
+}
+
+// Del removes key.
+func (b *Builder) Del(key string) *Builder {
+	b.init()
+	b.values.Del(key)
+	return b
+}
+
+// Values returns a copy of the values built so far.
+func (b *Builder) Values() url.Values {
+	return BuilderFrom(b.values).values
+}
+
+// Encode returns the values in URL-encoded form ("a=1&b=2"), sorted by key.
+func (b *Builder) Encode() string {
+	return b.values.Encode()
+}
+
+// Apply replaces the query string of u with the built values. Use BuilderFrom
+// with the URL's current query to modify parameters while keeping the rest.
+func (b *Builder) Apply(u *url.URL) {
+	u.RawQuery = b.Encode()
+}
+
+func (b *Builder) init() {
+	if b.values == nil {
+		b.values = url.Values{}
+	}
+}
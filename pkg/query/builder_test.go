@@ -0,0 +1,80 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuilderEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(b *Builder)
+		expected string
+	}{
+		{"string", func(b *Builder) { b.Set("q", "hello world") }, "q=hello+world"},
+		{"int", func(b *Builder) { b.SetInt("page", 2) }, "page=2"},
+		{"int64", func(b *Builder) { b.SetInt64("id", 9223372036854775807) }, "id=9223372036854775807"},
+		{"float64", func(b *Builder) { b.SetFloat64("price", 19.99) }, "price=19.99"},
+		{"float64 no exponent", func(b *Builder) { b.SetFloat64("n", 1e21) }, "n=1000000000000000000000"},
+		{"bool", func(b *Builder) { b.SetBool("active", true) }, "active=true"},
+		{
+			"time",
+			func(b *Builder) { b.SetTime("since", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), time.RFC3339) },
+			"since=2024-01-15T10%3A30%3A00Z",
+		},
+		{"strings", func(b *Builder) { b.SetStrings("tag", "go", "rust") }, "tag=go&tag=rust"},
+		{"ints", func(b *Builder) { b.SetInts("id", 3, 1) }, "id=3&id=1"},
+		{"add", func(b *Builder) { b.Add("tag", "go").Add("tag", "rust") }, "tag=go&tag=rust"},
+		{"set replaces", func(b *Builder) { b.SetStrings("tag", "go", "rust").Set("tag", "c") }, "tag=c"},
+		{"empty strings removes", func(b *Builder) { b.Set("tag", "go").SetStrings("tag") }, ""},
+		{"del", func(b *Builder) { b.SetInt("page", 1).SetInt("limit", 5).Del("page") }, "limit=5"},
+		{"sorted keys", func(b *Builder) { b.SetInt("z", 1).SetInt("a", 2) }, "a=2&z=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder()
+			tt.build(b)
+			if got := b.Encode(); got != tt.expected {
+				t.Errorf("Encode() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuilderZeroValue(t *testing.T) {
+	var b Builder
+	if got := b.Encode(); got != "" {
+		t.Errorf("zero Builder Encode() = %q, want empty", got)
+	}
+	if got := b.SetInt("page", 1).Encode(); got != "page=1" {
+		t.Errorf("zero Builder SetInt().Encode() = %q, want %q", got, "page=1")
+	}
+}
+
+func TestBuilderFromAndApply(t *testing.T) {
+	u, _ := url.Parse("https://example.com/posts?page=1&tag=go&tag=rust#top")
+	original := u.Query()
+
+	BuilderFrom(u.Query()).SetInt("page", 2).Apply(u)
+
+	if got := u.String(); got != "https://example.com/posts?page=2&tag=go&tag=rust#top" {
+		t.Errorf("Apply() URL = %q", got)
+	}
+	if original.Get("page") != "1" {
+		t.Error("BuilderFrom() modified the source values")
+	}
+}
+
+func TestBuilderValuesIsCopy(t *testing.T) {
+	b := NewBuilder().SetStrings("tag", "go")
+	values := b.Values()
+	values["tag"][0] = "changed"
+	values.Set("extra", "1")
+
+	if !reflect.DeepEqual(b.Values(), url.Values{"tag": {"go"}}) {
+		t.Errorf("Values() returned a shared map: builder now has %v", b.Values())
+	}
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps lower-cased unit suffixes to their multiplier.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+}
+
+// ByteSize extracts a human-friendly byte size such as ?max=10MB, returning the
+// number of bytes. Units are case-insensitive: SI units (k, kB, M, MB, G, GB,
+// T, TB, P, PB) are powers of 1000 and IEC units (Ki, KiB, Mi, MiB, Gi, GiB,
+// Ti, TiB, Pi, PiB) are powers of 1024. A bare number or a "B" suffix means
+// bytes, and fractional values such as 1.5GB are allowed.
+// Returns defaultValue if the key is missing, empty, negative, has an unknown
+// unit, or overflows int64.
+//
+// Example:
+//
+//	// URL: /uploads?max=512KiB
+//	limit := query.ByteSize(r, "max", 10<<20)  // 524288
+func ByteSize(r *http.Request, key string, defaultValue int64) int64 {
+	return Value(r, key, defaultValue, parseByteSize)
+}
+
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, func(c rune) bool {
+		return (c < '0' || c > '9') && c != '.'
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	number, unit := s[:end], strings.TrimSpace(s[end:])
+
+	multiplier, ok := byteUnits[strings.ToLower(unit)]
+	if !ok || number == "" {
+		return 0, strconv.ErrSyntax
+	}
+
+	// Integers are converted exactly; fractions go through float64.
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if m := int64(multiplier); n > math.MaxInt64/m {
+			return 0, strconv.ErrRange
+		}
+		return n * int64(multiplier), nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	bytes := f * multiplier
+	if bytes >= math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+	return int64(bytes), nil
+}
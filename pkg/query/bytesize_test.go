@@ -0,0 +1,44 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected int64
+	}{
+		{"bytes", "512", 512},
+		{"bytes suffix", "512B", 512},
+		{"si kilo", "512k", 512_000},
+		{"si megabytes", "10MB", 10_000_000},
+		{"si lower case", "10mb", 10_000_000},
+		{"iec gibibyte", "1GiB", 1 << 30},
+		{"iec short", "4Mi", 4 << 20},
+		{"fraction", "1.5GB", 1_500_000_000},
+		{"fraction iec", "0.5KiB", 512},
+		{"space before unit", "2 KB", 2000},
+		{"max int64", "9223372036854775807", 9223372036854775807},
+		{"overflow", "10000PB", -1},
+		{"overflow fraction", "9000.5PiB", -1},
+		{"unknown unit", "10XB", -1},
+		{"negative", "-5MB", -1},
+		{"no number", "MB", -1},
+		{"double dot", "1.2.3KB", -1},
+		{"empty", "", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?max="+url.QueryEscape(tt.value), nil)
+			got := ByteSize(r, "max", -1)
+			if got != tt.expected {
+				t.Errorf("ByteSize(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CanonicalOption configures the normalization performed by Canonical.
+type CanonicalOption func(*canonicalConfig)
+
+type canonicalConfig struct {
+	dropKeys     []string
+	dropPrefixes []string
+	dropEmpty    bool
+	sortValues   bool
+}
+
+// WithoutKeys omits the given parameters from the canonical form, for example
+// a signature or a cache-busting timestamp. Keys match exactly.
+func WithoutKeys(keys ...string) CanonicalOption {
+	return func(c *canonicalConfig) {
+		c.dropKeys = append(c.dropKeys, keys...)
+	}
+}
+
+// WithoutPrefixes omits every parameter whose name starts with one of the given
+// prefixes, such as "utm_" tracking parameters that do not affect the response.
+func WithoutPrefixes(prefixes ...string) CanonicalOption {
+	return func(c *canonicalConfig) {
+		c.dropPrefixes = append(c.dropPrefixes, prefixes...)
+	}
+}
+
+// DropEmpty omits empty values, so "?q=&page=2" and "?page=2" share a
+// canonical form. A key whose values are all empty is omitted entirely.
+func DropEmpty() CanonicalOption {
+	return func(c *canonicalConfig) {
+		c.dropEmpty = true
+	}
+}
+
+// SortValues orders the values of a repeated parameter, so "?tag=b&tag=a" and
+// "?tag=a&tag=b" share a canonical form. Only use it where the order of values
+// carries no meaning.
+func SortValues() CanonicalOption {
+	return func(c *canonicalConfig) {
+		c.sortValues = true
+	}
+}
+
+// Canonical returns the request's query string in canonical form: parameters
+// sorted by key, values re-encoded consistently, and the order of repeated
+// values preserved unless SortValues is given. Equivalent query strings such as
+// "?b=2&a=1" and "?a=%31&b=2" produce the same result, which makes it suitable
+// for cache keys, request signing and deduplication. Pairs that cannot be
+// decoded are dropped, as with r.URL.Query().
+//
+// Example:
+//
+//	// URL: /search?q=go+lang&utm_source=mail&page=2&sig=abc
+//	key := query.Canonical(r, query.WithoutKeys("sig"), query.WithoutPrefixes("utm_"))
+//	// key = "page=2&q=go+lang"
+func Canonical(r *http.Request, opts ...CanonicalOption) string {
+	return Canonicalize(r.URL.Query(), opts...)
+}
+
+// Canonicalize is Canonical for an existing set of values. values is not
+// modified.
+func Canonicalize(values url.Values, opts ...CanonicalOption) string {
+	var cfg canonicalConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if !cfg.dropped(k) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		vals := values[k]
+		if cfg.sortValues {
+			vals = slices.Sorted(slices.Values(vals))
+		}
+
+		escapedKey := url.QueryEscape(k)
+		for _, v := range vals {
+			if cfg.dropEmpty && v == "" {
+				continue
+			}
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(escapedKey)
+			sb.WriteByte('=')
+			sb.WriteString(url.QueryEscape(v))
+		}
+	}
+	return sb.String()
+}
+
+func (c *canonicalConfig) dropped(key string) bool {
+	if slices.Contains(c.dropKeys, key) {
+		return true
+	}
+	for _, prefix := range c.dropPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		opts     []CanonicalOption
+		expected string
+	}{
+		{"empty", "/", nil, ""},
+		{"sorted keys", "/?b=2&a=1&c=3", nil, "a=1&b=2&c=3"},
+		{"re-encoded", "/?q=go%20lang&a=%31&path=%2Fx", nil, "a=1&path=%2Fx&q=go+lang"},
+		{"value order preserved", "/?tag=b&tag=a", nil, "tag=b&tag=a"},
+		{"sorted values", "/?tag=b&tag=a&id=2", []CanonicalOption{SortValues()}, "id=2&tag=a&tag=b"},
+		{"empty values kept", "/?q=&page=2", nil, "page=2&q="},
+		{"empty values dropped", "/?q=&page=2&tag=&tag=go", []CanonicalOption{DropEmpty()}, "page=2&tag=go"},
+		{"without keys", "/?sig=abc&page=2&ts=1", []CanonicalOption{WithoutKeys("sig", "ts")}, "page=2"},
+		{"without prefixes", "/?utm_source=mail&utm_medium=x&page=2", []CanonicalOption{WithoutPrefixes("utm_")}, "page=2"},
+		{"bracket keys", "/?id[]=2&id=1", nil, "id=1&id%5B%5D=2"},
+		{"undecodable pair dropped", "/?a=%zz&b=1", nil, "b=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := Canonical(r, tt.opts...); got != tt.expected {
+				t.Errorf("Canonical() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeEquivalence(t *testing.T) {
+	a, _ := url.ParseQuery("b=2&a=hello+world&b=3")
+	b, _ := url.ParseQuery("a=hello%20world&b=2&b=3")
+	if Canonicalize(a) != Canonicalize(b) {
+		t.Errorf("Canonicalize() = %q and %q, want equal", Canonicalize(a), Canonicalize(b))
+	}
+	if got, want := Canonicalize(a), a.Encode(); got != want {
+		t.Errorf("Canonicalize() = %q, want %q to match url.Values.Encode", got, want)
+	}
+
+	_ = Canonicalize(a, SortValues(), WithoutKeys("a"))
+	if len(a["a"]) != 1 {
+		t.Error("Canonicalize() modified its input")
+	}
+}
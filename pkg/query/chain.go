@@ -0,0 +1,108 @@
+package query
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// SourceChain resolves a value from an ordered list of request locations:
+// query parameters, headers and cookies. The first location holding a
+// non-empty value wins. Create one with Chain.
+type SourceChain struct {
+	r       *http.Request
+	sources []func(*http.Request) string
+}
+
+// Chain starts a fallback chain for values that may arrive in several places,
+// such as API tokens accepted as a query parameter, header or cookie.
+//
+// Example:
+//
+//	token := query.Chain(r).
+//	    Query("token").
+//	    Header("X-Api-Token").
+//	    Cookie("token").
+//	    String("")
+func Chain(r *http.Request) *SourceChain {
+	return &SourceChain{r: r}
+}
+
+// Query adds the query parameter key to the chain.
+func (c *SourceChain) Query(key string) *SourceChain {
+	c.sources = append(c.sources, func(r *http.Request) string {
+		return String(r, key, "")
+	})
+	return c
+}
+
+// Header adds the request header name to the chain.
+func (c *SourceChain) Header(name string) *SourceChain {
+	c.sources = append(c.sources, func(r *http.Request) string {
+		return r.Header.Get(name)
+	})
+	return c
+}
+
+// Cookie adds the cookie name to the chain.
+func (c *SourceChain) Cookie(name string) *SourceChain {
+	c.sources = append(c.sources, func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	})
+	return c
+}
+
+// Lookup returns the first non-empty value in the chain, and whether one was found.
+func (c *SourceChain) Lookup() (string, bool) {
+	for _, source := range c.sources {
+		if val := source(c.r); val != "" {
+			return val, true
+		}
+	}
+	return "", false
+}
+
+// String returns the first non-empty value in the chain, or defaultValue if
+// every location is missing or empty.
+func (c *SourceChain) String(defaultValue string) string {
+	if val, ok := c.Lookup(); ok {
+		return val
+	}
+	return defaultValue
+}
+
+// Int returns the first non-empty value in the chain parsed as an int.
+// Returns defaultValue if every location is empty or the winning value cannot
+// be parsed; later locations are not consulted after a parse failure.
+func (c *SourceChain) Int(defaultValue int) int {
+	return ChainValue(c, defaultValue, strconv.Atoi)
+}
+
+// Bool returns the first non-empty value in the chain parsed with the same
+// flexible rules as Bool. Returns defaultValue if every location is empty or
+// the winning value is not a recognized boolean.
+func (c *SourceChain) Bool(defaultValue bool) bool {
+	return ChainValue(c, defaultValue, parseBool)
+}
+
+// ChainValue returns the first non-empty value in the chain converted with
+// parser, or defaultValue if every location is empty or the parser fails.
+//
+// Example:
+//
+//	tenant := query.ChainValue(query.Chain(r).Query("tenant").Header("X-Tenant-ID"), 0, strconv.Atoi)
+func ChainValue[T any](c *SourceChain, defaultValue T, parser Parser[T]) T {
+	val, ok := c.Lookup()
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := parser(val)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
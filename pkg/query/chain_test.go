@@ -0,0 +1,76 @@
+package query
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		header   string
+		cookie   string
+		expected string
+	}{
+		{"query wins", "/?token=q", "h", "c", "q"},
+		{"header fallback", "/", "h", "c", "h"},
+		{"empty query skipped", "/?token=", "h", "c", "h"},
+		{"cookie fallback", "/", "", "c", "c"},
+		{"nothing", "/", "", "", "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.header != "" {
+				r.Header.Set("X-Api-Token", tt.header)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "token", Value: tt.cookie})
+			}
+
+			got := Chain(r).Query("token").Header("X-Api-Token").Cookie("token").String("default")
+			if got != tt.expected {
+				t.Errorf("Chain().String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?token=q", nil)
+	r.Header.Set("X-Api-Token", "h")
+
+	if got := Chain(r).Header("X-Api-Token").Query("token").String(""); got != "h" {
+		t.Errorf("Chain().String() = %q, want sources consulted in declaration order", got)
+	}
+	if _, ok := Chain(r).Lookup(); ok {
+		t.Error("empty Chain().Lookup() ok = true, want false")
+	}
+}
+
+func TestChainTyped(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?page=abc", nil)
+	r.Header.Set("X-Page", "3")
+	r.Header.Set("X-Debug", "yes")
+	r.AddCookie(&http.Cookie{Name: "tenant", Value: "42"})
+
+	if got := Chain(r).Header("X-Page").Int(1); got != 3 {
+		t.Errorf("Chain().Int() = %v, want 3", got)
+	}
+	if got := Chain(r).Query("page").Header("X-Page").Int(1); got != 1 {
+		t.Errorf("Chain().Int() = %v, want default when the winning value is invalid", got)
+	}
+	if got := Chain(r).Query("debug").Header("X-Debug").Bool(false); !got {
+		t.Errorf("Chain().Bool() = %v, want true", got)
+	}
+	if got := ChainValue(Chain(r).Cookie("tenant"), int64(0), parseInt64); got != 42 {
+		t.Errorf("ChainValue() = %v, want 42", got)
+	}
+	if got := ChainValue(Chain(r).Cookie("missing"), 7, strconv.Atoi); got != 7 {
+		t.Errorf("ChainValue() = %v, want default", got)
+	}
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor is malformed or its signature
+	// does not match, which usually means it was tampered with or signed with a
+	// different secret.
+	ErrInvalidCursor = errors.New("query: invalid cursor")
+
+	// ErrEmptySecret is returned when a cursor is encoded or decoded without a secret.
+	ErrEmptySecret = errors.New("query: secret must not be empty")
+)
+
+// Cursor is an opaque, signed pagination token produced by EncodeCursor.
+// It is safe to place directly in a URL: the payload and its HMAC-SHA256
+// signature are both base64url-encoded without padding.
+type Cursor string
+
+// String returns the cursor as it should appear in a query string.
+func (c Cursor) String() string {
+	return string(c)
+}
+
+// EncodeCursor serializes data (typically a map or struct holding the keyset
+// position) as JSON and signs it with secret. The payload is not encrypted:
+// clients can read it but cannot alter it without invalidating the signature.
+//
+// Example:
+//
+//	next, err := query.EncodeCursor(PageKey{CreatedAt: last.CreatedAt, ID: last.ID}, secret)
+//	link := "/posts?cursor=" + next.String()
+func EncodeCursor(data any, secret []byte) (Cursor, error) {
+	if len(secret) == 0 {
+		return "", ErrEmptySecret
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return Cursor(encoded + "." + signCursor(encoded, secret)), nil
+}
+
+// DecodeCursor verifies the cursor in the query parameter with the given key
+// and decodes its payload into dst, which must be a non-nil pointer.
+// dst is only modified when decoding succeeds.
+//
+// Returns nil without touching dst if the key is missing or empty, which
+// conventionally means "start from the first page". Returns ErrInvalidCursor if
+// the cursor is malformed or its signature does not match.
+//
+// Example:
+//
+//	var pos PageKey
+//	if err := query.DecodeCursor(r, "cursor", secret, &pos); err != nil {
+//	    http.Error(w, "invalid cursor", http.StatusBadRequest)
+//	    return
+//	}
+func DecodeCursor(r *http.Request, key string, secret []byte, dst any) error {
+	val := first(r.URL.Query(), key)
+	if val == "" {
+		target := reflect.ValueOf(dst)
+		if target.Kind() != reflect.Pointer || target.IsNil() {
+			return ErrInvalidJSONTarget
+		}
+		return nil
+	}
+	return Cursor(val).Decode(secret, dst)
+}
+
+// Decode verifies the cursor's signature and decodes its payload into dst,
+// which must be a non-nil pointer. dst is only modified when decoding succeeds.
+func (c Cursor) Decode(secret []byte, dst any) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return ErrInvalidJSONTarget
+	}
+	if len(secret) == 0 {
+		return ErrEmptySecret
+	}
+
+	encoded, signature, ok := strings.Cut(string(c), ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+	if !hmac.Equal([]byte(signature), []byte(signCursor(encoded, secret))) {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	decoded := reflect.New(target.Elem().Type())
+	if err := json.Unmarshal(payload, decoded.Interface()); err != nil {
+		return err
+	}
+	target.Elem().Set(decoded.Elem())
+	return nil
+}
+
+// signCursor returns the base64url-encoded HMAC-SHA256 of the encoded payload.
+func signCursor(encoded string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	type position struct {
+		CreatedAt string `json:"created_at"`
+		ID        int    `json:"id"`
+	}
+	secret := []byte("s3cret")
+
+	cursor, err := EncodeCursor(position{CreatedAt: "2024-01-15T10:00:00Z", ID: 42}, secret)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	if strings.ContainsAny(cursor.String(), "+/= ") {
+		t.Errorf("EncodeCursor() = %q, want URL-safe token", cursor)
+	}
+
+	r := httptest.NewRequest("GET", "/?cursor="+cursor.String(), nil)
+	var got position
+	if err := DecodeCursor(r, "cursor", secret, &got); err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if got.ID != 42 || got.CreatedAt != "2024-01-15T10:00:00Z" {
+		t.Errorf("DecodeCursor() = %+v, want ID 42", got)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	secret := []byte("s3cret")
+	valid, _ := EncodeCursor(map[string]int{"id": 7}, secret)
+	payload, signature, _ := strings.Cut(valid.String(), ".")
+	forged, _ := EncodeCursor(map[string]int{"id": 8}, []byte("other"))
+	forgedPayload, _, _ := strings.Cut(forged.String(), ".")
+
+	tests := []struct {
+		name     string
+		url      string
+		secret   []byte
+		expected int
+		wantErr  error
+	}{
+		{"valid", "/?cursor=" + valid.String(), secret, 7, nil},
+		{"missing key", "/", secret, -1, nil},
+		{"wrong secret", "/?cursor=" + valid.String(), []byte("other"), -1, ErrInvalidCursor},
+		{"tampered payload", "/?cursor=" + forgedPayload + "." + signature, secret, -1, ErrInvalidCursor},
+		{"no signature", "/?cursor=" + payload, secret, -1, ErrInvalidCursor},
+		{"garbage", "/?cursor=%21%21.%21%21", secret, -1, ErrInvalidCursor},
+		{"empty secret", "/?cursor=" + valid.String(), nil, -1, ErrEmptySecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := map[string]int{"id": -1}
+			err := DecodeCursor(r, "cursor", tt.secret, &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeCursor() error = %v, want %v", err, tt.wantErr)
+			}
+			if got["id"] != tt.expected {
+				t.Errorf("DecodeCursor() id = %d, want %d", got["id"], tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeCursorErrors(t *testing.T) {
+	if _, err := EncodeCursor(map[string]int{"id": 1}, nil); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("EncodeCursor(nil secret) error = %v, want ErrEmptySecret", err)
+	}
+	if _, err := EncodeCursor(make(chan int), []byte("k")); err == nil {
+		t.Error("EncodeCursor(chan) error = nil, want marshal error")
+	}
+	if err := Cursor("a.b").Decode([]byte("k"), map[string]int{}); !errors.Is(err, ErrInvalidJSONTarget) {
+		t.Errorf("Decode(non-pointer) error = %v, want ErrInvalidJSONTarget", err)
+	}
+}
@@ -0,0 +1,182 @@
+package query
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxNumberDigits bounds the digits BigInt and DecimalOf accept, since parsing
+// arbitrary-precision numbers takes time superlinear in their length.
+const maxNumberDigits = 1000
+
+// ErrDecimalScale is reported when a decimal has more fractional digits than
+// the caller allows.
+var ErrDecimalScale = errors.New("too many decimal places")
+
+// Decimal is an exact decimal number such as a money amount, kept in its
+// canonical string form so that no precision is lost to float rounding. The
+// scale, the number of digits after the decimal point, is preserved as sent:
+// "12.50" has scale 2. The zero value is 0.
+type Decimal struct {
+	s string // canonical form, "" for the zero value
+}
+
+// ParseDecimal parses a plain decimal number: an optional minus sign, at least
+// one digit, and optionally a point followed by at least one digit. Exponents,
+// a leading "+", and forms such as ".5" or "5." are rejected. Redundant leading
+// zeros are dropped, so "007.50" becomes "7.50", and "-0" becomes "0".
+func ParseDecimal(s string) (Decimal, error) {
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	intPart, frac, hasPoint := strings.Cut(digits, ".")
+
+	if intPart == "" || !isDigits(intPart) || (hasPoint && (frac == "" || !isDigits(frac))) {
+		return Decimal{}, strconv.ErrSyntax
+	}
+	if len(intPart)+len(frac) > maxNumberDigits {
+		return Decimal{}, strconv.ErrSyntax
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	canonical := intPart
+	if hasPoint {
+		canonical += "." + frac
+	}
+	if neg && strings.Trim(canonical, "0.") != "" {
+		canonical = "-" + canonical
+	}
+	if canonical == "0" {
+		canonical = ""
+	}
+	return Decimal{s: canonical}, nil
+}
+
+// MustDecimal is like ParseDecimal but panics if s is invalid. It is meant for
+// defaults written as constants, such as query.MustDecimal("0.00").
+func MustDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic("query: MustDecimal(" + s + "): " + err.Error())
+	}
+	return d
+}
+
+// String returns the canonical form of d, such as "-12.50".
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int {
+	_, frac, _ := strings.Cut(d.s, ".")
+	return len(frac)
+}
+
+// Sign returns -1, 0 or +1 depending on whether d is negative, zero or
+// positive.
+func (d Decimal) Sign() int {
+	switch {
+	case strings.HasPrefix(d.s, "-"):
+		return -1
+	case strings.Trim(d.s, "0.") == "":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Unscaled returns d as an integer multiplied by 10^Scale, such as 1250 for
+// "12.50", for storing amounts in minor units.
+func (d Decimal) Unscaled() *big.Int {
+	n, _ := new(big.Int).SetString(strings.Replace(d.String(), ".", "", 1), 10)
+	return n
+}
+
+// Rat returns d as an exact rational number.
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseDecimal, so
+// Decimal fields can be used with Bind.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// DecimalOf extracts an exact decimal value from the query parameter with the
+// given key, such as a money amount. Values with more than maxScale digits
+// after the decimal point are rejected rather than rounded; a negative
+// maxScale allows any scale. Returns defaultValue if the key is missing,
+// empty, or not a valid decimal. See ParseDecimal for the accepted syntax.
+//
+// Example:
+//
+//	// URL: /payments?amount=19.99
+//	amount := query.DecimalOf(r, "amount", query.Decimal{}, 2)
+//	cents := amount.Unscaled()  // 1999
+func DecimalOf(r *http.Request, key string, defaultValue Decimal, maxScale int) Decimal {
+	return std.DecimalOf(r, key, defaultValue, maxScale)
+}
+
+// BigInt extracts an arbitrary-precision base-10 integer from the query
+// parameter with the given key. Values of more than 1000 digits are rejected.
+// Returns defaultValue if the key is missing, empty, or not an integer; the
+// default is returned as is, not copied.
+//
+// Example:
+//
+//	// URL: /blocks?from=18446744073709551617
+//	from := query.BigInt(r, "from", big.NewInt(0))
+func BigInt(r *http.Request, key string, defaultValue *big.Int) *big.Int {
+	return std.BigInt(r, key, defaultValue)
+}
+
+// DecimalOf extracts an exact decimal value, applying the Extractor's
+// DigitSeparators and DecimalComma settings. See the package-level DecimalOf.
+func (e *Extractor) DecimalOf(r *http.Request, key string, defaultValue Decimal, maxScale int) Decimal {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, func(s string) (Decimal, error) {
+		d, err := ParseDecimal(s)
+		if err == nil && maxScale >= 0 && d.Scale() > maxScale {
+			return Decimal{}, ErrDecimalScale
+		}
+		return d, err
+	}))
+}
+
+// BigInt extracts an arbitrary-precision integer, applying the Extractor's
+// DigitSeparators setting. See the package-level BigInt.
+func (e *Extractor) BigInt(r *http.Request, key string, defaultValue *big.Int) *big.Int {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, parseBigInt))
+}
+
+// parseBigInt parses a base-10 integer of at most maxNumberDigits digits.
+func parseBigInt(s string) (*big.Int, error) {
+	if len(strings.TrimLeft(s, "+-")) > maxNumberDigits {
+		return nil, strconv.ErrSyntax
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, strconv.ErrSyntax
+	}
+	return n, nil
+}
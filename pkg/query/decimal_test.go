@@ -0,0 +1,159 @@
+package query
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		scale     int
+		sign      int
+		wantError bool
+	}{
+		{"12.50", "12.50", 2, 1, false},
+		{"-0.001", "-0.001", 3, -1, false},
+		{"007.50", "7.50", 2, 1, false},
+		{"42", "42", 0, 1, false},
+		{"0", "0", 0, 0, false},
+		{"-0", "0", 0, 0, false},
+		{"-0.00", "0.00", 2, 0, false},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789", 9, 1, false},
+		{"", "", 0, 0, true},
+		{".5", "", 0, 0, true},
+		{"5.", "", 0, 0, true},
+		{"+5", "", 0, 0, true},
+		{"1e3", "", 0, 0, true},
+		{"1.2.3", "", 0, 0, true},
+		{"NaN", "", 0, 0, true},
+		{"--1", "", 0, 0, true},
+		{strings.Repeat("9", 1001), "", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDecimal(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseDecimal() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if got.String() != tt.expected || got.Scale() != tt.scale || got.Sign() != tt.sign {
+				t.Errorf("ParseDecimal() = %s (scale %d, sign %d), want %s (scale %d, sign %d)",
+					got, got.Scale(), got.Sign(), tt.expected, tt.scale, tt.sign)
+			}
+		})
+	}
+}
+
+func TestDecimalConversions(t *testing.T) {
+	d := MustDecimal("-12.50")
+	if got := d.Unscaled(); got.Cmp(big.NewInt(-1250)) != 0 {
+		t.Errorf("Decimal.Unscaled() = %v, want -1250", got)
+	}
+	if got := d.Rat(); got.Cmp(big.NewRat(-25, 2)) != 0 {
+		t.Errorf("Decimal.Rat() = %v, want -25/2", got)
+	}
+	if text, _ := d.MarshalText(); string(text) != "-12.50" {
+		t.Errorf("Decimal.MarshalText() = %q, want %q", text, "-12.50")
+	}
+
+	var zero Decimal
+	if zero != MustDecimal("0") || zero.String() != "0" || zero.Unscaled().Sign() != 0 {
+		t.Errorf("zero Decimal = %s, want 0", zero)
+	}
+}
+
+func TestDecimalOf(t *testing.T) {
+	def := MustDecimal("1.00")
+
+	tests := []struct {
+		name     string
+		url      string
+		maxScale int
+		expected string
+	}{
+		{"valid", "/?amount=19.99", 2, "19.99"},
+		{"fewer places", "/?amount=5", 2, "5"},
+		{"too many places", "/?amount=19.999", 2, "1.00"},
+		{"any scale", "/?amount=19.999", -1, "19.999"},
+		{"invalid", "/?amount=abc", 2, "1.00"},
+		{"missing", "/", 2, "1.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := DecimalOf(r, "amount", def, tt.maxScale); got.String() != tt.expected {
+				t.Errorf("DecimalOf() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractorDecimalOf(t *testing.T) {
+	var reported error
+	e := NewExtractor(Config{
+		DigitSeparators: ".",
+		DecimalComma:    true,
+		OnParseError:    func(_, _, _ string, err error) { reported = err },
+	})
+
+	r := httptest.NewRequest("GET", "/?amount=1.234,50&fine=0,125", nil)
+	if got := e.DecimalOf(r, "amount", Decimal{}, 2); got.String() != "1234.50" {
+		t.Errorf("Extractor.DecimalOf() = %s, want 1234.50", got)
+	}
+	if got := e.DecimalOf(r, "fine", Decimal{}, 2); got != (Decimal{}) {
+		t.Errorf("Extractor.DecimalOf() = %s, want default", got)
+	}
+	if !errors.Is(reported, ErrDecimalScale) {
+		t.Errorf("reported error = %v, want ErrDecimalScale", reported)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	def := big.NewInt(-1)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"beyond uint64", "/?n=18446744073709551617", "18446744073709551617"},
+		{"negative", "/?n=-42", "-42"},
+		{"hex rejected", "/?n=0x10", "-1"},
+		{"decimal rejected", "/?n=1.5", "-1"},
+		{"too long", "/?n=" + strings.Repeat("9", 1001), "-1"},
+		{"missing", "/", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := BigInt(r, "n", def); got.String() != tt.expected {
+				t.Errorf("BigInt() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindDecimal(t *testing.T) {
+	var params struct {
+		Amount Decimal  `query:"amount"`
+		Total  *big.Int `query:"total"`
+	}
+
+	r := httptest.NewRequest("GET", "/?amount=0.10&total=123456789012345678901234567890", nil)
+	if err := Bind(r, &params); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if params.Amount.String() != "0.10" || params.Total.String() != "123456789012345678901234567890" {
+		t.Errorf("Bind() = %s, %s", params.Amount, params.Total)
+	}
+}
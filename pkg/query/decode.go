@@ -0,0 +1,115 @@
+package query
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Decode converts the whole query string into a map suitable for encoding as
+// JSON, inferring types where the value is unambiguous:
+//
+//   - "true" and "false" become bool
+//   - integers become int64, unless they have a leading zero or "+" sign
+//     ("007" stays a string, as zip codes and IDs often look like that)
+//   - decimal numbers such as "2.5" or "1e3" become float64
+//   - everything else, including "", "NaN" and "0x1F", stays a string
+//
+// A parameter that appears once maps to a single value, and one that appears
+// several times maps to []any in the order sent. Values sent as key[] are
+// merged into key and always produce []any, even when there is only one.
+//
+// Example:
+//
+//	// URL: /proxy?page=2&active=true&zip=02134&tag=go&tag=rust&ids[]=7
+//	m := query.Decode(r)
+//	// map[string]any{"page": int64(2), "active": true, "zip": "02134",
+//	//     "tag": []any{"go", "rust"}, "ids": []any{int64(7)}}
+func Decode(r *http.Request) map[string]any {
+	values := r.URL.Query()
+	result := make(map[string]any, len(values))
+
+	for k := range values {
+		name := k
+		if base, ok := strings.CutSuffix(k, "[]"); ok && !strings.HasSuffix(base, "[]") {
+			name = base
+		}
+		if _, done := result[name]; done {
+			continue
+		}
+
+		vals := lookup(values, name)
+		_, bracketed := values[name+"[]"]
+		if len(vals) == 1 && !bracketed {
+			result[name] = inferValue(vals[0])
+			continue
+		}
+
+		list := make([]any, len(vals))
+		for i, v := range vals {
+			list[i] = inferValue(v)
+		}
+		result[name] = list
+	}
+	return result
+}
+
+// inferValue returns s as a bool, int64 or float64 when it is unambiguously one,
+// or s itself otherwise.
+func inferValue(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if !isPlainNumber(s) {
+		return s
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.ContainsAny(s, ".eE") {
+		return f
+	}
+	return s
+}
+
+// isPlainNumber reports whether s is a decimal number in JSON syntax: an
+// optional minus sign, an integer part without leading zeros, an optional
+// fraction and an optional exponent.
+func isPlainNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+
+	digits := func() int {
+		start := i
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+
+	start := i
+	if n := digits(); n == 0 || (n > 1 && s[start] == '0') {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
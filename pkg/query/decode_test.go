@@ -0,0 +1,81 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?page=2&active=true&off=false&zip=02134&ratio=2.5&tag=go&tag=rust&ids[]=7&q=&name=Alice&grid[][]=1", nil)
+
+	expected := map[string]any{
+		"page":     int64(2),
+		"active":   true,
+		"off":      false,
+		"zip":      "02134",
+		"ratio":    2.5,
+		"tag":      []any{"go", "rust"},
+		"ids":      []any{int64(7)},
+		"q":        "",
+		"name":     "Alice",
+		"grid[][]": int64(1),
+	}
+	if got := Decode(r); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %#v, want %#v", got, expected)
+	}
+
+	if got := Decode(httptest.NewRequest("GET", "/", nil)); len(got) != 0 {
+		t.Errorf("Decode() = %v, want empty map", got)
+	}
+}
+
+func TestDecodeMergesBrackets(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?id[]=3&id=1&id=x", nil)
+
+	expected := []any{int64(1), "x", int64(3)}
+	if got := Decode(r)["id"]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode()[id] = %#v, want %#v", got, expected)
+	}
+}
+
+func TestInferValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected any
+	}{
+		{"true", true},
+		{"false", false},
+		{"TRUE", "TRUE"},
+		{"yes", "yes"},
+		{"1", int64(1)},
+		{"0", int64(0)},
+		{"-42", int64(-42)},
+		{"+42", "+42"},
+		{"007", "007"},
+		{"-0", int64(0)},
+		{"99999999999999999999", "99999999999999999999"},
+		{"2.5", 2.5},
+		{"-0.5", -0.5},
+		{"1e3", 1000.0},
+		{"1.5E-2", 0.015},
+		{"1e400", "1e400"},
+		{".5", ".5"},
+		{"5.", "5."},
+		{"1e", "1e"},
+		{"NaN", "NaN"},
+		{"Inf", "Inf"},
+		{"0x1F", "0x1F"},
+		{"1_000", "1_000"},
+		{"", ""},
+		{"hello", "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := inferValue(tt.value); got != tt.expected {
+				t.Errorf("inferValue(%q) = %#v, want %#v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -32,6 +32,18 @@
 //	// Empty slice if parameter is missing
 //	filters := query.Strings(r, "filter")  // []string{}
 //
+// The array-bracket form used by jQuery, Axios and PHP clients is recognized
+// automatically, so ?id[]=1&id[]=2 works the same as ?id=1&id=2:
+//
+//	ids := query.Ints(r, "id", 0)  // []int{1, 2} for either form
+//
+// Duplicates can be dropped with Unique, or with Dedupe and SortedUnique for
+// any slice extractor:
+//
+//	// URL: /posts?tag=go&tag=rust&tag=go&id=3&id=1&id=3
+//	tags := query.Unique(r, "tag")                     // []string{"go", "rust"}
+//	ids  := query.SortedUnique(query.Ints(r, "id", 0)) // []int{1, 3}
+//
 // # Single vs Multiple Values
 //
 // When you don't know if a parameter appears once or multiple times, you have options:
@@ -54,6 +66,19 @@
 //	// Works for both /api?id=1 and /api?id=1&id=2&id=3
 //	id := query.Int(r, "id", 0)             // Always gets first value
 //
+// Option 4 - Pick a specific occurrence with At, or the last one with Last,
+// for proxies that append overriding parameters:
+//
+//	// URL: /api?limit=10&limit=50
+//	limit := query.LastInt(r, "limit", 25)  // 50
+//	first := query.At(r, "limit", 0, "")    // "10"
+//
+// Decode converts the whole query at once into a map[string]any ready for
+// JSON, keeping single values scalar and inferring numbers and booleans:
+//
+//	// URL: /proxy?page=2&active=true&zip=02134&tag=go&tag=rust
+//	m := query.Decode(r) // {"page": 2, "active": true, "zip": "02134", "tag": ["go", "rust"]}
+//
 // # Generic Slices with Type Conversion
 //
 // Use Slice with a parser function to convert multiple values to any type.
@@ -69,6 +94,28 @@
 //	})
 //	// Returns []float64{19.99, 0.0, 39.99}
 //
+// Value is the single-value counterpart, useful for custom types such as
+// enums, IDs or money amounts:
+//
+//	// URL: /orders?status=shipped
+//	status := query.Value(r, "status", StatusAny, ParseStatus)
+//
+// Types implementing encoding.TextUnmarshaler need no parser at all:
+//
+//	// URL: /hosts?addr=192.168.1.10
+//	addr := query.Unmarshal(r, "addr", netip.Addr{})
+//
+// Domain types used across many handlers can be registered once with
+// RegisterType, after which Value, Slice and Bind accept them without a parser
+// and Builder.SetValue formats them:
+//
+//	func init() {
+//	    query.RegisterType(ParseMoney, Money.String)
+//	}
+//
+//	// URL: /products?min=10.00EUR
+//	minPrice := query.Value(r, "min", Money{}, nil)
+//
 // For convenience, typed slice helpers are provided:
 //
 //	ids := query.Ints(r, "id", 0)           // []int with default 0
@@ -76,6 +123,20 @@
 //	prices := query.Float64s(r, "price", 0.0) // []float64 with default 0.0
 //	flags := query.Bools(r, "enabled", false) // []bool with default false
 //
+// # Sanitizing Strings
+//
+// Clean strips control characters, trims whitespace and truncates free-text
+// parameters in one call; StringWith exposes each step, plus HTML escaping:
+//
+//	q := query.Clean(r, "q", 256, "")
+//	title := query.StringWith(r, "title", "", query.StringOpts{TrimSpace: true, EscapeHTML: true})
+//
+// Raw and RawValues go the other way, returning values exactly as they were
+// sent, still percent-encoded, for byte-exact signature checks or proxying:
+//
+//	// URL: /callback?state=abc%3D%3D
+//	state := query.Raw(r, "state") // "abc%3D%3D", where String returns "abc=="
+//
 // # Numeric Types
 //
 // The package supports various numeric types with automatic parsing:
@@ -85,6 +146,35 @@
 //	ratio := query.Float64(r, "ratio", 0.0)    // float64: 3.14
 //	id    := query.Int64(r, "id", 0)           // int64: 123
 //
+// Int8, Int16, Int32 and Float32 check the bit size, so out-of-range values
+// such as ?priority=300 for an Int8 return the default rather than overflowing.
+//
+// Float64, Float32 and Float64s treat "NaN" and "Inf" as invalid, since they
+// poison arithmetic and cannot be encoded as JSON. Extractors with
+// Config.AllowNonFinite accept them.
+//
+// Amounts that must not suffer float rounding can be read exactly with
+// DecimalOf, which rejects values with more decimal places than allowed, and
+// integers beyond 64 bits with BigInt:
+//
+//	// URL: /payments?amount=19.99&block=18446744073709551617
+//	amount := query.DecimalOf(r, "amount", query.Decimal{}, 2)  // 19.99, Unscaled() 1999
+//	block  := query.BigInt(r, "block", big.NewInt(0))
+//
+// Human-facing report filters often arrive formatted. An Extractor with
+// DigitSeparators accepts grouped digits, and DecimalComma reads "," as the
+// decimal mark:
+//
+//	var de = query.NewExtractor(query.Config{DigitSeparators: ".", DecimalComma: true})
+//	// URL: /report?min=1.000&ratio=0,5
+//	minAmount := de.Int(r, "min", 0)      // 1000
+//	ratio     := de.Float64(r, "ratio", 0) // 0.5
+//
+// ByteSize understands SI and IEC units:
+//
+//	// URL: /uploads?max=10MB
+//	limit := query.ByteSize(r, "max", 1<<20)  // int64: 10000000
+//
 // # Boolean Parsing
 //
 // Booleans are parsed flexibly, accepting common true/false representations:
@@ -99,6 +189,254 @@
 // Recognized as false: "false", "0", "no", "off", "n"
 // Case-insensitive parsing
 //
+// Use BoolStrict for strconv.ParseBool semantics, or an Extractor with a custom
+// vocabulary:
+//
+//	q := query.NewExtractor(query.Config{
+//	    TrueValues:  []string{"ja"},
+//	    FalseValues: []string{"nein"},
+//	})
+//	active := q.Bool(r, "aktiv", false)
+//
+// # Bracketed Parameters
+//
+// PHP/Rails-style bracket syntax, as emitted by many frontend serializers, is
+// grouped by its base name:
+//
+//	// URL: /posts?filter[status]=active&filter[price][min]=10&filter[price][max]=50
+//	filter := query.Map(r, "filter")      // map[string]string{"status": "active"}
+//	tree   := query.Nested(r, "filter")   // {"status": "active", "price": {"min": "10", "max": "50"}}
+//
+// MapOf converts the values of a single-level map with a Parser.
+//
+// Dotted does the same for dot-separated names, as produced by qs with allowDots:
+//
+//	// URL: /posts?filter.status=active&filter.price.min=10
+//	filter := query.Dotted(r, "filter")  // {"status": "active", "price": {"min": "10"}}
+//
+// Prefixed groups flat names sharing a prefix, with the prefix stripped:
+//
+//	// URL: /landing?utm_source=mail&utm_campaign=spring
+//	utm := query.Prefixed(r, "utm_")  // map[string]string{"source": "mail", "campaign": "spring"}
+//
+// # Missing vs Zero Values
+//
+// The Ptr variants return nil when a parameter is absent, so handlers can tell
+// "not provided" apart from "provided as 0/false":
+//
+//	// URL: /users?active=false
+//	if active := query.BoolPtr(r, "active"); active != nil {
+//	    filter.Active = *active  // false, explicitly requested
+//	}
+//
+// StringPtr, IntPtr, Int64Ptr, Float64Ptr, BoolPtr and BoolPtrStrict are
+// provided, and ValuePtr works with any Parser.
+//
+// For boolean filters, TriBool makes the three cases explicit:
+//
+//	switch query.TriBool(r, "active") {
+//	case query.TriUnset: // don't filter
+//	case query.TriTrue:  // only active
+//	case query.TriFalse: // only inactive
+//	}
+//
+// # Times and Date Ranges
+//
+// Time parses RFC 3339, "2006-01-02 15:04:05" and "2006-01-02" values by
+// default, or any layouts you pass:
+//
+//	since := query.Time(r, "since", time.Time{})
+//
+// Unix reads epoch timestamps, in seconds by default, or detecting seconds vs
+// milliseconds by magnitude with UnixAuto:
+//
+//	since := query.Unix(r, "since", time.Time{}, query.UnixAuto)
+//
+// Location loads a time zone such as ?tz=America/New_York, optionally
+// restricted to an allowlist:
+//
+//	loc := query.Location(r, "tz", time.UTC)
+//
+// TimeRange extracts and validates a start/end pair in one call:
+//
+//	// URL: /reports?from=2024-01-01&to=2024-01-31
+//	start, end, ok := query.TimeRange(r, "from", "to", weekAgo, now,
+//	    query.MaxSpan(90*24*time.Hour), // reject ranges longer than 90 days
+//	    query.SwapReversed(),           // accept from/to in either order
+//	    query.InclusiveEnd(),           // "to=2024-01-31" covers the whole day
+//	)
+//
+// # URLs
+//
+// URL parses a parameter as a *url.URL. Parameters used as redirect or
+// callback targets should always be restricted to prevent open redirects:
+//
+//	// URL: /login?redirect_uri=https://app.example.com/home
+//	next := query.URL(r, "redirect_uri", home,
+//	    query.AllowSchemes("https"),
+//	    query.AllowHosts("app.example.com"),
+//	)
+//
+//	// Only accept same-origin paths such as "/account"
+//	back := query.URL(r, "back", home, query.RelativeOnly())
+//
+// # Language Tags
+//
+// Locale validates a BCP 47 language tag and canonicalizes its casing. Given an
+// allowlist, it resolves the closest supported language:
+//
+//	// URL: /docs?lang=de-at
+//	lang := query.Locale(r, "lang", "en", "en", "de", "fr") // "de"
+//
+// # Building Query Strings
+//
+// Builder goes the other way, producing query strings with typed setters:
+//
+//	next := *r.URL
+//	query.BuilderFrom(r.URL.Query()).
+//	    SetInt("page", page+1).
+//	    SetTime("since", since, time.DateOnly).
+//	    Apply(&next)
+//
+// Merge combines two sets of values under a MergePolicy, and Diff reports the
+// keys added, removed and changed between two sets, for "change one parameter,
+// keep the rest" links:
+//
+//	sorted := query.Merge(r.URL.Query(), url.Values{"sort": {"-price"}, "page": nil}, query.MergeReplace)
+//
+// Canonical produces a deterministic form of the request's query string, with
+// keys sorted and values re-encoded, for use as a cache or deduplication key:
+//
+//	key := r.URL.Path + "?" + query.Canonical(r, query.WithoutPrefixes("utm_"))
+//
+// # Fallback Chains
+//
+// Chain resolves values that may arrive as a query parameter, header or cookie,
+// taking the first non-empty one in declaration order:
+//
+//	token := query.Chain(r).Query("token").Header("X-Api-Token").Cookie("token").String("")
+//
+// # Struct Binding
+//
+// Bind fills a struct from the query string using `query` field tags. Fields
+// whose parameter is missing keep their current value, so defaults can be set
+// beforehand:
+//
+//	type ListParams struct {
+//	    Page  int      `query:"page"`
+//	    Limit int      `query:"limit"`
+//	    Tags  []string `query:"tag"`
+//	}
+//
+//	params := ListParams{Page: 1, Limit: 25}
+//	err := query.Bind(r, &params)
+//
+// Nested struct fields are bound from dotted or bracketed names, so a Filter
+// field tagged `query:"filter"` with a Status field tagged `query:"status"` reads
+// ?filter.status=active or ?filter[status]=active. Embedded structs are
+// flattened, and slices of structs are bound from indexed groups:
+//
+//	type Order struct {
+//	    Items []struct {
+//	        SKU string `query:"sku"`
+//	        Qty int    `query:"qty"`
+//	    } `query:"items"`
+//	}
+//
+//	// URL: /orders?items[0][sku]=A1&items[0][qty]=2&items[1][sku]=B2
+//
+// Tag options set defaults and validate values, reporting violations as field
+// errors wrapping a *RuleError:
+//
+//	type ListParams struct {
+//	    Limit int    `query:"limit,default=25,min=1,max=100"`
+//	    Order string `query:"order,oneof=asc desc"`
+//	    Token string `query:"token,required"`
+//	}
+//
+// Strict APIs can reject unexpected parameters such as ?pgae=2 with StrictBind,
+// or check them without binding using AllowKeys:
+//
+//	if unknown := query.AllowKeys(r, "page", "limit", "sort"); len(unknown) > 0 {
+//	    // respond with 400 Bad Request
+//	}
+//
+// # Field Selection
+//
+// Fields parses sparse fieldsets such as ?fields=id,name against an allowlist:
+//
+//	fields := query.Fields(r, "fields", []string{"id", "name", "email"})
+//	if fields.IsAll() {
+//	    // no selection made, return every field
+//	}
+//
+// Filter parses operator-prefixed values such as ?price=gte:10 or
+// ?status=in:open,pending, and FilterOf types the operand with a Parser:
+//
+//	cond, ok := query.FilterOf(r, "price", strconv.Atoi)
+//	// query.Condition[int]{Op: query.OpGte, Value: 10}, true
+//
+// Sort parses sort specifications such as ?sort=-created,title:
+//
+//	order := query.Sort(r, "sort", []string{"created", "title"})
+//	// []query.SortField{{Field: "created", Desc: true}, {Field: "title"}}
+//
+// Includes parses relationship paths such as ?include=author,comments.author
+// into an IncludeTree with a depth limit:
+//
+//	inc := query.Includes(r, "include", []string{"author", "comments", "comments.author"})
+//	inc.Has("comments.author")  // true
+//
+// The jsonapi subpackage combines these into a single parser for the JSON:API
+// conventions (page[number], sort, filter[x], fields[type] and include).
+//
+// # Parsing Policies
+//
+// An Extractor bundles parsing settings so an application can define its policy
+// once and share it across handlers. Its methods mirror the package-level
+// functions, and ValueWith, SliceWith and ValuePtrWith cover the generic ones:
+//
+//	var q = query.NewExtractor(query.Config{
+//	    TrimSpace:           true, // "?page=%202" reads as 2
+//	    MaxValueLength:      256,  // longer values are ignored
+//	    CaseInsensitiveKeys: true, // "?Page=2" matches "page"
+//	})
+//
+//	page := q.IntInRange(r, "page", 1, 1, 1000)
+//	err  := q.StrictBind(r, &params)
+//
+// Public endpoints should bound the work a single request can cause.
+// HardenedConfig limits value length and repetition count, so a key repeated
+// 10,000 times is treated as absent instead of feeding Slice or Bind:
+//
+//	var public = query.NewExtractor(query.HardenedConfig())
+//
+// APIs consolidating historical naming drift can map old names onto the
+// current one with Aliases, or per field with the alias tag option in Bind:
+//
+//	var q = query.NewExtractor(query.Config{
+//	    Aliases: map[string][]string{"per_page": {"limit", "page_size"}},
+//	})
+//	perPage := q.Int(r, "per_page", 25)  // also reads ?limit= or ?page_size=
+//
+// Bulk endpoints that should accept long lists, but only up to a point, can set
+// MaxItems to keep the first values instead, and Chunk splits what they keep
+// into batches:
+//
+//	var bulk = query.NewExtractor(query.Config{MaxItems: 500})
+//	for _, batch := range query.Chunk(bulk.Ints(r, "id", 0), 100) {
+//	    store.Load(batch)
+//	}
+//
+// r.URL.Query() silently drops pairs with invalid percent-escapes such as
+// ?label=50%. ParseTolerant and Tolerant keep every well-formed pair and report
+// the skipped ones, and TolerantSource plugs the same parsing into an Extractor:
+//
+//	values, skipped := query.Tolerant(r)
+//	for _, p := range skipped {
+//	    log.Printf("dropped %q: %v", p.Raw, p.Err)
+//	}
+//
 // # Error Handling
 //
 // Invalid values safely fall back to defaults without panicking:
@@ -108,21 +446,52 @@
 //	limit  := query.Int(r, "limit", 25)     // -5 (parsed successfully)
 //	active := query.Bool(r, "active", false) // false (unrecognized bool)
 //
-// Note: Negative numbers and zero are valid parse results. Use validation
-// logic after extraction if you need to enforce constraints.
+// Required parameters can be extracted with the OrFail variants, which write a
+// 400 Bad Request response on failure so the handler only has to return:
+//
+//	id, ok := query.IntOrFail(w, r, "id")
+//	if !ok {
+//	    return
+//	}
+//
+// The response is produced by DefaultErrorHandler, or by Config.ErrorHandler
+// when using an Extractor.
+//
+// Falling back to a default hides clients that keep sending malformed values.
+// Config.OnParseError reports each value an Extractor could not parse, for
+// example to feed a metric:
+//
+//	var q = query.NewExtractor(query.Config{
+//	    OnParseError: func(key, raw, targetType string, err error) {
+//	        parseFailures.WithLabelValues(key, targetType).Inc()
+//	    },
+//	})
+//
+// Note: Negative numbers and zero are valid parse results. Use the InRange
+// variants or validation logic after extraction if you need to enforce constraints.
+//
+// # Bounded Numbers
+//
+// IntInRange, Int64InRange and Float64InRange clamp parsed values to a range:
+//
+//	// URL: /products?limit=500
+//	limit := query.IntInRange(r, "limit", 25, 1, 100)  // 100 (clamped)
+//
+// The default is returned unchanged when the parameter is missing or invalid.
+//
+// IntRange and Float64Range read a min/max pair from two parameters, clamp both
+// ends to hard bounds and swap them if reversed:
+//
+//	// URL: /products?price_min=10&price_max=50
+//	lo, hi, ok := query.IntRange(r, "price_min", "price_max", 0, 10000)
+//	// lo = 10, hi = 50, ok = true (false when neither end was given)
 //
 // # Common Patterns
 //
 // Pagination:
 //
-//	page  := query.Int(r, "page", 1)
-//	limit := query.Int(r, "limit", 25)
-//	if page < 1 {
-//	    page = 1
-//	}
-//	if limit < 1 || limit > 100 {
-//	    limit = 25
-//	}
+//	page  := query.IntInRange(r, "page", 1, 1, math.MaxInt)
+//	limit := query.IntInRange(r, "limit", 25, 1, 100)
 //
 // Filtering:
 //
@@ -139,6 +508,11 @@
 //	sortBy   := query.String(r, "sort_by", "relevance")
 //	sortDir  := query.String(r, "sort_dir", "desc")
 //
+// List endpoints, with page, per_page, sort, q and filter[FIELD] in one call:
+//
+//	p := query.Std(r, query.SortableFields("name", "price"), query.DefaultSort("name"))
+//	items := store.List(p.Filters, p.Sort, p.Q, p.Offset(), p.PerPage)
+//
 // # Design Principles
 //
 // 1. Fail-safe: Never panic on invalid input
@@ -149,11 +523,30 @@
 //
 // # Performance Note
 //
-// Each function call parses r.URL.Query() independently. For high-frequency
-// extraction of many parameters, consider parsing once and storing the result:
+// Single-value extractors such as String, Int, Bool, Flag and Has scan
+// r.URL.RawQuery directly and do not allocate when the value needs no
+// unescaping. Multi-value extractors, Extractors that trim, cap or fold keys,
+// and unusually long query strings parse r.URL.Query() on each call. For
+// high-frequency extraction of many parameters, parse once with the Memoize
+// middleware and read the shared, read-only View it stores:
+//
+//	mux.Handle("/search", query.Memoize(searchHandler))
 //
-//	values := r.URL.Query()
-//	// Then use values.Get() directly if performance is critical
+//	// In searchHandler
+//	v := query.AllCached(r)
+//	for _, k := range v.Keys() {
+//	    // v.Get(k), v.Values(k)
+//	}
+//
+// To scan for one parameter in a very large query string, Pairs and Each walk
+// r.URL.RawQuery in place and can stop at the first match:
+//
+//	for k, v := range query.Pairs(r) {
+//	    if k == "_debug" {
+//	        // v
+//	        break
+//	    }
+//	}
 //
 // However, for typical web applications, the convenience outweighs the minimal
 // performance overhead.
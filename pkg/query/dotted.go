@@ -0,0 +1,69 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Dotted decodes dot-separated parameter names, as produced by the qs library's
+// allowDots option and several other JavaScript serializers, into a nested
+// map[string]any. Only keys starting with prefix followed by a dot are
+// included, and the prefix is stripped; an empty prefix decodes every dotted
+// key. Leaves are strings (the first value of the parameter), or []string for
+// keys ending in "[]". When a key is used both as a leaf and as a parent
+// (a.b=1&a.b.c=2), the nested form wins. Keys with empty segments or more than
+// ten levels deep are ignored.
+// Returns an empty map if no matching parameters are present.
+//
+// Example: For URL "?filter.price.min=10&filter.price.max=50&filter.status=active"
+//
+//	filter := query.Dotted(r, "filter")
+//	// map[string]any{
+//	//     "price":  map[string]any{"min": "10", "max": "50"},
+//	//     "status": "active",
+//	// }
+func Dotted(r *http.Request, prefix string) map[string]any {
+	return dottedFrom(r.URL.Query(), prefix)
+}
+
+func dottedFrom(values url.Values, prefix string) map[string]any {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	result := make(map[string]any)
+	for _, k := range keys {
+		if len(values[k]) == 0 {
+			continue
+		}
+
+		rest := k
+		if prefix != "" {
+			var ok bool
+			if rest, ok = strings.CutPrefix(k, prefix+"."); !ok {
+				continue
+			}
+		}
+
+		var leaf any = values[k][0]
+		if trimmed, ok := strings.CutSuffix(rest, "[]"); ok {
+			rest = trimmed
+			leaf = values[k]
+		}
+
+		path := strings.Split(rest, ".")
+		if len(path) > maxNestingDepth || slices.Contains(path, "") {
+			continue
+		}
+		if prefix == "" && len(path) < 2 {
+			continue
+		}
+
+		setPath(result, path, leaf)
+	}
+	return result
+}
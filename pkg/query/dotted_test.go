@@ -0,0 +1,104 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDotted(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		prefix   string
+		expected map[string]any
+	}{
+		{
+			"nested",
+			"/?a.b.c=1&a.b.d=2&a.e=3",
+			"a",
+			map[string]any{"b": map[string]any{"c": "1", "d": "2"}, "e": "3"},
+		},
+		{
+			"array leaf",
+			"/?filter.tag[]=go&filter.tag[]=rust",
+			"filter",
+			map[string]any{"tag": []string{"go", "rust"}},
+		},
+		{
+			"other prefixes ignored",
+			"/?filter.status=active&sort.by=name&filter=x&filterx.y=1",
+			"filter",
+			map[string]any{"status": "active"},
+		},
+		{
+			"empty prefix",
+			"/?a.b=1&c.d.e=2&plain=3",
+			"",
+			map[string]any{"a": map[string]any{"b": "1"}, "c": map[string]any{"d": map[string]any{"e": "2"}}},
+		},
+		{
+			"nested form wins",
+			"/?a.b=1&a.b.c=2",
+			"a",
+			map[string]any{"b": map[string]any{"c": "2"}},
+		},
+		{
+			"empty segments ignored",
+			"/?a..b=1&a.c.=2&a.d=3",
+			"a",
+			map[string]any{"d": "3"},
+		},
+		{
+			"too deep",
+			"/?a.1.2.3.4.5.6.7.8.9.10.11=x",
+			"a",
+			map[string]any{},
+		},
+		{"missing", "/", "a", map[string]any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Dotted(r, tt.prefix)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Dotted() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindNestedStruct(t *testing.T) {
+	type priceRange struct {
+		Min float64 `query:"min"`
+		Max float64 `query:"max"`
+	}
+	type filter struct {
+		Status string     `query:"status"`
+		Price  priceRange `query:"price"`
+	}
+	type params struct {
+		Page   int    `query:"page"`
+		Filter filter `query:"filter"`
+	}
+
+	r := httptest.NewRequest("GET", "/?page=2&filter.status=active&filter.price.min=10&filter.price.max=50", nil)
+	got := params{Filter: filter{Status: "all"}}
+	if err := Bind(r, &got); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	expected := params{Page: 2, Filter: filter{Status: "active", Price: priceRange{Min: 10, Max: 50}}}
+	if got != expected {
+		t.Errorf("Bind() = %+v, want %+v", got, expected)
+	}
+
+	r = httptest.NewRequest("GET", "/?filter.status=active&filter.prcie.min=1", nil)
+	err := StrictBind(r, &got)
+	var unknownErr *UnknownKeysError
+	if !errors.As(err, &unknownErr) || !reflect.DeepEqual(unknownErr.Keys, []string{"filter.prcie.min"}) {
+		t.Errorf("StrictBind() error = %v, want unknown filter.prcie.min", err)
+	}
+}
@@ -0,0 +1,45 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Base64 extracts a base64-encoded value from the query parameter with the given
+// key and returns the decoded bytes. Both the standard and URL-safe alphabets are
+// accepted, with or without padding. A '+' that arrived unescaped, and was
+// therefore decoded to a space, is restored before decoding.
+// Returns defaultValue if the key is missing, empty, or not valid base64.
+//
+// Example:
+//
+//	// URL: /download?token=aGVsbG8gd29ybGQ
+//	token := query.Base64(r, "token", nil)  // []byte("hello world")
+func Base64(r *http.Request, key string, defaultValue []byte) []byte {
+	return Value(r, key, defaultValue, parseBase64)
+}
+
+// Hex extracts a hex-encoded value (upper or lower case) from the query parameter
+// with the given key and returns the decoded bytes.
+// Returns defaultValue if the key is missing, empty, or not valid hex.
+//
+// Example:
+//
+//	// URL: /verify?sha256=9f86d081884c7d65...
+//	digest := query.Hex(r, "sha256", nil)
+func Hex(r *http.Request, key string, defaultValue []byte) []byte {
+	return Value(r, key, defaultValue, hex.DecodeString)
+}
+
+// parseBase64 decodes s using whichever base64 alphabet it is written in.
+func parseBase64(s string) ([]byte, error) {
+	s = strings.ReplaceAll(s, " ", "+")
+	s = strings.TrimRight(s, "=")
+
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
@@ -0,0 +1,61 @@
+package query
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBase64(t *testing.T) {
+	fallback := []byte("default")
+
+	tests := []struct {
+		name     string
+		url      string
+		expected []byte
+	}{
+		{"std padded", "/?v=aGVsbG8%3D", []byte("hello")},
+		{"std unpadded", "/?v=aGVsbG8", []byte("hello")},
+		{"url-safe", "/?v=-_8", []byte{0xfb, 0xff}},
+		{"std alphabet", "/?v=%2B%2F8%3D", []byte{0xfb, 0xff}},
+		{"unescaped plus", "/?v=+/8=", []byte{0xfb, 0xff}},
+		{"mixed alphabets", "/?v=-%2F8", fallback},
+		{"invalid chars", "/?v=not*base64", fallback},
+		{"missing key", "/?other=value", fallback},
+		{"empty value", "/?v=", fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Base64(r, "v", fallback)
+			if !bytes.Equal(got, tt.expected) {
+				t.Errorf("Base64() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected []byte
+	}{
+		{"lower case", "/?v=deadbeef", []byte{0xde, 0xad, 0xbe, 0xef}},
+		{"upper case", "/?v=DEADBEEF", []byte{0xde, 0xad, 0xbe, 0xef}},
+		{"odd length", "/?v=abc", nil},
+		{"invalid chars", "/?v=zz", nil},
+		{"missing key", "/", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Hex(r, "v", nil)
+			if !bytes.Equal(got, tt.expected) {
+				t.Errorf("Hex() = %x, want %x", got, tt.expected)
+			}
+		})
+	}
+}
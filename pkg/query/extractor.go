@@ -0,0 +1,526 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config describes the parsing policy of an Extractor. The zero value matches
+// the behavior of the package-level functions.
+type Config struct {
+	// TrueValues and FalseValues replace the vocabulary recognized by Bool and
+	// Bools, for example to accept localized values such as "ja"/"nein".
+	// Matching ignores case and surrounding whitespace. When both are empty the
+	// default vocabulary ("true", "1", "yes", "on", "y" / "false", "0", "no",
+	// "off", "n") is used.
+	TrueValues  []string
+	FalseValues []string
+
+	// TrimSpace removes leading and trailing whitespace from every value before
+	// it is used, so ?page=%202 is read as 2 and ?q=%20 counts as empty.
+	TrimSpace bool
+
+	// MaxValueLength discards values longer than this many bytes, as though they
+	// had not been sent. Zero means no limit.
+	MaxValueLength int
+
+	// MaxValueCount treats a parameter repeated more than this many times as
+	// absent, so ?id=1&id=2&... with thousands of repetitions cannot feed
+	// unbounded work into Slice or Bind. Zero means no limit.
+	MaxValueCount int
+
+	// MaxItems caps the number of values the slice extractors (Strings, Slice
+	// and the typed slice methods) and slice fields in Bind return, keeping the
+	// first MaxItems values and dropping the rest. Unlike MaxValueCount, a
+	// parameter over the cap is still used. Zero means no cap.
+	MaxItems int
+
+	// CaseInsensitiveKeys matches parameter names regardless of case, so ?Page=2
+	// is found when asking for "page". Values from all matching spellings are
+	// combined, in order of their spelling.
+	CaseInsensitiveKeys bool
+
+	// DisableArrayBrackets stops values sent as key[] from being merged into key.
+	DisableArrayBrackets bool
+
+	// Aliases maps a parameter name to alternative names that feed the same
+	// logical parameter, such as {"per_page": {"limit", "page_size"}}, for APIs
+	// consolidating historical naming drift. The first name present in the
+	// query wins, trying the parameter's own name before its aliases in the
+	// order listed. Aliases also count as allowed in AllowKeys and StrictBind.
+	Aliases map[string][]string
+
+	// DigitSeparators lists characters accepted as thousands separators by the
+	// numeric methods and Bind, such as "," or "_", so ?min=1,000 reads as 1000.
+	// A separator is only ignored between two digits.
+	DigitSeparators string
+
+	// DecimalComma makes floats use "," as the decimal mark, so ?ratio=0,5 reads
+	// as 0.5. Combine it with DigitSeparators "." for values such as "1.234,5".
+	DecimalComma bool
+
+	// AllowNonFinite accepts "NaN", "Inf" and "-Inf" as float values. By default
+	// they are treated as invalid, since they poison arithmetic and cannot be
+	// encoded as JSON; scientific endpoints that need them can opt in.
+	AllowNonFinite bool
+
+	// Source returns the values an Extractor reads from a request. Defaults to
+	// r.URL.Query(); other packages set it to extract from form bodies or
+	// similar sources with the same API.
+	Source func(r *http.Request) url.Values
+
+	// ErrorHandler writes the response when an OrFail method rejects a
+	// parameter, for example to produce a JSON problem document. Defaults to
+	// DefaultErrorHandler.
+	ErrorHandler ErrorHandler
+
+	// OnParseError, if set, is called whenever a non-empty value fails to parse
+	// and the Extractor falls back to a default (or nil, for the Ptr variants).
+	// Use it to count or log clients that send malformed input.
+	OnParseError ParseErrorHook
+}
+
+// ParseErrorHook observes a value that failed to parse. key is the parameter
+// name as requested, raw the offending value, targetType the Go type it was
+// being parsed into (for example "int" or "time.Time") and err the parser's
+// error. A hook is called synchronously and must be safe for concurrent use.
+type ParseErrorHook func(key, raw, targetType string, err error)
+
+// Extractor extracts query parameters according to a Config. Use it when the
+// package-level defaults do not fit an application's conventions; an Extractor
+// is safe for concurrent use and is typically created once and shared.
+//
+// Extractor methods mirror the package-level functions of the same name. The
+// generic functions are available as ValueWith, SliceWith and ValuePtrWith.
+//
+// Example:
+//
+//	var q = query.NewExtractor(query.Config{
+//	    TrueValues:          []string{"ja", "wahr"},
+//	    FalseValues:         []string{"nein", "falsch"},
+//	    TrimSpace:           true,
+//	    MaxValueLength:      256,
+//	    CaseInsensitiveKeys: true,
+//	})
+//
+//	active := q.Bool(r, "aktiv", false)
+//	page   := q.IntInRange(r, "page", 1, 1, 1000)
+type Extractor struct {
+	cfg        Config
+	boolTokens map[string]bool
+	rawScan    bool // single values can be scanned from RawQuery, see firstValue
+}
+
+// std is the Extractor behind the package-level functions.
+var std = NewExtractor(Config{})
+
+// HardenedConfig returns a Config suited to public endpoints: values are
+// trimmed, limited to 1 KiB each, and parameters may repeat at most 100 times.
+// Adjust the returned Config before passing it to NewExtractor as needed.
+func HardenedConfig() Config {
+	return Config{
+		TrimSpace:      true,
+		MaxValueLength: 1 << 10,
+		MaxValueCount:  100,
+	}
+}
+
+// NewExtractor returns an Extractor that applies cfg.
+func NewExtractor(cfg Config) *Extractor {
+	e := &Extractor{
+		cfg:     cfg,
+		rawScan: cfg.Source == nil && !cfg.TrimSpace && cfg.MaxValueLength <= 0 && cfg.MaxValueCount <= 0 && !cfg.CaseInsensitiveKeys && len(cfg.Aliases) == 0,
+	}
+	if len(cfg.TrueValues) > 0 || len(cfg.FalseValues) > 0 {
+		e.boolTokens = make(map[string]bool, len(cfg.TrueValues)+len(cfg.FalseValues))
+		for _, v := range cfg.TrueValues {
+			e.boolTokens[strings.ToLower(strings.TrimSpace(v))] = true
+		}
+		for _, v := range cfg.FalseValues {
+			e.boolTokens[strings.ToLower(strings.TrimSpace(v))] = false
+		}
+	}
+	return e
+}
+
+// ValueWith is Value using the policy of e.
+func ValueWith[T any](e *Extractor, r *http.Request, key string, defaultValue T, parser Parser[T]) T {
+	parser = resolveParser(parser)
+	val, _ := e.firstValue(r, key)
+	if val == "" {
+		return defaultValue
+	}
+
+	parsed, err := parser(val)
+	if err != nil {
+		reportParseError[T](e, key, val, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+// SliceWith is Slice using the policy of e.
+func SliceWith[T any](e *Extractor, r *http.Request, key string, defaultValue T, parser Parser[T]) []T {
+	parser = resolveParser(parser)
+	vals := e.capItems(e.lookup(e.values(r), key))
+	if len(vals) == 0 {
+		return []T{}
+	}
+
+	result := make([]T, len(vals))
+	for i, val := range vals {
+		parsed, err := parser(val)
+		if err != nil {
+			reportParseError[T](e, key, val, err)
+			result[i] = defaultValue
+		} else {
+			result[i] = parsed
+		}
+	}
+	return result
+}
+
+// ValuePtrWith is ValuePtr using the policy of e.
+func ValuePtrWith[T any](e *Extractor, r *http.Request, key string, parser Parser[T]) *T {
+	parser = resolveParser(parser)
+	val, ok := e.firstValue(r, key)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := parser(val)
+	if err != nil {
+		reportParseError[T](e, key, val, err)
+		return nil
+	}
+	return &parsed
+}
+
+// reportParseError passes a parse failure to the Extractor's OnParseError hook.
+func reportParseError[T any](e *Extractor, key, raw string, err error) {
+	if e.cfg.OnParseError != nil && raw != "" {
+		e.cfg.OnParseError(key, raw, reflect.TypeFor[T]().String(), err)
+	}
+}
+
+// String extracts a string value. See the package-level String.
+func (e *Extractor) String(r *http.Request, key string, defaultValue string) string {
+	val, _ := e.firstValue(r, key)
+	if val == "" {
+		return defaultValue
+	}
+	return val
+}
+
+// Strings extracts all values for a query parameter. See the package-level Strings.
+func (e *Extractor) Strings(r *http.Request, key string) []string {
+	vals := e.capItems(e.lookup(e.values(r), key))
+	if vals == nil {
+		return []string{}
+	}
+	return vals
+}
+
+// Int extracts an integer value. See the package-level Int.
+func (e *Extractor) Int(r *http.Request, key string, defaultValue int) int {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, strconv.Atoi))
+}
+
+// Int64 extracts an int64 value. See the package-level Int64.
+func (e *Extractor) Int64(r *http.Request, key string, defaultValue int64) int64 {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, parseInt64))
+}
+
+// Float64 extracts a float64 value. See the package-level Float64.
+func (e *Extractor) Float64(r *http.Request, key string, defaultValue float64) float64 {
+	return ValueWith(e, r, key, defaultValue, e.parseFloat64)
+}
+
+// Int8 extracts an int8 value. See the package-level Int8.
+func (e *Extractor) Int8(r *http.Request, key string, defaultValue int8) int8 {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, parseInt8))
+}
+
+// Int16 extracts an int16 value. See the package-level Int16.
+func (e *Extractor) Int16(r *http.Request, key string, defaultValue int16) int16 {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, parseInt16))
+}
+
+// Int32 extracts an int32 value. See the package-level Int32.
+func (e *Extractor) Int32(r *http.Request, key string, defaultValue int32) int32 {
+	return ValueWith(e, r, key, defaultValue, numberParser(e, parseInt32))
+}
+
+// Float32 extracts a float32 value. See the package-level Float32.
+func (e *Extractor) Float32(r *http.Request, key string, defaultValue float32) float32 {
+	return ValueWith(e, r, key, defaultValue, e.parseFloat32)
+}
+
+// IntInRange extracts an integer value clamped to [minValue, maxValue].
+// See the package-level IntInRange.
+func (e *Extractor) IntInRange(r *http.Request, key string, defaultValue, minValue, maxValue int) int {
+	return ValueWith(e, r, key, defaultValue, clamped(numberParser(e, strconv.Atoi), minValue, maxValue))
+}
+
+// Int64InRange extracts an int64 value clamped to [minValue, maxValue].
+// See the package-level Int64InRange.
+func (e *Extractor) Int64InRange(r *http.Request, key string, defaultValue, minValue, maxValue int64) int64 {
+	return ValueWith(e, r, key, defaultValue, clamped(numberParser(e, parseInt64), minValue, maxValue))
+}
+
+// Float64InRange extracts a float64 value clamped to [minValue, maxValue].
+// See the package-level Float64InRange.
+func (e *Extractor) Float64InRange(r *http.Request, key string, defaultValue, minValue, maxValue float64) float64 {
+	return ValueWith(e, r, key, defaultValue, clamped(e.parseFloat64, minValue, maxValue))
+}
+
+// Bool extracts a boolean value using the Extractor's vocabulary.
+// Returns defaultValue if the key is missing, empty, or not a recognized value.
+func (e *Extractor) Bool(r *http.Request, key string, defaultValue bool) bool {
+	return ValueWith(e, r, key, defaultValue, e.parseBool)
+}
+
+// BoolStrict extracts a boolean value using strconv.ParseBool semantics,
+// ignoring the Extractor's vocabulary. See the package-level BoolStrict.
+func (e *Extractor) BoolStrict(r *http.Request, key string, defaultValue bool) bool {
+	return ValueWith(e, r, key, defaultValue, strconv.ParseBool)
+}
+
+// Flag reports whether a CLI-style flag parameter is set, recognizing explicit
+// false values from the Extractor's vocabulary. See the package-level Flag.
+func (e *Extractor) Flag(r *http.Request, key string) bool {
+	val, ok := e.firstValue(r, key)
+	if !ok {
+		return false
+	}
+	if val == "" {
+		return true
+	}
+
+	parsed, err := e.parseBool(val)
+	if err != nil {
+		return true
+	}
+	return parsed
+}
+
+// Time extracts a time.Time value. See the package-level Time.
+func (e *Extractor) Time(r *http.Request, key string, defaultValue time.Time, layouts ...string) time.Time {
+	return ValueWith(e, r, key, defaultValue, timeParser(layouts))
+}
+
+// Ints extracts all integer values for a query parameter. See the package-level Ints.
+func (e *Extractor) Ints(r *http.Request, key string, defaultValue int) []int {
+	return SliceWith(e, r, key, defaultValue, numberParser(e, strconv.Atoi))
+}
+
+// Int64s extracts all int64 values for a query parameter. See the package-level Int64s.
+func (e *Extractor) Int64s(r *http.Request, key string, defaultValue int64) []int64 {
+	return SliceWith(e, r, key, defaultValue, numberParser(e, parseInt64))
+}
+
+// Float64s extracts all float64 values for a query parameter. See the package-level Float64s.
+func (e *Extractor) Float64s(r *http.Request, key string, defaultValue float64) []float64 {
+	return SliceWith(e, r, key, defaultValue, e.parseFloat64)
+}
+
+// Bools extracts all boolean values for a query parameter using the Extractor's
+// vocabulary. Unrecognized values are replaced with defaultValue.
+func (e *Extractor) Bools(r *http.Request, key string, defaultValue bool) []bool {
+	return SliceWith(e, r, key, defaultValue, e.parseBool)
+}
+
+// StringPtr extracts a string value, or nil if the key is missing.
+// See the package-level StringPtr.
+func (e *Extractor) StringPtr(r *http.Request, key string) *string {
+	return ValuePtrWith(e, r, key, func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// IntPtr extracts an integer value, or nil if the key is missing or invalid.
+func (e *Extractor) IntPtr(r *http.Request, key string) *int {
+	return ValuePtrWith(e, r, key, numberParser(e, strconv.Atoi))
+}
+
+// Int64Ptr extracts an int64 value, or nil if the key is missing or invalid.
+func (e *Extractor) Int64Ptr(r *http.Request, key string) *int64 {
+	return ValuePtrWith(e, r, key, numberParser(e, parseInt64))
+}
+
+// Float64Ptr extracts a float64 value, or nil if the key is missing or invalid.
+func (e *Extractor) Float64Ptr(r *http.Request, key string) *float64 {
+	return ValuePtrWith(e, r, key, e.parseFloat64)
+}
+
+// BoolPtr extracts a boolean value using the Extractor's vocabulary.
+// Returns nil if the key is missing, empty, or not a recognized value.
+func (e *Extractor) BoolPtr(r *http.Request, key string) *bool {
+	return ValuePtrWith(e, r, key, e.parseBool)
+}
+
+// Has checks if a query parameter exists (even if empty). See the package-level Has.
+func (e *Extractor) Has(r *http.Request, key string) bool {
+	_, ok := e.firstValue(r, key)
+	return ok
+}
+
+// Count returns the number of times a query parameter appears.
+// See the package-level Count.
+func (e *Extractor) Count(r *http.Request, key string) int {
+	return len(e.lookup(e.values(r), key))
+}
+
+// IsMultiple checks if a query parameter appears more than once.
+// See the package-level IsMultiple.
+func (e *Extractor) IsMultiple(r *http.Request, key string) bool {
+	return len(e.lookup(e.values(r), key)) > 1
+}
+
+// AllowKeys returns the query parameters that are not in the allowed list.
+// See the package-level AllowKeys.
+func (e *Extractor) AllowKeys(r *http.Request, keys ...string) []string {
+	return unknownKeys(e.values(r), e.withAliases(keys), e.cfg.CaseInsensitiveKeys)
+}
+
+// Bind populates a struct from the query string using the Extractor's policy.
+// See the package-level Bind.
+func (e *Extractor) Bind(r *http.Request, dst any) error {
+	_, err := e.bind(e.values(r), dst)
+	return err
+}
+
+// StrictBind is like Bind, but additionally rejects unknown parameters.
+// See the package-level StrictBind.
+func (e *Extractor) StrictBind(r *http.Request, dst any) error {
+	return e.strictBind(e.values(r), dst)
+}
+
+// values returns the request values the Extractor reads, from Config.Source
+// or the URL query.
+func (e *Extractor) values(r *http.Request) url.Values {
+	if e.cfg.Source != nil {
+		return e.cfg.Source(r)
+	}
+	return r.URL.Query()
+}
+
+func (e *Extractor) parseBool(s string) (bool, error) {
+	if e.boolTokens == nil {
+		return parseBool(s)
+	}
+	v, ok := e.boolTokens[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return false, strconv.ErrSyntax
+	}
+	return v, nil
+}
+
+// capItems limits vals to Config.MaxItems values.
+func (e *Extractor) capItems(vals []string) []string {
+	if e.cfg.MaxItems > 0 && len(vals) > e.cfg.MaxItems {
+		return vals[:e.cfg.MaxItems:e.cfg.MaxItems]
+	}
+	return vals
+}
+
+// lookup is the Extractor's counterpart of the package-level lookup, applying
+// key matching, trimming and limits from the Config. Values rejected by a limit
+// are treated as absent; see lookupErr for the reason.
+func (e *Extractor) lookup(values url.Values, key string) []string {
+	vals, _ := e.lookupErr(values, key)
+	return vals
+}
+
+// lookupErr is lookup that also reports ErrTooManyValues when the parameter
+// was dropped for exceeding MaxValueCount, or ErrValueTooLong when at least one
+// value was dropped for exceeding MaxValueLength. Configured aliases are tried
+// in order while no values have been found.
+func (e *Extractor) lookupErr(values url.Values, key string) ([]string, error) {
+	vals, err := e.lookupName(values, key)
+	for _, alias := range e.cfg.Aliases[key] {
+		if len(vals) > 0 || err != nil {
+			break
+		}
+		vals, err = e.lookupName(values, alias)
+	}
+	return vals, err
+}
+
+// withAliases returns keys followed by the aliases configured for them.
+func (e *Extractor) withAliases(keys []string) []string {
+	if len(e.cfg.Aliases) == 0 {
+		return keys
+	}
+	result := slices.Clip(keys)
+	for _, k := range keys {
+		result = append(result, e.cfg.Aliases[k]...)
+	}
+	return result
+}
+
+// lookupName is lookupErr for key alone, without its aliases.
+func (e *Extractor) lookupName(values url.Values, key string) ([]string, error) {
+	var vals []string
+	switch {
+	case e.cfg.CaseInsensitiveKeys:
+		vals = lookupFold(values, key, !e.cfg.DisableArrayBrackets)
+	case e.cfg.DisableArrayBrackets:
+		vals = values[key]
+	default:
+		vals = lookup(values, key)
+	}
+
+	if e.cfg.MaxValueCount > 0 && len(vals) > e.cfg.MaxValueCount {
+		return nil, ErrTooManyValues
+	}
+	if len(vals) == 0 || (!e.cfg.TrimSpace && e.cfg.MaxValueLength <= 0) {
+		return vals, nil
+	}
+
+	var err error
+	result := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if e.cfg.TrimSpace {
+			v = strings.TrimSpace(v)
+		}
+		if e.cfg.MaxValueLength > 0 && len(v) > e.cfg.MaxValueLength {
+			err = ErrValueTooLong
+			continue
+		}
+		result = append(result, v)
+	}
+	return result, err
+}
+
+// lookupFold is lookup with case-insensitive key matching. Values are combined
+// in the sorted order of the matching keys, with bracketed keys last.
+func lookupFold(values url.Values, key string, brackets bool) []string {
+	bracketKey := ""
+	if brackets && !strings.HasSuffix(key, "[]") {
+		bracketKey = key + "[]"
+	}
+
+	var plain, bracketed []string
+	for k := range values {
+		switch {
+		case strings.EqualFold(k, key):
+			plain = append(plain, k)
+		case bracketKey != "" && strings.EqualFold(k, bracketKey):
+			bracketed = append(bracketed, k)
+		}
+	}
+	slices.Sort(plain)
+	slices.Sort(bracketed)
+
+	var vals []string
+	for _, k := range append(plain, bracketed...) {
+		vals = append(vals, values[k]...)
+	}
+	return vals
+}
@@ -0,0 +1,299 @@
+package query
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractorBoolVocabulary(t *testing.T) {
+	e := NewExtractor(Config{
+		TrueValues:  []string{"ja", "Wahr"},
+		FalseValues: []string{"nein", "falsch"},
+	})
+
+	tests := []struct {
+		name         string
+		url          string
+		defaultValue bool
+		expected     bool
+	}{
+		{"custom true", "/?aktiv=ja", false, true},
+		{"custom true case-insensitive", "/?aktiv=WAHR", false, true},
+		{"custom false", "/?aktiv=nein", true, false},
+		{"custom false padded", "/?aktiv=%20falsch%20", true, false},
+		{"default vocabulary replaced", "/?aktiv=yes", false, false},
+		{"missing key", "/", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := e.Bool(r, "aktiv", tt.defaultValue)
+			if got != tt.expected {
+				t.Errorf("Extractor.Bool() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractorBoolsAndPtr(t *testing.T) {
+	e := NewExtractor(Config{TrueValues: []string{"oui"}, FalseValues: []string{"non"}})
+	r := httptest.NewRequest("GET", "/?f=oui&f=non&f=peut-etre&g=non", nil)
+
+	got := e.Bools(r, "f", true)
+	expected := []bool{true, false, true}
+	if len(got) != len(expected) {
+		t.Fatalf("Extractor.Bools() length = %d, want %d", len(got), len(expected))
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Extractor.Bools()[%d] = %v, want %v", i, got[i], expected[i])
+		}
+	}
+
+	assertPtr(t, "Extractor.BoolPtr", e.BoolPtr(r, "g"), ptr(false))
+	assertPtr(t, "Extractor.BoolPtr", e.BoolPtr(r, "missing"), nil)
+}
+
+func TestExtractorZeroConfig(t *testing.T) {
+	e := NewExtractor(Config{})
+	r := httptest.NewRequest("GET", "/?a=yes&b=off", nil)
+	if !e.Bool(r, "a", false) || e.Bool(r, "b", true) {
+		t.Error("Extractor with zero Config should use the default bool vocabulary")
+	}
+}
+
+func TestExtractorFlag(t *testing.T) {
+	e := NewExtractor(Config{TrueValues: []string{"ja"}, FalseValues: []string{"nein"}})
+	r := httptest.NewRequest("GET", "/?a&b=nein&c=ja&d=false", nil)
+
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"a", true},
+		{"b", false},
+		{"c", true},
+		{"d", true}, // "false" is not in the custom vocabulary
+		{"missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := e.Flag(r, tt.key); got != tt.expected {
+				t.Errorf("Extractor.Flag(%q) = %v, want %v", tt.key, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractorTrimSpace(t *testing.T) {
+	e := NewExtractor(Config{TrimSpace: true})
+	r := httptest.NewRequest("GET", "/?page=%202%20&q=%20%20&tag=%20go&tag=rust%20", nil)
+
+	if got := e.Int(r, "page", 1); got != 2 {
+		t.Errorf("Extractor.Int() = %v, want 2", got)
+	}
+	if got := e.String(r, "q", "all"); got != "all" {
+		t.Errorf("Extractor.String() = %q, want default for whitespace-only value", got)
+	}
+	if got := e.Strings(r, "tag"); !reflect.DeepEqual(got, []string{"go", "rust"}) {
+		t.Errorf("Extractor.Strings() = %q, want [go rust]", got)
+	}
+	if got := Int(r, "page", 1); got != 1 {
+		t.Errorf("Int() = %v, package-level functions should not trim", got)
+	}
+}
+
+func TestExtractorMaxValueLength(t *testing.T) {
+	e := NewExtractor(Config{MaxValueLength: 5})
+	long := strings.Repeat("a", 6)
+	r := httptest.NewRequest("GET", "/?q="+long+"&tag=go&tag="+long+"&tag=rust", nil)
+
+	if got := e.String(r, "q", "none"); got != "none" {
+		t.Errorf("Extractor.String() = %q, want default for over-long value", got)
+	}
+	if e.Has(r, "q") {
+		t.Error("Extractor.Has() = true, want over-long value treated as absent")
+	}
+	if got := e.Strings(r, "tag"); !reflect.DeepEqual(got, []string{"go", "rust"}) {
+		t.Errorf("Extractor.Strings() = %q, want [go rust]", got)
+	}
+}
+
+func TestExtractorCaseInsensitiveKeys(t *testing.T) {
+	e := NewExtractor(Config{CaseInsensitiveKeys: true})
+	r := httptest.NewRequest("GET", "/?Page=3&TAG=go&tag=rust&Tag[]=zig", nil)
+
+	if got := e.Int(r, "page", 1); got != 3 {
+		t.Errorf("Extractor.Int() = %v, want 3", got)
+	}
+	if got := e.Strings(r, "tag"); !reflect.DeepEqual(got, []string{"go", "rust", "zig"}) {
+		t.Errorf("Extractor.Strings() = %q, want [go rust zig]", got)
+	}
+	if got := e.AllowKeys(r, "PAGE", "tag"); len(got) != 0 {
+		t.Errorf("Extractor.AllowKeys() = %v, want none", got)
+	}
+	if got := Int(r, "page", 1); got != 1 {
+		t.Errorf("Int() = %v, package-level functions should match keys exactly", got)
+	}
+}
+
+func TestExtractorDisableArrayBrackets(t *testing.T) {
+	e := NewExtractor(Config{DisableArrayBrackets: true})
+	r := httptest.NewRequest("GET", "/?id=1&id[]=2", nil)
+
+	if got := e.Ints(r, "id", 0); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("Extractor.Ints() = %v, want [1]", got)
+	}
+	if got := e.Ints(r, "id[]", 0); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Extractor.Ints(id[]) = %v, want [2]", got)
+	}
+}
+
+func TestExtractorMethods(t *testing.T) {
+	e := NewExtractor(Config{TrimSpace: true, TrueValues: []string{"ja"}})
+	r := httptest.NewRequest("GET", "/?n=%2042&big=9000000000&f=2.5&b=ja&limit=500&id=1&id=x", nil)
+
+	if got := e.Int64(r, "big", 0); got != 9000000000 {
+		t.Errorf("Extractor.Int64() = %v, want 9000000000", got)
+	}
+	if got := e.Float64(r, "f", 0); got != 2.5 {
+		t.Errorf("Extractor.Float64() = %v, want 2.5", got)
+	}
+	if got := e.IntInRange(r, "limit", 25, 1, 100); got != 100 {
+		t.Errorf("Extractor.IntInRange() = %v, want 100", got)
+	}
+	if got := e.Ints(r, "id", -1); !reflect.DeepEqual(got, []int{1, -1}) {
+		t.Errorf("Extractor.Ints() = %v, want [1 -1]", got)
+	}
+	if got := e.Count(r, "id"); got != 2 || !e.IsMultiple(r, "id") {
+		t.Errorf("Extractor.Count() = %v, want 2", got)
+	}
+	if !e.Bool(r, "b", false) || e.BoolStrict(r, "b", false) {
+		t.Error("Extractor.BoolStrict() should ignore the custom vocabulary")
+	}
+	assertPtr(t, "Extractor.IntPtr", e.IntPtr(r, "n"), ptr(42))
+	assertPtr(t, "Extractor.StringPtr", e.StringPtr(r, "missing"), nil)
+	if got := ValueWith(e, r, "n", 0, func(s string) (int, error) { return len(s), nil }); got != 2 {
+		t.Errorf("ValueWith() = %v, want 2 (parser sees trimmed value)", got)
+	}
+	if got := SliceWith(e, r, "id", "?", func(s string) (string, error) { return s + s, nil }); !reflect.DeepEqual(got, []string{"11", "xx"}) {
+		t.Errorf("SliceWith() = %v, want [11 xx]", got)
+	}
+}
+
+func TestExtractorBind(t *testing.T) {
+	e := NewExtractor(Config{CaseInsensitiveKeys: true, TrimSpace: true, TrueValues: []string{"ja"}})
+
+	var params struct {
+		Page   int  `query:"page"`
+		Active bool `query:"active"`
+	}
+	r := httptest.NewRequest("GET", "/?PAGE=%204&Active=ja", nil)
+	if err := e.StrictBind(r, &params); err != nil {
+		t.Fatalf("Extractor.StrictBind() error = %v", err)
+	}
+	if params.Page != 4 || !params.Active {
+		t.Errorf("Extractor.StrictBind() = %+v, want {Page:4 Active:true}", params)
+	}
+
+	r = httptest.NewRequest("GET", "/?page=1&pgae=2", nil)
+	var unknownErr *UnknownKeysError
+	if err := e.StrictBind(r, &params); !errors.As(err, &unknownErr) {
+		t.Errorf("Extractor.StrictBind() error = %v, want UnknownKeysError", err)
+	}
+}
+
+func TestExtractorMaxValueCount(t *testing.T) {
+	e := NewExtractor(Config{MaxValueCount: 3})
+	r := httptest.NewRequest("GET", "/?id=1&id=2&id=3&tag=a&tag=b&tag[]=c&tag[]=d", nil)
+
+	if got := e.Ints(r, "id", 0); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Extractor.Ints() = %v, want [1 2 3] at the limit", got)
+	}
+	if got := e.Strings(r, "tag"); len(got) != 0 {
+		t.Errorf("Extractor.Strings() = %v, want none when over the limit", got)
+	}
+	if got := e.String(r, "tag", "none"); got != "none" {
+		t.Errorf("Extractor.String() = %q, want default when over the limit", got)
+	}
+	if e.Has(r, "tag") {
+		t.Error("Extractor.Has() = true, want parameter over the limit treated as absent")
+	}
+}
+
+func TestHardenedConfig(t *testing.T) {
+	e := NewExtractor(HardenedConfig())
+	target := "/?q=" + strings.Repeat("a", 2000) + "&page=%203%20" + strings.Repeat("&id=1", 101)
+	r := httptest.NewRequest("GET", target, nil)
+
+	if got := e.String(r, "q", "none"); got != "none" {
+		t.Errorf("Hardened String() returned a %d byte value, want default", len(got))
+	}
+	if got := e.Int(r, "page", 1); got != 3 {
+		t.Errorf("Hardened Int() = %v, want 3", got)
+	}
+	if got := e.Ints(r, "id", 0); len(got) != 0 {
+		t.Errorf("Hardened Ints() returned %d values, want none", len(got))
+	}
+}
+
+func TestExtractorOnParseError(t *testing.T) {
+	type failure struct {
+		key, raw, targetType string
+	}
+	var got []failure
+	e := NewExtractor(Config{
+		OnParseError: func(key, raw, targetType string, err error) {
+			if err == nil {
+				t.Errorf("OnParseError(%q) called with nil error", key)
+			}
+			got = append(got, failure{key, raw, targetType})
+		},
+	})
+	r := httptest.NewRequest("GET", "/?page=abc&limit=10&id=1&id=x&id=&at=yesterday&on=maybe&empty=", nil)
+
+	_ = e.Int(r, "page", 1)
+	_ = e.Int(r, "limit", 25)
+	_ = e.Ints(r, "id", 0)
+	_ = e.Time(r, "at", time.Time{})
+	_ = e.BoolPtr(r, "on")
+	_ = e.IntPtr(r, "empty")
+	_ = e.Int(r, "missing", 0)
+
+	expected := []failure{
+		{"page", "abc", "int"},
+		{"id", "x", "int"},
+		{"at", "yesterday", "time.Time"},
+		{"on", "maybe", "bool"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("OnParseError calls = %+v, want %+v", got, expected)
+	}
+}
+
+func TestExtractorSource(t *testing.T) {
+	e := NewExtractor(Config{
+		Source: func(r *http.Request) url.Values {
+			return url.Values{"page": {"4"}, "tag[]": {"go"}}
+		},
+	})
+	r := httptest.NewRequest("GET", "/?page=2&other=1", nil)
+
+	if got := e.Int(r, "page", 1); got != 4 {
+		t.Errorf("Extractor.Int() = %v, want 4 from Source", got)
+	}
+	if got := e.Strings(r, "tag"); !reflect.DeepEqual(got, []string{"go"}) {
+		t.Errorf("Extractor.Strings() = %v, want [go]", got)
+	}
+	if e.Has(r, "other") {
+		t.Error("Extractor.Has() = true, want the URL query ignored")
+	}
+}
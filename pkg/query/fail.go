@@ -0,0 +1,162 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+var (
+	// ErrMissing is reported by the OrFail functions when a required parameter
+	// is missing or empty.
+	ErrMissing = errors.New("missing required parameter")
+
+	// ErrTooManyValues is reported when a parameter is repeated more often than
+	// Config.MaxValueCount allows.
+	ErrTooManyValues = errors.New("too many values")
+
+	// ErrValueTooLong is reported when a value exceeds Config.MaxValueLength.
+	ErrValueTooLong = errors.New("value too long")
+
+	// ErrNotFinite is reported when a float parameter is "NaN" or "Inf" and the
+	// Extractor does not set Config.AllowNonFinite.
+	ErrNotFinite = errors.New("not a finite number")
+)
+
+// ParamError describes a query parameter that was missing or could not be parsed.
+type ParamError struct {
+	Key   string // Query parameter name
+	Value string // Raw value, empty if the parameter was missing or rejected by a limit
+	Err   error  // ErrMissing, ErrTooManyValues, ErrValueTooLong or the parser's error
+}
+
+func (e *ParamError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("query: %s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("query: %s=%q: %v", e.Key, e.Value, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorHandler writes the response for a parameter that failed extraction in
+// one of the OrFail functions. err is always a *ParamError.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// DefaultErrorHandler responds with 400 Bad Request and the error message as
+// plain text. It is used by the package-level OrFail functions and by
+// Extractors whose Config has no ErrorHandler.
+func DefaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// ValueOrFail extracts a required parameter and converts it using parser. If the
+// parameter is missing, empty or invalid, a 400 Bad Request response is written
+// and ok is false, in which case the handler should return immediately.
+//
+// Example:
+//
+//	// URL: /orders?status=shipped
+//	status, ok := query.ValueOrFail(w, r, "status", ParseStatus)
+//	if !ok {
+//	    return
+//	}
+func ValueOrFail[T any](w http.ResponseWriter, r *http.Request, key string, parser Parser[T]) (T, bool) {
+	return ValueOrFailWith(std, w, r, key, parser)
+}
+
+// ValueOrFailWith is ValueOrFail using the policy of e, including its
+// ErrorHandler.
+func ValueOrFailWith[T any](e *Extractor, w http.ResponseWriter, r *http.Request, key string, parser Parser[T]) (T, bool) {
+	parser = resolveParser(parser)
+	var zero T
+
+	vals, err := e.lookupErr(e.values(r), key)
+	if err != nil {
+		e.fail(w, r, &ParamError{Key: key, Err: err})
+		return zero, false
+	}
+	if len(vals) == 0 || vals[0] == "" {
+		e.fail(w, r, &ParamError{Key: key, Err: ErrMissing})
+		return zero, false
+	}
+
+	parsed, err := parser(vals[0])
+	if err != nil {
+		e.fail(w, r, &ParamError{Key: key, Value: vals[0], Err: err})
+		return zero, false
+	}
+	return parsed, true
+}
+
+// StringOrFail extracts a required string parameter. See ValueOrFail.
+func StringOrFail(w http.ResponseWriter, r *http.Request, key string) (string, bool) {
+	return std.StringOrFail(w, r, key)
+}
+
+// IntOrFail extracts a required integer parameter. See ValueOrFail.
+//
+// Example:
+//
+//	page, ok := query.IntOrFail(w, r, "page")
+//	if !ok {
+//	    return  // 400 Bad Request already written
+//	}
+func IntOrFail(w http.ResponseWriter, r *http.Request, key string) (int, bool) {
+	return std.IntOrFail(w, r, key)
+}
+
+// Int64OrFail extracts a required int64 parameter. See ValueOrFail.
+func Int64OrFail(w http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	return std.Int64OrFail(w, r, key)
+}
+
+// Float64OrFail extracts a required float64 parameter. See ValueOrFail.
+func Float64OrFail(w http.ResponseWriter, r *http.Request, key string) (float64, bool) {
+	return std.Float64OrFail(w, r, key)
+}
+
+// BoolOrFail extracts a required boolean parameter using the same flexible
+// parsing as Bool. See ValueOrFail.
+func BoolOrFail(w http.ResponseWriter, r *http.Request, key string) (bool, bool) {
+	return std.BoolOrFail(w, r, key)
+}
+
+// StringOrFail extracts a required string parameter. See ValueOrFail.
+func (e *Extractor) StringOrFail(w http.ResponseWriter, r *http.Request, key string) (string, bool) {
+	return ValueOrFailWith(e, w, r, key, func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// IntOrFail extracts a required integer parameter. See ValueOrFail.
+func (e *Extractor) IntOrFail(w http.ResponseWriter, r *http.Request, key string) (int, bool) {
+	return ValueOrFailWith(e, w, r, key, numberParser(e, strconv.Atoi))
+}
+
+// Int64OrFail extracts a required int64 parameter. See ValueOrFail.
+func (e *Extractor) Int64OrFail(w http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	return ValueOrFailWith(e, w, r, key, numberParser(e, parseInt64))
+}
+
+// Float64OrFail extracts a required float64 parameter. See ValueOrFail.
+func (e *Extractor) Float64OrFail(w http.ResponseWriter, r *http.Request, key string) (float64, bool) {
+	return ValueOrFailWith(e, w, r, key, e.parseFloat64)
+}
+
+// BoolOrFail extracts a required boolean parameter using the Extractor's
+// vocabulary. See ValueOrFail.
+func (e *Extractor) BoolOrFail(w http.ResponseWriter, r *http.Request, key string) (bool, bool) {
+	return ValueOrFailWith(e, w, r, key, e.parseBool)
+}
+
+func (e *Extractor) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if e.cfg.ErrorHandler != nil {
+		e.cfg.ErrorHandler(w, r, err)
+		return
+	}
+	DefaultErrorHandler(w, r, err)
+}
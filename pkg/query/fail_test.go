@@ -0,0 +1,150 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIntOrFail(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		expected   int
+		expectedOK bool
+		wantBody   string
+	}{
+		{"valid", "/?page=3", 3, true, ""},
+		{"missing", "/", 0, false, `query: page: missing required parameter`},
+		{"empty", "/?page=", 0, false, `query: page: missing required parameter`},
+		{"invalid", "/?page=abc", 0, false, `query: page="abc": strconv.Atoi: parsing "abc": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+
+			got, ok := IntOrFail(w, r, "page")
+			if got != tt.expected || ok != tt.expectedOK {
+				t.Errorf("IntOrFail() = %v, %v, want %v, %v", got, ok, tt.expected, tt.expectedOK)
+			}
+			if tt.expectedOK {
+				if w.Body.Len() != 0 {
+					t.Errorf("IntOrFail() wrote %q on success", w.Body.String())
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("IntOrFail() status = %d, want 400", w.Code)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.wantBody {
+				t.Errorf("IntOrFail() body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestOrFailTyped(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?s=hi&n=9000000000&f=1.5&b=yes", nil)
+	w := httptest.NewRecorder()
+
+	if got, ok := StringOrFail(w, r, "s"); got != "hi" || !ok {
+		t.Errorf("StringOrFail() = %q, %v", got, ok)
+	}
+	if got, ok := Int64OrFail(w, r, "n"); got != 9000000000 || !ok {
+		t.Errorf("Int64OrFail() = %v, %v", got, ok)
+	}
+	if got, ok := Float64OrFail(w, r, "f"); got != 1.5 || !ok {
+		t.Errorf("Float64OrFail() = %v, %v", got, ok)
+	}
+	if got, ok := BoolOrFail(w, r, "b"); !got || !ok {
+		t.Errorf("BoolOrFail() = %v, %v", got, ok)
+	}
+	if got, ok := ValueOrFail(w, r, "n", parseInt64); got != 9000000000 || !ok {
+		t.Errorf("ValueOrFail() = %v, %v", got, ok)
+	}
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("OrFail functions wrote a response on success: %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestOrFailCustomErrorHandler(t *testing.T) {
+	e := NewExtractor(Config{
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			var pe *ParamError
+			if !errors.As(err, &pe) {
+				t.Errorf("ErrorHandler got %T, want *ParamError", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(map[string]string{"parameter": pe.Key})
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?limit=lots", nil)
+	if _, ok := e.IntOrFail(w, r, "limit"); ok {
+		t.Fatal("Extractor.IntOrFail() ok = true, want false")
+	}
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", w.Code)
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != `{"parameter":"limit"}` {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestParamError(t *testing.T) {
+	err := error(&ParamError{Key: "page", Err: ErrMissing})
+	if !errors.Is(err, ErrMissing) {
+		t.Error("ParamError should unwrap to ErrMissing")
+	}
+
+	err = &ParamError{Key: "page", Value: "x", Err: strconv.ErrSyntax}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Error("ParamError should unwrap to the parser error")
+	}
+}
+
+func TestOrFailLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+		body    string
+	}{
+		{"too long", "/?q=abcdef", ErrValueTooLong, "query: q: value too long"},
+		{"too many", "/?q=a&q=b&q=c", ErrTooManyValues, "query: q: too many values"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr error
+			e := NewExtractor(Config{
+				MaxValueLength: 4,
+				MaxValueCount:  2,
+				ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+					gotErr = err
+					DefaultErrorHandler(w, r, err)
+				},
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if _, ok := e.StringOrFail(w, r, "q"); ok {
+				t.Fatal("StringOrFail() ok = true, want false")
+			}
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
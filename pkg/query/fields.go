@@ -0,0 +1,82 @@
+package query
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// FieldSet is the set of fields selected with a sparse-fieldset parameter such
+// as ?fields=id,name. The zero value selects all fields.
+type FieldSet struct {
+	fields   []string
+	selected bool // a selection was made, even if it matched no allowed field
+}
+
+// Fields parses a comma-separated field selection such as ?fields=id,name,created_at.
+// Repeated parameters (?fields=id&fields=name) are combined, surrounding
+// whitespace is ignored and duplicates are dropped. Fields not in allowed are
+// discarded; a nil allowed list accepts any field.
+//
+// When the parameter is missing or empty the returned set selects all fields,
+// as reported by IsAll. A selection consisting only of unknown fields selects
+// none, so the caller can decide whether to reject or ignore the request.
+//
+// Example:
+//
+//	// URL: /users?fields=id,name
+//	fields := query.Fields(r, "fields", []string{"id", "name", "email", "created_at"})
+//	if fields.Has("email") {
+//	    // include the email column
+//	}
+func Fields(r *http.Request, key string, allowed []string) FieldSet {
+	return fieldSetFrom(Strings(r, key), allowed)
+}
+
+func fieldSetFrom(vals []string, allowed []string) FieldSet {
+	var fields []string
+	present := false
+	for _, val := range vals {
+		for _, field := range strings.Split(val, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			present = true
+			if allowed != nil && !slices.Contains(allowed, field) {
+				continue
+			}
+			if !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	return FieldSet{fields: fields, selected: present}
+}
+
+// Has reports whether field is selected. Every field is selected when IsAll is true.
+func (s FieldSet) Has(field string) bool {
+	return s.IsAll() || slices.Contains(s.fields, field)
+}
+
+// IsAll reports whether no selection was made, meaning all fields should be returned.
+func (s FieldSet) IsAll() bool {
+	return !s.selected
+}
+
+// List returns the selected fields in request order, or nil when IsAll is true.
+func (s FieldSet) List() []string {
+	if s.IsAll() {
+		return nil
+	}
+	return slices.Clone(s.fields)
+}
+
+// Len returns the number of selected fields, or 0 when IsAll is true.
+func (s FieldSet) Len() int {
+	if s.IsAll() {
+		return 0
+	}
+	return len(s.fields)
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	allowed := []string{"id", "name", "email", "created_at"}
+
+	tests := []struct {
+		name      string
+		url       string
+		allowed   []string
+		expectAll bool
+		expected  []string
+	}{
+		{"selection", "/?fields=id,name", allowed, false, []string{"id", "name"}},
+		{"whitespace and duplicates", "/?fields=%20id%20,,name,id", allowed, false, []string{"id", "name"}},
+		{"repeated params", "/?fields=id&fields[]=email", allowed, false, []string{"id", "email"}},
+		{"unknown dropped", "/?fields=id,password", allowed, false, []string{"id"}},
+		{"only unknown", "/?fields=password", allowed, false, nil},
+		{"nil allowed accepts any", "/?fields=anything", nil, false, []string{"anything"}},
+		{"missing", "/", allowed, true, nil},
+		{"empty", "/?fields=", allowed, true, nil},
+		{"only commas", "/?fields=,,", allowed, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Fields(r, "fields", tt.allowed)
+			if got.IsAll() != tt.expectAll {
+				t.Errorf("Fields().IsAll() = %v, want %v", got.IsAll(), tt.expectAll)
+			}
+			if list := got.List(); !reflect.DeepEqual(list, tt.expected) && !(len(list) == 0 && len(tt.expected) == 0) {
+				t.Errorf("Fields().List() = %v, want %v", list, tt.expected)
+			}
+			if got.Len() != len(tt.expected) {
+				t.Errorf("Fields().Len() = %d, want %d", got.Len(), len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestFieldSetHas(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?fields=id,name", nil)
+	fields := Fields(r, "fields", nil)
+	if !fields.Has("id") || !fields.Has("name") || fields.Has("email") {
+		t.Errorf("FieldSet.Has() mismatch for %v", fields.List())
+	}
+
+	var all FieldSet
+	if !all.IsAll() || !all.Has("anything") {
+		t.Error("zero FieldSet should select all fields")
+	}
+
+	none := Fields(httptest.NewRequest("GET", "/?fields=secret", nil), "fields", []string{"id"})
+	if none.IsAll() || none.Has("id") {
+		t.Error("selection of only unknown fields should select none")
+	}
+}
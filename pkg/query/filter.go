@@ -0,0 +1,114 @@
+package query
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Op is a comparison operator used in operator-prefixed filter values.
+type Op string
+
+// Operators recognized by Filter and FilterOf.
+const (
+	OpEq    Op = "eq"
+	OpNe    Op = "ne"
+	OpGt    Op = "gt"
+	OpGte   Op = "gte"
+	OpLt    Op = "lt"
+	OpLte   Op = "lte"
+	OpIn    Op = "in"  // comma-separated list
+	OpNotIn Op = "nin" // comma-separated list
+)
+
+// Condition is a parsed filter value such as gte:100. For OpIn and OpNotIn the
+// operands are in Values and Value is the zero value; for every other operator
+// the operand is in Value and Values is nil.
+type Condition[T any] struct {
+	Op     Op
+	Value  T
+	Values []T
+}
+
+// Filter parses an operator-prefixed filter value such as ?created_at=gte:2024-01-01,
+// ?price=lt:100 or ?status=in:open,pending. A value without a recognized
+// operator prefix is an OpEq condition on the whole value, so ?status=open
+// works as expected. If ops are given, only those operators are accepted.
+// ok is false if the key is missing or empty, or the operator is not allowed.
+//
+// Example:
+//
+//	// URL: /orders?status=in:open,pending
+//	if cond, ok := query.Filter(r, "status"); ok {
+//	    // cond.Op == query.OpIn, cond.Values == []string{"open", "pending"}
+//	}
+func Filter(r *http.Request, key string, ops ...Op) (Condition[string], bool) {
+	return FilterOf(r, key, func(s string) (string, error) {
+		return s, nil
+	}, ops...)
+}
+
+// FilterOf is Filter with the operand converted by parser, so the condition is
+// typed. ok is false if the operand, or any list element, fails to parse.
+//
+// Example:
+//
+//	// URL: /events?created_at=gte:2024-01-01
+//	cond, ok := query.FilterOf(r, "created_at", parseDate, query.OpGte, query.OpLt)
+func FilterOf[T any](r *http.Request, key string, parser Parser[T], ops ...Op) (Condition[T], bool) {
+	return parseCondition(String(r, key, ""), parser, ops)
+}
+
+// FiltersOf parses every value of a repeated filter parameter, so ranges can be
+// expressed as ?price=gte:10&price=lt:100. Values that fail to parse or use a
+// disallowed operator are skipped. Returns an empty slice if none are valid.
+func FiltersOf[T any](r *http.Request, key string, parser Parser[T], ops ...Op) []Condition[T] {
+	result := []Condition[T]{}
+	for _, val := range Strings(r, key) {
+		if cond, ok := parseCondition(val, parser, ops); ok {
+			result = append(result, cond)
+		}
+	}
+	return result
+}
+
+func parseCondition[T any](val string, parser Parser[T], ops []Op) (Condition[T], bool) {
+	if val == "" {
+		return Condition[T]{}, false
+	}
+
+	op, operand := OpEq, val
+	if prefix, rest, found := strings.Cut(val, ":"); found && knownOp(Op(prefix)) {
+		op, operand = Op(prefix), rest
+	}
+	if len(ops) > 0 && !slices.Contains(ops, op) {
+		return Condition[T]{}, false
+	}
+
+	if op == OpIn || op == OpNotIn {
+		parts := strings.Split(operand, ",")
+		values := make([]T, 0, len(parts))
+		for _, part := range parts {
+			parsed, err := parser(part)
+			if err != nil {
+				return Condition[T]{}, false
+			}
+			values = append(values, parsed)
+		}
+		return Condition[T]{Op: op, Values: values}, true
+	}
+
+	parsed, err := parser(operand)
+	if err != nil {
+		return Condition[T]{}, false
+	}
+	return Condition[T]{Op: op, Value: parsed}, true
+}
+
+func knownOp(op Op) bool {
+	switch op {
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNotIn:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		ops      []Op
+		expected Condition[string]
+		ok       bool
+	}{
+		{"plain value", "/?status=open", nil, Condition[string]{Op: OpEq, Value: "open"}, true},
+		{"explicit eq", "/?status=eq:open", nil, Condition[string]{Op: OpEq, Value: "open"}, true},
+		{"ne", "/?status=ne:closed", nil, Condition[string]{Op: OpNe, Value: "closed"}, true},
+		{"in", "/?status=in:open,pending", nil, Condition[string]{Op: OpIn, Values: []string{"open", "pending"}}, true},
+		{"nin", "/?status=nin:closed", nil, Condition[string]{Op: OpNotIn, Values: []string{"closed"}}, true},
+		{"unknown prefix is value", "/?status=foo:bar", nil, Condition[string]{Op: OpEq, Value: "foo:bar"}, true},
+		{"time with colons", "/?status=10:30", nil, Condition[string]{Op: OpEq, Value: "10:30"}, true},
+		{"empty operand", "/?status=gt:", nil, Condition[string]{Op: OpGt, Value: ""}, true},
+		{"allowed op", "/?status=in:a", []Op{OpEq, OpIn}, Condition[string]{Op: OpIn, Values: []string{"a"}}, true},
+		{"disallowed op", "/?status=gt:a", []Op{OpEq, OpIn}, Condition[string]{}, false},
+		{"missing", "/", nil, Condition[string]{}, false},
+		{"empty", "/?status=", nil, Condition[string]{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got, ok := Filter(r, "status", tt.ops...)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Filter() = %+v, %v, want %+v, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFilterOf(t *testing.T) {
+	parseDate := func(s string) (time.Time, error) {
+		return time.Parse(time.DateOnly, s)
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected Condition[time.Time]
+		ok       bool
+	}{
+		{"gte", "/?created_at=gte:2024-01-01", Condition[time.Time]{Op: OpGte, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{"lte", "/?created_at=lte:2024-12-31", Condition[time.Time]{Op: OpLte, Value: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)}, true},
+		{"invalid operand", "/?created_at=gte:yesterday", Condition[time.Time]{}, false},
+		{"invalid list element", "/?created_at=in:2024-01-01,nope", Condition[time.Time]{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got, ok := FilterOf(r, "created_at", parseDate)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("FilterOf() = %+v, %v, want %+v, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFiltersOf(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?price=gte:10&price=lt:abc&price=lt:100&price=ne:5", nil)
+
+	got := FiltersOf(r, "price", strconv.Atoi, OpGte, OpLt)
+	expected := []Condition[int]{{Op: OpGte, Value: 10}, {Op: OpLt, Value: 100}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("FiltersOf() = %+v, want %+v", got, expected)
+	}
+
+	if got := FiltersOf(httptest.NewRequest("GET", "/", nil), "price", strconv.Atoi); len(got) != 0 || got == nil {
+		t.Errorf("FiltersOf(missing) = %#v, want empty slice", got)
+	}
+}
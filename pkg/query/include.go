@@ -0,0 +1,116 @@
+package query
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxIncludeDepth is the deepest relationship path, in segments, that
+// Includes accepts unless MaxIncludeDepth is given.
+const DefaultMaxIncludeDepth = 3
+
+// IncludeTree is a tree of requested relationships, keyed by relationship name.
+// For ?include=author,comments.author it holds
+// {"author": {}, "comments": {"author": {}}}. A nil IncludeTree includes nothing.
+type IncludeTree map[string]IncludeTree
+
+// IncludeOption configures Includes.
+type IncludeOption func(*includeConfig)
+
+type includeConfig struct {
+	maxDepth int
+}
+
+// MaxIncludeDepth sets the deepest relationship path, in segments, that
+// Includes accepts. Deeper paths are discarded.
+func MaxIncludeDepth(n int) IncludeOption {
+	return func(c *includeConfig) {
+		c.maxDepth = n
+	}
+}
+
+// Includes parses a comma-separated list of dotted relationship paths, as used
+// for JSON:API compound documents and "expand" parameters, into an IncludeTree.
+// Repeated parameters are combined and surrounding whitespace is ignored.
+//
+// Paths not in allowed are discarded; a nil allowed list accepts any path. Paths
+// deeper than DefaultMaxIncludeDepth (or MaxIncludeDepth) are discarded
+// regardless. Including a nested path implies its parents, so allowing
+// "comments.author" also includes "comments".
+//
+// Example:
+//
+//	// URL: /articles/1?include=author,comments.author
+//	inc := query.Includes(r, "include", []string{"author", "comments", "comments.author"})
+//	if inc.Has("comments.author") {
+//	    // preload comment authors
+//	}
+func Includes(r *http.Request, key string, allowed []string, opts ...IncludeOption) IncludeTree {
+	cfg := includeConfig{maxDepth: DefaultMaxIncludeDepth}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tree := IncludeTree{}
+	for _, val := range Strings(r, key) {
+		for _, path := range strings.Split(val, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" || (allowed != nil && !slices.Contains(allowed, path)) {
+				continue
+			}
+
+			segments := strings.Split(path, ".")
+			if len(segments) > cfg.maxDepth || slices.Contains(segments, "") {
+				continue
+			}
+			tree.add(segments)
+		}
+	}
+	return tree
+}
+
+func (t IncludeTree) add(segments []string) {
+	node := t
+	for _, segment := range segments {
+		child, ok := node[segment]
+		if !ok {
+			child = IncludeTree{}
+			node[segment] = child
+		}
+		node = child
+	}
+}
+
+// Has reports whether the dotted relationship path is included.
+func (t IncludeTree) Has(path string) bool {
+	node := t
+	for _, segment := range strings.Split(path, ".") {
+		child, ok := node[segment]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return true
+}
+
+// Sub returns the relationships included beneath name, or nil if name is not
+// included. This is convenient when loading relationships recursively.
+func (t IncludeTree) Sub(name string) IncludeTree {
+	return t[name]
+}
+
+// Paths returns every included path in dotted form, sorted, with parents
+// listed before their children.
+func (t IncludeTree) Paths() []string {
+	paths := []string{}
+	for name, child := range t {
+		paths = append(paths, name)
+		for _, sub := range child.Paths() {
+			paths = append(paths, name+"."+sub)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestIncludes(t *testing.T) {
+	allowed := []string{"author", "comments", "comments.author", "comments.author.avatar", "a.b.c.d"}
+
+	tests := []struct {
+		name     string
+		url      string
+		allowed  []string
+		opts     []IncludeOption
+		expected []string
+	}{
+		{"simple", "/?include=author", allowed, nil, []string{"author"}},
+		{"nested implies parent", "/?include=comments.author", allowed, nil, []string{"comments", "comments.author"}},
+		{"combined", "/?include=author,%20comments.author&include=comments", allowed, nil, []string{"author", "comments", "comments.author"}},
+		{"not allowed", "/?include=author,secrets", allowed, nil, []string{"author"}},
+		{"nil allowed accepts any", "/?include=x.y", nil, nil, []string{"x", "x.y"}},
+		{"default depth limit", "/?include=a.b.c.d", allowed, nil, []string{}},
+		{"custom depth limit", "/?include=comments.author.avatar", allowed, []IncludeOption{MaxIncludeDepth(2)}, []string{}},
+		{"empty segments", "/?include=comments..author,,", nil, nil, []string{}},
+		{"missing", "/", allowed, nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Includes(r, "include", tt.allowed, tt.opts...).Paths()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Includes().Paths() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIncludeTree(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?include=author,comments.author", nil)
+	inc := Includes(r, "include", nil)
+
+	for path, want := range map[string]bool{
+		"author":          true,
+		"comments":        true,
+		"comments.author": true,
+		"author.comments": false,
+		"tags":            false,
+	} {
+		if got := inc.Has(path); got != want {
+			t.Errorf("IncludeTree.Has(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if sub := inc.Sub("comments"); !sub.Has("author") {
+		t.Errorf("IncludeTree.Sub(comments) = %v, want author included", sub)
+	}
+	if sub := inc.Sub("tags"); sub != nil || sub.Has("x") {
+		t.Errorf("IncludeTree.Sub(tags) = %v, want nil", sub)
+	}
+}
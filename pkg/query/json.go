@@ -0,0 +1,100 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// DefaultMaxJSONSize is the largest JSON parameter, in bytes, that JSON decodes
+// unless MaxJSONSize is given.
+const DefaultMaxJSONSize = 8 << 10
+
+var (
+	// ErrJSONTooLarge is returned by JSON when a parameter exceeds the size limit.
+	ErrJSONTooLarge = errors.New("query: JSON parameter exceeds size limit")
+
+	// ErrInvalidJSONTarget is returned by JSON when dst is not a non-nil pointer.
+	ErrInvalidJSONTarget = errors.New("query: JSON destination must be a non-nil pointer")
+)
+
+// JSONOption configures JSON decoding.
+type JSONOption func(*jsonConfig)
+
+type jsonConfig struct {
+	maxSize               int
+	disallowUnknownFields bool
+}
+
+// MaxJSONSize sets the largest parameter value, in bytes, that JSON will decode.
+func MaxJSONSize(n int) JSONOption {
+	return func(c *jsonConfig) {
+		c.maxSize = n
+	}
+}
+
+// DisallowUnknownFields makes JSON reject objects containing keys that do not
+// match any exported field of the destination struct.
+func DisallowUnknownFields() JSONOption {
+	return func(c *jsonConfig) {
+		c.disallowUnknownFields = true
+	}
+}
+
+// JSON decodes a JSON-encoded query parameter into dst, which must be a non-nil
+// pointer. dst is only modified when decoding succeeds, so values stored in it
+// beforehand act as the default.
+//
+// Returns nil without touching dst if the key is missing or empty. Returns an
+// error, leaving dst untouched, if the value is larger than the size limit
+// (DefaultMaxJSONSize unless MaxJSONSize is given) or is not valid JSON for dst.
+//
+// Example:
+//
+//	// URL: /search?filter={"status":"active","tags":["go"]}
+//	filter := Filter{Status: "all"}  // default
+//	if err := query.JSON(r, "filter", &filter); err != nil {
+//	    http.Error(w, "invalid filter", http.StatusBadRequest)
+//	    return
+//	}
+func JSON(r *http.Request, key string, dst any, opts ...JSONOption) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return ErrInvalidJSONTarget
+	}
+
+	val := first(r.URL.Query(), key)
+	if val == "" {
+		return nil
+	}
+
+	cfg := jsonConfig{maxSize: DefaultMaxJSONSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.maxSize > 0 && len(val) > cfg.maxSize {
+		return ErrJSONTooLarge
+	}
+
+	dec := json.NewDecoder(strings.NewReader(val))
+	if cfg.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	// Decode into a fresh value so a failure part-way through leaves dst intact.
+	decoded := reflect.New(target.Elem().Type())
+	if err := dec.Decode(decoded.Interface()); err != nil {
+		return err
+	}
+	// More reports false before a stray ']' or '}', so read one more token
+	// and require the end of the input instead.
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("query: unexpected data after JSON value")
+	}
+
+	target.Elem().Set(decoded.Elem())
+	return nil
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	type filter struct {
+		Status string   `json:"status"`
+		Tags   []string `json:"tags"`
+	}
+	fallback := filter{Status: "all"}
+
+	tests := []struct {
+		name     string
+		value    string
+		opts     []JSONOption
+		expected filter
+		wantErr  bool
+	}{
+		{"valid object", `{"status":"active","tags":["go","rust"]}`, nil, filter{Status: "active", Tags: []string{"go", "rust"}}, false},
+		{"partial object replaces default", `{"tags":["go"]}`, nil, filter{Tags: []string{"go"}}, false},
+		{"missing key", "", nil, fallback, false},
+		{"invalid json", `{"status":`, nil, fallback, true},
+		{"wrong type", `{"status":42}`, nil, fallback, true},
+		{"trailing data", `{"status":"active"} {}`, nil, fallback, true},
+		{"stray closing brace", `{"status":"active"}}`, nil, fallback, true},
+		{"stray closing bracket", `{"status":"active"}]`, nil, fallback, true},
+		{"trailing whitespace", `{"status":"active"} `, nil, filter{Status: "active"}, false},
+		{"unknown field allowed", `{"status":"active","extra":1}`, nil, filter{Status: "active"}, false},
+		{"unknown field rejected", `{"status":"active","extra":1}`, []JSONOption{DisallowUnknownFields()}, fallback, true},
+		{"too large", `{"status":"` + strings.Repeat("a", 100) + `"}`, []JSONOption{MaxJSONSize(64)}, fallback, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/"
+			if tt.value != "" {
+				target = "/?filter=" + url.QueryEscape(tt.value)
+			}
+			r := httptest.NewRequest("GET", target, nil)
+
+			got := fallback
+			err := JSON(r, "filter", &got, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("JSON() decoded %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?filter="+url.QueryEscape(`{"a":1}`), nil)
+
+	var m map[string]any
+	if err := JSON(r, "filter", m); !errors.Is(err, ErrInvalidJSONTarget) {
+		t.Errorf("JSON(non-pointer) error = %v, want ErrInvalidJSONTarget", err)
+	}
+	if err := JSON(r, "filter", nil); !errors.Is(err, ErrInvalidJSONTarget) {
+		t.Errorf("JSON(nil) error = %v, want ErrInvalidJSONTarget", err)
+	}
+	if err := JSON(r, "filter", &m, MaxJSONSize(3)); !errors.Is(err, ErrJSONTooLarge) {
+		t.Errorf("JSON(too large) error = %v, want ErrJSONTooLarge", err)
+	}
+	if err := JSON(r, "filter", &m); err != nil || m["a"] != 1.0 {
+		t.Errorf("JSON(map) = %v, %v, want map[a:1], nil", m, err)
+	}
+}
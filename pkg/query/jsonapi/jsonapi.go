@@ -0,0 +1,138 @@
+// Package jsonapi parses the query parameters defined by the JSON:API
+// specification (https://jsonapi.org/format/#fetching) into a single Params
+// value: page[number] and page[size], sort, filter[...], fields[TYPE] and
+// include.
+//
+// Like the query package, parsing is fail-safe: unsupported sort fields,
+// sparse fields and include paths are dropped, and invalid page values fall
+// back to their defaults.
+//
+// Example:
+//
+//	var articleParams = jsonapi.Options{
+//	    SortFields: []string{"created", "title"},
+//	    Fields:     map[string][]string{"articles": {"title", "body", "author"}},
+//	    Includes:   []string{"author", "comments", "comments.author"},
+//	}
+//
+//	func listArticles(w http.ResponseWriter, r *http.Request) {
+//	    // URL: /articles?page[number]=2&sort=-created&filter[status]=published&include=author
+//	    p := jsonapi.Parse(r, articleParams)
+//	    articles := store.List(p.Filter["status"], p.Sort, p.Page.Offset(), p.Page.Size)
+//	    // ...
+//	}
+package jsonapi
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+// Default page sizes used when Options leaves them unset.
+const (
+	DefaultPageSize    = 20
+	DefaultMaxPageSize = 100
+)
+
+// Options describes what an endpoint supports. The zero value accepts any sort
+// field, sparse field and include path, with the default page sizes.
+type Options struct {
+	// DefaultPageSize is used when page[size] is missing or invalid.
+	// Defaults to DefaultPageSize.
+	DefaultPageSize int
+
+	// MaxPageSize is the largest page[size] accepted; larger values are clamped.
+	// Defaults to DefaultMaxPageSize.
+	MaxPageSize int
+
+	// SortFields lists the fields that may be sorted by. Nil accepts any field.
+	SortFields []string
+
+	// Fields lists, per resource type, the fields that may be requested with
+	// fields[TYPE]. Nil accepts any type and field; when non-nil, types that are
+	// not listed are ignored.
+	Fields map[string][]string
+
+	// Includes lists the relationship paths that may be requested with include.
+	// Nil accepts any path.
+	Includes []string
+
+	// MaxIncludeDepth limits the depth of include paths.
+	// Defaults to query.DefaultMaxIncludeDepth.
+	MaxIncludeDepth int
+}
+
+// Page holds the page[number] and page[size] pagination parameters.
+type Page struct {
+	Number int // 1-based page number
+	Size   int
+}
+
+// Offset returns the number of items before the page, for use with
+// offset-based storage queries.
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Params holds the parsed JSON:API query parameters of a request.
+type Params struct {
+	Page    Page
+	Sort    []query.SortField
+	Filter  map[string]string         // filter[KEY] values, keyed by KEY
+	Fields  map[string]query.FieldSet // sparse fieldsets, keyed by resource type
+	Include query.IncludeTree
+}
+
+// FieldsFor returns the sparse fieldset requested for a resource type. When no
+// fields[TYPE] parameter was sent, the returned set selects all fields.
+func (p Params) FieldsFor(resourceType string) query.FieldSet {
+	return p.Fields[resourceType]
+}
+
+// Parse extracts the JSON:API query parameters from r according to opts.
+func Parse(r *http.Request, opts Options) Params {
+	defaultSize := opts.DefaultPageSize
+	if defaultSize <= 0 {
+		defaultSize = DefaultPageSize
+	}
+	maxSize := opts.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPageSize
+	}
+	var includeOpts []query.IncludeOption
+	if opts.MaxIncludeDepth > 0 {
+		includeOpts = append(includeOpts, query.MaxIncludeDepth(opts.MaxIncludeDepth))
+	}
+
+	return Params{
+		Page: Page{
+			Number: query.IntInRange(r, "page[number]", 1, 1, math.MaxInt),
+			Size:   query.IntInRange(r, "page[size]", defaultSize, 1, maxSize),
+		},
+		Sort:    query.Sort(r, "sort", opts.SortFields),
+		Filter:  query.Map(r, "filter"),
+		Fields:  parseFields(r, opts.Fields),
+		Include: query.Includes(r, "include", opts.Includes, includeOpts...),
+	}
+}
+
+func parseFields(r *http.Request, allowed map[string][]string) map[string]query.FieldSet {
+	result := make(map[string]query.FieldSet)
+	for resourceType := range query.Map(r, "fields") {
+		var allowedFields []string
+		if allowed != nil {
+			var ok bool
+			if allowedFields, ok = allowed[resourceType]; !ok {
+				continue
+			}
+		}
+
+		fields := query.Fields(r, "fields["+resourceType+"]", allowedFields)
+		if !fields.IsAll() {
+			result[resourceType] = fields
+		}
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package jsonapi_test
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+	"github.com/mallardduck/go-http-helpers/pkg/query/jsonapi"
+)
+
+func TestParse(t *testing.T) {
+	opts := jsonapi.Options{
+		SortFields: []string{"created", "title"},
+		Fields: map[string][]string{
+			"articles": {"title", "body"},
+			"people":   {"name"},
+		},
+		Includes: []string{"author", "comments", "comments.author"},
+	}
+
+	target := "/articles?" + url.Values{
+		"page[number]":     {"3"},
+		"page[size]":       {"10"},
+		"sort":             {"-created,secret,title"},
+		"filter[status]":   {"published"},
+		"filter[author]":   {"42"},
+		"fields[articles]": {"title,password"},
+		"fields[people]":   {"name"},
+		"fields[secrets]":  {"value"},
+		"include":          {"author,comments.author,tags"},
+	}.Encode()
+	r := httptest.NewRequest("GET", target, nil)
+
+	p := jsonapi.Parse(r, opts)
+
+	if p.Page != (jsonapi.Page{Number: 3, Size: 10}) {
+		t.Errorf("Page = %+v, want {3 10}", p.Page)
+	}
+	if p.Page.Offset() != 20 {
+		t.Errorf("Page.Offset() = %d, want 20", p.Page.Offset())
+	}
+
+	wantSort := []query.SortField{{Field: "created", Desc: true}, {Field: "title"}}
+	if !reflect.DeepEqual(p.Sort, wantSort) {
+		t.Errorf("Sort = %v, want %v", p.Sort, wantSort)
+	}
+
+	wantFilter := map[string]string{"status": "published", "author": "42"}
+	if !reflect.DeepEqual(p.Filter, wantFilter) {
+		t.Errorf("Filter = %v, want %v", p.Filter, wantFilter)
+	}
+
+	if got := p.FieldsFor("articles").List(); !reflect.DeepEqual(got, []string{"title"}) {
+		t.Errorf("FieldsFor(articles) = %v, want [title]", got)
+	}
+	if got := p.FieldsFor("people").List(); !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("FieldsFor(people) = %v, want [name]", got)
+	}
+	if _, ok := p.Fields["secrets"]; ok {
+		t.Error("Fields should ignore types not listed in Options.Fields")
+	}
+	if !p.FieldsFor("comments").IsAll() {
+		t.Error("FieldsFor(comments) should select all fields when not requested")
+	}
+
+	wantInclude := []string{"author", "comments", "comments.author"}
+	if got := p.Include.Paths(); !reflect.DeepEqual(got, wantInclude) {
+		t.Errorf("Include = %v, want %v", got, wantInclude)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		opts jsonapi.Options
+		want jsonapi.Page
+	}{
+		{"no params", "/", jsonapi.Options{}, jsonapi.Page{Number: 1, Size: jsonapi.DefaultPageSize}},
+		{"custom default size", "/", jsonapi.Options{DefaultPageSize: 5}, jsonapi.Page{Number: 1, Size: 5}},
+		{"size clamped", "/?page[size]=1000", jsonapi.Options{}, jsonapi.Page{Number: 1, Size: jsonapi.DefaultMaxPageSize}},
+		{"custom max size", "/?page[size]=60", jsonapi.Options{MaxPageSize: 50}, jsonapi.Page{Number: 1, Size: 50}},
+		{"invalid number", "/?page[number]=abc&page[size]=0", jsonapi.Options{}, jsonapi.Page{Number: 1, Size: 1}},
+		{"negative number", "/?page[number]=-4", jsonapi.Options{}, jsonapi.Page{Number: 1, Size: jsonapi.DefaultPageSize}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			p := jsonapi.Parse(r, tt.opts)
+			if p.Page != tt.want {
+				t.Errorf("Page = %+v, want %+v", p.Page, tt.want)
+			}
+			if len(p.Sort) != 0 || len(p.Filter) != 0 || len(p.Fields) != 0 || len(p.Include) != 0 {
+				t.Errorf("Parse() = %+v, want empty sort, filter, fields and include", p)
+			}
+		})
+	}
+}
+
+func TestParseZeroOptionsAcceptsAnything(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?sort=-anything&fields[widgets]=a,b&include=x.y", nil)
+	p := jsonapi.Parse(r, jsonapi.Options{})
+
+	if !reflect.DeepEqual(p.Sort, []query.SortField{{Field: "anything", Desc: true}}) {
+		t.Errorf("Sort = %v", p.Sort)
+	}
+	if got := p.FieldsFor("widgets").List(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("FieldsFor(widgets) = %v, want [a b]", got)
+	}
+	if !p.Include.Has("x.y") {
+		t.Errorf("Include = %v, want x.y", p.Include.Paths())
+	}
+}
+
+func TestParseMaxIncludeDepth(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?include=a,a.b", nil)
+	p := jsonapi.Parse(r, jsonapi.Options{MaxIncludeDepth: 1})
+	if got := p.Include.Paths(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Include = %v, want [a]", got)
+	}
+}
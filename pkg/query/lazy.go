@@ -0,0 +1,60 @@
+package query
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ValueOr is like Value, but the default is produced by calling defaultFunc,
+// which only happens when the key is missing, empty, or cannot be parsed.
+// Use it when computing the default is expensive or time-dependent.
+//
+// Example:
+//
+//	// The tenant lookup only runs when ?limit is absent or invalid
+//	limit := query.ValueOr(r, "limit", func() int { return tenant.DefaultLimit(ctx) }, strconv.Atoi)
+func ValueOr[T any](r *http.Request, key string, defaultFunc func() T, parser Parser[T]) T {
+	parser = resolveParser(parser)
+	val := first(r.URL.Query(), key)
+	if val == "" {
+		return defaultFunc()
+	}
+
+	parsed, err := parser(val)
+	if err != nil {
+		return defaultFunc()
+	}
+	return parsed
+}
+
+// StringOr extracts a string value, calling defaultFunc only if the key is
+// missing or empty.
+func StringOr(r *http.Request, key string, defaultFunc func() string) string {
+	return ValueOr(r, key, defaultFunc, func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// IntOr extracts an integer value, calling defaultFunc only if the key is
+// missing, empty, or cannot be parsed as an int.
+func IntOr(r *http.Request, key string, defaultFunc func() int) int {
+	return ValueOr(r, key, defaultFunc, strconv.Atoi)
+}
+
+// Int64Or extracts an int64 value, calling defaultFunc only if the key is
+// missing, empty, or cannot be parsed as an int64.
+func Int64Or(r *http.Request, key string, defaultFunc func() int64) int64 {
+	return ValueOr(r, key, defaultFunc, parseInt64)
+}
+
+// Float64Or extracts a float64 value, calling defaultFunc only if the key is
+// missing, empty, or cannot be parsed as a float64.
+func Float64Or(r *http.Request, key string, defaultFunc func() float64) float64 {
+	return ValueOr(r, key, defaultFunc, parseFloat64)
+}
+
+// BoolOr extracts a boolean value, calling defaultFunc only if the key is
+// missing, empty, or cannot be parsed as a bool.
+func BoolOr(r *http.Request, key string, defaultFunc func() bool) bool {
+	return ValueOr(r, key, defaultFunc, parseBool)
+}
@@ -0,0 +1,61 @@
+package query
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIntOr(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		expected      int
+		expectedCalls int
+	}{
+		{"present value", "/?limit=50", 50, 0},
+		{"missing key", "/", 25, 1},
+		{"empty value", "/?limit=", 25, 1},
+		{"invalid value", "/?limit=abc", 25, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			calls := 0
+			got := IntOr(r, "limit", func() int {
+				calls++
+				return 25
+			})
+			if got != tt.expected {
+				t.Errorf("IntOr() = %d, want %d", got, tt.expected)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("IntOr() called defaultFunc %d times, want %d", calls, tt.expectedCalls)
+			}
+		})
+	}
+}
+
+func TestTypedOrVariants(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?s=hi&i=9000000000&f=1.5&b=yes", nil)
+	mustNotCall := func(t *testing.T) { t.Helper(); t.Error("defaultFunc called for present value") }
+
+	if got := StringOr(r, "s", func() string { mustNotCall(t); return "" }); got != "hi" {
+		t.Errorf("StringOr() = %q, want %q", got, "hi")
+	}
+	if got := StringOr(r, "missing", func() string { return "fallback" }); got != "fallback" {
+		t.Errorf("StringOr() = %q, want %q", got, "fallback")
+	}
+	if got := Int64Or(r, "i", func() int64 { mustNotCall(t); return 0 }); got != 9000000000 {
+		t.Errorf("Int64Or() = %d, want 9000000000", got)
+	}
+	if got := Float64Or(r, "f", func() float64 { mustNotCall(t); return 0 }); got != 1.5 {
+		t.Errorf("Float64Or() = %f, want 1.5", got)
+	}
+	if got := BoolOr(r, "b", func() bool { mustNotCall(t); return false }); !got {
+		t.Error("BoolOr() = false, want true")
+	}
+	if got := BoolOr(r, "s", func() bool { return true }); !got {
+		t.Error("BoolOr() with invalid value = false, want default true")
+	}
+}
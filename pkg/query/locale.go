@@ -0,0 +1,208 @@
+package query
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Locale extracts a BCP 47 language tag from the query parameter with the
+// given key, such as ?lang=pt-BR. The value is checked against the tag syntax
+// of RFC 5646 and returned with canonical casing ("pt-br" becomes "pt-BR",
+// "zh-hant-tw" becomes "zh-Hant-TW"). Underscores are accepted as separators,
+// so POSIX-style values like "en_US" resolve too.
+//
+// If allowed tags are given, the result is the best allowed match: the exact
+// tag, else the longest allowed prefix of it ("de-CH-1996" matches "de-CH",
+// then "de"), else the first allowed tag with the same primary language ("pt-PT"
+// matches "pt-BR"). The matching entry of allowed is returned as written.
+// Returns defaultValue if the key is missing, empty, malformed, or matches no
+// allowed tag.
+//
+// Only the syntax is validated; whether a subtag is registered is not checked.
+//
+// Example:
+//
+//	// URL: /docs?lang=pt-br
+//	lang := query.Locale(r, "lang", "en", "en", "de", "pt-BR")
+//	// lang = "pt-BR"
+func Locale(r *http.Request, key string, defaultValue string, allowed ...string) string {
+	return Value(r, key, defaultValue, func(s string) (string, error) {
+		tag, ok := canonicalLocale(s)
+		if !ok {
+			return "", strconv.ErrSyntax
+		}
+		if len(allowed) == 0 {
+			return tag, nil
+		}
+		if match, ok := matchLocale(tag, allowed); ok {
+			return match, nil
+		}
+		return "", strconv.ErrSyntax
+	})
+}
+
+// matchLocale returns the entry of allowed that best matches the canonical tag,
+// using RFC 4647 lookup and then falling back to the primary language.
+func matchLocale(tag string, allowed []string) (string, bool) {
+	canonical := make([]string, len(allowed))
+	for i, a := range allowed {
+		canonical[i], _ = canonicalLocale(a)
+	}
+
+	for prefix := tag; prefix != ""; prefix = truncateLocale(prefix) {
+		for i, c := range canonical {
+			if c == prefix {
+				return allowed[i], true
+			}
+		}
+	}
+
+	language, _, _ := strings.Cut(tag, "-")
+	for i, c := range canonical {
+		if l, _, _ := strings.Cut(c, "-"); l == language && c != "" {
+			return allowed[i], true
+		}
+	}
+	return "", false
+}
+
+// truncateLocale removes the last subtag of tag, along with a single-character
+// subtag left dangling at the end, as described by RFC 4647 section 3.4.
+func truncateLocale(tag string) string {
+	i := strings.LastIndexByte(tag, '-')
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if j := strings.LastIndexByte(tag, '-'); j >= 0 && j == len(tag)-2 {
+		tag = tag[:j]
+	}
+	return tag
+}
+
+// canonicalLocale validates s as a BCP 47 language tag and returns it with
+// canonical casing: language and variants lowercase, script in title case and
+// region uppercase.
+func canonicalLocale(s string) (string, bool) {
+	subtags := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' })
+	if len(subtags) == 0 || strings.Count(s, "-")+strings.Count(s, "_") != len(subtags)-1 {
+		return "", false
+	}
+	for i, st := range subtags {
+		if len(st) > 8 || !isAlnum(st) {
+			return "", false
+		}
+		subtags[i] = strings.ToLower(st)
+	}
+
+	i := 0
+	if subtags[0] == "x" {
+		// Private use only, e.g. "x-whatever".
+		return strings.Join(subtags, "-"), len(subtags) > 1
+	}
+
+	// language, then up to three extlang subtags for 2-3 letter languages
+	lang := subtags[i]
+	if !isAlpha(lang) || len(lang) < 2 || len(lang) == 4 {
+		return "", false
+	}
+	i++
+	if len(lang) <= 3 {
+		for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); n++ {
+			i++
+		}
+	}
+
+	// script
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		subtags[i] = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+		i++
+	}
+
+	// region
+	if i < len(subtags) {
+		switch st := subtags[i]; {
+		case len(st) == 2 && isAlpha(st):
+			subtags[i] = strings.ToUpper(st)
+			i++
+		case len(st) == 3 && isDigits(st):
+			i++
+		}
+	}
+
+	// variants
+	var variants []string
+	for i < len(subtags) {
+		st := subtags[i]
+		if !(len(st) >= 5 || (len(st) == 4 && isDigits(st[:1]))) {
+			break
+		}
+		if slices.Contains(variants, st) {
+			return "", false
+		}
+		variants = append(variants, st)
+		i++
+	}
+
+	// extensions, each a singleton followed by at least one 2-8 character subtag
+	var singletons []string
+	for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+		if slices.Contains(singletons, subtags[i]) {
+			return "", false
+		}
+		singletons = append(singletons, subtags[i])
+		i++
+
+		start := i
+		for i < len(subtags) && len(subtags[i]) >= 2 {
+			i++
+		}
+		if i == start {
+			return "", false
+		}
+	}
+
+	// private use
+	if i < len(subtags) && subtags[i] == "x" {
+		if i == len(subtags)-1 {
+			return "", false
+		}
+		i = len(subtags)
+	}
+
+	if i != len(subtags) {
+		return "", false
+	}
+	return strings.Join(subtags, "-"), true
+}
+
+func isAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		allowed  []string
+		expected string
+	}{
+		{"missing", "", nil, "en"},
+		{"language", "DE", nil, "de"},
+		{"language region", "pt-br", nil, "pt-BR"},
+		{"underscore separator", "en_us", nil, "en-US"},
+		{"script and region", "zh-hant-tw", nil, "zh-Hant-TW"},
+		{"numeric region", "es-419", nil, "es-419"},
+		{"variant", "de-ch-1996", nil, "de-CH-1996"},
+		{"extension", "en-US-u-CA-gregory", nil, "en-US-u-ca-gregory"},
+		{"private use", "en-x-Pirate", nil, "en-x-pirate"},
+		{"private use only", "x-klingon", nil, "x-klingon"},
+		{"extlang", "zh-yue-hk", nil, "zh-yue-HK"},
+
+		{"empty subtag", "en--US", nil, "en"},
+		{"trailing separator", "en-", nil, "en"},
+		{"subtag too long", "en-abcdefghi", nil, "en"},
+		{"invalid character", "en-U$", nil, "en"},
+		{"four letter language", "abcd", nil, "en"},
+		{"single letter language", "e", nil, "en"},
+		{"numeric language", "12", nil, "en"},
+		{"dangling extension", "en-u", nil, "en"},
+		{"duplicate variant", "sl-rozaj-rozaj", nil, "en"},
+		{"duplicate extension", "en-a-bbb-a-ccc", nil, "en"},
+		{"empty private use", "en-x", nil, "en"},
+		{"path traversal", "../etc/passwd", nil, "en"},
+
+		{"allowed exact", "pt-br", []string{"en", "pt-BR"}, "pt-BR"},
+		{"allowed case-insensitive", "DE", []string{"en", "de"}, "de"},
+		{"allowed returned as written", "pt-BR", []string{"en", "pt_br"}, "pt_br"},
+		{"allowed prefix", "de-CH-1996", []string{"en", "de-CH", "de"}, "de-CH"},
+		{"allowed shorter prefix", "zh-Hant-TW", []string{"zh", "zh-Hans"}, "zh"},
+		{"allowed same language", "pt-PT", []string{"en", "pt-BR"}, "pt-BR"},
+		{"allowed no match", "fr", []string{"en", "de"}, "en"},
+		{"invalid allowed entry ignored", "fr", []string{"--", "fr-CA"}, "fr-CA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/"
+			if tt.value != "" {
+				target = "/?lang=" + url.QueryEscape(tt.value)
+			}
+			r := httptest.NewRequest("GET", target, nil)
+			if got := Locale(r, "lang", "en", tt.allowed...); got != tt.expected {
+				t.Errorf("Locale(%q) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
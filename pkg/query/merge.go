@@ -0,0 +1,104 @@
+package query
+
+import (
+	"net/url"
+	"slices"
+)
+
+// MergePolicy decides how Merge combines a key present in both base and
+// override.
+type MergePolicy int
+
+const (
+	// MergeReplace replaces the values in base with those in override. An
+	// override key with no values removes the key, so
+	// url.Values{"page": nil} drops pagination from the result.
+	MergeReplace MergePolicy = iota
+
+	// MergeAppend adds the values in override after those in base.
+	MergeAppend
+
+	// MergeKeep keeps the values in base and only adds keys base lacks.
+	MergeKeep
+)
+
+// Merge returns a new url.Values combining base and override according to
+// policy. Neither argument is modified. It is meant for building "change one
+// parameter, keep the rest" links, such as switching the sort order of a
+// listing while preserving its filters.
+//
+// Example:
+//
+//	// URL: /products?color=red&sort=price&page=3
+//	next := query.Merge(r.URL.Query(), url.Values{
+//	    "sort": {"-price"},
+//	    "page": nil,  // back to the first page
+//	}, query.MergeReplace)
+//	link := "/products?" + next.Encode()  // color=red&sort=-price
+func Merge(base, override url.Values, policy MergePolicy) url.Values {
+	result := make(url.Values, len(base)+len(override))
+	for k, vals := range base {
+		result[k] = slices.Clone(vals)
+	}
+
+	for k, vals := range override {
+		existing, ok := result[k]
+		switch {
+		case !ok:
+			if len(vals) > 0 {
+				result[k] = slices.Clone(vals)
+			}
+		case policy == MergeAppend:
+			result[k] = append(existing, vals...)
+		case policy == MergeKeep:
+		case len(vals) == 0:
+			delete(result, k)
+		default:
+			result[k] = slices.Clone(vals)
+		}
+	}
+	return result
+}
+
+// ValuesDiff describes how one set of query values differs from another.
+type ValuesDiff struct {
+	Added   url.Values // Keys only in the second set, with their values
+	Removed url.Values // Keys only in the first set, with their values
+	Changed url.Values // Keys in both whose values differ, with the second set's values
+}
+
+// Empty reports whether the two sets of values were equal.
+func (d ValuesDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares a with b. Values are compared in order, so ?tag=a&tag=b and
+// ?tag=b&tag=a differ; a key with no values counts as absent.
+//
+// Example:
+//
+//	// Only carry parameters that differ from the listing defaults
+//	d := query.Diff(defaults, r.URL.Query())
+//	link := "/products?" + query.Merge(d.Added, d.Changed, query.MergeReplace).Encode()
+func Diff(a, b url.Values) ValuesDiff {
+	d := ValuesDiff{Added: url.Values{}, Removed: url.Values{}, Changed: url.Values{}}
+
+	for k, vals := range a {
+		if len(vals) == 0 {
+			continue
+		}
+		other := b[k]
+		switch {
+		case len(other) == 0:
+			d.Removed[k] = slices.Clone(vals)
+		case !slices.Equal(vals, other):
+			d.Changed[k] = slices.Clone(other)
+		}
+	}
+	for k, vals := range b {
+		if len(vals) > 0 && len(a[k]) == 0 {
+			d.Added[k] = slices.Clone(vals)
+		}
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := url.Values{"color": {"red"}, "sort": {"price"}, "page": {"3"}, "tag": {"a"}}
+
+	tests := []struct {
+		name     string
+		override url.Values
+		policy   MergePolicy
+		expected url.Values
+	}{
+		{"replace", url.Values{"sort": {"-price"}, "tag": {"b", "c"}}, MergeReplace,
+			url.Values{"color": {"red"}, "sort": {"-price"}, "page": {"3"}, "tag": {"b", "c"}}},
+		{"replace removes empty", url.Values{"page": nil, "size": {"50"}}, MergeReplace,
+			url.Values{"color": {"red"}, "sort": {"price"}, "tag": {"a"}, "size": {"50"}}},
+		{"append", url.Values{"tag": {"b"}, "size": {"50"}}, MergeAppend,
+			url.Values{"color": {"red"}, "sort": {"price"}, "page": {"3"}, "tag": {"a", "b"}, "size": {"50"}}},
+		{"keep", url.Values{"sort": {"name"}, "size": {"50"}, "q": nil}, MergeKeep,
+			url.Values{"color": {"red"}, "sort": {"price"}, "page": {"3"}, "tag": {"a"}, "size": {"50"}}},
+		{"nil override", nil, MergeReplace, base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Merge(base, tt.override, tt.policy); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Merge() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeDoesNotModifyInputs(t *testing.T) {
+	base := url.Values{"tag": {"a"}}
+	override := url.Values{"tag": {"b"}}
+
+	got := Merge(base, override, MergeAppend)
+	got["tag"][0] = "changed"
+	got.Add("new", "1")
+
+	if !reflect.DeepEqual(base, url.Values{"tag": {"a"}}) || !reflect.DeepEqual(override, url.Values{"tag": {"b"}}) {
+		t.Errorf("Merge() modified its inputs: base = %v, override = %v", base, override)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := url.Values{"color": {"red"}, "sort": {"price"}, "tag": {"a", "b"}, "empty": nil}
+	b := url.Values{"color": {"red"}, "sort": {"-price"}, "tag": {"b", "a"}, "page": {"2"}}
+
+	got := Diff(a, b)
+	expected := ValuesDiff{
+		Added:   url.Values{"page": {"2"}},
+		Removed: url.Values{},
+		Changed: url.Values{"sort": {"-price"}, "tag": {"b", "a"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Diff() = %+v, want %+v", got, expected)
+	}
+	if got.Empty() {
+		t.Error("Diff().Empty() = true, want false")
+	}
+
+	back := Diff(b, a)
+	if !reflect.DeepEqual(back.Removed, url.Values{"page": {"2"}}) {
+		t.Errorf("Diff() removed = %v, want page", back.Removed)
+	}
+	if !Diff(a, a).Empty() {
+		t.Error("Diff(a, a).Empty() = false, want true")
+	}
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// numberParser wraps parser so that values are normalized according to the
+// Extractor's DigitSeparators and DecimalComma settings before parsing.
+func numberParser[T any](e *Extractor, parser Parser[T]) Parser[T] {
+	if e.cfg.DigitSeparators == "" && !e.cfg.DecimalComma {
+		return parser
+	}
+	return func(s string) (T, error) {
+		return parser(e.normalizeNumber(s))
+	}
+}
+
+// parseFloat64 parses a float64 according to the Extractor's number settings.
+func (e *Extractor) parseFloat64(s string) (float64, error) {
+	return e.parseFloat(s, 64)
+}
+
+// parseFloat32 parses a float32 according to the Extractor's number settings.
+func (e *Extractor) parseFloat32(s string) (float32, error) {
+	f, err := e.parseFloat(s, 32)
+	return float32(f), err
+}
+
+func (e *Extractor) parseFloat(s string, bitSize int) (float64, error) {
+	s = e.normalizeNumber(s)
+	if e.cfg.AllowNonFinite {
+		return strconv.ParseFloat(s, bitSize)
+	}
+	return parseFinite(s, bitSize)
+}
+
+// normalizeNumber removes digit separators from s and replaces a decimal comma
+// with a point. Separators are only removed between two digits, so "1,,000"
+// and ",5" are left for the parser to reject.
+func (e *Extractor) normalizeNumber(s string) string {
+	if e.cfg.DigitSeparators == "" && !e.cfg.DecimalComma {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	prev := rune(-1)
+	for i, c := range s {
+		switch {
+		case c == ',' && e.cfg.DecimalComma:
+			c = '.'
+		case strings.ContainsRune(e.cfg.DigitSeparators, c):
+			next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(c):])
+			if isDigit(prev) && isDigit(next) {
+				prev = c
+				continue
+			}
+		}
+		b.WriteRune(c)
+		prev = c
+	}
+	return b.String()
+}
+
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,148 @@
+package query
+
+import (
+	"errors"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExtractorDigitSeparators(t *testing.T) {
+	e := NewExtractor(Config{DigitSeparators: ",_"})
+
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{"plain", "/?n=1000", 1000},
+		{"comma", "/?n=1,000,000", 1000000},
+		{"underscore", "/?n=1_000", 1000},
+		{"indian grouping", "/?n=1,00,000", 100000},
+		{"negative", "/?n=-2,500", -2500},
+		{"doubled separator", "/?n=1,,000", -1},
+		{"leading separator", "/?n=,100", -1},
+		{"trailing separator", "/?n=100,", -1},
+		{"unlisted separator", "/?n=1.000", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := e.Int(r, "n", -1); got != tt.expected {
+				t.Errorf("Extractor.Int() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractorDecimalComma(t *testing.T) {
+	e := NewExtractor(Config{DigitSeparators: ". ", DecimalComma: true})
+
+	tests := []struct {
+		name     string
+		url      string
+		expected float64
+	}{
+		{"comma decimal", "/?x=0,5", 0.5},
+		{"grouped", "/?x=1.234.567,25", 1234567.25},
+		{"space grouped", "/?x=12+345,5", 12345.5},
+		{"integer", "/?x=42", 42},
+		{"two decimal marks", "/?x=1,2,3", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := e.Float64(r, "x", -1); got != tt.expected {
+				t.Errorf("Extractor.Float64() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	r := httptest.NewRequest("GET", "/?n=1.500", nil)
+	if got := e.Int(r, "n", -1); got != 1500 {
+		t.Errorf("Extractor.Int() = %d, want 1500", got)
+	}
+}
+
+func TestDigitSeparatorsDefaultOff(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?n=1,000&x=0,5", nil)
+	if got := Int(r, "n", -1); got != -1 {
+		t.Errorf("Int() = %d, want -1", got)
+	}
+	if got := Float64(r, "x", -1); got != -1 {
+		t.Errorf("Float64() = %v, want -1", got)
+	}
+}
+
+func TestBindDigitSeparators(t *testing.T) {
+	e := NewExtractor(Config{DigitSeparators: ","})
+	r := httptest.NewRequest("GET", "/?min=10,000&max=2,500,000&ids=1,000&ids=2,000", nil)
+
+	var params struct {
+		Min uint32  `query:"min"`
+		Max float64 `query:"max,max=3000000"`
+		IDs []int   `query:"ids"`
+	}
+	if err := e.Bind(r, &params); err != nil {
+		t.Fatalf("Extractor.Bind() error = %v", err)
+	}
+	if params.Min != 10000 || params.Max != 2500000 || len(params.IDs) != 2 || params.IDs[1] != 2000 {
+		t.Errorf("Extractor.Bind() = %+v", params)
+	}
+}
+
+func TestFloatNonFinite(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"NaN", "NaN"},
+		{"Inf", "Inf"},
+		{"negative infinity", "-Infinity"},
+		{"lowercase", "inf"},
+	}
+
+	allow := NewExtractor(Config{AllowNonFinite: true})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?x="+tt.value+"&x=1.5", nil)
+			if got := Float64(r, "x", 7); got != 7 {
+				t.Errorf("Float64() = %v, want default", got)
+			}
+			if got := Float32(r, "x", 7); got != 7 {
+				t.Errorf("Float32() = %v, want default", got)
+			}
+			if got := Float64s(r, "x", 7); got[0] != 7 || got[1] != 1.5 {
+				t.Errorf("Float64s() = %v, want [7 1.5]", got)
+			}
+			if got := allow.Float64(r, "x", 7); got == 7 {
+				t.Errorf("Extractor.Float64() with AllowNonFinite = %v, want %s", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestFloatNonFiniteErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?x=NaN", nil)
+	if _, ok := Float64OrFail(w, r, "x"); ok {
+		t.Fatal("Float64OrFail() ok = true, want false")
+	}
+	if body := w.Body.String(); !strings.Contains(body, ErrNotFinite.Error()) {
+		t.Errorf("Float64OrFail() body = %q, want %q", body, ErrNotFinite.Error())
+	}
+
+	var params struct {
+		Ratio float64 `query:"ratio"`
+	}
+	r = httptest.NewRequest("GET", "/?ratio=Inf", nil)
+	if err := Bind(r, &params); !errors.Is(err, ErrNotFinite) {
+		t.Errorf("Bind() error = %v, want ErrNotFinite", err)
+	}
+	if err := NewExtractor(Config{AllowNonFinite: true}).Bind(r, &params); err != nil || !math.IsInf(params.Ratio, 1) {
+		t.Errorf("Extractor.Bind() = %v, %v, want +Inf", params.Ratio, err)
+	}
+}
@@ -0,0 +1,370 @@
+package odata
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSyntax is reported when $filter is not a valid expression.
+var ErrSyntax = errors.New("syntax error")
+
+// Expr is a node of a parsed $filter expression: *Comparison, *Logical or *Not.
+type Expr interface {
+	// String returns the expression in canonical $filter syntax.
+	String() string
+
+	expr()
+}
+
+// CompareOp is a comparison operator.
+type CompareOp string
+
+// Comparison operators supported in $filter.
+const (
+	Eq CompareOp = "eq"
+	Ne CompareOp = "ne"
+	Gt CompareOp = "gt"
+	Ge CompareOp = "ge"
+	Lt CompareOp = "lt"
+	Le CompareOp = "le"
+)
+
+// LogicalOp is a binary logical operator.
+type LogicalOp string
+
+// Logical operators supported in $filter.
+const (
+	And LogicalOp = "and"
+	Or  LogicalOp = "or"
+)
+
+// Comparison compares a property with a literal, as in Price lt 20.
+type Comparison struct {
+	Property string
+	Op       CompareOp
+	Value    Literal
+}
+
+// Logical combines two expressions with and or or.
+type Logical struct {
+	Op          LogicalOp
+	Left, Right Expr
+}
+
+// Not negates an expression.
+type Not struct {
+	Expr Expr
+}
+
+func (*Comparison) expr() {}
+func (*Logical) expr()    {}
+func (*Not) expr()        {}
+
+func (c *Comparison) String() string {
+	return c.Property + " " + string(c.Op) + " " + c.Value.String()
+}
+
+func (l *Logical) String() string {
+	return "(" + l.Left.String() + " " + string(l.Op) + " " + l.Right.String() + ")"
+}
+
+func (n *Not) String() string {
+	return "not " + wrapParens(n.Expr.String())
+}
+
+func wrapParens(s string) string {
+	if strings.HasPrefix(s, "(") {
+		return s
+	}
+	return "(" + s + ")"
+}
+
+// LiteralKind identifies the type of a Literal.
+type LiteralKind int
+
+// Literal kinds.
+const (
+	StringLiteral   LiteralKind = iota // Value is a string
+	IntLiteral                         // Value is an int64
+	FloatLiteral                       // Value is a float64
+	BoolLiteral                        // Value is a bool
+	NullLiteral                        // Value is nil
+	DateTimeLiteral                    // Value is a time.Time
+)
+
+// Literal is a constant value in a $filter expression.
+type Literal struct {
+	Kind  LiteralKind
+	Value any
+	Raw   string // Literal as written, including quotes for strings
+}
+
+func (l Literal) String() string {
+	return l.Raw
+}
+
+// ParseFilter parses a $filter expression. Properties are checked against
+// properties unless it is nil, and nesting of parentheses and not is limited
+// to maxDepth (DefaultMaxFilterDepth if zero or negative).
+func ParseFilter(s string, properties []string, maxDepth int) (Expr, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxFilterDepth
+	}
+
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrSyntax)
+	}
+
+	p := &filterParser{tokens: tokens, properties: properties, maxDepth: maxDepth}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, tok.text)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokString
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string // raw text; for strings, including quotes
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")"})
+			i++
+		case c == '\'':
+			end := i + 1
+			for {
+				next := strings.IndexByte(s[end:], '\'')
+				if next < 0 {
+					return nil, fmt.Errorf("%w: unterminated string", ErrSyntax)
+				}
+				end += next + 1
+				// A doubled quote is an escaped quote inside the string.
+				if end < len(s) && s[end] == '\'' {
+					end++
+					continue
+				}
+				break
+			}
+			tokens = append(tokens, token{kind: tokString, text: s[i:end]})
+			i = end
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\n\r()'", rune(s[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: s[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens     []token
+	pos        int
+	properties []string
+	depth      int
+	maxDepth   int
+}
+
+func (p *filterParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (token, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return token{}, fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+	p.pos++
+	return tok, nil
+}
+
+// peekKeyword reports whether the next token is the given keyword, ignoring case.
+func (p *filterParser) peekKeyword(keyword string) bool {
+	tok, ok := p.peek()
+	return ok && tok.kind == tokWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *filterParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: Or, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: And, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (Expr, error) {
+	if p.peekKeyword("not") {
+		p.pos++
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: e}, nil
+	}
+
+	if tok, ok := p.peek(); ok && tok.kind == tokLParen {
+		p.pos++
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok, err := p.next(); err != nil || tok.kind != tokRParen {
+			return nil, fmt.Errorf("%w: missing closing parenthesis", ErrSyntax)
+		}
+		return e, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) enter() error {
+	p.depth++
+	if p.depth > p.maxDepth {
+		return ErrFilterTooComplex
+	}
+	return nil
+}
+
+func (p *filterParser) leave() {
+	p.depth--
+}
+
+func (p *filterParser) parseComparison() (Expr, error) {
+	prop, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if prop.kind != tokWord {
+		return nil, fmt.Errorf("%w: expected property, got %q", ErrSyntax, prop.text)
+	}
+	if err := checkProperty(prop.text, p.properties); err != nil {
+		return nil, err
+	}
+
+	opTok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := CompareOp(strings.ToLower(opTok.text))
+	switch op {
+	case Eq, Ne, Gt, Ge, Lt, Le:
+	default:
+		return nil, fmt.Errorf("%w: unsupported operator %q", ErrSyntax, opTok.text)
+	}
+
+	valTok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	lit, err := parseLiteral(valTok)
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{Property: prop.text, Op: op, Value: lit}, nil
+}
+
+func parseLiteral(tok token) (Literal, error) {
+	switch tok.kind {
+	case tokString:
+		unquoted := strings.ReplaceAll(tok.text[1:len(tok.text)-1], "''", "'")
+		return Literal{Kind: StringLiteral, Value: unquoted, Raw: tok.text}, nil
+	case tokWord:
+	default:
+		return Literal{}, fmt.Errorf("%w: expected literal, got %q", ErrSyntax, tok.text)
+	}
+
+	s := tok.text
+	switch strings.ToLower(s) {
+	case "true":
+		return Literal{Kind: BoolLiteral, Value: true, Raw: "true"}, nil
+	case "false":
+		return Literal{Kind: BoolLiteral, Value: false, Raw: "false"}, nil
+	case "null":
+		return Literal{Kind: NullLiteral, Raw: "null"}, nil
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return Literal{Kind: IntLiteral, Value: n, Raw: s}, nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Literal{Kind: DateTimeLiteral, Value: t, Raw: s}, nil
+		}
+	}
+	// ParseFloat also accepts words such as "Inf" and "NaN", which are not
+	// valid here, so require a leading digit, sign or decimal point.
+	if strings.ContainsAny(s[:1], "0123456789+-.") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return Literal{Kind: FloatLiteral, Value: f, Raw: s}, nil
+		}
+	}
+	return Literal{}, fmt.Errorf("%w: invalid literal %q", ErrSyntax, s)
+}
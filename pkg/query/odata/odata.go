@@ -0,0 +1,234 @@
+// Package odata parses the OData system query options $top, $skip, $count,
+// $orderby, $select and a bounded subset of $filter, for services that must keep
+// OData-compatible query strings.
+//
+// Unlike the query package, Parse reports malformed options as errors rather
+// than falling back to defaults: silently dropping a $filter would return more
+// data than the client asked for, so callers should respond with 400 Bad Request.
+//
+// The supported $filter grammar covers comparisons (eq, ne, gt, ge, lt, le)
+// between a property and a literal, the logical operators and, or and not, and
+// parentheses. Literals are single-quoted strings, numbers, true, false, null,
+// and unquoted dates or date-times.
+//
+// Example:
+//
+//	// URL: /Products?$filter=Price lt 20 and Category eq 'Books'&$orderby=Name desc&$top=10
+//	p, err := odata.Parse(r, odata.Options{
+//	    Properties: []string{"Name", "Price", "Category"},
+//	})
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+package odata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+)
+
+// Default limits used when Options leaves them unset.
+const (
+	DefaultMaxTop          = 1000
+	DefaultMaxFilterLength = 2048
+	DefaultMaxFilterDepth  = 16
+)
+
+var (
+	// ErrInvalidValue is reported for option values that cannot be parsed.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrUnknownProperty is reported for properties not listed in Options.Properties.
+	ErrUnknownProperty = errors.New("unknown property")
+
+	// ErrFilterTooComplex is reported when $filter exceeds the length or nesting limit.
+	ErrFilterTooComplex = errors.New("filter too complex")
+)
+
+// OptionError describes a system query option that could not be parsed.
+type OptionError struct {
+	Option string // Option name, such as "$filter"
+	Value  string // Raw option value
+	Err    error  // Underlying error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("odata: %s=%q: %v", e.Option, e.Value, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// Options describes the limits and properties supported by an endpoint.
+type Options struct {
+	// Properties lists the property names that may appear in $select, $orderby
+	// and $filter. Nil accepts any property.
+	Properties []string
+
+	// DefaultTop is used when $top is absent. Zero means no limit was requested.
+	DefaultTop int
+
+	// MaxTop is the largest $top accepted. Defaults to DefaultMaxTop.
+	MaxTop int
+
+	// MaxFilterLength and MaxFilterDepth bound the size and parenthesis/not
+	// nesting of $filter. They default to DefaultMaxFilterLength and
+	// DefaultMaxFilterDepth.
+	MaxFilterLength int
+	MaxFilterDepth  int
+}
+
+// Params holds the parsed system query options of a request.
+type Params struct {
+	Top     int               // $top, or Options.DefaultTop when absent
+	Skip    int               // $skip, or 0 when absent
+	Count   bool              // $count=true
+	OrderBy []query.SortField // $orderby, in request order
+	Select  []string          // $select, nil when absent
+	Filter  Expr              // $filter, nil when absent
+}
+
+// Parse extracts the OData system query options from r. It returns an
+// *OptionError for the first option that is malformed, refers to an unknown
+// property, or exceeds a limit.
+func Parse(r *http.Request, opts Options) (Params, error) {
+	if opts.MaxTop <= 0 {
+		opts.MaxTop = DefaultMaxTop
+	}
+	if opts.MaxFilterLength <= 0 {
+		opts.MaxFilterLength = DefaultMaxFilterLength
+	}
+	if opts.MaxFilterDepth <= 0 {
+		opts.MaxFilterDepth = DefaultMaxFilterDepth
+	}
+
+	p := Params{Top: opts.DefaultTop}
+	var err error
+
+	if raw := query.String(r, "$top", ""); raw != "" {
+		if p.Top, err = parseCount(raw, opts.MaxTop); err != nil {
+			return Params{}, &OptionError{Option: "$top", Value: raw, Err: err}
+		}
+	}
+	if raw := query.String(r, "$skip", ""); raw != "" {
+		if p.Skip, err = parseCount(raw, -1); err != nil {
+			return Params{}, &OptionError{Option: "$skip", Value: raw, Err: err}
+		}
+	}
+	if raw := query.String(r, "$count", ""); raw != "" {
+		if p.Count, err = strconv.ParseBool(raw); err != nil {
+			return Params{}, &OptionError{Option: "$count", Value: raw, Err: ErrInvalidValue}
+		}
+	}
+	if raw := query.String(r, "$orderby", ""); raw != "" {
+		if p.OrderBy, err = parseOrderBy(raw, opts.Properties); err != nil {
+			return Params{}, &OptionError{Option: "$orderby", Value: raw, Err: err}
+		}
+	}
+	if raw := query.String(r, "$select", ""); raw != "" {
+		if p.Select, err = parseSelect(raw, opts.Properties); err != nil {
+			return Params{}, &OptionError{Option: "$select", Value: raw, Err: err}
+		}
+	}
+	if raw := query.String(r, "$filter", ""); raw != "" {
+		if len(raw) > opts.MaxFilterLength {
+			return Params{}, &OptionError{Option: "$filter", Value: raw, Err: ErrFilterTooComplex}
+		}
+		if p.Filter, err = ParseFilter(raw, opts.Properties, opts.MaxFilterDepth); err != nil {
+			return Params{}, &OptionError{Option: "$filter", Value: raw, Err: err}
+		}
+	}
+	return p, nil
+}
+
+// parseCount parses a non-negative integer no larger than maxValue (when positive).
+func parseCount(raw string, maxValue int) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, ErrInvalidValue
+	}
+	if maxValue > 0 && n > maxValue {
+		return 0, fmt.Errorf("%w: exceeds maximum of %d", ErrInvalidValue, maxValue)
+	}
+	return n, nil
+}
+
+func parseOrderBy(raw string, properties []string) ([]query.SortField, error) {
+	var result []query.SortField
+	for _, item := range strings.Split(raw, ",") {
+		words := strings.Fields(item)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, ErrInvalidValue
+		}
+
+		field := query.SortField{Field: words[0]}
+		if len(words) == 2 {
+			switch strings.ToLower(words[1]) {
+			case "asc":
+			case "desc":
+				field.Desc = true
+			default:
+				return nil, ErrInvalidValue
+			}
+		}
+		if err := checkProperty(field.Field, properties); err != nil {
+			return nil, err
+		}
+		result = append(result, field)
+	}
+	return result, nil
+}
+
+func parseSelect(raw string, properties []string) ([]string, error) {
+	var result []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil, ErrInvalidValue
+		}
+		if item != "*" {
+			if err := checkProperty(item, properties); err != nil {
+				return nil, err
+			}
+		}
+		if !slices.Contains(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func checkProperty(name string, properties []string) error {
+	if !isIdentifier(name) {
+		return fmt.Errorf("%w: %q is not a property name", ErrInvalidValue, name)
+	}
+	if properties != nil && !slices.Contains(properties, name) {
+		return fmt.Errorf("%w %q", ErrUnknownProperty, name)
+	}
+	return nil
+}
+
+// isIdentifier reports whether s is a property name or a "/"-separated path of them.
+func isIdentifier(s string) bool {
+	for _, segment := range strings.Split(s, "/") {
+		if segment == "" {
+			return false
+		}
+		for i, c := range segment {
+			letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			digit := c >= '0' && c <= '9'
+			if !letter && (i == 0 || !digit) {
+				return false
+			}
+		}
+	}
+	return true
+}
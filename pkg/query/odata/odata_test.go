@@ -0,0 +1,201 @@
+package odata_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/query"
+	"github.com/mallardduck/go-http-helpers/pkg/query/odata"
+)
+
+func targetURL(params map[string]string) *url.URL {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return &url.URL{Path: "/Products", RawQuery: values.Encode()}
+}
+
+func TestParse(t *testing.T) {
+	u := targetURL(map[string]string{
+		"$top":     "10",
+		"$skip":    "20",
+		"$count":   "true",
+		"$orderby": "Name desc, Price",
+		"$select":  "Name,Price,Name",
+		"$filter":  "Price lt 20 and Category eq 'Books'",
+	})
+	r := httptest.NewRequest("GET", u.String(), nil)
+
+	p, err := odata.Parse(r, odata.Options{Properties: []string{"Name", "Price", "Category"}})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if p.Top != 10 || p.Skip != 20 || !p.Count {
+		t.Errorf("Parse() top/skip/count = %d/%d/%v, want 10/20/true", p.Top, p.Skip, p.Count)
+	}
+	wantOrder := []query.SortField{{Field: "Name", Desc: true}, {Field: "Price"}}
+	if !reflect.DeepEqual(p.OrderBy, wantOrder) {
+		t.Errorf("OrderBy = %v, want %v", p.OrderBy, wantOrder)
+	}
+	if !reflect.DeepEqual(p.Select, []string{"Name", "Price"}) {
+		t.Errorf("Select = %v, want [Name Price]", p.Select)
+	}
+
+	wantFilter := &odata.Logical{
+		Op:    odata.And,
+		Left:  &odata.Comparison{Property: "Price", Op: odata.Lt, Value: odata.Literal{Kind: odata.IntLiteral, Value: int64(20), Raw: "20"}},
+		Right: &odata.Comparison{Property: "Category", Op: odata.Eq, Value: odata.Literal{Kind: odata.StringLiteral, Value: "Books", Raw: "'Books'"}},
+	}
+	if !reflect.DeepEqual(p.Filter, wantFilter) {
+		t.Errorf("Filter = %v, want %v", p.Filter, wantFilter)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	r := httptest.NewRequest("GET", "/Products", nil)
+	p, err := odata.Parse(r, odata.Options{DefaultTop: 50})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(p, odata.Params{Top: 50}) {
+		t.Errorf("Parse() = %+v, want only the default top", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	opts := odata.Options{Properties: []string{"Name", "Price"}, MaxTop: 100}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		option  string
+		wantErr error
+	}{
+		{"top not a number", map[string]string{"$top": "ten"}, "$top", odata.ErrInvalidValue},
+		{"top negative", map[string]string{"$top": "-1"}, "$top", odata.ErrInvalidValue},
+		{"top above max", map[string]string{"$top": "101"}, "$top", odata.ErrInvalidValue},
+		{"skip invalid", map[string]string{"$skip": "x"}, "$skip", odata.ErrInvalidValue},
+		{"count invalid", map[string]string{"$count": "maybe"}, "$count", odata.ErrInvalidValue},
+		{"orderby direction", map[string]string{"$orderby": "Name sideways"}, "$orderby", odata.ErrInvalidValue},
+		{"orderby unknown", map[string]string{"$orderby": "Secret"}, "$orderby", odata.ErrUnknownProperty},
+		{"select unknown", map[string]string{"$select": "Name,Secret"}, "$select", odata.ErrUnknownProperty},
+		{"select empty item", map[string]string{"$select": "Name,"}, "$select", odata.ErrInvalidValue},
+		{"filter unknown", map[string]string{"$filter": "Secret eq 1"}, "$filter", odata.ErrUnknownProperty},
+		{"filter syntax", map[string]string{"$filter": "Name eq"}, "$filter", odata.ErrSyntax},
+		{"filter too long", map[string]string{"$filter": "Name eq '" + string(make([]byte, 3000)) + "'"}, "$filter", odata.ErrFilterTooComplex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", targetURL(tt.params).String(), nil)
+			_, err := odata.Parse(r, opts)
+
+			var optErr *odata.OptionError
+			if !errors.As(err, &optErr) {
+				t.Fatalf("Parse() error = %v, want *OptionError", err)
+			}
+			if optErr.Option != tt.option {
+				t.Errorf("OptionError.Option = %q, want %q", optErr.Option, tt.option)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected string
+	}{
+		{"Name eq 'Milk'", "Name eq 'Milk'"},
+		{"Price GE 2.5", "Price ge 2.5"},
+		{"A eq 1 or B eq 2 and C eq 3", "(A eq 1 or (B eq 2 and C eq 3))"},
+		{"(A eq 1 or B eq 2) and C eq 3", "((A eq 1 or B eq 2) and C eq 3)"},
+		{"not A eq 1", "not (A eq 1)"},
+		{"not (A eq 1 or B ne null)", "not (A eq 1 or B ne null)"},
+		{"Name eq 'O''Brien'", "Name eq 'O''Brien'"},
+		{"Active eq true", "Active eq true"},
+		{"Address/City eq 'Oslo'", "Address/City eq 'Oslo'"},
+		{"Created gt 2024-01-01T00:00:00Z", "Created gt 2024-01-01T00:00:00Z"},
+		{"Temp lt -3.5", "Temp lt -3.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			e, err := odata.ParseFilter(tt.filter, nil, 0)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if got := e.String(); got != tt.expected {
+				t.Errorf("ParseFilter().String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFilterLiterals(t *testing.T) {
+	tests := []struct {
+		filter string
+		kind   odata.LiteralKind
+		value  any
+	}{
+		{"A eq 'x'", odata.StringLiteral, "x"},
+		{"A eq 42", odata.IntLiteral, int64(42)},
+		{"A eq 4.2", odata.FloatLiteral, 4.2},
+		{"A eq false", odata.BoolLiteral, false},
+		{"A eq null", odata.NullLiteral, nil},
+		{"A eq 2024-03-01", odata.DateTimeLiteral, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			e, err := odata.ParseFilter(tt.filter, nil, 0)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			lit := e.(*odata.Comparison).Value
+			if lit.Kind != tt.kind || !reflect.DeepEqual(lit.Value, tt.value) {
+				t.Errorf("literal = %v (%v), want %v (%v)", lit.Value, lit.Kind, tt.value, tt.kind)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		filter  string
+		wantErr error
+	}{
+		{"", odata.ErrSyntax},
+		{"Name", odata.ErrSyntax},
+		{"Name like 'x'", odata.ErrSyntax},
+		{"Name eq 'unterminated", odata.ErrSyntax},
+		{"Name eq NaN", odata.ErrSyntax},
+		{"Name eq bareword", odata.ErrSyntax},
+		{"(Name eq 'x'", odata.ErrSyntax},
+		{"Name eq 'x')", odata.ErrSyntax},
+		{"Name eq 'x' and", odata.ErrSyntax},
+		{"'x' eq Name", odata.ErrSyntax},
+		{"contains(Name, 'x')", odata.ErrSyntax},
+		{"1abc eq 1", odata.ErrInvalidValue},
+		{"((((A eq 1))))", odata.ErrFilterTooComplex},
+		{"not not not not A eq 1", odata.ErrFilterTooComplex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			_, err := odata.ParseFilter(tt.filter, nil, 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseFilter() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
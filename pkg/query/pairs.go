@@ -0,0 +1,65 @@
+package query
+
+import (
+	"iter"
+	"net/http"
+	"strings"
+)
+
+// Pairs returns an iterator over the key/value pairs of the request's query
+// string, in the order they appear. The query string is walked in place
+// without building url.Values, so stopping early after the parameter of
+// interest costs nothing for the rest of a very large query string. Keys and
+// values are unescaped; repeated keys yield one pair each, and pairs that
+// url.ParseQuery would reject, those containing ";" or invalid escapes, are
+// skipped. Keys are yielded as sent, so brackets are not interpreted.
+//
+// Example:
+//
+//	for k, v := range query.Pairs(r) {
+//	    log.Printf("%s=%q", k, v)
+//	}
+func Pairs(r *http.Request) iter.Seq2[string, string] {
+	rawQuery := r.URL.RawQuery
+	return func(yield func(string, string) bool) {
+		for rest := rawQuery; rest != ""; {
+			var pair string
+			pair, rest, _ = strings.Cut(rest, "&")
+			if pair == "" || strings.Contains(pair, ";") {
+				continue
+			}
+
+			rawKey, rawVal, _ := strings.Cut(pair, "=")
+			key, ok := unescapeValue(rawKey)
+			if !ok {
+				continue
+			}
+			val, ok := unescapeValue(rawVal)
+			if !ok {
+				continue
+			}
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// Each calls fn for each key/value pair of the request's query string, as
+// yielded by Pairs, until fn returns false.
+//
+// Example:
+//
+//	// Middleware looking for a single debug flag in a long query string
+//	debug := false
+//	query.Each(r, func(key, value string) bool {
+//	    debug = key == "_debug"
+//	    return !debug
+//	})
+func Each(r *http.Request, fn func(key, value string) bool) {
+	for k, v := range Pairs(r) {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected [][2]string
+	}{
+		{"empty", "/", nil},
+		{"in order", "/?b=2&a=1&b=3", [][2]string{{"b", "2"}, {"a", "1"}, {"b", "3"}}},
+		{"unescaped", "/?q=red+shoes&na%6De=%C3%A9", [][2]string{{"q", "red shoes"}, {"name", "é"}}},
+		{"no value", "/?flag&empty=", [][2]string{{"flag", ""}, {"empty", ""}}},
+		{"brackets kept", "/?id[]=1&filter[x]=y", [][2]string{{"id[]", "1"}, {"filter[x]", "y"}}},
+		{"malformed skipped", "/?a=1&&b=%zz&c;d=2&e=3", [][2]string{{"a", "1"}, {"e", "3"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			var got [][2]string
+			for k, v := range Pairs(r) {
+				got = append(got, [2]string{k, v})
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Pairs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEach(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?a=1&_debug=1&b=2", nil)
+
+	var seen []string
+	Each(r, func(key, _ string) bool {
+		seen = append(seen, key)
+		return key != "_debug"
+	})
+	if !reflect.DeepEqual(seen, []string{"a", "_debug"}) {
+		t.Errorf("Each() visited %v, want [a _debug]", seen)
+	}
+}
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Prefixed collects every query parameter whose name starts with prefix into a
+// map keyed by the rest of the name, for passing along tracking or
+// vendor-specific parameters as a group. If a parameter appears multiple times,
+// the first value is used. A parameter named exactly prefix is ignored.
+// Returns an empty map if no matching parameters are present.
+//
+// Example:
+//
+//	// URL: /landing?utm_source=mail&utm_campaign=spring&page=2
+//	utm := query.Prefixed(r, "utm_")
+//	// map[string]string{"source": "mail", "campaign": "spring"}
+func Prefixed(r *http.Request, prefix string) map[string]string {
+	result := make(map[string]string)
+	for k, vals := range PrefixedValues(r, prefix) {
+		result[k] = vals[0]
+	}
+	return result
+}
+
+// PrefixedValues is like Prefixed but keeps every value of each parameter.
+func PrefixedValues(r *http.Request, prefix string) url.Values {
+	result := make(url.Values)
+	for k, vals := range r.URL.Query() {
+		name, ok := strings.CutPrefix(k, prefix)
+		if !ok || name == "" || len(vals) == 0 {
+			continue
+		}
+		result[name] = vals
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPrefixed(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		prefix   string
+		expected map[string]string
+	}{
+		{"utm parameters", "/?utm_source=mail&utm_campaign=spring&page=2", "utm_", map[string]string{"source": "mail", "campaign": "spring"}},
+		{"first value wins", "/?x_id=1&x_id=2", "x_", map[string]string{"id": "1"}},
+		{"bare prefix ignored", "/?utm_=1&utm_medium=cpc", "utm_", map[string]string{"medium": "cpc"}},
+		{"case-sensitive", "/?UTM_source=mail", "utm_", map[string]string{}},
+		{"no matches", "/?page=2", "utm_", map[string]string{}},
+		{"empty value", "/?x_flag=", "x_", map[string]string{"flag": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := Prefixed(r, tt.prefix); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Prefixed() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrefixedValues(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?acme.tag=a&acme.tag=b&acme.mode=x&other=1", nil)
+
+	expected := url.Values{"tag": {"a", "b"}, "mode": {"x"}}
+	if got := PrefixedValues(r, "acme."); !reflect.DeepEqual(got, expected) {
+		t.Errorf("PrefixedValues() = %v, want %v", got, expected)
+	}
+}
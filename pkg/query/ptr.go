@@ -0,0 +1,58 @@
+package query
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ValuePtr extracts a single value from the query parameter with the given key
+// and converts it using the provided parser, returning a pointer to the result.
+// Returns nil if the key is missing or the parser returns an error.
+//
+// Pointer variants let handlers distinguish "parameter not provided" from
+// "parameter provided as the zero value", which matters for PATCH-style
+// filters where ?active=false must not be treated like an absent filter.
+//
+// Example:
+//
+//	// URL: /users?active=false
+//	if active := query.BoolPtr(r, "active"); active != nil {
+//	    filter.Active = *active  // false, explicitly requested
+//	}
+func ValuePtr[T any](r *http.Request, key string, parser Parser[T]) *T {
+	return ValuePtrWith(std, r, key, parser)
+}
+
+// StringPtr extracts a string value from the query parameter with the given key.
+// Returns nil if the key is missing. Unlike String, a present but empty value
+// (?name=) returns a pointer to the empty string.
+func StringPtr(r *http.Request, key string) *string {
+	return ValuePtr(r, key, func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// IntPtr extracts an integer value from the query parameter with the given key.
+// Returns nil if the key is missing, empty, or cannot be parsed as an int.
+func IntPtr(r *http.Request, key string) *int {
+	return ValuePtr(r, key, strconv.Atoi)
+}
+
+// Int64Ptr extracts an int64 value from the query parameter with the given key.
+// Returns nil if the key is missing, empty, or cannot be parsed as an int64.
+func Int64Ptr(r *http.Request, key string) *int64 {
+	return ValuePtr(r, key, parseInt64)
+}
+
+// Float64Ptr extracts a float64 value from the query parameter with the given key.
+// Returns nil if the key is missing, empty, or cannot be parsed as a float64.
+func Float64Ptr(r *http.Request, key string) *float64 {
+	return ValuePtr(r, key, parseFloat64)
+}
+
+// BoolPtr extracts a boolean value from the query parameter with the given key.
+// Returns nil if the key is missing, empty, or cannot be parsed as a bool.
+// Uses the same flexible parsing as Bool.
+func BoolPtr(r *http.Request, key string) *bool {
+	return ValuePtr(r, key, parseBool)
+}
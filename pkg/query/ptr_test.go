@@ -0,0 +1,101 @@
+package query
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIntPtr(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *int
+	}{
+		{"valid int", "/?count=42", ptr(42)},
+		{"zero", "/?count=0", ptr(0)},
+		{"missing key", "/?other=value", nil},
+		{"empty value", "/?count=", nil},
+		{"invalid int", "/?count=abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			assertPtr(t, "IntPtr", IntPtr(r, "count"), tt.expected)
+		})
+	}
+}
+
+func TestInt64Ptr(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?id=9223372036854775807&zero=0", nil)
+	assertPtr(t, "Int64Ptr", Int64Ptr(r, "id"), ptr(int64(9223372036854775807)))
+	assertPtr(t, "Int64Ptr", Int64Ptr(r, "zero"), ptr(int64(0)))
+	assertPtr(t, "Int64Ptr", Int64Ptr(r, "missing"), nil)
+}
+
+func TestFloat64Ptr(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?price=19.99&zero=0&bad=x", nil)
+	assertPtr(t, "Float64Ptr", Float64Ptr(r, "price"), ptr(19.99))
+	assertPtr(t, "Float64Ptr", Float64Ptr(r, "zero"), ptr(0.0))
+	assertPtr(t, "Float64Ptr", Float64Ptr(r, "bad"), nil)
+	assertPtr(t, "Float64Ptr", Float64Ptr(r, "missing"), nil)
+}
+
+func TestBoolPtr(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *bool
+	}{
+		{"true", "/?active=true", ptr(true)},
+		{"false", "/?active=false", ptr(false)},
+		{"flexible false", "/?active=no", ptr(false)},
+		{"missing key", "/?other=value", nil},
+		{"empty value", "/?active=", nil},
+		{"invalid", "/?active=maybe", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			assertPtr(t, "BoolPtr", BoolPtr(r, "active"), tt.expected)
+		})
+	}
+}
+
+func TestStringPtr(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *string
+	}{
+		{"present value", "/?name=Alice", ptr("Alice")},
+		{"empty value", "/?name=", ptr("")},
+		{"no equals", "/?name", ptr("")},
+		{"missing key", "/?other=value", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			assertPtr(t, "StringPtr", StringPtr(r, "name"), tt.expected)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func assertPtr[T comparable](t *testing.T, fn string, got, want *T) {
+	t.Helper()
+	switch {
+	case got == nil && want == nil:
+	case got == nil:
+		t.Errorf("%s() = nil, want %v", fn, *want)
+	case want == nil:
+		t.Errorf("%s() = %v, want nil", fn, *got)
+	case *got != *want:
+		t.Errorf("%s() = %v, want %v", fn, *got, *want)
+	}
+}
@@ -1,7 +1,11 @@
 package query
 
 import (
+	"cmp"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -9,56 +13,91 @@ import (
 // String extracts a string value from the query parameter with the given key.
 // Returns defaultValue if the key is missing or empty.
 func String(r *http.Request, key string, defaultValue string) string {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return defaultValue
-	}
-	return val
+	return std.String(r, key, defaultValue)
 }
 
 // Int extracts an integer value from the query parameter with the given key.
 // Returns defaultValue if the key is missing, empty, or cannot be parsed as an int.
 func Int(r *http.Request, key string, defaultValue int) int {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return defaultValue
-	}
-
-	parsed, err := strconv.Atoi(val)
-	if err != nil {
-		return defaultValue
-	}
-	return parsed
+	return Value(r, key, defaultValue, strconv.Atoi)
 }
 
 // Int64 extracts an int64 value from the query parameter with the given key.
 // Returns defaultValue if the key is missing, empty, or cannot be parsed as an int64.
 func Int64(r *http.Request, key string, defaultValue int64) int64 {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return defaultValue
-	}
-
-	parsed, err := strconv.ParseInt(val, 10, 64)
-	if err != nil {
-		return defaultValue
-	}
-	return parsed
+	return Value(r, key, defaultValue, parseInt64)
 }
 
 // Float64 extracts a float64 value from the query parameter with the given key.
-// Returns defaultValue if the key is missing, empty, or cannot be parsed as a float64.
+// Returns defaultValue if the key is missing, empty, or cannot be parsed as a
+// finite float64; "NaN" and "Inf" are treated as invalid. Use an Extractor with
+// Config.AllowNonFinite to accept them.
 func Float64(r *http.Request, key string, defaultValue float64) float64 {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return defaultValue
-	}
+	return Value(r, key, defaultValue, parseFloat64)
+}
 
-	parsed, err := strconv.ParseFloat(val, 64)
-	if err != nil {
-		return defaultValue
+// Int8 extracts an int8 value from the query parameter with the given key.
+// Returns defaultValue if the key is missing, empty, cannot be parsed, or is
+// outside the range of an int8, so ?priority=300 falls back to the default
+// instead of silently wrapping around.
+func Int8(r *http.Request, key string, defaultValue int8) int8 {
+	return Value(r, key, defaultValue, parseInt8)
+}
+
+// Int16 extracts an int16 value from the query parameter with the given key.
+// Returns defaultValue if the key is missing, empty, cannot be parsed, or is
+// outside the range of an int16.
+func Int16(r *http.Request, key string, defaultValue int16) int16 {
+	return Value(r, key, defaultValue, parseInt16)
+}
+
+// Int32 extracts an int32 value from the query parameter with the given key.
+// Returns defaultValue if the key is missing, empty, cannot be parsed, or is
+// outside the range of an int32.
+func Int32(r *http.Request, key string, defaultValue int32) int32 {
+	return Value(r, key, defaultValue, parseInt32)
+}
+
+// Float32 extracts a float32 value from the query parameter with the given key.
+// Returns defaultValue if the key is missing, empty, cannot be parsed, or is
+// too large in magnitude for a float32.
+func Float32(r *http.Request, key string, defaultValue float32) float32 {
+	return Value(r, key, defaultValue, parseFloat32)
+}
+
+// IntInRange extracts an integer value and clamps it to the range [minValue, maxValue].
+// Returns defaultValue if the key is missing, empty, or cannot be parsed as an int.
+// The default itself is returned as-is and is not clamped.
+//
+// Example:
+//
+//	// URL: /products?limit=500
+//	limit := query.IntInRange(r, "limit", 25, 1, 100)  // 100
+func IntInRange(r *http.Request, key string, defaultValue, minValue, maxValue int) int {
+	return Value(r, key, defaultValue, clamped(strconv.Atoi, minValue, maxValue))
+}
+
+// Int64InRange extracts an int64 value and clamps it to the range [minValue, maxValue].
+// Returns defaultValue if the key is missing, empty, or cannot be parsed as an int64.
+func Int64InRange(r *http.Request, key string, defaultValue, minValue, maxValue int64) int64 {
+	return Value(r, key, defaultValue, clamped(parseInt64, minValue, maxValue))
+}
+
+// Float64InRange extracts a float64 value and clamps it to the range [minValue, maxValue].
+// Returns defaultValue if the key is missing, empty, or cannot be parsed as a float64.
+func Float64InRange(r *http.Request, key string, defaultValue, minValue, maxValue float64) float64 {
+	return Value(r, key, defaultValue, clamped(parseFloat64, minValue, maxValue))
+}
+
+// clamped wraps parser so that successfully parsed values are limited to [lo, hi].
+func clamped[T cmp.Ordered](parser Parser[T], lo, hi T) Parser[T] {
+	return func(s string) (T, error) {
+		v, err := parser(s)
+		if err != nil {
+			return v, err
+		}
+		return min(max(v, lo), hi), nil
 	}
-	return parsed
 }
 
 // Bool extracts a boolean value from the query parameter with the given key.
@@ -67,36 +106,138 @@ func Float64(r *http.Request, key string, defaultValue float64) float64 {
 // Recognized as true (case-insensitive): "true", "1", "yes", "on", "y"
 // Recognized as false (case-insensitive): "false", "0", "no", "off", "n"
 func Bool(r *http.Request, key string, defaultValue bool) bool {
-	val := r.URL.Query().Get(key)
+	return Value(r, key, defaultValue, parseBool)
+}
+
+// BoolStrict extracts a boolean value using strconv.ParseBool semantics, for APIs
+// that must only accept spec-compliant values.
+// Returns defaultValue if the key is missing, empty, or cannot be parsed.
+//
+// Recognized as true: "1", "t", "T", "TRUE", "true", "True"
+// Recognized as false: "0", "f", "F", "FALSE", "false", "False"
+func BoolStrict(r *http.Request, key string, defaultValue bool) bool {
+	return Value(r, key, defaultValue, strconv.ParseBool)
+}
+
+// Flag reports whether a CLI-style flag parameter is set. Bare presence (?verbose
+// or ?verbose=) counts as set, an explicit false value (?verbose=false, =0, =no,
+// =off, =n) does not, and absence does not. Any other value counts as set.
+//
+// Example: For URL "?verbose&dry_run=false"
+//
+//	query.Flag(r, "verbose")  // true
+//	query.Flag(r, "dry_run")  // false
+//	query.Flag(r, "force")    // false (missing)
+func Flag(r *http.Request, key string) bool {
+	return std.Flag(r, key)
+}
+
+// Strings extracts all values for a query parameter that appears multiple times.
+// Values sent with the array-bracket form (?tag[]=go) are included as well.
+// Returns an empty slice if the key is not present.
+//
+// Example: For URL "?tag=go&tag=rust&tag=python" or "?tag[]=go&tag[]=rust&tag[]=python"
+//
+//	tags := query.Strings(r, "tag")  // []string{"go", "rust", "python"}
+func Strings(r *http.Request, key string) []string {
+	return std.Strings(r, key)
+}
+
+// Parser is a function that converts a string to type T, returning an error if conversion fails.
+type Parser[T any] func(string) (T, error)
+
+// Value extracts a single value from the query parameter with the given key and
+// converts it using the provided parser. This is the generic counterpart of Slice
+// and the building block for the typed extractors such as Int and Bool.
+// Returns defaultValue if the key is missing, empty, or the parser returns an error.
+// A nil parser uses the parser registered for T with RegisterType.
+//
+// Example:
+//
+//	// URL: /orders?status=shipped
+//	status := query.Value(r, "status", StatusAny, ParseStatus)
+func Value[T any](r *http.Request, key string, defaultValue T, parser Parser[T]) T {
+	return ValueWith(std, r, key, defaultValue, parser)
+}
+
+// ValueAt extracts the value at index i among all values of the query parameter
+// and converts it using the provided parser. Negative indexes count from the
+// end, so -1 is the last value. Indexing covers the same values as Strings.
+// Returns defaultValue if the index is out of range, the value is empty, or the
+// parser returns an error.
+//
+// Example:
+//
+//	// URL: /api?limit=10&limit=50  (a proxy appended an override)
+//	limit := query.ValueAt(r, "limit", -1, 25, strconv.Atoi)  // 50
+func ValueAt[T any](r *http.Request, key string, i int, defaultValue T, parser Parser[T]) T {
+	parser = resolveParser(parser)
+	val := at(std.lookup(r.URL.Query(), key), i)
 	if val == "" {
 		return defaultValue
 	}
 
-	parsed, err := parseBool(val)
+	parsed, err := parser(val)
 	if err != nil {
 		return defaultValue
 	}
 	return parsed
 }
 
-// Strings extracts all values for a query parameter that appears multiple times.
-// Returns an empty slice if the key is not present.
+// LastValue is ValueAt for the last value, for upstream proxies that append
+// overriding parameters to the end of the query string.
+func LastValue[T any](r *http.Request, key string, defaultValue T, parser Parser[T]) T {
+	return ValueAt(r, key, -1, defaultValue, parser)
+}
+
+// At extracts the string value at index i among all values of the query
+// parameter; negative indexes count from the end.
+// Returns defaultValue if the index is out of range or the value is empty.
 //
-// Example: For URL "?tag=go&tag=rust&tag=python"
+// Example: For URL "?step=a&step=b&step=c"
 //
-//	tags := query.Strings(r, "tag")  // []string{"go", "rust", "python"}
-func Strings(r *http.Request, key string) []string {
-	vals := r.URL.Query()[key]
-	if vals == nil {
-		return []string{}
+//	query.At(r, "step", 1, "")   // "b"
+//	query.At(r, "step", -1, "")  // "c"
+//	query.At(r, "step", 5, "")   // ""
+func At(r *http.Request, key string, i int, defaultValue string) string {
+	if val := at(std.lookup(r.URL.Query(), key), i); val != "" {
+		return val
 	}
-	return vals
+	return defaultValue
 }
 
-// Parser is a function that converts a string to type T, returning an error if conversion fails.
-type Parser[T any] func(string) (T, error)
+// Last extracts the last string value of the query parameter.
+// Returns defaultValue if the key is missing or its last value is empty.
+func Last(r *http.Request, key string, defaultValue string) string {
+	return At(r, key, -1, defaultValue)
+}
+
+// LastInt extracts the last value of the query parameter as an int.
+// Returns defaultValue if the key is missing, empty, or cannot be parsed.
+func LastInt(r *http.Request, key string, defaultValue int) int {
+	return LastValue(r, key, defaultValue, strconv.Atoi)
+}
+
+// IntAt extracts the value at index i of the query parameter as an int;
+// negative indexes count from the end.
+// Returns defaultValue if the index is out of range, empty, or cannot be parsed.
+func IntAt(r *http.Request, key string, i int, defaultValue int) int {
+	return ValueAt(r, key, i, defaultValue, strconv.Atoi)
+}
+
+// at returns vals[i], counting from the end for negative i, or "" if out of range.
+func at(vals []string, i int) string {
+	if i < 0 {
+		i += len(vals)
+	}
+	if i < 0 || i >= len(vals) {
+		return ""
+	}
+	return vals[i]
+}
 
 // Slice extracts all values for a query parameter and converts them using the provided parser.
+// Like Strings, values sent as key[] are included after those sent as key.
 // If a value cannot be parsed, the defaultValue is used for that element.
 // Returns an empty slice if the key is not present.
 //
@@ -106,21 +247,7 @@ type Parser[T any] func(string) (T, error)
 //	ids := query.Slice(r, "id", 0, strconv.Atoi)
 //	// Returns []int{1, 2, 0, 5}
 func Slice[T any](r *http.Request, key string, defaultValue T, parser Parser[T]) []T {
-	vals := r.URL.Query()[key]
-	if len(vals) == 0 {
-		return []T{}
-	}
-
-	result := make([]T, len(vals))
-	for i, val := range vals {
-		parsed, err := parser(val)
-		if err != nil {
-			result[i] = defaultValue
-		} else {
-			result[i] = parsed
-		}
-	}
-	return result
+	return SliceWith(std, r, key, defaultValue, parser)
 }
 
 // Ints extracts all integer values for a query parameter.
@@ -137,17 +264,13 @@ func Ints(r *http.Request, key string, defaultValue int) []int {
 // Int64s extracts all int64 values for a query parameter.
 // Invalid values are replaced with defaultValue.
 func Int64s(r *http.Request, key string, defaultValue int64) []int64 {
-	return Slice(r, key, defaultValue, func(s string) (int64, error) {
-		return strconv.ParseInt(s, 10, 64)
-	})
+	return Slice(r, key, defaultValue, parseInt64)
 }
 
 // Float64s extracts all float64 values for a query parameter.
 // Invalid values are replaced with defaultValue.
 func Float64s(r *http.Request, key string, defaultValue float64) []float64 {
-	return Slice(r, key, defaultValue, func(s string) (float64, error) {
-		return strconv.ParseFloat(s, 64)
-	})
+	return Slice(r, key, defaultValue, parseFloat64)
 }
 
 // Bools extracts all boolean values for a query parameter.
@@ -157,6 +280,50 @@ func Bools(r *http.Request, key string, defaultValue bool) []bool {
 	return Slice(r, key, defaultValue, parseBool)
 }
 
+// parseInt64 parses a base-10 int64.
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// parseInt8 parses a base-10 int8.
+func parseInt8(s string) (int8, error) {
+	n, err := strconv.ParseInt(s, 10, 8)
+	return int8(n), err
+}
+
+// parseInt16 parses a base-10 int16.
+func parseInt16(s string) (int16, error) {
+	n, err := strconv.ParseInt(s, 10, 16)
+	return int16(n), err
+}
+
+// parseInt32 parses a base-10 int32.
+func parseInt32(s string) (int32, error) {
+	n, err := strconv.ParseInt(s, 10, 32)
+	return int32(n), err
+}
+
+// parseFloat32 parses a finite float32.
+func parseFloat32(s string) (float32, error) {
+	f, err := parseFinite(s, 32)
+	return float32(f), err
+}
+
+// parseFloat64 parses a finite float64.
+func parseFloat64(s string) (float64, error) {
+	return parseFinite(s, 64)
+}
+
+// parseFinite is strconv.ParseFloat, but rejects the "NaN" and "Inf" forms
+// it accepts, which would otherwise poison arithmetic and JSON encoding.
+func parseFinite(s string, bitSize int) (float64, error) {
+	f, err := strconv.ParseFloat(s, bitSize)
+	if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return 0, ErrNotFinite
+	}
+	return f, err
+}
+
 // parseBool is the internal bool parser that can return an error.
 func parseBool(s string) (bool, error) {
 	lower := strings.ToLower(strings.TrimSpace(s))
@@ -179,8 +346,7 @@ func parseBool(s string) (bool, error) {
 //	query.Has(r, "active")   // true (no value)
 //	query.Has(r, "missing")  // false
 func Has(r *http.Request, key string) bool {
-	_, exists := r.URL.Query()[key]
-	return exists
+	return std.Has(r, key)
 }
 
 // Count returns the number of times a query parameter appears.
@@ -190,7 +356,7 @@ func Has(r *http.Request, key string) bool {
 //
 //	query.Count(r, "id")  // 3
 func Count(r *http.Request, key string) int {
-	return len(r.URL.Query()[key])
+	return std.Count(r, key)
 }
 
 // IsMultiple checks if a query parameter appears more than once.
@@ -202,7 +368,7 @@ func Count(r *http.Request, key string) int {
 //	query.IsMultiple(r, "multi")   // true
 //	query.IsMultiple(r, "missing") // false
 func IsMultiple(r *http.Request, key string) bool {
-	return len(r.URL.Query()[key]) > 1
+	return std.IsMultiple(r, key)
 }
 
 // First returns the first element of a slice, or defaultValue if the slice is empty.
@@ -219,11 +385,39 @@ func First[T any](slice []T, defaultValue T) T {
 	return slice[0]
 }
 
+// lookup returns all values for key, followed by any values sent with the
+// array-bracket form key[] as serialized by jQuery, Axios and PHP clients.
+func lookup(values url.Values, key string) []string {
+	vals := values[key]
+	if strings.HasSuffix(key, "[]") {
+		return vals
+	}
+
+	bracketed := values[key+"[]"]
+	if len(bracketed) == 0 {
+		return vals
+	}
+	if len(vals) == 0 {
+		return bracketed
+	}
+	return append(slices.Clip(vals), bracketed...)
+}
+
+// first returns the first value for key as resolved by lookup, or "" if there is none.
+func first(values url.Values, key string) string {
+	vals := lookup(values, key)
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
 // All returns the entire parsed query string as a map.
 // This is useful when you need to iterate over all parameters or
 // when you need to extract many values and want to parse once.
 //
-// The returned map is a copy and can be safely modified.
+// The returned map is a copy and can be safely modified. Handlers that call All
+// repeatedly can use AllCached instead, which parses the query string once.
 func All(r *http.Request) map[string][]string {
 	values := r.URL.Query()
 	result := make(map[string][]string, len(values))
@@ -1,8 +1,10 @@
 package query
 
 import (
+	"errors"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
 )
 
@@ -47,6 +49,7 @@ func TestInt(t *testing.T) {
 		{"negative int", "/?offset=-5", "offset", 0, -5},
 		{"zero", "/?count=0", "count", 10, 0},
 		{"large number", "/?id=999999", "id", 0, 999999},
+		{"array bracket fallback", "/?page[]=7", "page", 1, 7},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +115,134 @@ func TestFloat64(t *testing.T) {
 	}
 }
 
+func TestSmallWidthInts(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected [3]int64 // Int8, Int16, Int32
+	}{
+		{"fits all", "/?n=100", [3]int64{100, 100, 100}},
+		{"int8 overflow", "/?n=300", [3]int64{-1, 300, 300}},
+		{"int8 min", "/?n=-128", [3]int64{-128, -128, -128}},
+		{"int16 overflow", "/?n=40000", [3]int64{-1, -1, 40000}},
+		{"int32 overflow", "/?n=3000000000", [3]int64{-1, -1, -1}},
+		{"invalid", "/?n=abc", [3]int64{-1, -1, -1}},
+		{"missing", "/", [3]int64{-1, -1, -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := [3]int64{
+				int64(Int8(r, "n", -1)),
+				int64(Int16(r, "n", -1)),
+				int64(Int32(r, "n", -1)),
+			}
+			if got != tt.expected {
+				t.Errorf("Int8/Int16/Int32() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected float32
+	}{
+		{"valid", "/?f=1.5", 1.5},
+		{"rounded", "/?f=0.1", 0.1},
+		{"overflow", "/?f=1e39", -1},
+		{"invalid", "/?f=abc", -1},
+		{"missing", "/", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Float32(r, "f", -1)
+			if got != tt.expected {
+				t.Errorf("Float32() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIntInRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected int
+	}{
+		{"within range", "/?limit=50", 50},
+		{"below min", "/?limit=0", 1},
+		{"above max", "/?limit=500", 100},
+		{"at min", "/?limit=1", 1},
+		{"at max", "/?limit=100", 100},
+		{"negative", "/?limit=-10", 1},
+		{"missing key", "/?other=value", 25},
+		{"invalid", "/?limit=abc", 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := IntInRange(r, "limit", 25, 1, 100)
+			if got != tt.expected {
+				t.Errorf("IntInRange() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInt64InRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected int64
+	}{
+		{"within range", "/?id=5000000000", 5000000000},
+		{"above max", "/?id=9223372036854775807", 9000000000},
+		{"below min", "/?id=-1", 0},
+		{"invalid", "/?id=x", 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Int64InRange(r, "id", 42, 0, 9000000000)
+			if got != tt.expected {
+				t.Errorf("Int64InRange() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFloat64InRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected float64
+	}{
+		{"within range", "/?ratio=0.5", 0.5},
+		{"below min", "/?ratio=-0.5", 0.0},
+		{"above max", "/?ratio=1.5", 1.0},
+		{"missing key", "/", 0.25},
+		{"invalid", "/?ratio=half", 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Float64InRange(r, "ratio", 0.25, 0.0, 1.0)
+			if got != tt.expected {
+				t.Errorf("Float64InRange() = %f, want %f", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestBool(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -160,6 +291,67 @@ func TestBool(t *testing.T) {
 	}
 }
 
+func TestBoolStrict(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		defaultValue bool
+		expected     bool
+	}{
+		{"true", "/?active=true", false, true},
+		{"True", "/?active=True", false, true},
+		{"t", "/?active=t", false, true},
+		{"1", "/?active=1", false, true},
+		{"false", "/?active=false", true, false},
+		{"0", "/?active=0", true, false},
+		{"yes rejected", "/?active=yes", false, false},
+		{"on rejected", "/?active=on", false, false},
+		{"no rejected", "/?active=no", true, true},
+		{"mixed case rejected", "/?active=tRUE", false, false},
+		{"missing key", "/?other=value", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := BoolStrict(r, "active", tt.defaultValue)
+			if got != tt.expected {
+				t.Errorf("BoolStrict() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected bool
+	}{
+		{"bare presence", "/?verbose", true},
+		{"empty value", "/?verbose=", true},
+		{"explicit true", "/?verbose=true", true},
+		{"explicit 1", "/?verbose=1", true},
+		{"explicit false", "/?verbose=false", false},
+		{"explicit 0", "/?verbose=0", false},
+		{"explicit no", "/?verbose=no", false},
+		{"explicit off", "/?verbose=OFF", false},
+		{"unrecognized value", "/?verbose=loud", true},
+		{"missing key", "/?other", false},
+		{"array brackets", "/?verbose[]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Flag(r, "verbose")
+			if got != tt.expected {
+				t.Errorf("Flag() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestStrings(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -197,6 +389,30 @@ func TestStrings(t *testing.T) {
 			"tag",
 			[]string{"go", "", "rust"},
 		},
+		{
+			"array brackets",
+			"/?tag[]=go&tag[]=rust",
+			"tag",
+			[]string{"go", "rust"},
+		},
+		{
+			"encoded array brackets",
+			"/?tag%5B%5D=go&tag%5B%5D=rust",
+			"tag",
+			[]string{"go", "rust"},
+		},
+		{
+			"plain before brackets",
+			"/?tag[]=rust&tag=go",
+			"tag",
+			[]string{"go", "rust"},
+		},
+		{
+			"explicit bracket key",
+			"/?tag[]=go&tag=rust",
+			"tag[]",
+			[]string{"go"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -267,6 +483,13 @@ func TestInts(t *testing.T) {
 			99,
 			[]int{99, 99},
 		},
+		{
+			"array brackets",
+			"/?id[]=1&id[]=2&id[]=x",
+			"id",
+			0,
+			[]int{1, 2, 0},
+		},
 	}
 
 	for _, tt := range tests {
@@ -452,6 +675,88 @@ func TestSliceGeneric(t *testing.T) {
 	}
 }
 
+func TestValueGeneric(t *testing.T) {
+	type status int
+	const (
+		statusAny status = iota
+		statusActive
+		statusArchived
+	)
+	parseStatus := func(s string) (status, error) {
+		switch s {
+		case "active":
+			return statusActive, nil
+		case "archived":
+			return statusArchived, nil
+		default:
+			return statusAny, errors.New("unknown status")
+		}
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		expected status
+	}{
+		{"known value", "/?status=active", statusActive},
+		{"other known value", "/?status=archived", statusArchived},
+		{"unknown value", "/?status=deleted", statusAny},
+		{"missing key", "/?other=value", statusAny},
+		{"empty value", "/?status=", statusAny},
+		{"first of multiple", "/?status=archived&status=active", statusArchived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Value(r, "status", statusAny, parseStatus)
+			if got != tt.expected {
+				t.Errorf("Value() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAtAndLast(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?step=a&step=&step=c&step[]=d&n=1&n=2&n=x", nil)
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"first", At(r, "step", 0, "def"), "a"},
+		{"empty value", At(r, "step", 1, "def"), "def"},
+		{"bracketed included", At(r, "step", 3, "def"), "d"},
+		{"negative", At(r, "step", -2, "def"), "c"},
+		{"out of range", At(r, "step", 4, "def"), "def"},
+		{"negative out of range", At(r, "step", -5, "def"), "def"},
+		{"last", Last(r, "step", "def"), "d"},
+		{"last missing", Last(r, "missing", "def"), "def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %q, want %q", tt.got, tt.expected)
+			}
+		})
+	}
+
+	if got := IntAt(r, "n", 1, 0); got != 2 {
+		t.Errorf("IntAt() = %v, want 2", got)
+	}
+	if got := LastInt(r, "n", -1); got != -1 {
+		t.Errorf("LastInt() = %v, want default for invalid last value", got)
+	}
+	if got := LastValue(r, "n", "", func(s string) (string, error) { return s + "!", nil }); got != "x!" {
+		t.Errorf("LastValue() = %q, want x!", got)
+	}
+	if got := ValueAt(r, "n", -3, 0, strconv.Atoi); got != 1 {
+		t.Errorf("ValueAt() = %v, want 1", got)
+	}
+}
+
 func TestHas(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -464,6 +769,7 @@ func TestHas(t *testing.T) {
 		{"present no equals", "/?active", "active", true},
 		{"missing", "/?other=value", "name", false},
 		{"empty query", "/", "name", false},
+		{"array brackets", "/?name[]=a", "name", true},
 	}
 
 	for _, tt := range tests {
@@ -489,6 +795,8 @@ func TestCount(t *testing.T) {
 		{"multiple values", "/?id=1&id=2&id=3", "id", 3},
 		{"empty value", "/?id=", "id", 1},
 		{"mixed", "/?id=1&id=&id=3", "id", 3},
+		{"array brackets", "/?id[]=1&id[]=2", "id", 2},
+		{"plain and brackets", "/?id=1&id[]=2", "id", 2},
 	}
 
 	for _, tt := range tests {
@@ -513,6 +821,7 @@ func TestIsMultiple(t *testing.T) {
 		{"single value", "/?id=1", "id", false},
 		{"two values", "/?id=1&id=2", "id", true},
 		{"three values", "/?id=1&id=2&id=3", "id", true},
+		{"array brackets", "/?id[]=1&id[]=2", "id", true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"cmp"
+	"net/http"
+	"strconv"
+)
+
+// IntRange extracts a min/max pair from two query parameters, the "range
+// filter" of listing endpoints such as ?price_min=10&price_max=50.
+//
+// Both values are clamped to the hard bounds [floor, ceil]; pass math.MinInt
+// and math.MaxInt for an unbounded range. A missing, empty or unparseable end
+// resolves to floor or ceil respectively, and a reversed pair is swapped, so
+// lo <= hi always holds. ok reports whether the request supplied at least one
+// valid end, i.e. whether the filter should be applied at all.
+//
+// Example:
+//
+//	// URL: /products?price_min=500&price_max=20
+//	lo, hi, ok := query.IntRange(r, "price_min", "price_max", 0, 10000)
+//	// lo = 20, hi = 500, ok = true
+//	if ok {
+//	    q = q.Where("price BETWEEN ? AND ?", lo, hi)
+//	}
+func IntRange(r *http.Request, minKey, maxKey string, floor, ceil int) (lo, hi int, ok bool) {
+	return valueRange(r, minKey, maxKey, floor, ceil, strconv.Atoi)
+}
+
+// Float64Range extracts a min/max pair of float64 values from two query
+// parameters. NaN and infinite values are rejected like any other invalid
+// value; pass math.Inf(-1) and math.Inf(1) as floor and ceil for an unbounded
+// range. See IntRange.
+func Float64Range(r *http.Request, minKey, maxKey string, floor, ceil float64) (lo, hi float64, ok bool) {
+	return valueRange(r, minKey, maxKey, floor, ceil, parseFloat64)
+}
+
+// valueRange resolves both ends of a range, clamping them to [floor, ceil].
+func valueRange[T cmp.Ordered](r *http.Request, minKey, maxKey string, floor, ceil T, parser Parser[T]) (lo, hi T, ok bool) {
+	parse := clamped(parser, floor, ceil)
+	lo, hi = floor, ceil
+
+	if val, _ := std.firstValue(r, minKey); val != "" {
+		if v, err := parse(val); err == nil {
+			lo, ok = v, true
+		}
+	}
+	if val, _ := std.firstValue(r, maxKey); val != "" {
+		if v, err := parse(val); err == nil {
+			hi, ok = v, true
+		}
+	}
+
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	return lo, hi, ok
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIntRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		expectedLo int
+		expectedHi int
+		expectedOK bool
+	}{
+		{"both present", "/?min=10&max=50", 10, 50, true},
+		{"both missing", "/", 0, 1000, false},
+		{"only min", "/?min=10", 10, 1000, true},
+		{"only max", "/?max=50", 0, 50, true},
+		{"reversed swapped", "/?min=50&max=10", 10, 50, true},
+		{"clamped to bounds", "/?min=-5&max=5000", 0, 1000, true},
+		{"min above ceiling", "/?min=2000", 1000, 1000, true},
+		{"invalid min", "/?min=abc&max=50", 0, 50, true},
+		{"both invalid", "/?min=abc&max=", 0, 1000, false},
+		{"equal", "/?min=7&max=7", 7, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			lo, hi, ok := IntRange(r, "min", "max", 0, 1000)
+			if lo != tt.expectedLo || hi != tt.expectedHi || ok != tt.expectedOK {
+				t.Errorf("IntRange() = %v, %v, %v, want %v, %v, %v", lo, hi, ok, tt.expectedLo, tt.expectedHi, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestFloat64Range(t *testing.T) {
+	inf := math.Inf(1)
+
+	tests := []struct {
+		name       string
+		url        string
+		expectedLo float64
+		expectedHi float64
+		expectedOK bool
+	}{
+		{"both present", "/?lat_min=-12.5&lat_max=40.25", -12.5, 40.25, true},
+		{"unbounded max", "/?lat_min=1.5", 1.5, inf, true},
+		{"reversed swapped", "/?lat_min=3&lat_max=-3", -3, 3, true},
+		{"NaN rejected", "/?lat_min=NaN&lat_max=2", math.Inf(-1), 2, true},
+		{"missing", "/", math.Inf(-1), inf, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			lo, hi, ok := Float64Range(r, "lat_min", "lat_max", math.Inf(-1), inf)
+			if lo != tt.expectedLo || hi != tt.expectedHi || ok != tt.expectedOK {
+				t.Errorf("Float64Range() = %v, %v, %v, want %v, %v, %v", lo, hi, ok, tt.expectedLo, tt.expectedHi, tt.expectedOK)
+			}
+		})
+	}
+}
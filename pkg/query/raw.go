@@ -0,0 +1,67 @@
+package query
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Raw returns the first value of the query parameter with the given key exactly
+// as it appeared in r.URL.RawQuery, without percent-decoding, for byte-exact
+// signature checks or pass-through proxying. The key itself is matched after
+// decoding, so ?na%6De=x is found as "name".
+//
+// Raw sees the same parameters as String, including key[] values, but returns
+// them undecoded: for ?q=a%2Bb+c, String returns "a+b c" and Raw "a%2Bb+c".
+// Returns "" if the key is missing.
+//
+// Example:
+//
+//	// URL: /callback?state=abc%3D%3D&code=xyz
+//	state := query.Raw(r, "state")  // "abc%3D%3D"
+func Raw(r *http.Request, key string) string {
+	vals := RawValues(r, key)
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
+// RawValues returns all values of the query parameter with the given key
+// exactly as they appeared in r.URL.RawQuery, in the order of Strings.
+// Returns an empty slice if the key is not present. See Raw.
+func RawValues(r *http.Request, key string) []string {
+	brackets := !strings.HasSuffix(key, "[]")
+
+	plain, bracketed := []string{}, []string(nil)
+	for rawQuery := r.URL.RawQuery; rawQuery != ""; {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if pair == "" || strings.Contains(pair, ";") {
+			continue
+		}
+
+		rawKey, rawVal, _ := strings.Cut(pair, "=")
+		if !validEscapes(rawVal) {
+			// url.ParseQuery drops the pair, so the decoded accessors never see it.
+			continue
+		}
+		switch {
+		case unescapedEquals(rawKey, key, ""):
+			plain = append(plain, rawVal)
+		case brackets && unescapedEquals(rawKey, key, "[]"):
+			bracketed = append(bracketed, rawVal)
+		}
+	}
+	return append(plain, bracketed...)
+}
+
+// validEscapes reports whether every "%" in s starts a valid escape sequence.
+func validEscapes(s string) bool {
+	for i := strings.IndexByte(s, '%'); i >= 0; i = strings.IndexByte(s, '%') {
+		if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			return false
+		}
+		s = s[i+3:]
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRaw(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		key      string
+		expected string
+	}{
+		{"plain", "/?state=abc", "state", "abc"},
+		{"percent-encoded kept", "/?state=abc%3D%3D", "state", "abc%3D%3D"},
+		{"plus kept", "/?q=a%2Bb+c", "q", "a%2Bb+c"},
+		{"lowercase escapes kept", "/?q=%c3%a9", "q", "%c3%a9"},
+		{"encoded key", "/?na%6De=x", "name", "x"},
+		{"first value", "/?id=1&id=2", "id", "1"},
+		{"bracket fallback", "/?id%5B%5D=a%20b", "id", "a%20b"},
+		{"empty value", "/?q=", "q", ""},
+		{"missing", "/?other=1", "q", ""},
+		{"invalid escape skipped", "/?q=%zz&q=ok", "q", "ok"},
+		{"semicolon skipped", "/?q=a;b&q=ok", "q", "ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := Raw(r, tt.key); got != tt.expected {
+				t.Errorf("Raw() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRawValues(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?tag[]=c%2B%2B&tag=go&x=1&tag=r%C3%BCst&tag[]=zig", nil)
+
+	if got, want := RawValues(r, "tag"), []string{"go", "r%C3%BCst", "c%2B%2B", "zig"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RawValues() = %q, want %q", got, want)
+	}
+	if got, want := RawValues(r, "tag[]"), []string{"c%2B%2B", "zig"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RawValues(tag[]) = %q, want %q", got, want)
+	}
+	if got := RawValues(r, "missing"); got == nil || len(got) != 0 {
+		t.Errorf("RawValues(missing) = %#v, want empty slice", got)
+	}
+
+	// The same values as Strings, in the same order, undecoded.
+	if got, want := len(RawValues(r, "tag")), len(Strings(r, "tag")); got != want {
+		t.Errorf("RawValues() returned %d values, Strings() %d", got, want)
+	}
+}
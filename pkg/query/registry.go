@@ -0,0 +1,108 @@
+package query
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// registeredType holds the parser and formatter registered for one type.
+type registeredType struct {
+	parser any                                 // Parser[T]
+	parse  func(string) (reflect.Value, error) // parser, for Bind
+	format func(reflect.Value) string          // nil if no formatter was registered
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[reflect.Type]registeredType{}
+)
+
+// RegisterType registers parse as the parser for values of type T, and format,
+// if non-nil, as its formatter. Once registered, Bind parses fields of type T
+// (and pointers and slices of it) with parse, Value, Slice and the other
+// generic extractors use parse when given a nil parser, and Builder.SetValue
+// uses format. Registered parsers take precedence over the built-in handling of
+// T, so a struct type with a registered parser is read from a single parameter
+// rather than bound field by field.
+//
+// RegisterType is meant to be called during program initialization; a later
+// registration for the same type replaces the earlier one.
+//
+// Example:
+//
+//	func init() {
+//	    query.RegisterType(ParseMoney, Money.String)
+//	}
+//
+//	// URL: /products?min=10.00EUR
+//	minPrice := query.Value(r, "min", Money{}, nil)
+func RegisterType[T any](parse Parser[T], format func(T) string) {
+	if parse == nil {
+		panic("query: RegisterType with nil parser")
+	}
+
+	entry := registeredType{
+		parser: parse,
+		parse: func(s string) (reflect.Value, error) {
+			v, err := parse(s)
+			return reflect.ValueOf(&v).Elem(), err
+		},
+	}
+	if format != nil {
+		entry.format = func(v reflect.Value) string {
+			return format(v.Interface().(T))
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[reflect.TypeFor[T]()] = entry
+}
+
+// RegisteredParser returns the parser registered for T with RegisterType, if any.
+func RegisteredParser[T any]() (Parser[T], bool) {
+	entry, ok := lookupType(reflect.TypeFor[T]())
+	if !ok {
+		return nil, false
+	}
+	return entry.parser.(Parser[T]), true
+}
+
+// lookupType returns the registration for t, if any.
+func lookupType(t reflect.Type) (registeredType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	entry, ok := registry[t]
+	return entry, ok
+}
+
+// resolveParser returns parser, or the parser registered for T when parser is
+// nil. If neither exists, the returned parser always fails.
+func resolveParser[T any](parser Parser[T]) Parser[T] {
+	if parser != nil {
+		return parser
+	}
+	if registered, ok := RegisteredParser[T](); ok {
+		return registered
+	}
+	return func(string) (T, error) {
+		var zero T
+		return zero, fmt.Errorf("query: no parser registered for %T", zero)
+	}
+}
+
+// formatValue formats v with the formatter registered for its type, its
+// MarshalText method, or fmt.Sprint, in that order.
+func formatValue(v any) string {
+	if entry, ok := lookupType(reflect.TypeOf(v)); ok && entry.format != nil {
+		return entry.format(reflect.ValueOf(v))
+	}
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// money is a struct type, so without registration Bind would treat it as a
+// nested struct.
+type money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m money) String() string {
+	return fmt.Sprintf("%d.%02d%s", m.Cents/100, m.Cents%100, m.Currency)
+}
+
+func parseMoney(s string) (money, error) {
+	if len(s) < 4 {
+		return money{}, errors.New("invalid amount")
+	}
+	amount, err := strconv.ParseFloat(s[:len(s)-3], 64)
+	if err != nil {
+		return money{}, err
+	}
+	return money{Cents: int64(math.Round(amount * 100)), Currency: s[len(s)-3:]}, nil
+}
+
+func init() {
+	RegisterType(parseMoney, money.String)
+}
+
+func TestRegisteredValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected money
+	}{
+		{"parsed", "/?min=12.50EUR", money{Cents: 1250, Currency: "EUR"}},
+		{"missing", "/", money{Currency: "USD"}},
+		{"invalid", "/?min=EUR", money{Currency: "USD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := Value(r, "min", money{Currency: "USD"}, nil); got != tt.expected {
+				t.Errorf("Value() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisteredSlice(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?price=1EUR&price=2.5USD", nil)
+	got := Slice(r, "price", money{}, nil)
+	expected := []money{{Cents: 100, Currency: "EUR"}, {Cents: 250, Currency: "USD"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Slice() = %v, want %v", got, expected)
+	}
+}
+
+func TestUnregisteredNilParser(t *testing.T) {
+	type unregistered struct{ N int }
+
+	var reported error
+	e := NewExtractor(Config{OnParseError: func(_, _, _ string, err error) { reported = err }})
+	r := httptest.NewRequest("GET", "/?x=1", nil)
+
+	if got := ValueWith(e, r, "x", unregistered{N: 7}, nil); got.N != 7 {
+		t.Errorf("ValueWith() = %v, want default", got)
+	}
+	if reported == nil {
+		t.Error("ValueWith() did not report the missing parser")
+	}
+	if _, ok := RegisteredParser[unregistered](); ok {
+		t.Error("RegisteredParser() ok = true for unregistered type")
+	}
+}
+
+func TestRegisteredBind(t *testing.T) {
+	type params struct {
+		Min    money   `query:"min"`
+		Max    *money  `query:"max"`
+		Prices []money `query:"price"`
+	}
+
+	r := httptest.NewRequest("GET", "/?min=1EUR&max=9.99EUR&price=2EUR&price=3EUR", nil)
+	var got params
+	if err := Bind(r, &got); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	expected := params{
+		Min:    money{Cents: 100, Currency: "EUR"},
+		Max:    &money{Cents: 999, Currency: "EUR"},
+		Prices: []money{{Cents: 200, Currency: "EUR"}, {Cents: 300, Currency: "EUR"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Bind() = %+v, want %+v", got, expected)
+	}
+
+	r = httptest.NewRequest("GET", "/?min=cheap", nil)
+	var fe *FieldError
+	if err := Bind(r, &got); !errors.As(err, &fe) || fe.Key != "min" {
+		t.Errorf("Bind() error = %v, want field error for min", err)
+	}
+}
+
+func TestBuilderSetValue(t *testing.T) {
+	got := NewBuilder().
+		SetValue("min", money{Cents: 1250, Currency: "EUR"}).
+		SetValue("since", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)).
+		SetValue("n", 3).
+		Encode()
+
+	expected := "min=12.50EUR&n=3&since=2024-01-02T00%3A00%3A00Z"
+	if got != expected {
+		t.Errorf("Builder.Encode() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,98 @@
+package query
+
+import (
+	"html"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringOpts configures the sanitization performed by StringWith. Steps are
+// applied in field order: control characters are stripped, whitespace trimmed,
+// the value truncated, and finally HTML-escaped.
+type StringOpts struct {
+	// StripControl removes control characters (including tabs and newlines) and
+	// invalid UTF-8 sequences.
+	StripControl bool
+
+	// TrimSpace removes leading and trailing whitespace.
+	TrimSpace bool
+
+	// MaxLength truncates the value to at most this many characters (runes),
+	// never splitting a multi-byte character. Zero means no limit.
+	MaxLength int
+
+	// EscapeHTML escapes <, >, &, ' and " so the value can be embedded in HTML.
+	// Escaping happens after truncation, so the result may exceed MaxLength.
+	EscapeHTML bool
+}
+
+// StringWith extracts a string value and sanitizes it according to opts.
+// Returns defaultValue if the key is missing, or if the value is empty after
+// sanitization.
+//
+// Example:
+//
+//	// URL: /search?q=%20%20golang%00%20
+//	q := query.StringWith(r, "q", "", query.StringOpts{
+//	    StripControl: true,
+//	    TrimSpace:    true,
+//	    MaxLength:    256,
+//	})  // "golang"
+func StringWith(r *http.Request, key string, defaultValue string, opts StringOpts) string {
+	val := sanitize(String(r, key, ""), opts)
+	if val == "" {
+		return defaultValue
+	}
+	return val
+}
+
+// Clean extracts a string value with control characters and invalid UTF-8
+// removed, surrounding whitespace trimmed, and truncated to maxLength characters.
+// It is shorthand for the sanitization most search and free-text parameters need.
+// Returns defaultValue if the key is missing or nothing is left after cleaning.
+//
+// Example:
+//
+//	q := query.Clean(r, "q", 256, "")
+func Clean(r *http.Request, key string, maxLength int, defaultValue string) string {
+	return StringWith(r, key, defaultValue, StringOpts{
+		StripControl: true,
+		TrimSpace:    true,
+		MaxLength:    maxLength,
+	})
+}
+
+func sanitize(s string, opts StringOpts) string {
+	if opts.StripControl {
+		s = strings.Map(func(c rune) rune {
+			if c == utf8.RuneError || unicode.IsControl(c) {
+				return -1
+			}
+			return c
+		}, strings.ToValidUTF8(s, ""))
+	}
+	if opts.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if opts.MaxLength > 0 && utf8.RuneCountInString(s) > opts.MaxLength {
+		s = truncateRunes(s, opts.MaxLength)
+	}
+	if opts.EscapeHTML {
+		s = html.EscapeString(s)
+	}
+	return s
+}
+
+// truncateRunes returns the first n runes of s.
+func truncateRunes(s string, n int) string {
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestStringWith(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		opts     StringOpts
+		expected string
+	}{
+		{"no options", " a\x00b ", StringOpts{}, " a\x00b "},
+		{"strip control", "a\x00b\x1fc\td\ne", StringOpts{StripControl: true}, "abcde"},
+		{"strip invalid utf8", "a\xffb", StringOpts{StripControl: true}, "ab"},
+		{"keeps unicode", "héllo 世界", StringOpts{StripControl: true}, "héllo 世界"},
+		{"trim", "  go  ", StringOpts{TrimSpace: true}, "go"},
+		{"truncate bytes", "abcdef", StringOpts{MaxLength: 3}, "abc"},
+		{"truncate runes", "世界你好", StringOpts{MaxLength: 2}, "世界"},
+		{"under limit", "ab", StringOpts{MaxLength: 3}, "ab"},
+		{"escape html", `<b>"x" & 'y'</b>`, StringOpts{EscapeHTML: true}, "&lt;b&gt;&#34;x&#34; &amp; &#39;y&#39;&lt;/b&gt;"},
+		{"escape after truncate", "<<<<", StringOpts{MaxLength: 2, EscapeHTML: true}, "&lt;&lt;"},
+		{"trim after strip", "\x00  go  \x00", StringOpts{StripControl: true, TrimSpace: true}, "go"},
+		{"empty after sanitize uses default", " \x00 ", StringOpts{StripControl: true, TrimSpace: true}, "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?q="+url.QueryEscape(tt.value), nil)
+			got := StringWith(r, "q", "default", tt.opts)
+			if got != tt.expected {
+				t.Errorf("StringWith() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"cleaned", "/?q=%20%20gopher%00%20", "gophe"},
+		{"cleaned short", "/?q=%20go%00%20", "go"},
+		{"truncated", "/?q=abcdefghij", "abcde"},
+		{"whitespace only", "/?q=%20%09%20", "none"},
+		{"missing", "/", "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Clean(r, "q", 5, "none")
+			if got != tt.expected {
+				t.Errorf("Clean() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
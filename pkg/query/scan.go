@@ -0,0 +1,123 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxScanParams bounds the fast path. Query strings with more parameters go
+// through url.ParseQuery, so its own parameter limit applies exactly as it
+// would for r.URL.Query().
+const maxScanParams = 1000
+
+// firstValue returns the first value of key as e.lookup would resolve it, and
+// whether the key is present. Extractors reading the URL query without
+// trimming, limits or case-insensitive keys scan r.URL.RawQuery directly,
+// avoiding the url.Values map that r.URL.Query() builds on every call.
+func (e *Extractor) firstValue(r *http.Request, key string) (string, bool) {
+	if e.rawScan && strings.Count(r.URL.RawQuery, "&") < maxScanParams {
+		return scanFirst(r.URL.RawQuery, key, !e.cfg.DisableArrayBrackets && !strings.HasSuffix(key, "[]"))
+	}
+
+	vals := e.lookup(e.values(r), key)
+	if len(vals) == 0 {
+		return "", false
+	}
+	return vals[0], true
+}
+
+// scanFirst finds the first value of key in rawQuery with the same results as
+// url.ParseQuery followed by lookup: pairs containing ";" or invalid escapes are
+// skipped, and with brackets a key[] value is used only if key itself is absent.
+// It allocates only when the matching value contains escapes.
+func scanFirst(rawQuery, key string, brackets bool) (string, bool) {
+	var bracketVal string
+	bracketFound := false
+
+	for rawQuery != "" {
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if pair == "" || strings.Contains(pair, ";") {
+			continue
+		}
+
+		rawKey, rawVal, _ := strings.Cut(pair, "=")
+		plain := unescapedEquals(rawKey, key, "")
+		if !plain && (!brackets || bracketFound || !unescapedEquals(rawKey, key, "[]")) {
+			continue
+		}
+
+		val, ok := unescapeValue(rawVal)
+		if !ok {
+			continue
+		}
+		if plain {
+			return val, true
+		}
+		bracketVal, bracketFound = val, true
+	}
+	return bracketVal, bracketFound
+}
+
+// unescapedEquals reports whether the query-unescaped form of raw equals
+// prefix+suffix, without allocating. Invalid escapes never match.
+func unescapedEquals(raw, prefix, suffix string) bool {
+	n, total := 0, len(prefix)+len(suffix)
+	for i := 0; i < len(raw); {
+		var c byte
+		switch raw[i] {
+		case '%':
+			if i+2 >= len(raw) || !isHex(raw[i+1]) || !isHex(raw[i+2]) {
+				return false
+			}
+			c = unhex(raw[i+1])<<4 | unhex(raw[i+2])
+			i += 3
+		case '+':
+			c = ' '
+			i++
+		default:
+			c = raw[i]
+			i++
+		}
+
+		if n >= total {
+			return false
+		}
+		want := byte(0)
+		if n < len(prefix) {
+			want = prefix[n]
+		} else {
+			want = suffix[n-len(prefix)]
+		}
+		if c != want {
+			return false
+		}
+		n++
+	}
+	return n == total
+}
+
+// unescapeValue query-unescapes s, returning s itself when it has no escapes.
+func unescapeValue(s string) (string, bool) {
+	if !strings.ContainsAny(s, "%+") {
+		return s, true
+	}
+	val, err := url.QueryUnescape(s)
+	return val, err == nil
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
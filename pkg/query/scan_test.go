@@ -0,0 +1,112 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestScanFirstMatchesParseQuery(t *testing.T) {
+	queries := []string{
+		"",
+		"a=1",
+		"a=1&a=2",
+		"a",
+		"a=",
+		"&&a=1&&",
+		"b=2&a=1",
+		"a[]=1&a=2",
+		"a%5B%5D=1&a%5B%5D=2",
+		"a[]=1&b=2",
+		"a%3D=x&a=y",
+		"a=x%3Dy",
+		"a=hello+world",
+		"a+b=1",
+		"a%20b=1",
+		"a=%zz&a=ok",
+		"a%zz=1&a=2",
+		"a=1;b=2&a=3",
+		"a;=1&a=2",
+		"%61=1",
+		"a=%",
+		"a%=1",
+		"A=1&a=2",
+		"=empty-key",
+		"a[]=1",
+		"a[][]=1&a[]=2",
+	}
+	keys := []string{"a", "a[]", "b", "a b", "a=", "", "missing"}
+
+	for _, q := range queries {
+		values, _ := url.ParseQuery(q)
+		for _, key := range keys {
+			for _, brackets := range []bool{true, false} {
+				var want []string
+				if brackets {
+					want = lookup(values, key)
+				} else {
+					want = values[key]
+				}
+				wantVal, wantOK := "", len(want) > 0
+				if wantOK {
+					wantVal = want[0]
+				}
+
+				got, ok := scanFirst(q, key, brackets && !strings.HasSuffix(key, "[]"))
+				if got != wantVal || ok != wantOK {
+					t.Errorf("scanFirst(%q, %q, %v) = %q, %v, want %q, %v", q, key, brackets, got, ok, wantVal, wantOK)
+				}
+			}
+		}
+	}
+}
+
+func TestFirstValueFallback(t *testing.T) {
+	// Query strings beyond maxScanParams are parsed by net/url.
+	target := "/?" + strings.Repeat("x=1&", maxScanParams) + "page=7"
+	r := httptest.NewRequest("GET", target, nil)
+	if got := Int(r, "page", 1); got != 7 {
+		t.Errorf("Int() = %v, want 7", got)
+	}
+
+	// Extractors that transform values must not use the raw scan.
+	e := NewExtractor(Config{TrimSpace: true})
+	r = httptest.NewRequest("GET", "/?page=%207%20", nil)
+	if got := e.Int(r, "page", 1); got != 7 {
+		t.Errorf("Extractor.Int() = %v, want 7", got)
+	}
+}
+
+func TestSingleValueAllocations(t *testing.T) {
+	r := httptest.NewRequest("GET", "/products?category=books&page=2&limit=50&active=true&sort=price", nil)
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"String", func() { _ = String(r, "sort", "") }},
+		{"Int", func() { _ = Int(r, "page", 1) }},
+		{"Bool", func() { _ = Bool(r, "active", false) }},
+		{"Has", func() { _ = Has(r, "limit") }},
+		{"missing", func() { _ = Int(r, "offset", 0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.fn); allocs != 0 {
+				t.Errorf("%s allocated %v times per call, want 0", tt.name, allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkIntParsedQuery(b *testing.B) {
+	// TrimSpace disables the raw scan, so every call parses r.URL.Query().
+	e := NewExtractor(Config{TrimSpace: true})
+	r := httptest.NewRequest("GET", "/?page=42", nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = e.Int(r, "page", 1)
+	}
+}
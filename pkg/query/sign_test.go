@@ -0,0 +1,81 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("s3cret")
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	sign := func(raw string, expires time.Time) string {
+		u, _ := url.Parse(raw)
+		if err := Sign(u, secret, expires); err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return u.String()
+	}
+
+	valid := sign("https://example.com/download?file=report.pdf&v=2", future)
+
+	tests := []struct {
+		name    string
+		target  string
+		secret  []byte
+		wantErr error
+	}{
+		{"valid", valid, secret, nil},
+		{"no expiry", sign("https://example.com/download?file=a.pdf", time.Time{}), secret, nil},
+		{"reordered params", reorder(valid), secret, nil},
+		{"expired", sign("https://example.com/download?file=report.pdf", past), secret, ErrSignatureExpired},
+		{"wrong secret", valid, []byte("other"), ErrInvalidSignature},
+		{"tampered param", strings.Replace(valid, "report.pdf", "secrets.pdf", 1), secret, ErrInvalidSignature},
+		{"added param", valid + "&admin=1", secret, ErrInvalidSignature},
+		{"tampered path", strings.Replace(valid, "/download", "/delete", 1), secret, ErrInvalidSignature},
+		{"extended expiry", strings.Replace(valid, "expires=", "expires=9", 1), secret, ErrInvalidSignature},
+		{"unsigned", "https://example.com/download?file=report.pdf", secret, ErrInvalidSignature},
+		{"empty secret", valid, nil, ErrEmptySecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			if err := VerifySigned(r, tt.secret); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifySigned() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignReplacesSignature(t *testing.T) {
+	secret := []byte("s3cret")
+	u, _ := url.Parse("/download?file=a.pdf")
+	_ = Sign(u, secret, time.Time{})
+	_ = Sign(u, secret, time.Time{})
+
+	if n := len(u.Query()[SignatureParam]); n != 1 {
+		t.Errorf("re-signed URL has %d signatures, want 1", n)
+	}
+	if err := VerifySigned(httptest.NewRequest("GET", u.String(), nil), secret); err != nil {
+		t.Errorf("VerifySigned() after re-signing error = %v", err)
+	}
+	if err := Sign(u, nil, time.Time{}); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("Sign(nil secret) error = %v, want ErrEmptySecret", err)
+	}
+}
+
+// reorder reverses the order of the query parameters of raw.
+func reorder(raw string) string {
+	base, rawQuery, _ := strings.Cut(raw, "?")
+	pairs := strings.Split(rawQuery, "&")
+	for i, j := 0, len(pairs)-1; i < j; i, j = i+1, j-1 {
+		pairs[i], pairs[j] = pairs[j], pairs[i]
+	}
+	return base + "?" + strings.Join(pairs, "&")
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+)
+
+// Unique extracts all values for a query parameter with duplicates removed,
+// preserving the order in which values were first seen.
+// Returns an empty slice if the key is not present.
+//
+// Example: For URL "?tag=go&tag=rust&tag=go"
+//
+//	tags := query.Unique(r, "tag")  // []string{"go", "rust"}
+func Unique(r *http.Request, key string) []string {
+	return Dedupe(Strings(r, key))
+}
+
+// Dedupe returns a new slice with duplicate elements removed, preserving the
+// order in which elements were first seen. It composes with any slice
+// extractor.
+//
+// Example:
+//
+//	// URL: /items?id=3&id=1&id=3
+//	ids := query.Dedupe(query.Ints(r, "id", 0))  // []int{3, 1}
+func Dedupe[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// SortedUnique returns a new slice holding the distinct elements of s in
+// ascending order.
+//
+// Example:
+//
+//	// URL: /items?id=3&id=1&id=3
+//	ids := query.SortedUnique(query.Ints(r, "id", 0))  // []int{1, 3}
+func SortedUnique[T cmp.Ordered](s []T) []T {
+	result := slices.Clone(s)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// Chunk splits s into consecutive batches of at most n elements, for handing
+// bulk input such as ?id=... repeated thousands of times to a backend in
+// bounded pieces. The batches share s's backing array but are clipped, so
+// appending to one does not overwrite the next. Returns nil if s is empty or
+// n is less than 1.
+//
+// Example:
+//
+//	// URL: /items?id=1&id=2&id=3&id=4&id=5
+//	for _, batch := range query.Chunk(query.Ints(r, "id", 0), 2) {
+//	    store.Load(batch)  // [1 2], [3 4], [5]
+//	}
+func Chunk[T any](s []T, n int) [][]T {
+	if len(s) == 0 || n < 1 {
+		return nil
+	}
+	return slices.Collect(slices.Chunk(s, n))
+}
@@ -0,0 +1,116 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected []string
+	}{
+		{"duplicates removed", "/?tag=go&tag=rust&tag=go", []string{"go", "rust"}},
+		{"order preserved", "/?tag=b&tag=a&tag=b&tag=c&tag=a", []string{"b", "a", "c"}},
+		{"case-sensitive", "/?tag=Go&tag=go", []string{"Go", "go"}},
+		{"no duplicates", "/?tag=go", []string{"go"}},
+		{"missing key", "/", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Unique(r, "tag")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Unique() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	input := []int{3, 1, 3, 2, 1}
+	got := Dedupe(input)
+	if !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Errorf("Dedupe() = %v, want [3 1 2]", got)
+	}
+	if !reflect.DeepEqual(input, []int{3, 1, 3, 2, 1}) {
+		t.Errorf("Dedupe() modified its input: %v", input)
+	}
+	if got := Dedupe([]int(nil)); got == nil || len(got) != 0 {
+		t.Errorf("Dedupe(nil) = %#v, want empty slice", got)
+	}
+}
+
+func TestSortedUnique(t *testing.T) {
+	input := []int{3, 1, 3, 2, 1}
+	got := SortedUnique(input)
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("SortedUnique() = %v, want [1 2 3]", got)
+	}
+	if !reflect.DeepEqual(input, []int{3, 1, 3, 2, 1}) {
+		t.Errorf("SortedUnique() modified its input: %v", input)
+	}
+
+	r := httptest.NewRequest("GET", "/?tag=rust&tag=go&tag=rust", nil)
+	if got := SortedUnique(Strings(r, "tag")); !reflect.DeepEqual(got, []string{"go", "rust"}) {
+		t.Errorf("SortedUnique(Strings()) = %v, want [go rust]", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		n        int
+		expected [][]int
+	}{
+		{"even", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"remainder", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"larger than slice", []int{1, 2}, 10, [][]int{{1, 2}}},
+		{"empty", nil, 2, nil},
+		{"invalid size", []int{1, 2}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.input, tt.n); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Chunk() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	input := []int{1, 2, 3, 4}
+	chunks := Chunk(input, 2)
+	grown := append(chunks[0], 99)
+	if input[2] != 3 || grown[2] != 99 {
+		t.Errorf("appending to a chunk overwrote the next one: %v", input)
+	}
+}
+
+func TestMaxItems(t *testing.T) {
+	e := NewExtractor(Config{MaxItems: 2})
+	r := httptest.NewRequest("GET", "/?id=1&id=2&id=3&tag=a&tag=b&tag=c", nil)
+
+	if got := e.Ints(r, "id", 0); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Extractor.Ints() = %v, want [1 2]", got)
+	}
+	if got := e.Strings(r, "tag"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Extractor.Strings() = %v, want [a b]", got)
+	}
+	if got := SliceWith(e, r, "id", "", func(s string) (string, error) { return s, nil }); len(got) != 2 {
+		t.Errorf("SliceWith() = %v, want 2 values", got)
+	}
+	if got := e.Int(r, "id", 0); got != 1 {
+		t.Errorf("Extractor.Int() = %d, want 1", got)
+	}
+
+	var params struct {
+		IDs []int `query:"id"`
+	}
+	if err := e.Bind(r, &params); err != nil || !reflect.DeepEqual(params.IDs, []int{1, 2}) {
+		t.Errorf("Extractor.Bind() = %v, %v, want [1 2]", params.IDs, err)
+	}
+}
@@ -0,0 +1,61 @@
+package query
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// SortField is one entry of a sort specification.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// String returns the field in sort-parameter form, prefixed with "-" when descending.
+func (f SortField) String() string {
+	if f.Desc {
+		return "-" + f.Field
+	}
+	return f.Field
+}
+
+// Sort parses a comma-separated sort specification such as ?sort=-created,title,
+// where a leading "-" sorts that field in descending order. Repeated parameters
+// are combined, surrounding whitespace is ignored, and only the first occurrence
+// of each field is kept. Fields not in allowed are discarded; a nil allowed list
+// accepts any field.
+// Returns an empty slice if the key is missing or no field is allowed.
+//
+// Example:
+//
+//	// URL: /articles?sort=-created,title
+//	order := query.Sort(r, "sort", []string{"created", "title"})
+//	// []query.SortField{{Field: "created", Desc: true}, {Field: "title"}}
+func Sort(r *http.Request, key string, allowed []string) []SortField {
+	return parseSort(Strings(r, key), allowed)
+}
+
+// parseSort parses the sort specifications in vals. See Sort.
+func parseSort(vals []string, allowed []string) []SortField {
+	result := []SortField{}
+	for _, val := range vals {
+		for _, spec := range strings.Split(val, ",") {
+			// A "+" prefix arrives as a space unless percent-encoded.
+			spec = strings.TrimSpace(spec)
+			field := SortField{Field: strings.TrimPrefix(spec, "+")}
+			if rest, ok := strings.CutPrefix(spec, "-"); ok {
+				field = SortField{Field: rest, Desc: true}
+			}
+
+			if field.Field == "" || (allowed != nil && !slices.Contains(allowed, field.Field)) {
+				continue
+			}
+			if slices.ContainsFunc(result, func(f SortField) bool { return f.Field == field.Field }) {
+				continue
+			}
+			result = append(result, field)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	allowed := []string{"created", "title", "price"}
+
+	tests := []struct {
+		name     string
+		url      string
+		allowed  []string
+		expected []SortField
+	}{
+		{"ascending", "/?sort=title", allowed, []SortField{{Field: "title"}}},
+		{"descending", "/?sort=-created", allowed, []SortField{{Field: "created", Desc: true}}},
+		{"multiple", "/?sort=-created,title", allowed, []SortField{{Field: "created", Desc: true}, {Field: "title"}}},
+		{"plus prefix", "/?sort=%2Bprice,+title", allowed, []SortField{{Field: "price"}, {Field: "title"}}},
+		{"repeated params", "/?sort=title&sort=-price", allowed, []SortField{{Field: "title"}, {Field: "price", Desc: true}}},
+		{"first occurrence wins", "/?sort=title,-title", allowed, []SortField{{Field: "title"}}},
+		{"not allowed", "/?sort=password,title", allowed, []SortField{{Field: "title"}}},
+		{"nil allowed accepts any", "/?sort=-anything", nil, []SortField{{Field: "anything", Desc: true}}},
+		{"empty entries", "/?sort=,-,title,", allowed, []SortField{{Field: "title"}}},
+		{"missing", "/", allowed, []SortField{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Sort(r, "sort", tt.allowed)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Sort() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortFieldString(t *testing.T) {
+	if got := (SortField{Field: "created", Desc: true}).String(); got != "-created" {
+		t.Errorf("SortField.String() = %q, want -created", got)
+	}
+	if got := (SortField{Field: "title"}).String(); got != "title" {
+		t.Errorf("SortField.String() = %q, want title", got)
+	}
+}
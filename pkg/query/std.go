@@ -0,0 +1,163 @@
+package query
+
+import (
+	"math"
+	"net/http"
+	"slices"
+)
+
+// Defaults used by Std unless overridden with options.
+const (
+	DefaultPerPage    = 20
+	DefaultMaxPerPage = 100
+	DefaultMaxQLength = 256
+)
+
+// StdParams is the bundle of list-endpoint parameters extracted by Std.
+type StdParams struct {
+	Page    int                          // 1-based page number, at least 1
+	PerPage int                          // Page size, clamped to [1, max]
+	Sort    []SortField                  // Sort order, or the default sort if none was sent
+	Q       string                       // Cleaned free-text search, "" if not sent
+	Filters map[string]Condition[string] // Conditions from filter[FIELD]=[op:]value
+}
+
+// Offset returns the number of items to skip for the current page.
+func (p StdParams) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// StdOption configures Std.
+type StdOption func(*stdConfig)
+
+type stdConfig struct {
+	pageKey, perPageKey, sortKey, qKey, filterKey string
+
+	perPage, maxPerPage int
+	maxQLength          int
+	sortFields          []string
+	defaultSort         string
+	filterFields        []string
+}
+
+// ListKeys sets the parameter names Std reads. An empty name keeps the
+// default: "page", "per_page", "sort", "q" and "filter".
+func ListKeys(page, perPage, sort, q, filter string) StdOption {
+	return func(c *stdConfig) {
+		setKey(&c.pageKey, page)
+		setKey(&c.perPageKey, perPage)
+		setKey(&c.sortKey, sort)
+		setKey(&c.qKey, q)
+		setKey(&c.filterKey, filter)
+	}
+}
+
+func setKey(dst *string, name string) {
+	if name != "" {
+		*dst = name
+	}
+}
+
+// PerPageLimits sets the page size used when none is sent and the largest
+// page size accepted. Values below 1 keep DefaultPerPage and
+// DefaultMaxPerPage respectively.
+func PerPageLimits(defaultSize, maxSize int) StdOption {
+	return func(c *stdConfig) {
+		if defaultSize > 0 {
+			c.perPage = defaultSize
+		}
+		if maxSize > 0 {
+			c.maxPerPage = maxSize
+		}
+	}
+}
+
+// MaxQLength sets the longest search string, in characters, that Std keeps.
+// Longer values are truncated. The default is DefaultMaxQLength.
+func MaxQLength(n int) StdOption {
+	return func(c *stdConfig) {
+		c.maxQLength = n
+	}
+}
+
+// SortableFields restricts the fields accepted in the sort parameter. Without
+// it any field is accepted.
+func SortableFields(fields ...string) StdOption {
+	return func(c *stdConfig) {
+		c.sortFields = fields
+	}
+}
+
+// DefaultSort sets the sort order used when the request sends no usable sort
+// field, written like the parameter itself, such as "-created,title".
+func DefaultSort(spec string) StdOption {
+	return func(c *stdConfig) {
+		c.defaultSort = spec
+	}
+}
+
+// FilterableFields restricts the fields accepted as filter[FIELD]. Without it
+// any field is accepted.
+func FilterableFields(fields ...string) StdOption {
+	return func(c *stdConfig) {
+		c.filterFields = fields
+	}
+}
+
+// Std extracts the parameters most list endpoints need in one call:
+// ?page=2&per_page=50&sort=-created&q=shoes&filter[status]=in:open,pending.
+//
+// Page defaults to 1 and PerPage to DefaultPerPage; out-of-range values are
+// clamped rather than rejected. Sort follows Sort, Q follows Clean, and each
+// filter[FIELD] value is parsed as by Filter, with values that fail to parse
+// dropped. Parameter names, limits and allowed fields are set with options.
+//
+// Example:
+//
+//	// URL: /products?page=2&sort=-price&filter[color]=in:red,blue
+//	p := query.Std(r,
+//	    query.SortableFields("price", "name"),
+//	    query.DefaultSort("name"),
+//	    query.FilterableFields("color", "size"),
+//	)
+//	products := store.List(p.Filters, p.Sort, p.Offset(), p.PerPage)
+func Std(r *http.Request, opts ...StdOption) StdParams {
+	cfg := stdConfig{
+		pageKey:    "page",
+		perPageKey: "per_page",
+		sortKey:    "sort",
+		qKey:       "q",
+		filterKey:  "filter",
+		perPage:    DefaultPerPage,
+		maxPerPage: DefaultMaxPerPage,
+		maxQLength: DefaultMaxQLength,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	p := StdParams{
+		Page:    IntInRange(r, cfg.pageKey, 1, 1, math.MaxInt),
+		PerPage: IntInRange(r, cfg.perPageKey, min(cfg.perPage, cfg.maxPerPage), 1, cfg.maxPerPage),
+		Sort:    Sort(r, cfg.sortKey, cfg.sortFields),
+		Q:       Clean(r, cfg.qKey, cfg.maxQLength, ""),
+		Filters: map[string]Condition[string]{},
+	}
+	if len(p.Sort) == 0 && cfg.defaultSort != "" {
+		p.Sort = parseSort([]string{cfg.defaultSort}, nil)
+	}
+
+	for field, val := range Map(r, cfg.filterKey) {
+		if cfg.filterFields != nil && !slices.Contains(cfg.filterFields, field) {
+			continue
+		}
+		if cond, ok := parseCondition(val, identity, nil); ok {
+			p.Filters[field] = cond
+		}
+	}
+	return p
+}
+
+func identity(s string) (string, error) {
+	return s, nil
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStd(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		opts []StdOption
+		want StdParams
+	}{
+		{
+			name: "defaults",
+			url:  "/",
+			want: StdParams{Page: 1, PerPage: 20, Sort: []SortField{}, Filters: map[string]Condition[string]{}},
+		},
+		{
+			name: "all parameters",
+			url:  "/?page=3&per_page=50&sort=-created,title&q=+red%20shoes+&filter[status]=in:open,pending&filter[color]=red",
+			want: StdParams{
+				Page:    3,
+				PerPage: 50,
+				Sort:    []SortField{{Field: "created", Desc: true}, {Field: "title"}},
+				Q:       "red shoes",
+				Filters: map[string]Condition[string]{
+					"status": {Op: OpIn, Values: []string{"open", "pending"}},
+					"color":  {Op: OpEq, Value: "red"},
+				},
+			},
+		},
+		{
+			name: "clamped",
+			url:  "/?page=0&per_page=1000",
+			want: StdParams{Page: 1, PerPage: 100, Sort: []SortField{}, Filters: map[string]Condition[string]{}},
+		},
+		{
+			name: "custom keys and limits",
+			url:  "/?p=2&limit=500&order=name&search=x&where[id]=gt:5",
+			opts: []StdOption{ListKeys("p", "limit", "order", "search", "where"), PerPageLimits(10, 200)},
+			want: StdParams{
+				Page:    2,
+				PerPage: 200,
+				Sort:    []SortField{{Field: "name"}},
+				Q:       "x",
+				Filters: map[string]Condition[string]{"id": {Op: OpGt, Value: "5"}},
+			},
+		},
+		{
+			name: "allowed fields and default sort",
+			url:  "/?sort=secret&filter[color]=red&filter[owner]=me",
+			opts: []StdOption{SortableFields("name"), DefaultSort("-created"), FilterableFields("color")},
+			want: StdParams{
+				Page:    1,
+				PerPage: 20,
+				Sort:    []SortField{{Field: "created", Desc: true}},
+				Filters: map[string]Condition[string]{"color": {Op: OpEq, Value: "red"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := Std(r, tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Std() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdOffset(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?page=3&per_page=25", nil)
+	if got := Std(r).Offset(); got != 50 {
+		t.Errorf("StdParams.Offset() = %d, want 50", got)
+	}
+}
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ElementError describes a single value of a multi-value parameter that could
+// not be parsed.
+type ElementError struct {
+	Key   string // Parameter name
+	Index int    // Position of the value among the parameter's values
+	Value string // Raw value that failed to parse
+	Err   error  // Error returned by the parser
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("query: %s[%d] %q: %v", e.Key, e.Index, e.Value, e.Err)
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// ElementErrors collects the ElementError of every value that failed to parse.
+type ElementErrors []*ElementError
+
+func (e ElementErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual element errors, so errors.Is and errors.As
+// can inspect them.
+func (e ElementErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Indices returns the positions of the values that failed to parse.
+func (e ElementErrors) Indices() []int {
+	indices := make([]int, len(e))
+	for i, err := range e {
+		indices[i] = err.Index
+	}
+	return indices
+}
+
+// SliceStrict extracts all values for a query parameter and converts them using
+// the provided parser. Unlike Slice, invalid values are skipped rather than
+// replaced by a default, and reported in an ElementErrors error so handlers can
+// reject the request. The returned slice always contains the valid values.
+// Returns an empty slice and a nil error if the key is not present.
+//
+// Example:
+//
+//	// URL: /api/items?id=1&id=oops&id=3
+//	ids, err := query.SliceStrict(r, "id", strconv.Atoi)
+//	// ids = []int{1, 3}, err reports index 1 ("oops")
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+func SliceStrict[T any](r *http.Request, key string, parser Parser[T]) ([]T, error) {
+	parser = resolveParser(parser)
+	vals := lookup(r.URL.Query(), key)
+	result := make([]T, 0, len(vals))
+
+	var errs ElementErrors
+	for i, val := range vals {
+		parsed, err := parser(val)
+		if err != nil {
+			errs = append(errs, &ElementError{Key: key, Index: i, Value: val, Err: err})
+			continue
+		}
+		result = append(result, parsed)
+	}
+
+	if len(errs) > 0 {
+		return result, errs
+	}
+	return result, nil
+}
+
+// IntsStrict extracts all integer values for a query parameter, skipping and
+// reporting invalid values. See SliceStrict.
+func IntsStrict(r *http.Request, key string) ([]int, error) {
+	return SliceStrict(r, key, strconv.Atoi)
+}
+
+// Int64sStrict extracts all int64 values for a query parameter, skipping and
+// reporting invalid values. See SliceStrict.
+func Int64sStrict(r *http.Request, key string) ([]int64, error) {
+	return SliceStrict(r, key, parseInt64)
+}
+
+// Float64sStrict extracts all float64 values for a query parameter, skipping and
+// reporting invalid values. See SliceStrict.
+func Float64sStrict(r *http.Request, key string) ([]float64, error) {
+	return SliceStrict(r, key, parseFloat64)
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestIntsStrict(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		expected        []int
+		expectedIndices []int
+	}{
+		{"all valid", "/?id=1&id=2&id=3", []int{1, 2, 3}, nil},
+		{"one invalid", "/?id=1&id=oops&id=3", []int{1, 3}, []int{1}},
+		{"all invalid", "/?id=a&id=b", []int{}, []int{0, 1}},
+		{"empty value invalid", "/?id=1&id=", []int{1}, []int{1}},
+		{"missing key", "/?other=1", []int{}, nil},
+		{"array brackets", "/?id[]=4&id[]=x", []int{4}, []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got, err := IntsStrict(r, "id")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("IntsStrict() = %v, want %v", got, tt.expected)
+			}
+
+			if tt.expectedIndices == nil {
+				if err != nil {
+					t.Errorf("IntsStrict() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs ElementErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("IntsStrict() error = %v, want ElementErrors", err)
+			}
+			if !reflect.DeepEqual(errs.Indices(), tt.expectedIndices) {
+				t.Errorf("ElementErrors.Indices() = %v, want %v", errs.Indices(), tt.expectedIndices)
+			}
+		})
+	}
+}
+
+func TestSliceStrictErrors(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?n=1&n=x", nil)
+	_, err := SliceStrict(r, "n", strconv.Atoi)
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("errors.Is(err, strconv.ErrSyntax) = false for %v", err)
+	}
+
+	var elem *ElementError
+	if !errors.As(err, &elem) {
+		t.Fatalf("errors.As(err, *ElementError) = false for %v", err)
+	}
+	if elem.Key != "n" || elem.Index != 1 || elem.Value != "x" {
+		t.Errorf("ElementError = %+v, want key n, index 1, value x", elem)
+	}
+
+	expected := `query: n[1] "x": strconv.Atoi: parsing "x": invalid syntax`
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestInt64sAndFloat64sStrict(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?v=1&v=2.5&v=bad", nil)
+
+	ints, err := Int64sStrict(r, "v")
+	if !reflect.DeepEqual(ints, []int64{1}) || err == nil {
+		t.Errorf("Int64sStrict() = %v, %v, want [1] with error", ints, err)
+	}
+
+	floats, err := Float64sStrict(r, "v")
+	if !reflect.DeepEqual(floats, []float64{1, 2.5}) || err == nil {
+		t.Errorf("Float64sStrict() = %v, %v, want [1 2.5] with error", floats, err)
+	}
+}
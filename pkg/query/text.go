@@ -0,0 +1,40 @@
+package query
+
+import (
+	"encoding"
+	"net/http"
+)
+
+// Unmarshal extracts a value of any type whose pointer implements
+// encoding.TextUnmarshaler, such as custom ID types, semantic versions or
+// net/netip addresses, without writing a parser closure.
+// Returns defaultValue if the key is missing, empty, or UnmarshalText fails.
+//
+// Example:
+//
+//	// URL: /hosts?addr=192.168.1.10
+//	addr := query.Unmarshal(r, "addr", netip.Addr{})
+func Unmarshal[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](r *http.Request, key string, defaultValue T) T {
+	return Value(r, key, defaultValue, TextParser[T, PT]())
+}
+
+// TextParser returns a Parser that decodes values with UnmarshalText, for use
+// with Slice, ValuePtr and the other Parser-based functions.
+//
+// Example:
+//
+//	// URL: /hosts?addr=10.0.0.1&addr=10.0.0.2
+//	addrs := query.Slice(r, "addr", netip.Addr{}, query.TextParser[netip.Addr]())
+func TextParser[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}]() Parser[T] {
+	return func(s string) (T, error) {
+		var v T
+		err := PT(&v).UnmarshalText([]byte(s))
+		return v, err
+	}
+}
@@ -0,0 +1,78 @@
+package query
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// level is a test enum implementing encoding.TextUnmarshaler.
+type level int
+
+const (
+	levelInfo level = iota
+	levelWarn
+	levelError
+)
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "info":
+		*l = levelInfo
+	case "warn":
+		*l = levelWarn
+	case "error":
+		*l = levelError
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected level
+	}{
+		{"valid", "/?level=error", levelError},
+		{"case insensitive", "/?level=WARN", levelWarn},
+		{"invalid", "/?level=loud", levelInfo},
+		{"missing", "/", levelInfo},
+		{"empty", "/?level=", levelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Unmarshal(r, "level", levelInfo)
+			if got != tt.expected {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshalStdlibType(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?addr=192.168.1.10&bad=999.1.1.1", nil)
+
+	if got := Unmarshal(r, "addr", netip.Addr{}); got != netip.MustParseAddr("192.168.1.10") {
+		t.Errorf("Unmarshal(addr) = %v, want 192.168.1.10", got)
+	}
+	if got := Unmarshal(r, "bad", netip.IPv6Unspecified()); got != netip.IPv6Unspecified() {
+		t.Errorf("Unmarshal(bad) = %v, want default", got)
+	}
+}
+
+func TestTextParser(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?level=warn&level=bogus&level=error", nil)
+
+	got := Slice(r, "level", levelInfo, TextParser[level]())
+	expected := []level{levelWarn, levelInfo, levelError}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Slice(TextParser) = %v, want %v", got, expected)
+	}
+}
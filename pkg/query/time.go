@@ -0,0 +1,263 @@
+package query
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts tried, in order, when no layouts are given
+// to Time or TimeRange.
+var DefaultTimeLayouts = []string{time.RFC3339Nano, time.DateTime, time.DateOnly}
+
+// Time extracts a time.Time value from the query parameter with the given key.
+// The value is parsed with each layout in turn; if no layouts are given,
+// DefaultTimeLayouts is used. Returns defaultValue if the key is missing, empty,
+// or matches none of the layouts.
+//
+// Example:
+//
+//	// URL: /events?since=2024-01-15
+//	since := query.Time(r, "since", time.Time{})              // 2024-01-15 00:00 UTC
+//	at    := query.Time(r, "at", time.Now(), time.Kitchen)    // custom layout
+func Time(r *http.Request, key string, defaultValue time.Time, layouts ...string) time.Time {
+	return Value(r, key, defaultValue, timeParser(layouts))
+}
+
+// timeParser returns a Parser trying each layout in turn.
+func timeParser(layouts []string) Parser[time.Time] {
+	return func(s string) (time.Time, error) {
+		parsed, _, ok := parseTime(s, layouts)
+		if !ok {
+			return time.Time{}, strconv.ErrSyntax
+		}
+		return parsed, nil
+	}
+}
+
+// UnixUnit is the unit of a Unix timestamp parameter.
+type UnixUnit int
+
+// Units accepted by Unix.
+const (
+	UnixSeconds UnixUnit = iota
+	UnixMillis
+	UnixMicros
+	UnixNanos
+
+	// UnixAuto infers the unit from the magnitude of the value: up to 11 digits
+	// are seconds, up to 14 milliseconds, up to 17 microseconds, and anything
+	// larger nanoseconds. This is unambiguous for dates between 1973 and 5138.
+	UnixAuto
+)
+
+// Unix extracts a Unix timestamp such as ?since=1700000000 as a time.Time in
+// UTC. The value is read in seconds unless another unit is given; UnixAuto
+// accepts seconds and millisecond timestamps (as sent by JavaScript's
+// Date.now) from the same parameter.
+// Returns defaultValue if the key is missing, empty, or not an integer.
+//
+// Example:
+//
+//	// URL: /events?since=1700000000000
+//	since := query.Unix(r, "since", time.Time{}, query.UnixAuto)  // 2023-11-14 22:13:20 UTC
+func Unix(r *http.Request, key string, defaultValue time.Time, unit ...UnixUnit) time.Time {
+	u := UnixSeconds
+	if len(unit) > 0 {
+		u = unit[0]
+	}
+
+	return Value(r, key, defaultValue, func(s string) (time.Time, error) {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return unixTime(n, u).UTC(), nil
+	})
+}
+
+func unixTime(n int64, unit UnixUnit) time.Time {
+	if unit == UnixAuto {
+		switch abs := max(n, -n); {
+		case abs < 1e11:
+			unit = UnixSeconds
+		case abs < 1e14:
+			unit = UnixMillis
+		case abs < 1e17:
+			unit = UnixMicros
+		default:
+			unit = UnixNanos
+		}
+	}
+
+	switch unit {
+	case UnixMillis:
+		return time.UnixMilli(n)
+	case UnixMicros:
+		return time.UnixMicro(n)
+	case UnixNanos:
+		return time.Unix(0, n)
+	default:
+		return time.Unix(n, 0)
+	}
+}
+
+// Location extracts a time zone from the query parameter with the given key,
+// such as ?tz=America/New_York, loading it with time.LoadLocation. If allowed
+// names are given, only those zones are accepted. "Local" is always rejected so
+// the server's own zone never leaks into responses.
+// Returns defaultValue if the key is missing, empty, not allowed, or not a
+// known zone.
+//
+// Example:
+//
+//	// URL: /reports/daily?tz=Europe/Berlin
+//	loc := query.Location(r, "tz", time.UTC)
+//	day := time.Now().In(loc).Format(time.DateOnly)
+func Location(r *http.Request, key string, defaultValue *time.Location, allowed ...string) *time.Location {
+	return Value(r, key, defaultValue, func(s string) (*time.Location, error) {
+		if s == "Local" || (len(allowed) > 0 && !slices.Contains(allowed, s)) {
+			return nil, strconv.ErrSyntax
+		}
+		return time.LoadLocation(s)
+	})
+}
+
+// parseTime parses s with the first matching layout and reports which layout matched.
+func parseTime(s string, layouts []string) (time.Time, string, bool) {
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, layout, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// TimeRangeOption configures the validation performed by TimeRange.
+type TimeRangeOption func(*timeRangeConfig)
+
+type timeRangeConfig struct {
+	layouts      []string
+	maxSpan      time.Duration
+	swapReversed bool
+	inclusiveEnd bool
+}
+
+// TimeLayouts sets the layouts used to parse both ends of the range.
+// Defaults to DefaultTimeLayouts.
+func TimeLayouts(layouts ...string) TimeRangeOption {
+	return func(c *timeRangeConfig) {
+		c.layouts = layouts
+	}
+}
+
+// MaxSpan rejects ranges longer than d.
+func MaxSpan(d time.Duration) TimeRangeOption {
+	return func(c *timeRangeConfig) {
+		c.maxSpan = d
+	}
+}
+
+// SwapReversed swaps the start and end when the end comes before the start,
+// instead of rejecting the range.
+func SwapReversed() TimeRangeOption {
+	return func(c *timeRangeConfig) {
+		c.swapReversed = true
+	}
+}
+
+// InclusiveEnd treats a date-only end value (such as "2024-01-31") as covering
+// that whole day by advancing it to the following midnight. The returned pair
+// can then always be used as a half-open [start, end) interval.
+func InclusiveEnd() TimeRangeOption {
+	return func(c *timeRangeConfig) {
+		c.inclusiveEnd = true
+	}
+}
+
+// TimeRange extracts a validated start/end pair from two query parameters.
+//
+// Each end falls back to its default independently when missing, empty, or
+// unparseable. If the resolved range is reversed (and SwapReversed is not set)
+// or exceeds MaxSpan, the defaults are returned and ok is false.
+//
+// Example:
+//
+//	// URL: /reports?from=2024-01-01&to=2024-01-31
+//	now := time.Now()
+//	start, end, ok := query.TimeRange(r, "from", "to", now.AddDate(0, 0, -7), now,
+//	    query.MaxSpan(90*24*time.Hour),
+//	    query.InclusiveEnd(),
+//	)
+//	// start = 2024-01-01 00:00, end = 2024-02-01 00:00, ok = true
+func TimeRange(r *http.Request, fromKey, toKey string, defaultStart, defaultEnd time.Time, opts ...TimeRangeOption) (start, end time.Time, ok bool) {
+	var cfg timeRangeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	values := r.URL.Query()
+	start, end = defaultStart, defaultEnd
+
+	if val := first(values, fromKey); val != "" {
+		if parsed, _, ok := parseTime(val, cfg.layouts); ok {
+			start = parsed
+		}
+	}
+	if val := first(values, toKey); val != "" {
+		if parsed, layout, ok := parseTime(val, cfg.layouts); ok {
+			if cfg.inclusiveEnd && isDateOnlyLayout(layout) {
+				parsed = parsed.AddDate(0, 0, 1)
+			}
+			end = parsed
+		}
+	}
+
+	if end.Before(start) {
+		if !cfg.swapReversed {
+			return defaultStart, defaultEnd, false
+		}
+		start, end = end, start
+	}
+
+	if cfg.maxSpan > 0 && end.Sub(start) > cfg.maxSpan {
+		return defaultStart, defaultEnd, false
+	}
+
+	return start, end, true
+}
+
+// isDateOnlyLayout reports whether layout has no clock element: an hour
+// ("15", "03", "3"), minute ("04", "4"), second ("05", "5"), fractional
+// second (".000", ",999") or AM/PM ("PM", "pm"). No date or zone element of
+// package time contains the digits 3, 4 or 5, so any of them is a clock
+// element, as package time reads it.
+func isDateOnlyLayout(layout string) bool {
+	if strings.ContainsAny(layout, "345") || strings.Contains(layout, "PM") || strings.Contains(layout, "pm") {
+		return false
+	}
+	for i := 0; i < len(layout)-1; i++ {
+		if c := layout[i]; c != '.' && c != ',' {
+			continue
+		}
+		// A run of 0s or 9s after a separator, not followed by another
+		// digit, is a fractional second.
+		digit := layout[i+1]
+		if digit != '0' && digit != '9' {
+			continue
+		}
+		j := i + 1
+		for j < len(layout) && layout[j] == digit {
+			j++
+		}
+		if j == len(layout) || layout[j] < '0' || layout[j] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	_ "time/tzdata" // Location tests must not depend on the host's zone database
+)
+
+func TestTime(t *testing.T) {
+	fallback := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		url      string
+		layouts  []string
+		expected time.Time
+	}{
+		{"rfc3339", "/?at=" + url.QueryEscape("2024-01-15T10:30:00Z"), nil, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"rfc3339 offset", "/?at=" + url.QueryEscape("2024-01-15T10:30:00+02:00"), nil, time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC)},
+		{"date time", "/?at=" + url.QueryEscape("2024-01-15 10:30:00"), nil, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"date only", "/?at=2024-01-15", nil, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"custom layout", "/?at=15/01/2024", []string{"02/01/2006"}, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"custom layout mismatch", "/?at=2024-01-15", []string{"02/01/2006"}, fallback},
+		{"missing key", "/?other=value", nil, fallback},
+		{"empty value", "/?at=", nil, fallback},
+		{"invalid", "/?at=yesterday", nil, fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Time(r, "at", fallback, tt.layouts...)
+			if !got.Equal(tt.expected) {
+				t.Errorf("Time() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	defStart, defEnd := day(1), day(8)
+
+	tests := []struct {
+		name          string
+		url           string
+		opts          []TimeRangeOption
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedOK    bool
+	}{
+		{"both present", "/?from=2024-01-10&to=2024-01-20", nil, day(10), day(20), true},
+		{"both missing", "/", nil, defStart, defEnd, true},
+		{"only from", "/?from=2024-01-03", nil, day(3), defEnd, true},
+		{"only to", "/?to=2024-01-05", nil, defStart, day(5), true},
+		{"invalid from uses default", "/?from=bad&to=2024-01-05", nil, defStart, day(5), true},
+		{"reversed rejected", "/?from=2024-01-20&to=2024-01-10", nil, defStart, defEnd, false},
+		{"reversed swapped", "/?from=2024-01-20&to=2024-01-10", []TimeRangeOption{SwapReversed()}, day(10), day(20), true},
+		{"within max span", "/?from=2024-01-10&to=2024-01-17", []TimeRangeOption{MaxSpan(7 * 24 * time.Hour)}, day(10), day(17), true},
+		{"exceeds max span", "/?from=2024-01-10&to=2024-01-18", []TimeRangeOption{MaxSpan(7 * 24 * time.Hour)}, defStart, defEnd, false},
+		{"inclusive end date only", "/?from=2024-01-10&to=2024-01-20", []TimeRangeOption{InclusiveEnd()}, day(10), day(21), true},
+		{
+			"inclusive end ignores clock values",
+			"/?from=2024-01-10&to=" + url.QueryEscape("2024-01-20T12:00:00Z"),
+			[]TimeRangeOption{InclusiveEnd()},
+			day(10),
+			time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC),
+			true,
+		},
+		{"inclusive end single day", "/?from=2024-01-10&to=2024-01-10", []TimeRangeOption{InclusiveEnd()}, day(10), day(11), true},
+		{
+			"inclusive end ignores hour-only values",
+			"/?from=2024-01-10&to=2024-01-20T12",
+			[]TimeRangeOption{InclusiveEnd(), TimeLayouts(time.DateOnly, "2006-01-02T15")},
+			day(10),
+			time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC),
+			true,
+		},
+		{"custom layouts", "/?from=10.01.2024&to=20.01.2024", []TimeRangeOption{TimeLayouts("02.01.2006")}, day(10), day(20), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			start, end, ok := TimeRange(r, "from", "to", defStart, defEnd, tt.opts...)
+			if !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) || ok != tt.expectedOK {
+				t.Errorf("TimeRange() = (%v, %v, %v), want (%v, %v, %v)",
+					start, end, ok, tt.expectedStart, tt.expectedEnd, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestIsDateOnlyLayout(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected bool
+	}{
+		{time.DateOnly, true},
+		{"02.01.2006", true},
+		{"Jan _2, 2006", true},
+		{"Monday, 02-Jan-06 MST", true},
+		{"2006-01-02 -0700", true},
+		{time.RFC3339, false},
+		{"2006-01-02T15", false},
+		{"15h", false},
+		{time.Kitchen, false},
+		{"2006-01-02 3pm", false},
+		{"2006-01-02 .000", false},
+		{"2006-01-02,999", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			if got := isDateOnlyLayout(tt.layout); got != tt.expected {
+				t.Errorf("isDateOnlyLayout(%q) = %v, want %v", tt.layout, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		allowed  []string
+		expected string
+	}{
+		{"valid zone", "/?tz=America/New_York", nil, "America/New_York"},
+		{"utc", "/?tz=UTC", nil, "UTC"},
+		{"unknown zone", "/?tz=Mars/Olympus", nil, "Europe/London"},
+		{"local rejected", "/?tz=Local", nil, "Europe/London"},
+		{"path traversal", "/?tz=../../etc/passwd", nil, "Europe/London"},
+		{"allowed", "/?tz=Europe/Berlin", []string{"Europe/Berlin", "UTC"}, "Europe/Berlin"},
+		{"not allowed", "/?tz=Asia/Tokyo", []string{"Europe/Berlin", "UTC"}, "Europe/London"},
+		{"missing", "/", nil, "Europe/London"},
+		{"empty", "/?tz=", nil, "Europe/London"},
+	}
+
+	fallback, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Location(r, "tz", fallback, tt.allowed...)
+			if got.String() != tt.expected {
+				t.Errorf("Location() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnix(t *testing.T) {
+	fallback := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		url      string
+		unit     []UnixUnit
+		expected time.Time
+	}{
+		{"seconds by default", "/?ts=1700000000", nil, want},
+		{"explicit millis", "/?ts=1700000000000", []UnixUnit{UnixMillis}, want},
+		{"explicit micros", "/?ts=1700000000000000", []UnixUnit{UnixMicros}, want},
+		{"explicit nanos", "/?ts=1700000000000000000", []UnixUnit{UnixNanos}, want},
+		{"auto seconds", "/?ts=1700000000", []UnixUnit{UnixAuto}, want},
+		{"auto millis", "/?ts=1700000000000", []UnixUnit{UnixAuto}, want},
+		{"auto micros", "/?ts=1700000000000000", []UnixUnit{UnixAuto}, want},
+		{"auto nanos", "/?ts=1700000000000000000", []UnixUnit{UnixAuto}, want},
+		{"auto negative", "/?ts=-86400", []UnixUnit{UnixAuto}, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"zero", "/?ts=0", nil, time.Unix(0, 0).UTC()},
+		{"fraction", "/?ts=1700000000.5", nil, fallback},
+		{"invalid", "/?ts=yesterday", nil, fallback},
+		{"missing", "/", nil, fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := Unix(r, "ts", fallback, tt.unit...)
+			if !got.Equal(tt.expected) {
+				t.Errorf("Unix() = %v, want %v", got, tt.expected)
+			}
+			if got != fallback && got.Location() != time.UTC {
+				t.Errorf("Unix() location = %v, want UTC", got.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxTolerantPairs matches the default parameter limit of url.ParseQuery.
+const maxTolerantPairs = 10000
+
+var (
+	// errSemicolon matches the error url.ParseQuery reports for pairs containing ";".
+	errSemicolon = errors.New("invalid semicolon separator in query")
+
+	// ErrTooManyPairs is reported by ParseTolerant for the part of a query
+	// string beyond its first 10000 pairs.
+	ErrTooManyPairs = errors.New("query: too many parameters")
+)
+
+// MalformedPair describes a key=value pair that ParseTolerant skipped.
+type MalformedPair struct {
+	Raw string // The pair exactly as it appeared in the query string
+	Err error  // Why the pair could not be decoded
+}
+
+// ParseTolerant parses rawQuery like url.ParseQuery, keeping every well-formed
+// pair, and returns each pair it had to skip along with the reason: pairs
+// containing ";" and pairs with invalid percent-escapes such as "q=100%" or
+// "%zz=1". r.URL.Query() drops such pairs silently, and url.ParseQuery reports
+// only the first; analytics endpoints that must not lose data from sloppy
+// clients can log or repair the skipped pairs instead.
+//
+// The returned values are identical to those of url.ParseQuery. Empty pairs
+// such as the one in "a=1&&b=2" are ignored without being reported. Where
+// url.ParseQuery rejects a query string of more than 10000 pairs outright,
+// ParseTolerant keeps the first 10000 and reports the rest, unparsed, as a
+// single MalformedPair wrapping ErrTooManyPairs.
+//
+// Example:
+//
+//	// URL: /collect?event=click&label=50%&page=home
+//	values, skipped := query.Tolerant(r)
+//	// values: event=click, page=home
+//	// skipped: [{Raw: "label=50%", Err: invalid URL escape "%"}]
+func ParseTolerant(rawQuery string) (url.Values, []MalformedPair) {
+	values := make(url.Values)
+	var skipped []MalformedPair
+
+	for n := 0; rawQuery != ""; n++ {
+		if n == maxTolerantPairs {
+			skipped = append(skipped, MalformedPair{Raw: rawQuery, Err: ErrTooManyPairs})
+			break
+		}
+
+		var pair string
+		pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+		if pair == "" {
+			continue
+		}
+		if strings.Contains(pair, ";") {
+			skipped = append(skipped, MalformedPair{Raw: pair, Err: errSemicolon})
+			continue
+		}
+
+		rawKey, rawVal, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			skipped = append(skipped, MalformedPair{Raw: pair, Err: err})
+			continue
+		}
+		val, err := url.QueryUnescape(rawVal)
+		if err != nil {
+			skipped = append(skipped, MalformedPair{Raw: pair, Err: err})
+			continue
+		}
+		values[key] = append(values[key], val)
+	}
+	return values, skipped
+}
+
+// Tolerant is ParseTolerant for r.URL.RawQuery.
+func Tolerant(r *http.Request) (url.Values, []MalformedPair) {
+	return ParseTolerant(r.URL.RawQuery)
+}
+
+// TolerantSource reads the well-formed pairs of r.URL.RawQuery with
+// ParseTolerant, for use as Config.Source. It returns the same values as
+// r.URL.Query(), except that overly long query strings are truncated instead
+// of yielding no values at all.
+func TolerantSource(r *http.Request) url.Values {
+	values, _ := ParseTolerant(r.URL.RawQuery)
+	return values
+}
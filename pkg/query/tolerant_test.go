@@ -0,0 +1,102 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTolerant(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawQuery    string
+		expected    url.Values
+		wantSkipped []string
+	}{
+		{"well formed", "a=1&b=x+y&a=2", url.Values{"a": {"1", "2"}, "b": {"x y"}}, nil},
+		{"bad value escape", "event=click&label=50%&page=home",
+			url.Values{"event": {"click"}, "page": {"home"}}, []string{"label=50%"}},
+		{"bad key escape", "%zz=1&ok=2", url.Values{"ok": {"2"}}, []string{"%zz=1"}},
+		{"semicolon", "a=1;b=2&c=3", url.Values{"c": {"3"}}, []string{"a=1;b=2"}},
+		{"several bad pairs", "x=%&y=%4&z=%41",
+			url.Values{"z": {"A"}}, []string{"x=%", "y=%4"}},
+		{"empty pairs ignored", "&a=1&&", url.Values{"a": {"1"}}, nil},
+		{"empty", "", url.Values{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, skipped := ParseTolerant(tt.rawQuery)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseTolerant() values = %v, want %v", got, tt.expected)
+			}
+
+			var raw []string
+			for _, p := range skipped {
+				if p.Err == nil {
+					t.Errorf("ParseTolerant() skipped %q without an error", p.Raw)
+				}
+				raw = append(raw, p.Raw)
+			}
+			if !reflect.DeepEqual(raw, tt.wantSkipped) {
+				t.Errorf("ParseTolerant() skipped = %q, want %q", raw, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestParseTolerantTooManyPairs(t *testing.T) {
+	rawQuery := strings.Repeat("a=1&", maxTolerantPairs) + "b=2&c=3"
+
+	got, skipped := ParseTolerant(rawQuery)
+	if len(got["a"]) != maxTolerantPairs || got.Has("b") {
+		t.Errorf("ParseTolerant() kept %d values of a, b present %v", len(got["a"]), got.Has("b"))
+	}
+	if len(skipped) != 1 || skipped[0].Raw != "b=2&c=3" || !errors.Is(skipped[0].Err, ErrTooManyPairs) {
+		t.Errorf("ParseTolerant() skipped = %v, want remainder with ErrTooManyPairs", skipped)
+	}
+}
+
+func TestTolerantSource(t *testing.T) {
+	e := NewExtractor(Config{Source: TolerantSource})
+	r := httptest.NewRequest("GET", "/?page=2", nil)
+	r.URL.RawQuery = "page=2&label=50%&page=3"
+
+	if got := e.Int(r, "page", 1); got != 2 {
+		t.Errorf("Extractor.Int() = %d, want 2", got)
+	}
+	if _, skipped := Tolerant(r); len(skipped) != 1 {
+		t.Errorf("Tolerant() skipped = %v, want 1 pair", skipped)
+	}
+}
+
+func FuzzParseTolerant(f *testing.F) {
+	for _, seed := range []string{
+		"a=1&b=2", "a=%zz&b=2", "a;b=1", "%=&=%&&", "k%5B%5D=1&k[]=2", "x=100%&y=%41%",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, rawQuery string) {
+		got, skipped := ParseTolerant(rawQuery)
+
+		want, err := url.ParseQuery(rawQuery)
+		if strings.Count(rawQuery, "&") >= maxTolerantPairs {
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("ParseTolerant(%q) = %v, url.ParseQuery = %v", rawQuery, got, want)
+		}
+		if (err != nil) != (len(skipped) > 0) {
+			t.Fatalf("ParseTolerant(%q) skipped %v, url.ParseQuery error %v", rawQuery, skipped, err)
+		}
+		for _, p := range skipped {
+			if p.Err == nil || !strings.Contains(rawQuery, p.Raw) {
+				t.Fatalf("ParseTolerant(%q) reported invalid skipped pair %+v", rawQuery, p)
+			}
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package query
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Tri is a three-state boolean for filters that distinguish "don't filter"
+// from "only true" and "only false". The zero value is TriUnset.
+type Tri int8
+
+const (
+	TriUnset Tri = iota // Parameter missing, empty or not a boolean
+	TriTrue
+	TriFalse
+)
+
+// String returns "unset", "true" or "false".
+func (t Tri) String() string {
+	switch t {
+	case TriTrue:
+		return "true"
+	case TriFalse:
+		return "false"
+	default:
+		return "unset"
+	}
+}
+
+// IsSet reports whether t is TriTrue or TriFalse.
+func (t Tri) IsSet() bool {
+	return t == TriTrue || t == TriFalse
+}
+
+// Ptr returns t as a *bool: nil for TriUnset, otherwise a pointer to its value.
+func (t Tri) Ptr() *bool {
+	if !t.IsSet() {
+		return nil
+	}
+	v := t == TriTrue
+	return &v
+}
+
+// UnmarshalText parses text with the same flexible vocabulary as Bool, so Tri
+// fields can be used with Bind.
+func (t *Tri) UnmarshalText(text []byte) error {
+	v, err := parseBool(string(text))
+	if err != nil {
+		return err
+	}
+	*t = triOf(v)
+	return nil
+}
+
+// TriBool extracts a boolean filter from the query parameter with the given key
+// as an explicit three-state value, using the same flexible parsing as Bool.
+// Returns TriUnset if the key is missing, empty, or not a recognized value.
+//
+// Example:
+//
+//	// URL: /users?active=no
+//	switch query.TriBool(r, "active") {
+//	case query.TriTrue:
+//	    q = q.Where("active")
+//	case query.TriFalse:
+//	    q = q.Where("NOT active")  // this case
+//	}
+func TriBool(r *http.Request, key string) Tri {
+	return std.TriBool(r, key)
+}
+
+// BoolPtrStrict is like BoolPtr but accepts only the values strconv.ParseBool
+// does ("1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false",
+// "False"). Returns nil if the key is missing, empty, or not one of those.
+func BoolPtrStrict(r *http.Request, key string) *bool {
+	return ValuePtr(r, key, strconv.ParseBool)
+}
+
+// TriBool extracts a three-state boolean using the Extractor's vocabulary.
+// See the package-level TriBool.
+func (e *Extractor) TriBool(r *http.Request, key string) Tri {
+	return ValueWith(e, r, key, TriUnset, func(s string) (Tri, error) {
+		v, err := e.parseBool(s)
+		return triOf(v), err
+	})
+}
+
+// BoolPtrStrict extracts a boolean value using strconv.ParseBool semantics,
+// ignoring the Extractor's vocabulary. See the package-level BoolPtrStrict.
+func (e *Extractor) BoolPtrStrict(r *http.Request, key string) *bool {
+	return ValuePtrWith(e, r, key, strconv.ParseBool)
+}
+
+func triOf(v bool) Tri {
+	if v {
+		return TriTrue
+	}
+	return TriFalse
+}
@@ -0,0 +1,111 @@
+package query
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTriBool(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected Tri
+	}{
+		{"missing", "/", TriUnset},
+		{"empty", "/?active=", TriUnset},
+		{"true", "/?active=true", TriTrue},
+		{"yes", "/?active=yes", TriTrue},
+		{"false", "/?active=false", TriFalse},
+		{"off", "/?active=off", TriFalse},
+		{"invalid", "/?active=maybe", TriUnset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := TriBool(r, "active"); got != tt.expected {
+				t.Errorf("TriBool() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractorTriBool(t *testing.T) {
+	e := NewExtractor(Config{TrueValues: []string{"ja"}, FalseValues: []string{"nein"}})
+
+	r := httptest.NewRequest("GET", "/?a=nein&b=ja&c=true", nil)
+	if got := e.TriBool(r, "a"); got != TriFalse {
+		t.Errorf("Extractor.TriBool(a) = %v, want false", got)
+	}
+	if got := e.TriBool(r, "b"); got != TriTrue {
+		t.Errorf("Extractor.TriBool(b) = %v, want true", got)
+	}
+	if got := e.TriBool(r, "c"); got != TriUnset {
+		t.Errorf("Extractor.TriBool(c) = %v, want unset", got)
+	}
+}
+
+func TestTriMethods(t *testing.T) {
+	tests := []struct {
+		tri    Tri
+		str    string
+		isSet  bool
+		ptrNil bool
+	}{
+		{TriUnset, "unset", false, true},
+		{TriTrue, "true", true, false},
+		{TriFalse, "false", true, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.tri.String(); got != tt.str {
+			t.Errorf("Tri.String() = %q, want %q", got, tt.str)
+		}
+		if got := tt.tri.IsSet(); got != tt.isSet {
+			t.Errorf("%v.IsSet() = %v, want %v", tt.tri, got, tt.isSet)
+		}
+		p := tt.tri.Ptr()
+		if (p == nil) != tt.ptrNil || (p != nil && *p != (tt.tri == TriTrue)) {
+			t.Errorf("%v.Ptr() = %v", tt.tri, p)
+		}
+	}
+}
+
+func TestBindTri(t *testing.T) {
+	var params struct {
+		Active   Tri `query:"active"`
+		Archived Tri `query:"archived"`
+	}
+
+	r := httptest.NewRequest("GET", "/?active=no", nil)
+	if err := Bind(r, &params); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if params.Active != TriFalse || params.Archived != TriUnset {
+		t.Errorf("Bind() = %+v, want Active false and Archived unset", params)
+	}
+}
+
+func TestBoolPtrStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *bool
+	}{
+		{"missing", "/", nil},
+		{"true", "/?x=true", ptr(true)},
+		{"T", "/?x=T", ptr(true)},
+		{"zero", "/?x=0", ptr(false)},
+		{"yes not accepted", "/?x=yes", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := BoolPtrStrict(r, "x")
+			if (got == nil) != (tt.expected == nil) || (got != nil && *got != *tt.expected) {
+				t.Errorf("BoolPtrStrict() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
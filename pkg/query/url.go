@@ -0,0 +1,154 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// URLOption configures the validation performed by URL.
+type URLOption func(*urlConfig)
+
+type urlConfig struct {
+	schemes         []string
+	hosts           []string
+	requireAbsolute bool
+	relativeOnly    bool
+}
+
+// AllowSchemes restricts URL to the given schemes (case-insensitive).
+// Protocol-relative references such as "//example.com/path" carry no scheme
+// and are rejected once a scheme restriction is in place, and so are URLs
+// with a scheme but no host, such as "https:evil.com", which browsers
+// resolve against hosts of their own choosing.
+//
+// Example:
+//
+//	u := query.URL(r, "callback", nil, query.AllowSchemes("https"))
+func AllowSchemes(schemes ...string) URLOption {
+	return func(c *urlConfig) {
+		c.schemes = append(c.schemes, schemes...)
+	}
+}
+
+// AllowHosts restricts absolute URLs to the given hosts (case-insensitive,
+// port ignored). Relative URLs are unaffected unless RequireAbsolute is also set.
+func AllowHosts(hosts ...string) URLOption {
+	return func(c *urlConfig) {
+		c.hosts = append(c.hosts, hosts...)
+	}
+}
+
+// RequireAbsolute rejects URLs that do not have both a scheme and a host.
+func RequireAbsolute() URLOption {
+	return func(c *urlConfig) {
+		c.requireAbsolute = true
+	}
+}
+
+// RelativeOnly accepts only same-origin relative paths such as "/account".
+// Absolute URLs, protocol-relative references ("//evil.com") and paths that
+// browsers may reinterpret as such ("/\evil.com") are rejected. This is the
+// safest choice for post-login redirect targets.
+func RelativeOnly() URLOption {
+	return func(c *urlConfig) {
+		c.relativeOnly = true
+	}
+}
+
+// URL extracts a URL from the query parameter with the given key.
+// Returns defaultValue if the key is missing, empty, cannot be parsed as a URL,
+// or fails any of the validation options.
+//
+// Without options any parseable URL is accepted, including "javascript:" URLs,
+// so parameters used as redirect or callback targets should always be
+// restricted with AllowSchemes, AllowHosts or RelativeOnly.
+//
+// Example:
+//
+//	// URL: /login?redirect_uri=https://app.example.com/home
+//	u := query.URL(r, "redirect_uri", nil,
+//	    query.AllowSchemes("https"),
+//	    query.AllowHosts("app.example.com"),
+//	)
+func URL(r *http.Request, key string, defaultValue *url.URL, opts ...URLOption) *url.URL {
+	val := first(r.URL.Query(), key)
+	if val == "" {
+		return defaultValue
+	}
+
+	var cfg urlConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	parsed, err := url.Parse(val)
+	if err != nil || !cfg.valid(parsed, val) {
+		return defaultValue
+	}
+	return parsed
+}
+
+func (c *urlConfig) valid(u *url.URL, raw string) bool {
+	// Browsers treat backslashes like forward slashes, so "/\host" and "\\host"
+	// behave as protocol-relative references even though url.Parse sees a path.
+	protocolRelative := u.Scheme == "" && (u.Host != "" ||
+		strings.HasPrefix(raw, "//") || strings.HasPrefix(raw, "/\\") || strings.HasPrefix(raw, "\\"))
+
+	if c.relativeOnly {
+		return u.Scheme == "" && u.Host == "" && u.Opaque == "" && !protocolRelative
+	}
+
+	if c.requireAbsolute && (u.Scheme == "" || u.Host == "") {
+		return false
+	}
+
+	// With a scheme, browsers take "https:evil.com", "https:///evil.com" and
+	// "https:/\evil.com" to evil.com, while url.Parse finds no host in them,
+	// so restricted URLs must carry their host the standard way.
+	if u.Scheme != "" && (len(c.schemes) > 0 || len(c.hosts) > 0) {
+		if u.Opaque != "" || u.Host == "" || strings.Contains(authority(raw), `\`) {
+			return false
+		}
+	}
+
+	if len(c.schemes) > 0 {
+		if protocolRelative {
+			return false
+		}
+		if u.Scheme != "" && !containsFold(c.schemes, u.Scheme) {
+			return false
+		}
+	}
+
+	if len(c.hosts) > 0 && (u.Host != "" || protocolRelative) {
+		if !containsFold(c.hosts, u.Hostname()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// authority returns what precedes the path of the absolute URL raw: the
+// scheme, the slashes after it and the authority.
+func authority(raw string) string {
+	i := strings.Index(raw, "//")
+	if i < 0 {
+		return raw
+	}
+	if end := strings.IndexAny(raw[i+2:], "/?#"); end >= 0 {
+		return raw[:i+2+end]
+	}
+	return raw
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	fallback, _ := url.Parse("/home")
+
+	tests := []struct {
+		name     string
+		url      string
+		opts     []URLOption
+		expected string
+	}{
+		{"absolute https", "/?next=" + url.QueryEscape("https://example.com/a?b=c"), nil, "https://example.com/a?b=c"},
+		{"relative path", "/?next=/account", nil, "/account"},
+		{"missing key", "/?other=value", nil, "/home"},
+		{"empty value", "/?next=", nil, "/home"},
+		{"unparseable", "/?next=" + url.QueryEscape("http://[::1"), nil, "/home"},
+
+		// Scheme restrictions
+		{"allowed scheme", "/?next=" + url.QueryEscape("https://example.com"), []URLOption{AllowSchemes("https")}, "https://example.com"},
+		{"scheme case-insensitive", "/?next=" + url.QueryEscape("HTTPS://example.com"), []URLOption{AllowSchemes("https")}, "https://example.com"},
+		{"disallowed scheme", "/?next=" + url.QueryEscape("http://example.com"), []URLOption{AllowSchemes("https")}, "/home"},
+		{"javascript scheme", "/?next=" + url.QueryEscape("javascript:alert(1)"), []URLOption{AllowSchemes("https")}, "/home"},
+		{"protocol-relative with schemes", "/?next=" + url.QueryEscape("//evil.com"), []URLOption{AllowSchemes("https")}, "/home"},
+		{"relative with schemes", "/?next=/account", []URLOption{AllowSchemes("https")}, "/account"},
+
+		// Absolute requirement
+		{"require absolute ok", "/?next=" + url.QueryEscape("https://example.com/x"), []URLOption{RequireAbsolute()}, "https://example.com/x"},
+		{"require absolute relative", "/?next=/account", []URLOption{RequireAbsolute()}, "/home"},
+		{"require absolute protocol-relative", "/?next=" + url.QueryEscape("//example.com"), []URLOption{RequireAbsolute()}, "/home"},
+
+		// Host restrictions
+		{"allowed host", "/?next=" + url.QueryEscape("https://app.example.com:8443/x"), []URLOption{AllowHosts("app.example.com")}, "https://app.example.com:8443/x"},
+		{"disallowed host", "/?next=" + url.QueryEscape("https://evil.com/x"), []URLOption{AllowHosts("app.example.com")}, "/home"},
+		{"missing slashes", "/?next=" + url.QueryEscape("https:evil.com"), []URLOption{AllowSchemes("https"), AllowHosts("app.example.com")}, "/home"},
+		{"triple slash", "/?next=" + url.QueryEscape("https:///evil.com"), []URLOption{AllowSchemes("https"), AllowHosts("app.example.com")}, "/home"},
+		{"slash backslash after scheme", "/?next=" + url.QueryEscape(`https:/\evil.com`), []URLOption{AllowSchemes("https"), AllowHosts("app.example.com")}, "/home"},
+		{"backslash in authority", "/?next=" + url.QueryEscape(`https://app.example.com\@evil.com`), []URLOption{AllowSchemes("https"), AllowHosts("app.example.com")}, "/home"},
+		{"backslash trick with hosts", "/?next=" + url.QueryEscape(`/\evil.com`), []URLOption{AllowHosts("app.example.com")}, "/home"},
+
+		// Relative only
+		{"relative only path", "/?next=/account?tab=1", []URLOption{RelativeOnly()}, "/account?tab=1"},
+		{"relative only absolute", "/?next=" + url.QueryEscape("https://example.com"), []URLOption{RelativeOnly()}, "/home"},
+		{"relative only protocol-relative", "/?next=" + url.QueryEscape("//evil.com"), []URLOption{RelativeOnly()}, "/home"},
+		{"relative only backslash", "/?next=" + url.QueryEscape(`/\evil.com`), []URLOption{RelativeOnly()}, "/home"},
+		{"relative only opaque", "/?next=" + url.QueryEscape("mailto:a@b.c"), []URLOption{RelativeOnly()}, "/home"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := URL(r, "next", fallback, tt.opts...)
+			if got == nil {
+				t.Fatalf("URL() = nil, want %q", tt.expected)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("URL() = %q, want %q", got.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLNilDefault(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?next="+url.QueryEscape("http://example.com"), nil)
+	if got := URL(r, "next", nil, AllowSchemes("https")); got != nil {
+		t.Errorf("URL() = %v, want nil", got)
+	}
+}
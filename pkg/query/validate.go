@@ -0,0 +1,157 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RuleError reports a bound value that violates a validation rule from its
+// field's `query` tag. It is the Err of the corresponding FieldError.
+type RuleError struct {
+	Rule  string // "min", "max" or "oneof"
+	Param string // The rule's parameter, such as "100" for max=100
+}
+
+func (e *RuleError) Error() string {
+	switch e.Rule {
+	case "min":
+		return "must be at least " + e.Param
+	case "max":
+		return "must be at most " + e.Param
+	default:
+		return "must be one of " + strings.ReplaceAll(e.Param, " ", ", ")
+	}
+}
+
+// fieldRules are the options following the name in a `query` tag.
+type fieldRules struct {
+	def        string
+	hasDefault bool
+	required   bool
+	min, max   string
+	oneof      string
+	aliases    []string
+}
+
+// parseRules parses the options of a `query` tag such as
+// "limit,default=25,min=1,max=100".
+func parseRules(field reflect.StructField) (fieldRules, error) {
+	var rules fieldRules
+
+	tag, _ := field.Tag.Lookup("query")
+	_, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		name, param, hasParam := strings.Cut(opt, "=")
+
+		switch {
+		case name == "required" && !hasParam:
+			rules.required = true
+		case name == "default" && hasParam:
+			rules.def, rules.hasDefault = param, true
+		case name == "min" && param != "":
+			rules.min = param
+		case name == "max" && param != "":
+			rules.max = param
+		case name == "oneof" && param != "":
+			rules.oneof = param
+		case name == "alias" && param != "":
+			rules.aliases = strings.Fields(param)
+		default:
+			return rules, fmt.Errorf("invalid tag option %q", opt)
+		}
+	}
+	return rules, nil
+}
+
+// validate checks the parsed field value v, bound from the raw vals, against
+// rules. Rules apply to each element of a slice and to the target of a pointer.
+// It returns the offending raw value along with the error.
+func (e *Extractor) validate(v reflect.Value, vals []string, rules fieldRules) (string, error) {
+	if rules.min == "" && rules.max == "" && rules.oneof == "" {
+		return "", nil
+	}
+
+	if v.Kind() != reflect.Slice {
+		return vals[0], e.validateOne(v, vals[0], rules)
+	}
+	for i, val := range vals {
+		if err := e.validateOne(v.Index(i), val, rules); err != nil {
+			return val, err
+		}
+	}
+	return "", nil
+}
+
+func (e *Extractor) validateOne(v reflect.Value, raw string, rules fieldRules) error {
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if rules.oneof != "" && !slices.Contains(strings.Fields(rules.oneof), raw) {
+		return &RuleError{Rule: "oneof", Param: rules.oneof}
+	}
+	if rules.min != "" {
+		if c, err := e.compareBound(v, rules.min); err != nil {
+			return err
+		} else if c < 0 {
+			return &RuleError{Rule: "min", Param: rules.min}
+		}
+	}
+	if rules.max != "" {
+		if c, err := e.compareBound(v, rules.max); err != nil {
+			return err
+		} else if c > 0 {
+			return &RuleError{Rule: "max", Param: rules.max}
+		}
+	}
+	return nil
+}
+
+// compareBound compares v with the bound given in a min or max option,
+// returning -1, 0 or +1. Numbers, durations and times are compared by value,
+// with the bound parsed like the field itself; strings are compared by length
+// in characters.
+func (e *Extractor) compareBound(v reflect.Value, param string) (int, error) {
+	invalid := func() (int, error) {
+		return 0, fmt.Errorf("invalid tag bound %q for %s", param, v.Type())
+	}
+
+	if v.Kind() == reflect.String {
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return invalid()
+		}
+		return cmp.Compare(utf8.RuneCountInString(v.String()), n), nil
+	}
+
+	if v.Type() != timeType && !isNumericKind(v.Kind()) {
+		return invalid()
+	}
+	bound, err := e.parseScalar(v.Type(), param)
+	if err != nil {
+		return invalid()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Compare(bound.Interface().(time.Time)), nil
+	case v.CanInt():
+		return cmp.Compare(v.Int(), bound.Int()), nil
+	case v.CanUint():
+		return cmp.Compare(v.Uint(), bound.Uint()), nil
+	default:
+		return cmp.Compare(v.Float(), bound.Float()), nil
+	}
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return reflect.Int <= k && k <= reflect.Float64
+}
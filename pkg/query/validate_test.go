@@ -0,0 +1,153 @@
+package query
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type validatedParams struct {
+	Page    int           `query:"page,default=1,min=1"`
+	Limit   int           `query:"limit,default=25,min=1,max=100"`
+	Order   string        `query:"order,oneof=asc desc"`
+	Q       string        `query:"q,min=3,max=10"`
+	IDs     []uint        `query:"id,max=1000"`
+	Timeout time.Duration `query:"timeout,max=30s"`
+	Since   *time.Time    `query:"since,min=2020-01-01"`
+	Token   string        `query:"token,required"`
+}
+
+func TestBindValidation(t *testing.T) {
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected validatedParams
+	}{
+		{"defaults", "/?token=x", validatedParams{Page: 1, Limit: 25, Token: "x"}},
+		{"empty uses default", "/?token=x&limit=", validatedParams{Page: 1, Limit: 25, Token: "x"}},
+		{"within bounds", "/?token=x&page=3&limit=100&order=desc&q=gopher&id=7&id=1000&timeout=30s&since=2024-01-02",
+			validatedParams{Page: 3, Limit: 100, Order: "desc", Q: "gopher", IDs: []uint{7, 1000},
+				Timeout: 30 * time.Second, Since: &since, Token: "x"}},
+		{"string length counts characters", "/?token=x&q=%C3%A9t%C3%A9",
+			validatedParams{Page: 1, Limit: 25, Q: "été", Token: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			var got validatedParams
+			if err := Bind(r, &got); err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Bind() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindValidationErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantField string
+		wantValue string
+		wantRule  string
+	}{
+		{"below min", "/?token=x&page=0", "Page", "0", "min"},
+		{"above max", "/?token=x&limit=101", "Limit", "101", "max"},
+		{"not one of", "/?token=x&order=up", "Order", "up", "oneof"},
+		{"string too short", "/?token=x&q=go", "Q", "go", "min"},
+		{"string too long", "/?token=x&q=gophergopher", "Q", "gophergopher", "max"},
+		{"slice element", "/?token=x&id=1&id=1001", "IDs", "1001", "max"},
+		{"duration", "/?token=x&timeout=1m", "Timeout", "1m", "max"},
+		{"time", "/?token=x&since=2019-12-31", "Since", "2019-12-31", "min"},
+		{"required", "/?token=", "Token", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got := validatedParams{Limit: 50}
+			err := Bind(r, &got)
+
+			var bindErrs BindErrors
+			if !errors.As(err, &bindErrs) || len(bindErrs) != 1 {
+				t.Fatalf("Bind() error = %v, want 1 field error", err)
+			}
+			fe := bindErrs[0]
+			if fe.Field != tt.wantField || fe.Value != tt.wantValue {
+				t.Errorf("Bind() field error = %s=%q, want %s=%q", fe.Field, fe.Value, tt.wantField, tt.wantValue)
+			}
+
+			var ruleErr *RuleError
+			switch {
+			case tt.wantRule == "":
+				if !errors.Is(err, ErrMissing) {
+					t.Errorf("Bind() error = %v, want ErrMissing", err)
+				}
+			case !errors.As(err, &ruleErr) || ruleErr.Rule != tt.wantRule:
+				t.Errorf("Bind() error = %v, want %s rule error", err, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestBindValidationLeavesFieldUntouched(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?token=x&limit=500&page=2", nil)
+	got := validatedParams{Limit: 50}
+	if err := Bind(r, &got); err == nil {
+		t.Fatal("Bind() error = nil, want rule error")
+	}
+	if got.Limit != 50 || got.Page != 2 {
+		t.Errorf("Bind() = %+v, want Limit untouched and Page bound", got)
+	}
+}
+
+func TestBindInvalidTag(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  any
+	}{
+		{"unknown option", &struct {
+			N int `query:"n,mni=1"`
+		}{}},
+		{"bound not a number", &struct {
+			N int `query:"n,max=many"`
+		}{}},
+		{"bound on unsupported type", &struct {
+			B bool `query:"b,min=1"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?n=5&b=true", nil)
+			var fe *FieldError
+			if err := Bind(r, tt.dst); !errors.As(err, &fe) {
+				t.Errorf("Bind() error = %v, want FieldError", err)
+			}
+		})
+	}
+}
+
+func TestRuleErrorMessage(t *testing.T) {
+	tests := []struct {
+		err      *RuleError
+		expected string
+	}{
+		{&RuleError{Rule: "min", Param: "1"}, "must be at least 1"},
+		{&RuleError{Rule: "max", Param: "100"}, "must be at most 100"},
+		{&RuleError{Rule: "oneof", Param: "asc desc"}, "must be one of asc, desc"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.expected {
+			t.Errorf("RuleError.Error() = %q, want %q", got, tt.expected)
+		}
+	}
+}
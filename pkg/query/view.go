@@ -0,0 +1,99 @@
+package query
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// View is a read-only view of a parsed query string. Unlike All, which copies
+// the parameters on every call, a View is parsed once and shared: its methods
+// return copies wherever a caller could otherwise modify the underlying values,
+// and Clone returns a mutable copy when one is needed. The zero value is an
+// empty View.
+type View struct {
+	values   url.Values
+	rawQuery string
+}
+
+// NewView parses rawQuery into a View. Malformed pairs are skipped, as with
+// r.URL.Query().
+func NewView(rawQuery string) View {
+	values, _ := url.ParseQuery(rawQuery)
+	return View{values: values, rawQuery: rawQuery}
+}
+
+// Get returns the first value of key with the same key[] handling as String,
+// or "" if the key is missing.
+func (v View) Get(key string) string {
+	return first(v.values, key)
+}
+
+// Values returns a copy of all values of key, in the order of Strings.
+func (v View) Values(key string) []string {
+	return slices.Clone(lookup(v.values, key))
+}
+
+// Has reports whether key is present, even with an empty value.
+func (v View) Has(key string) bool {
+	return len(lookup(v.values, key)) > 0
+}
+
+// Len returns the number of distinct keys.
+func (v View) Len() int {
+	return len(v.values)
+}
+
+// Keys returns the distinct keys, sorted.
+func (v View) Keys() []string {
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Clone returns a deep copy of the values that the caller may modify.
+func (v View) Clone() url.Values {
+	result := make(url.Values, len(v.values))
+	for k, vals := range v.values {
+		result[k] = slices.Clone(vals)
+	}
+	return result
+}
+
+type viewKey struct{}
+
+// Memoize is middleware that parses the query string once per request and
+// stores the result in the request context, where AllCached finds it. Use it
+// in front of handlers that read many parameters or iterate over all of them.
+//
+// Example:
+//
+//	mux.Handle("/search", query.Memoize(searchHandler))
+func Memoize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), viewKey{}, NewView(r.URL.RawQuery))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AllCached returns the request's query string as a View. Behind Memoize the
+// View parsed by the middleware is returned without further work; otherwise,
+// or if r.URL.RawQuery has been changed since, the query string is parsed
+// again.
+//
+// Example:
+//
+//	v := query.AllCached(r)
+//	for _, k := range v.Keys() {
+//	    log.Printf("%s=%q", k, v.Values(k))
+//	}
+func AllCached(r *http.Request) View {
+	if v, ok := r.Context().Value(viewKey{}).(View); ok && v.rawQuery == r.URL.RawQuery {
+		return v
+	}
+	return NewView(r.URL.RawQuery)
+}
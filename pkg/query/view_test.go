@@ -0,0 +1,73 @@
+package query
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	v := NewView("tag=go&tag=rust&ids[]=1&page=2&empty=")
+
+	if got := v.Get("page"); got != "2" {
+		t.Errorf("View.Get() = %q, want %q", got, "2")
+	}
+	if got := v.Values("ids"); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("View.Values(ids) = %v, want [1]", got)
+	}
+	if !v.Has("empty") || v.Has("missing") {
+		t.Errorf("View.Has() = %v/%v, want true/false", v.Has("empty"), v.Has("missing"))
+	}
+	if got := v.Keys(); !reflect.DeepEqual(got, []string{"empty", "ids[]", "page", "tag"}) {
+		t.Errorf("View.Keys() = %v", got)
+	}
+	if got := v.Len(); got != 4 {
+		t.Errorf("View.Len() = %d, want 4", got)
+	}
+
+	// Copies must not write through to the view.
+	v.Values("tag")[0] = "changed"
+	clone := v.Clone()
+	clone["tag"][1] = "changed"
+	clone.Set("page", "9")
+	if got := v.Values("tag"); !reflect.DeepEqual(got, []string{"go", "rust"}) || v.Get("page") != "2" {
+		t.Errorf("View modified through a copy: tag = %v, page = %q", got, v.Get("page"))
+	}
+
+	var zero View
+	if zero.Len() != 0 || zero.Get("x") != "" || len(zero.Clone()) != 0 {
+		t.Error("zero View is not empty")
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	var first, second View
+	h := Memoize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first = AllCached(r)
+		second = AllCached(r)
+
+		r.URL.RawQuery = "page=5"
+		if got := AllCached(r).Get("page"); got != "5" {
+			t.Errorf("AllCached() after RawQuery change = %q, want %q", got, "5")
+		}
+	}))
+
+	r := httptest.NewRequest("GET", "/?page=2", nil)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if first.Get("page") != "2" {
+		t.Errorf("AllCached() page = %q, want %q", first.Get("page"), "2")
+	}
+	if reflect.ValueOf(first.values).UnsafePointer() != reflect.ValueOf(second.values).UnsafePointer() {
+		t.Error("AllCached() parsed the query string twice behind Memoize")
+	}
+}
+
+func TestAllCachedWithoutMiddleware(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?a=1&a=2", nil)
+	if got := AllCached(r).Clone(); !reflect.DeepEqual(got, url.Values{"a": {"1", "2"}}) {
+		t.Errorf("AllCached() = %v", got)
+	}
+}
@@ -0,0 +1,335 @@
+package secheaders
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// ErrAudit is returned by Report.Err when a report has findings at or above
+// the given severity.
+var ErrAudit = errors.New("secheaders: security header audit failed")
+
+// Severity ranks a Finding.
+type Severity uint8
+
+// Severities, from least to most urgent.
+const (
+	SeverityInfo   Severity = iota // Worth knowing, no direct risk
+	SeverityLow                    // Defense in depth
+	SeverityMedium                 // Weakens a protection
+	SeverityHigh                   // A protection is missing or ineffective
+)
+
+// String returns "info", "low", "medium" or "high".
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+// Finding is a missing or weak security header found by Audit.
+type Finding struct {
+	Header   string   // Header the finding is about
+	Severity Severity // How much it matters
+	Problem  string   // What is wrong
+	Fix      string   // How to remediate it
+}
+
+// String formats f as "high: Content-Security-Policy: missing (fix: ...)".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (fix: %s)", f.Severity, f.Header, f.Problem, f.Fix)
+}
+
+// Report is the result of Audit: its findings, most severe first.
+type Report struct {
+	Findings []Finding
+}
+
+// Score rates the headers from 0 to 100, taking 20 points per high finding,
+// 10 per medium and 5 per low one. Info findings are free.
+func (r Report) Score() int {
+	score := 100
+	for _, f := range r.Findings {
+		switch f.Severity {
+		case SeverityHigh:
+			score -= 20
+		case SeverityMedium:
+			score -= 10
+		case SeverityLow:
+			score -= 5
+		}
+	}
+	return max(score, 0)
+}
+
+// Grade turns Score into a letter: "A+" for a perfect score, then "A" from 90,
+// "B" from 75, "C" from 60, "D" from 45 and "F" below.
+func (r Report) Grade() string {
+	switch score := r.Score(); {
+	case score == 100:
+		return "A+"
+	case score >= 90:
+		return "A"
+	case score >= 75:
+		return "B"
+	case score >= 60:
+		return "C"
+	case score >= 45:
+		return "D"
+	}
+	return "F"
+}
+
+// Err returns an error wrapping ErrAudit that lists the findings at or above
+// threshold, or nil if there are none, so that a test can fail on them:
+//
+//	if err := secheaders.AuditHandler(handler, req).Err(secheaders.SeverityMedium); err != nil {
+//	    t.Error(err)
+//	}
+func (r Report) Err(threshold Severity) error {
+	var problems []string
+	for _, f := range r.Findings {
+		if f.Severity >= threshold {
+			problems = append(problems, f.String())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w:\n%s", ErrAudit, strings.Join(problems, "\n"))
+}
+
+// AuditHandler serves r with h and audits the response headers, for tests:
+//
+//	report := secheaders.AuditHandler(handler, httptest.NewRequest("GET", "/", nil))
+func AuditHandler(h http.Handler, r *http.Request) Report {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return Audit(w.Result().Header)
+}
+
+// Audit inspects the security headers of a response, as sent over HTTPS, and
+// reports those that are missing, invalid or weak, in the spirit of
+// securityheaders.com. It checks the headers Config sets, the framing
+// protection given by either X-Frame-Options or CSP frame-ancestors, and
+// headers that leak server details or are deprecated. The headers of a
+// response built from Strict get a perfect score.
+func Audit(h http.Header) Report {
+	var a auditor
+	a.csp(h)
+	a.hsts(h)
+	a.noSniff(h)
+	a.framing(h)
+	a.referrerPolicy(h)
+	a.crossOrigin(h)
+	a.permissionsPolicy(h)
+	a.leaks(h)
+	slices.SortStableFunc(a.findings, func(x, y Finding) int {
+		return cmp.Compare(y.Severity, x.Severity)
+	})
+	return Report{Findings: a.findings}
+}
+
+type auditor struct {
+	findings []Finding
+}
+
+func (a *auditor) add(header string, severity Severity, problem, fix string) {
+	a.findings = append(a.findings, Finding{Header: header, Severity: severity, Problem: problem, Fix: fix})
+}
+
+func (a *auditor) csp(h http.Header) {
+	const name = headers.ContentSecurityPolicy
+	value := h.Get(name)
+	if value == "" {
+		if h.Get(headers.ContentSecurityPolicyReportOnly) != "" {
+			a.add(name, SeverityMedium, "only a report-only policy is sent, nothing is enforced",
+				"enforce the policy once its reports are clean, with csp.Builder.Enforce")
+			return
+		}
+		a.add(name, SeverityHigh, "missing",
+			"send a policy such as default-src 'self'; object-src 'none'; base-uri 'none', built with csp.NewBuilder")
+		return
+	}
+	p, err := csp.Parse(value)
+	if err != nil {
+		a.add(name, SeverityHigh, "invalid: "+err.Error(), "build the policy with csp.NewBuilder")
+		return
+	}
+
+	scripts, ok := p.Get(csp.ScriptSrc)
+	if !ok {
+		scripts, ok = p.Get(csp.DefaultSrc)
+	}
+	switch {
+	case !ok:
+		a.add(name, SeverityHigh, "scripts are not restricted: neither script-src nor default-src is set",
+			"set script-src, for instance to 'self' or to a nonce with 'strict-dynamic'")
+	default:
+		hasNonce := slices.ContainsFunc(scripts, func(s string) bool {
+			s = strings.ToLower(s)
+			return strings.HasPrefix(s, "'nonce-") || strings.HasPrefix(s, "'sha")
+		})
+		if containsFold(scripts, csp.UnsafeInline) && !hasNonce {
+			a.add(name, SeverityHigh, "script-src allows 'unsafe-inline', so injected scripts run",
+				"replace 'unsafe-inline' with nonces or hashes, see csp.Nonce and csp.SHA256")
+		}
+		if containsFold(scripts, csp.UnsafeEval) {
+			a.add(name, SeverityMedium, "script-src allows 'unsafe-eval'",
+				"remove 'unsafe-eval', or use 'wasm-unsafe-eval' if only WebAssembly needs it")
+		}
+		if slices.ContainsFunc(scripts, func(s string) bool {
+			s = strings.ToLower(s)
+			return s == "*" || s == "http:" || s == "https:" || s == "data:"
+		}) && !containsFold(scripts, csp.StrictDynamic) {
+			a.add(name, SeverityMedium, "script-src allows scripts from any host",
+				"list the hosts scripts come from, or use a nonce with 'strict-dynamic'")
+		}
+	}
+
+	objects, ok := p.Get(csp.ObjectSrc)
+	if !ok {
+		objects, _ = p.Get(csp.DefaultSrc)
+	}
+	if len(objects) != 1 || !strings.EqualFold(objects[0], csp.None) {
+		a.add(name, SeverityLow, "plugins are allowed: object-src is not 'none'",
+			"add object-src 'none'")
+	}
+	if _, ok := p.Get(csp.BaseURI); !ok {
+		a.add(name, SeverityLow, "base-uri is not set, so an injected <base> can redirect relative URLs",
+			"add base-uri 'none', or 'self' if the site uses <base>")
+	}
+}
+
+func containsFold(sources []string, keyword string) bool {
+	return slices.ContainsFunc(sources, func(s string) bool { return strings.EqualFold(s, keyword) })
+}
+
+func (a *auditor) hsts(h http.Header) {
+	const name = headers.StrictTransportSecurity
+	const minAge = 180 * 24 * time.Hour
+	value := h.Get(name)
+	if value == "" {
+		a.add(name, SeverityHigh, "missing", "send max-age=63072000; includeSubDomains, see headers.HSTSValue")
+		return
+	}
+	v, err := headers.ParseHSTS(value)
+	switch {
+	case err != nil:
+		a.add(name, SeverityHigh, "invalid: "+err.Error(), "format the value with headers.HSTSValue")
+	case v.MaxAge == 0:
+		a.add(name, SeverityHigh, "max-age=0 removes the policy", "set max-age to at least one year")
+	case v.MaxAge < minAge:
+		a.add(name, SeverityMedium, "max-age is shorter than six months", "set max-age to at least one year")
+	case !v.IncludeSubDomains:
+		a.add(name, SeverityLow, "subdomains are not covered", "add includeSubDomains once every subdomain serves HTTPS")
+	}
+	if v.Preload && v.Validate() != nil {
+		a.add(name, SeverityLow, "preload is set but the value does not qualify: "+v.Validate().Error(),
+			"use a max-age of at least one year with includeSubDomains")
+	}
+}
+
+func (a *auditor) noSniff(h http.Header) {
+	const name = headers.XContentTypeOptions
+	if !strings.EqualFold(strings.TrimSpace(h.Get(name)), "nosniff") {
+		a.add(name, SeverityMedium, "not set to nosniff, so browsers may guess content types",
+			"send X-Content-Type-Options: nosniff")
+	}
+}
+
+func (a *auditor) framing(h http.Header) {
+	if p, err := csp.Parse(h.Get(headers.ContentSecurityPolicy)); err == nil {
+		if _, ok := p.Get(csp.FrameAncestors); ok {
+			return
+		}
+	}
+	values := h.Values(headers.XFrameOptions)
+	if len(values) == 0 {
+		a.add(headers.XFrameOptions, SeverityMedium, "the page can be framed by any site, enabling clickjacking",
+			"add frame-ancestors 'none' to the CSP, or send X-Frame-Options: DENY")
+		return
+	}
+	if _, err := headers.ParseXFrameOptions(values...); err != nil {
+		a.add(headers.XFrameOptions, SeverityMedium, "invalid, so browsers ignore it: "+err.Error(),
+			"send DENY or SAMEORIGIN, or use CSP frame-ancestors")
+	}
+}
+
+func (a *auditor) referrerPolicy(h http.Header) {
+	const name = headers.ReferrerPolicy
+	values := h.Values(name)
+	if len(values) == 0 {
+		a.add(name, SeverityLow, "missing, so the browser default applies",
+			"send strict-origin-when-cross-origin or no-referrer")
+		return
+	}
+	switch headers.ParseReferrerPolicy(values...) {
+	case headers.ReferrerPolicyUnsafeURL, headers.ReferrerPolicyNoReferrerWhenDowngrade:
+		a.add(name, SeverityMedium, "full URLs, including paths and queries, are sent to other sites",
+			"send strict-origin-when-cross-origin or no-referrer")
+	case "":
+		a.add(name, SeverityLow, "no recognized policy", "send strict-origin-when-cross-origin or no-referrer")
+	}
+}
+
+func (a *auditor) crossOrigin(h http.Header) {
+	if h.Get(headers.CrossOriginOpenerPolicy) == "" {
+		a.add(headers.CrossOriginOpenerPolicy, SeverityLow, "missing, so cross-origin popups keep a handle on this window",
+			"send same-origin, or same-origin-allow-popups if the site opens OAuth or payment popups")
+	}
+	if h.Get(headers.CrossOriginResourcePolicy) == "" {
+		a.add(headers.CrossOriginResourcePolicy, SeverityInfo, "missing, so other sites can embed this response",
+			"send same-origin, or same-site for resources shared with subdomains")
+	}
+	if h.Get(headers.CrossOriginEmbedderPolicy) == "" {
+		a.add(headers.CrossOriginEmbedderPolicy, SeverityInfo, "missing, so the page is not cross-origin isolated",
+			"send require-corp or credentialless if the page needs SharedArrayBuffer or precise timers")
+	}
+}
+
+func (a *auditor) permissionsPolicy(h http.Header) {
+	const name = headers.PermissionsPolicy
+	value := h.Get(name)
+	if value == "" {
+		a.add(name, SeverityLow, "missing, so embedded content may ask for device features",
+			"turn off unused features, such as camera=(), microphone=(), geolocation=()")
+		return
+	}
+	if _, err := sfv.ParseDictionary(value); err != nil {
+		a.add(name, SeverityLow, "invalid, so browsers ignore it: "+err.Error(),
+			"write it as a structured field dictionary, such as camera=(), geolocation=(self)")
+	}
+}
+
+func (a *auditor) leaks(h http.Header) {
+	if server := h.Get(headers.Server); strings.ContainsAny(server, "0123456789") {
+		a.add(headers.Server, SeverityInfo, "reveals software versions: "+server,
+			"remove the version, or the header")
+	}
+	if powered := h.Get(headers.XPoweredBy); powered != "" {
+		a.add(headers.XPoweredBy, SeverityLow, "reveals the server stack: "+powered, "remove the header")
+	}
+	if xss := strings.TrimSpace(h.Get(headers.XXSSProtection)); xss != "" && xss != "0" {
+		a.add(headers.XXSSProtection, SeverityLow, "enables a filter browsers removed, which could itself be abused",
+			"send X-XSS-Protection: 0 or remove it, and rely on CSP")
+	}
+}
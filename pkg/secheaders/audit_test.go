@@ -0,0 +1,153 @@
+package secheaders_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/secheaders"
+)
+
+func TestAuditStrict(t *testing.T) {
+	h, err := secheaders.Middleware(http.NotFoundHandler(), secheaders.Strict())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := secheaders.AuditHandler(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	if len(report.Findings) != 0 {
+		t.Errorf("Strict() findings = %v, want none", report.Findings)
+	}
+	if report.Score() != 100 || report.Grade() != "A+" {
+		t.Errorf("Score() = %d, Grade() = %q, want 100, A+", report.Score(), report.Grade())
+	}
+	if err := report.Err(secheaders.SeverityInfo); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestAuditEmpty(t *testing.T) {
+	report := secheaders.Audit(http.Header{})
+	if got := report.Grade(); got != "F" {
+		t.Errorf("Grade() = %q, want F", got)
+	}
+	if report.Findings[0].Severity != secheaders.SeverityHigh {
+		t.Errorf("first finding = %v, want the most severe first", report.Findings[0])
+	}
+	for i := 1; i < len(report.Findings); i++ {
+		if report.Findings[i].Severity > report.Findings[i-1].Severity {
+			t.Fatalf("findings not sorted by severity: %v", report.Findings)
+		}
+	}
+
+	err := report.Err(secheaders.SeverityHigh)
+	if !errors.Is(err, secheaders.ErrAudit) {
+		t.Fatalf("Err() = %v, want %v", err, secheaders.ErrAudit)
+	}
+	if !strings.Contains(err.Error(), "high: Content-Security-Policy: missing") {
+		t.Errorf("Err() = %q, want it to list the missing CSP", err)
+	}
+	if strings.Contains(err.Error(), "low:") {
+		t.Errorf("Err(SeverityHigh) = %q, lists low findings", err)
+	}
+}
+
+func TestAuditFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		severity secheaders.Severity
+		problem  string
+	}{
+		{"report-only CSP", headers.ContentSecurityPolicyReportOnly, "default-src 'self'", secheaders.SeverityMedium, "only a report-only policy"},
+		{"invalid CSP", headers.ContentSecurityPolicy, "default-src 'self', img-src *", secheaders.SeverityHigh, "invalid"},
+		{"no script restriction", headers.ContentSecurityPolicy, "img-src 'self'; object-src 'none'; base-uri 'none'", secheaders.SeverityHigh, "scripts are not restricted"},
+		{"unsafe-inline", headers.ContentSecurityPolicy, "default-src 'self' 'unsafe-inline'; object-src 'none'; base-uri 'none'", secheaders.SeverityHigh, "'unsafe-inline'"},
+		{"unsafe-eval", headers.ContentSecurityPolicy, "script-src 'self' 'unsafe-eval'; object-src 'none'; base-uri 'none'", secheaders.SeverityMedium, "'unsafe-eval'"},
+		{"any host", headers.ContentSecurityPolicy, "script-src https:; object-src 'none'; base-uri 'none'", secheaders.SeverityMedium, "any host"},
+		{"plugins", headers.ContentSecurityPolicy, "script-src 'self'; base-uri 'none'", secheaders.SeverityLow, "plugins"},
+		{"base-uri", headers.ContentSecurityPolicy, "default-src 'none'", secheaders.SeverityLow, "base-uri"},
+		{"HSTS removed", headers.StrictTransportSecurity, "max-age=0", secheaders.SeverityHigh, "removes the policy"},
+		{"HSTS short", headers.StrictTransportSecurity, "max-age=3600; includeSubDomains", secheaders.SeverityMedium, "shorter than six months"},
+		{"HSTS subdomains", headers.StrictTransportSecurity, "max-age=63072000", secheaders.SeverityLow, "subdomains"},
+		{"HSTS preload", headers.StrictTransportSecurity, "max-age=63072000; preload", secheaders.SeverityLow, "preload"},
+		{"HSTS invalid", headers.StrictTransportSecurity, "includeSubDomains", secheaders.SeverityHigh, "invalid"},
+		{"sniffing", headers.XContentTypeOptions, "sniff", secheaders.SeverityMedium, "nosniff"},
+		{"referrer leak", headers.ReferrerPolicy, "unsafe-url", secheaders.SeverityMedium, "full URLs"},
+		{"referrer unknown", headers.ReferrerPolicy, "everything", secheaders.SeverityLow, "no recognized policy"},
+		{"permissions invalid", headers.PermissionsPolicy, "camera=(", secheaders.SeverityLow, "invalid"},
+		{"server version", headers.Server, "nginx/1.25.3", secheaders.SeverityInfo, "versions"},
+		{"powered by", headers.XPoweredBy, "PHP/8.3", secheaders.SeverityLow, "server stack"},
+		{"XSS filter", headers.XXSSProtection, "1; mode=block", secheaders.SeverityLow, "filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			secure, err := secheaders.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header == headers.ContentSecurityPolicyReportOnly {
+					w.Header().Del(headers.ContentSecurityPolicy)
+				}
+				w.Header().Set(tt.header, tt.value)
+			}), secheaders.Strict())
+			if err != nil {
+				t.Fatal(err)
+			}
+			secure.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			report := secheaders.Audit(w.Header())
+			var found []secheaders.Finding
+			for _, f := range report.Findings {
+				if strings.Contains(f.Problem, tt.problem) {
+					found = append(found, f)
+				}
+			}
+			if len(found) != 1 || found[0].Severity != tt.severity || found[0].Fix == "" {
+				t.Errorf("findings = %v, want one %s finding about %q", report.Findings, tt.severity, tt.problem)
+			}
+		})
+	}
+}
+
+func TestAuditFraming(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{"frame-ancestors", http.Header{headers.ContentSecurityPolicy: {"frame-ancestors 'self'"}}, false},
+		{"X-Frame-Options", http.Header{headers.XFrameOptions: {"DENY"}}, false},
+		{"neither", http.Header{headers.ContentSecurityPolicy: {"default-src 'self'"}}, true},
+		{"conflicting X-Frame-Options", http.Header{headers.XFrameOptions: {"DENY", "SAMEORIGIN"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			for _, f := range secheaders.Audit(tt.header).Findings {
+				got = got || f.Header == headers.XFrameOptions
+			}
+			if got != tt.want {
+				t.Errorf("framing finding = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	tests := map[secheaders.Severity]string{
+		secheaders.SeverityInfo:   "info",
+		secheaders.SeverityLow:    "low",
+		secheaders.SeverityMedium: "medium",
+		secheaders.SeverityHigh:   "high",
+		9:                         "unknown",
+	}
+	for s, want := range tests {
+		if got := s.String(); got != want {
+			t.Errorf("Severity(%d).String() = %q, want %q", s, got, want)
+		}
+	}
+}
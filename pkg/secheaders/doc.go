@@ -0,0 +1,41 @@
+// Package secheaders sets a bundle of security response headers with one
+// middleware: Content-Security-Policy, Strict-Transport-Security,
+// X-Content-Type-Options, X-Frame-Options, Referrer-Policy, the
+// Cross-Origin-*-Policy headers and Permissions-Policy.
+//
+// A Config describes the headers, built with the csp package and the value
+// types of the headers package, and three presets cover the usual cases:
+//
+//   - Strict: an HTML application serving everything from its own origin,
+//     with a nonce-based CSP and cross-origin isolation
+//   - APIOnly: a JSON or other non-HTML API
+//   - Relaxed: an existing site, with the CSP only reported at first
+//
+// Example:
+//
+//	c := secheaders.Strict()
+//	c.CSP.ImgSrc("https://cdn.example.com")
+//	handler, err := secheaders.Middleware(mux, c)
+//	if err != nil {
+//	    log.Fatal(err) // csp.ErrInvalidSource, headers.ErrHSTSPreload, ...
+//	}
+//
+// # Nonces
+//
+// With Config.Nonce set, every response gets a fresh nonce in the script-src
+// and style-src directives of its policies, and handlers read it with Nonce to
+// mark their inline scripts and styles:
+//
+//	fmt.Fprintf(w, `<script nonce="%s">init()</script>`, secheaders.Nonce(r))
+//
+// # Auditing
+//
+// Audit inspects the headers of any response and reports what is missing or
+// weak, with a severity and a hint on how to fix it, and a score. In tests,
+// AuditHandler runs a handler and audits its response:
+//
+//	report := secheaders.AuditHandler(handler, httptest.NewRequest("GET", "/", nil))
+//	if err := report.Err(secheaders.SeverityMedium); err != nil {
+//	    t.Fatal(err)
+//	}
+package secheaders
@@ -0,0 +1,69 @@
+package secheaders
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+)
+
+type nonceKey struct{}
+
+// Middleware sets the headers of c on every response before calling next,
+// which can still change or remove them. c is validated and copied once, so
+// changing it afterwards has no effect; an invalid Config is reported here,
+// at startup, rather than sending a broken policy.
+//
+// Example:
+//
+//	secure, err := secheaders.Middleware(mux, secheaders.Strict())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	http.ListenAndServe(":8080", secure)
+func Middleware(next http.Handler, c Config) (http.Handler, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.CSP != nil {
+		c.CSP = c.CSP.Clone()
+	}
+	if c.CSPReportOnly != nil {
+		c.CSPReportOnly = c.CSPReportOnly.Clone()
+	}
+	if c.HSTS != nil {
+		hsts := *c.HSTS
+		c.HSTS = &hsts
+	}
+	c.ReferrerPolicy = slices.Clone(c.ReferrerPolicy)
+
+	if !c.Nonce {
+		h := http.Header{}
+		c.set(h, "")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, values := range h {
+				w.Header()[name] = slices.Clone(values)
+			}
+			next.ServeHTTP(w, r)
+		}), nil
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce := csp.NewNonce()
+		c.set(w.Header(), nonce)
+		ctx := context.WithValue(r.Context(), nonceKey{}, nonce)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}), nil
+}
+
+// Nonce returns the CSP nonce Middleware generated for r, to put in the nonce
+// attribute of inline scripts and styles, or "" if Config.Nonce is not set.
+//
+// Example:
+//
+//	<script nonce="{{ .Nonce }}">...</script>
+func Nonce(r *http.Request) string {
+	nonce, _ := r.Context().Value(nonceKey{}).(string)
+	return nonce
+}
@@ -0,0 +1,170 @@
+package secheaders_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/secheaders"
+)
+
+func serve(t *testing.T, c secheaders.Config, next http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	h, err := secheaders.Middleware(next, c)
+	if err != nil {
+		t.Fatalf("Middleware() error = %v", err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w
+}
+
+func TestMiddlewarePresets(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   secheaders.Config
+		expected map[string]string
+	}{
+		{
+			name:   "Strict",
+			config: secheaders.Strict(),
+			expected: map[string]string{
+				headers.StrictTransportSecurity:   "max-age=63072000; includeSubDomains",
+				headers.XContentTypeOptions:       "nosniff",
+				headers.XFrameOptions:             "DENY",
+				headers.ReferrerPolicy:            "no-referrer",
+				headers.CrossOriginOpenerPolicy:   "same-origin",
+				headers.CrossOriginEmbedderPolicy: "require-corp",
+				headers.CrossOriginResourcePolicy: "same-origin",
+				headers.PermissionsPolicy: "accelerometer=(), camera=(), geolocation=(), gyroscope=(), " +
+					"magnetometer=(), microphone=(), payment=(), usb=()",
+			},
+		},
+		{
+			name:   "APIOnly",
+			config: secheaders.APIOnly(),
+			expected: map[string]string{
+				headers.ContentSecurityPolicy:     "default-src 'none'; frame-ancestors 'none'",
+				headers.StrictTransportSecurity:   "max-age=63072000; includeSubDomains",
+				headers.XContentTypeOptions:       "nosniff",
+				headers.XFrameOptions:             "DENY",
+				headers.ReferrerPolicy:            "no-referrer",
+				headers.CrossOriginResourcePolicy: "",
+				headers.PermissionsPolicy:         "",
+			},
+		},
+		{
+			name:   "Relaxed",
+			config: secheaders.Relaxed(),
+			expected: map[string]string{
+				headers.ContentSecurityPolicy:           "",
+				headers.ContentSecurityPolicyReportOnly: "default-src 'self'; object-src 'none'",
+				headers.StrictTransportSecurity:         "max-age=31536000",
+				headers.XFrameOptions:                   "SAMEORIGIN",
+				headers.ReferrerPolicy:                  "strict-origin-when-cross-origin",
+				headers.CrossOriginOpenerPolicy:         "same-origin-allow-popups",
+				headers.CrossOriginEmbedderPolicy:       "",
+				headers.CrossOriginResourcePolicy:       "same-site",
+			},
+		},
+		{
+			name: "custom",
+			config: secheaders.Config{
+				NoSniff:           true,
+				ReferrerPolicy:    []headers.ReferrerPolicyValue{headers.ReferrerPolicyNoReferrer, headers.ReferrerPolicyStrictOrigin},
+				PermissionsPolicy: "geolocation=(self)",
+			},
+			expected: map[string]string{
+				headers.XContentTypeOptions:     "nosniff",
+				headers.ReferrerPolicy:          "no-referrer, strict-origin",
+				headers.PermissionsPolicy:       "geolocation=(self)",
+				headers.StrictTransportSecurity: "",
+				headers.XFrameOptions:           "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, tt.config, func(http.ResponseWriter, *http.Request) {})
+			for name, want := range tt.expected {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareNonce(t *testing.T) {
+	c := secheaders.Strict()
+	// style-src 'none' is left alone: 'none' cannot take a nonce.
+	c.CSP = csp.NewBuilder().ScriptSrc(csp.Self).StyleSrc(csp.None).ImgSrc(csp.Self)
+
+	var nonces []string
+	h, err := secheaders.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, secheaders.Nonce(r))
+	}), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var policies []string
+	for range 2 {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		policies = append(policies, w.Header().Get(headers.ContentSecurityPolicy))
+	}
+
+	if nonces[0] == "" || nonces[0] == nonces[1] {
+		t.Fatalf("nonces = %q, want two different ones", nonces)
+	}
+	for i, policy := range policies {
+		want := "script-src 'self' 'nonce-" + nonces[i] + "'; style-src 'none'; img-src 'self'"
+		if policy != want {
+			t.Errorf("policy %d = %q, want %q", i, policy, want)
+		}
+	}
+}
+
+func TestMiddlewareWithoutNonce(t *testing.T) {
+	c := secheaders.APIOnly()
+	w := serve(t, c, func(w http.ResponseWriter, r *http.Request) {
+		if got := secheaders.Nonce(r); got != "" {
+			t.Errorf("Nonce() = %q, want empty", got)
+		}
+		// Handlers can override what the middleware set.
+		w.Header().Set(headers.XFrameOptions, "SAMEORIGIN")
+	})
+	if got := w.Header().Get(headers.XFrameOptions); got != "SAMEORIGIN" {
+		t.Errorf("X-Frame-Options = %q, want the handler's value", got)
+	}
+}
+
+func TestMiddlewareCopiesConfig(t *testing.T) {
+	c := secheaders.APIOnly()
+	h, err := secheaders.Middleware(http.NotFoundHandler(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CSP.ImgSrc("https://a.example")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := w.Header().Get(headers.ContentSecurityPolicy); strings.Contains(got, "img-src") {
+		t.Errorf("Content-Security-Policy = %q, changed after Middleware", got)
+	}
+}
+
+func TestMiddlewareInvalid(t *testing.T) {
+	c := secheaders.Strict()
+	c.FrameOptions = "ALLOWALL"
+	h, err := secheaders.Middleware(http.NotFoundHandler(), c)
+	if !errors.Is(err, secheaders.ErrInvalidConfig) || h != nil {
+		t.Errorf("Middleware() = %v, %v, want nil, %v", h, err, secheaders.ErrInvalidConfig)
+	}
+}
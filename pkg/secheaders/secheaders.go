@@ -0,0 +1,264 @@
+package secheaders
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+)
+
+// ErrInvalidConfig is returned by Config.Validate and Middleware for a Config
+// that would send an invalid header.
+var ErrInvalidConfig = errors.New("secheaders: invalid configuration")
+
+// Cross-Origin-Opener-Policy values.
+const (
+	COOPUnsafeNone            = "unsafe-none"
+	COOPSameOriginAllowPopups = "same-origin-allow-popups"
+	COOPSameOrigin            = "same-origin"
+	COOPNoopenerAllowPopups   = "noopener-allow-popups"
+)
+
+// Cross-Origin-Embedder-Policy values.
+const (
+	COEPUnsafeNone     = "unsafe-none"
+	COEPRequireCorp    = "require-corp"
+	COEPCredentialless = "credentialless"
+)
+
+// Cross-Origin-Resource-Policy values.
+const (
+	CORPSameSite    = "same-site"
+	CORPSameOrigin  = "same-origin"
+	CORPCrossOrigin = "cross-origin"
+)
+
+// denyFeatures is the Permissions-Policy of Strict, which turns off the
+// device features a typical application does not use.
+const denyFeatures = "accelerometer=(), camera=(), geolocation=(), gyroscope=(), " +
+	"magnetometer=(), microphone=(), payment=(), usb=()"
+
+// Config is a bundle of security response headers. Each field controls one
+// header, and its zero value leaves the header out, so a Config only sends
+// what it is told to. Start from a preset and adjust it:
+//
+//	c := secheaders.Strict()
+//	c.CSP.ImgSrc("https://cdn.example.com")
+//	c.CrossOriginEmbedderPolicy = "" // embeds third-party images
+type Config struct {
+	// CSP is the enforced Content-Security-Policy.
+	CSP *csp.Builder
+
+	// CSPReportOnly is a Content-Security-Policy-Report-Only policy, sent next
+	// to CSP to try out a stricter one.
+	CSPReportOnly *csp.Builder
+
+	// Nonce adds a fresh nonce to the script-src and style-src directives of
+	// both policies on every response, when they have them. Handlers read it
+	// with Nonce(r) to put it on their inline scripts and styles.
+	Nonce bool
+
+	// HSTS is the Strict-Transport-Security policy. Browsers ignore it on
+	// plain HTTP responses, so it is safe to send on both.
+	HSTS *headers.HSTSValue
+
+	// NoSniff sends X-Content-Type-Options: nosniff.
+	NoSniff bool
+
+	// FrameOptions is the X-Frame-Options value, for browsers that do not
+	// support the frame-ancestors directive of CSP.
+	FrameOptions headers.XFrameOptionsValue
+
+	// ReferrerPolicy is the Referrer-Policy, a fallback list as accepted by
+	// headers.SetReferrerPolicy.
+	ReferrerPolicy []headers.ReferrerPolicyValue
+
+	// CrossOriginOpenerPolicy is one of the COOP constants.
+	CrossOriginOpenerPolicy string
+
+	// CrossOriginEmbedderPolicy is one of the COEP constants.
+	CrossOriginEmbedderPolicy string
+
+	// CrossOriginResourcePolicy is one of the CORP constants.
+	CrossOriginResourcePolicy string
+
+	// PermissionsPolicy is a Permissions-Policy value, a structured field
+	// dictionary such as "camera=(), geolocation=(self)".
+	PermissionsPolicy string
+}
+
+// Strict returns the configuration for an HTML application that loads
+// everything from its own origin: a nonce-based CSP that blocks plugins,
+// framing and <base> tricks, two years of HSTS on all subdomains, no
+// referrer, cross-origin isolation, and no access to device features.
+func Strict() Config {
+	return Config{
+		CSP: csp.NewBuilder().
+			DefaultSrc(csp.Self).
+			ScriptSrc(csp.Self).
+			StyleSrc(csp.Self).
+			ObjectSrc(csp.None).
+			BaseURI(csp.None).
+			FormAction(csp.Self).
+			FrameAncestors(csp.None).
+			UpgradeInsecureRequests(),
+		Nonce:                     true,
+		HSTS:                      &headers.HSTSValue{MaxAge: 2 * 365 * 24 * time.Hour, IncludeSubDomains: true},
+		NoSniff:                   true,
+		FrameOptions:              headers.XFODeny,
+		ReferrerPolicy:            []headers.ReferrerPolicyValue{headers.ReferrerPolicyNoReferrer},
+		CrossOriginOpenerPolicy:   COOPSameOrigin,
+		CrossOriginEmbedderPolicy: COEPRequireCorp,
+		CrossOriginResourcePolicy: CORPSameOrigin,
+		PermissionsPolicy:         denyFeatures,
+	}
+}
+
+// APIOnly returns the configuration for an API that serves data rather than
+// pages: a CSP that lets a response load nothing and be framed nowhere, in
+// case a browser renders it, HSTS, and no referrer. Cross-Origin-Resource-Policy
+// is left out, since it does not affect CORS requests and would only get in
+// the way of plain cross-origin fetches.
+func APIOnly() Config {
+	return Config{
+		CSP:            csp.NewBuilder().DefaultSrc(csp.None).FrameAncestors(csp.None),
+		HSTS:           &headers.HSTSValue{MaxAge: 2 * 365 * 24 * time.Hour, IncludeSubDomains: true},
+		NoSniff:        true,
+		FrameOptions:   headers.XFODeny,
+		ReferrerPolicy: []headers.ReferrerPolicyValue{headers.ReferrerPolicyNoReferrer},
+	}
+}
+
+// Relaxed returns a configuration that existing sites can adopt without
+// breaking: one year of HSTS without subdomains, framing by the same origin,
+// the browsers' default referrer policy, popups kept working, and the
+// same-origin CSP only reported, not enforced, until its violations are fixed.
+func Relaxed() Config {
+	return Config{
+		CSPReportOnly:             csp.NewBuilder().DefaultSrc(csp.Self).ObjectSrc(csp.None),
+		HSTS:                      &headers.HSTSValue{MaxAge: 365 * 24 * time.Hour},
+		NoSniff:                   true,
+		FrameOptions:              headers.XFOSameOrigin,
+		ReferrerPolicy:            []headers.ReferrerPolicyValue{headers.ReferrerPolicyStrictOriginWhenCrossOrigin},
+		CrossOriginOpenerPolicy:   COOPSameOriginAllowPopups,
+		CrossOriginResourcePolicy: CORPSameSite,
+	}
+}
+
+// Validate reports the first field of c that would send an invalid header,
+// as an error wrapping ErrInvalidConfig and the error of the package that
+// checked it, such as csp.ErrInvalidSource or headers.ErrHSTSPreload.
+func (c Config) Validate() error {
+	for _, b := range []*csp.Builder{c.CSP, c.CSPReportOnly} {
+		if b == nil {
+			continue
+		}
+		if _, err := b.Header(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+	if c.HSTS != nil {
+		if err := c.HSTS.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+	if c.FrameOptions != "" && !c.FrameOptions.Valid() {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidConfig, headers.ErrInvalidXFrameOptions, string(c.FrameOptions))
+	}
+	for i, v := range c.ReferrerPolicy {
+		if !v.Valid() || slices.Contains(c.ReferrerPolicy[:i], v) {
+			return fmt.Errorf("%w: %w: %q", ErrInvalidConfig, headers.ErrInvalidReferrerPolicy, string(v))
+		}
+	}
+	checks := []struct {
+		name, value string
+		allowed     []string
+	}{
+		{headers.CrossOriginOpenerPolicy, c.CrossOriginOpenerPolicy, []string{COOPUnsafeNone, COOPSameOriginAllowPopups, COOPSameOrigin, COOPNoopenerAllowPopups}},
+		{headers.CrossOriginEmbedderPolicy, c.CrossOriginEmbedderPolicy, []string{COEPUnsafeNone, COEPRequireCorp, COEPCredentialless}},
+		{headers.CrossOriginResourcePolicy, c.CrossOriginResourcePolicy, []string{CORPSameSite, CORPSameOrigin, CORPCrossOrigin}},
+	}
+	for _, check := range checks {
+		if check.value != "" && !slices.Contains(check.allowed, check.value) {
+			return fmt.Errorf("%w: %s %q", ErrInvalidConfig, check.name, check.value)
+		}
+	}
+	if c.PermissionsPolicy != "" {
+		if _, err := sfv.ParseDictionary(c.PermissionsPolicy); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, headers.PermissionsPolicy, err)
+		}
+	}
+	return nil
+}
+
+// set adds the headers of c to h, with nonce added to the script-src and
+// style-src directives when it is not empty. c must be valid.
+func (c Config) set(h http.Header, nonce string) {
+	policies := []struct {
+		name    string
+		builder *csp.Builder
+	}{
+		{headers.ContentSecurityPolicy, c.CSP},
+		{headers.ContentSecurityPolicyReportOnly, c.CSPReportOnly},
+	}
+	for _, p := range policies {
+		if p.builder == nil {
+			continue
+		}
+		if value, err := withNonce(p.builder, nonce).Header(); err == nil && value != "" {
+			h.Set(p.name, value)
+		}
+	}
+	if c.HSTS != nil {
+		h.Set(headers.StrictTransportSecurity, c.HSTS.String())
+	}
+	if c.NoSniff {
+		h.Set(headers.XContentTypeOptions, "nosniff")
+	}
+	if c.FrameOptions != "" {
+		h.Set(headers.XFrameOptions, string(c.FrameOptions))
+	}
+	if len(c.ReferrerPolicy) > 0 {
+		names := make([]string, len(c.ReferrerPolicy))
+		for i, v := range c.ReferrerPolicy {
+			names[i] = string(v)
+		}
+		h.Set(headers.ReferrerPolicy, strings.Join(names, ", "))
+	}
+	setIf(h, headers.CrossOriginOpenerPolicy, c.CrossOriginOpenerPolicy)
+	setIf(h, headers.CrossOriginEmbedderPolicy, c.CrossOriginEmbedderPolicy)
+	setIf(h, headers.CrossOriginResourcePolicy, c.CrossOriginResourcePolicy)
+	setIf(h, headers.PermissionsPolicy, c.PermissionsPolicy)
+}
+
+// withNonce returns b with the nonce added to the script-src and style-src
+// directives it sets, except those set to 'none', or b itself if nonce is
+// empty.
+func withNonce(b *csp.Builder, nonce string) *csp.Builder {
+	if nonce == "" {
+		return b
+	}
+	p, err := b.Policy()
+	if err != nil {
+		return b
+	}
+	b = b.Clone()
+	for _, d := range []csp.Directive{csp.ScriptSrc, csp.StyleSrc} {
+		if sources, ok := p.Get(d); ok && !slices.Equal(sources, []string{csp.None}) {
+			b.Add(d, csp.Nonce(nonce))
+		}
+	}
+	return b
+}
+
+func setIf(h http.Header, name, value string) {
+	if value != "" {
+		h.Set(name, value)
+	}
+}
@@ -0,0 +1,63 @@
+package secheaders_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mallardduck/go-http-helpers/pkg/csp"
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/headers/sfv"
+	"github.com/mallardduck/go-http-helpers/pkg/secheaders"
+)
+
+func TestPresetsValid(t *testing.T) {
+	presets := map[string]func() secheaders.Config{
+		"Strict":  secheaders.Strict,
+		"APIOnly": secheaders.APIOnly,
+		"Relaxed": secheaders.Relaxed,
+	}
+	for name, preset := range presets {
+		t.Run(name, func(t *testing.T) {
+			if err := preset().Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *secheaders.Config)
+		err    error
+	}{
+		{"zero", func(c *secheaders.Config) { *c = secheaders.Config{} }, nil},
+		{"csp", func(c *secheaders.Config) { c.CSP.ScriptSrc("self") }, csp.ErrInvalidSource},
+		{"csp report-only", func(c *secheaders.Config) { c.CSPReportOnly = csp.NewBuilder().ImgSrc("") }, csp.ErrInvalidSource},
+		{"hsts", func(c *secheaders.Config) { c.HSTS = &headers.HSTSValue{MaxAge: time.Hour, Preload: true} }, headers.ErrHSTSPreload},
+		{"frame options", func(c *secheaders.Config) { c.FrameOptions = "ALLOW-FROM https://a.example" }, headers.ErrInvalidXFrameOptions},
+		{"referrer policy", func(c *secheaders.Config) { c.ReferrerPolicy = []headers.ReferrerPolicyValue{"never"} }, headers.ErrInvalidReferrerPolicy},
+		{"repeated referrer policy", func(c *secheaders.Config) {
+			c.ReferrerPolicy = []headers.ReferrerPolicyValue{headers.ReferrerPolicyOrigin, headers.ReferrerPolicyOrigin}
+		}, headers.ErrInvalidReferrerPolicy},
+		{"coop", func(c *secheaders.Config) { c.CrossOriginOpenerPolicy = "same-site" }, secheaders.ErrInvalidConfig},
+		{"coep", func(c *secheaders.Config) { c.CrossOriginEmbedderPolicy = "require-cors" }, secheaders.ErrInvalidConfig},
+		{"corp", func(c *secheaders.Config) { c.CrossOriginResourcePolicy = "none" }, secheaders.ErrInvalidConfig},
+		{"permissions policy", func(c *secheaders.Config) { c.PermissionsPolicy = "camera=()," }, sfv.ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := secheaders.Strict()
+			tt.modify(&c)
+			err := c.Validate()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.err)
+			}
+			if err != nil && !errors.Is(err, secheaders.ErrInvalidConfig) {
+				t.Errorf("Validate() error = %v, want it to wrap %v", err, secheaders.ErrInvalidConfig)
+			}
+		})
+	}
+}
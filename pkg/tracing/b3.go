@@ -0,0 +1,185 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+// ParseB3 parses a single b3 header value, such as
+// "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90",
+// in the Zipkin B3 format: trace ID, of 64 or 128 bits, span ID, then
+// optionally the sampling state, 1, 0 or d for debug, and the parent span
+// ID. A value holding only a sampling state, such as "0", yields a
+// TraceContext that is not valid but carries the decision. Returns an error
+// wrapping ErrInvalidB3 for a malformed value.
+func ParseB3(value string) (TraceContext, error) {
+	var tc TraceContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) == 1 {
+		if parts[0] == "" {
+			return tc, ErrInvalidB3
+		}
+		return tc, tc.setB3Sampling(parts[0], "")
+	}
+	if len(parts) > 4 {
+		return tc, ErrInvalidB3
+	}
+	var err error
+	if tc.TraceID, err = parseB3TraceID(parts[0]); err != nil {
+		return TraceContext{}, err
+	}
+	if tc.SpanID, err = parseB3SpanID(parts[1]); err != nil {
+		return TraceContext{}, err
+	}
+	if len(parts) > 2 {
+		if err := tc.setB3Sampling(parts[2], ""); err != nil {
+			return TraceContext{}, err
+		}
+	}
+	if len(parts) > 3 {
+		if tc.ParentSpanID, err = parseB3SpanID(parts[3]); err != nil {
+			return TraceContext{}, err
+		}
+	}
+	return tc, nil
+}
+
+// B3 formats tc as a single b3 header value, with the sampling state and,
+// if known, the parent span ID. A tc that is not valid is formatted as its
+// sampling state alone.
+func (tc TraceContext) B3() string {
+	sampling := "0"
+	switch {
+	case tc.Debug:
+		sampling = "d"
+	case tc.Sampled:
+		sampling = "1"
+	}
+	if !tc.IsValid() {
+		return sampling
+	}
+	s := tc.TraceID.String() + "-" + tc.SpanID.String() + "-" + sampling
+	if tc.ParentSpanID.IsValid() {
+		s += "-" + tc.ParentSpanID.String()
+	}
+	return s
+}
+
+// ParseB3Multi parses the X-B3-TraceId, X-B3-SpanId, X-B3-ParentSpanId,
+// X-B3-Sampled and X-B3-Flags headers of h, the multi-header Zipkin B3
+// format. As with ParseB3, headers holding only a sampling decision yield a
+// TraceContext that is not valid, and so do no headers at all. Returns an
+// error wrapping ErrInvalidB3 for malformed headers, or a trace ID without
+// a span ID or the reverse.
+func ParseB3Multi(h http.Header) (TraceContext, error) {
+	var tc TraceContext
+	traceID, spanID := h.Get(headers.XB3TraceID), h.Get(headers.XB3SpanID)
+	if (traceID == "") != (spanID == "") {
+		return tc, fmt.Errorf("%w: trace ID and span ID go together", ErrInvalidB3)
+	}
+	var err error
+	if traceID != "" {
+		if tc.TraceID, err = parseB3TraceID(traceID); err != nil {
+			return TraceContext{}, err
+		}
+		if tc.SpanID, err = parseB3SpanID(spanID); err != nil {
+			return TraceContext{}, err
+		}
+		if parent := h.Get(headers.XB3ParentSpanID); parent != "" {
+			if tc.ParentSpanID, err = parseB3SpanID(parent); err != nil {
+				return TraceContext{}, err
+			}
+		}
+	}
+	sampled, flags := h.Get(headers.XB3Sampled), h.Get(headers.XB3Flags)
+	if sampled != "" || flags != "" {
+		if err := tc.setB3Sampling(sampled, flags); err != nil {
+			return TraceContext{}, err
+		}
+	}
+	return tc, nil
+}
+
+// SetB3Multi sets the X-B3-* headers of h from tc, for services that only
+// understand the multi-header B3 format. Headers for which tc has nothing
+// are removed.
+func SetB3Multi(h http.Header, tc TraceContext) {
+	for _, name := range []string{headers.XB3TraceID, headers.XB3SpanID, headers.XB3ParentSpanID, headers.XB3Sampled, headers.XB3Flags} {
+		h.Del(name)
+	}
+	if tc.IsValid() {
+		h.Set(headers.XB3TraceID, tc.TraceID.String())
+		h.Set(headers.XB3SpanID, tc.SpanID.String())
+		if tc.ParentSpanID.IsValid() {
+			h.Set(headers.XB3ParentSpanID, tc.ParentSpanID.String())
+		}
+	}
+	switch {
+	case tc.Debug:
+		h.Set(headers.XB3Flags, "1")
+	case tc.Sampled:
+		h.Set(headers.XB3Sampled, "1")
+	default:
+		h.Set(headers.XB3Sampled, "0")
+	}
+}
+
+// setB3Sampling sets the sampling decision from a single-header sampling
+// state, or from the X-B3-Sampled and X-B3-Flags headers.
+func (tc *TraceContext) setB3Sampling(sampling, flags string) error {
+	switch sampling {
+	case "1", "true": // "true" is a legacy X-B3-Sampled value
+		tc.Sampled = true
+	case "d":
+		if flags != "" {
+			return fmt.Errorf("%w: sampling state %q", ErrInvalidB3, sampling)
+		}
+		tc.Sampled, tc.Debug = true, true
+	case "0", "false", "":
+	default:
+		return fmt.Errorf("%w: sampling state %q", ErrInvalidB3, sampling)
+	}
+	switch flags {
+	case "1":
+		tc.Sampled, tc.Debug = true, true
+	case "":
+	default:
+		return fmt.Errorf("%w: flags %q", ErrInvalidB3, flags)
+	}
+	return nil
+}
+
+// parseB3TraceID parses a trace ID of 16 or 32 hex digits, left-padding
+// 64-bit IDs with zeros.
+func parseB3TraceID(s string) (TraceID, error) {
+	var id TraceID
+	if len(s) != 16 && len(s) != 32 {
+		return id, fmt.Errorf("%w: trace ID %q", ErrInvalidB3, s)
+	}
+	b, ok := lowerHex(s)
+	if !ok {
+		return id, fmt.Errorf("%w: trace ID %q", ErrInvalidB3, s)
+	}
+	copy(id[len(id)-len(b):], b)
+	if !id.IsValid() {
+		return id, fmt.Errorf("%w: zero trace ID", ErrInvalidB3)
+	}
+	return id, nil
+}
+
+// parseB3SpanID parses a span ID of 16 hex digits.
+func parseB3SpanID(s string) (SpanID, error) {
+	var id SpanID
+	b, ok := lowerHex(s)
+	if len(s) != 16 || !ok {
+		return id, fmt.Errorf("%w: span ID %q", ErrInvalidB3, s)
+	}
+	copy(id[:], b)
+	if !id.IsValid() {
+		return id, fmt.Errorf("%w: zero span ID", ErrInvalidB3)
+	}
+	return id, nil
+}
@@ -0,0 +1,144 @@
+package tracing_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/tracing"
+)
+
+func TestParseB3(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		valid     bool
+		sampled   bool
+		debug     bool
+		parent    string
+		wantError bool
+	}{
+		{"ids only", traceID + "-" + spanID, true, false, false, "", false},
+		{"sampled", traceID + "-" + spanID + "-1", true, true, false, "", false},
+		{"with parent", traceID + "-" + spanID + "-0-" + parentID, true, false, false, parentID, false},
+		{"debug", traceID + "-" + spanID + "-d", true, true, true, "", false},
+		{"64-bit trace ID", "a3ce929d0e0e4736-" + spanID + "-1", true, true, false, "", false},
+		{"deny only", "0", false, false, false, "", false},
+		{"accept only", "1", false, true, false, "", false},
+		{"debug only", "d", false, true, true, "", false},
+		{"bad sampling", traceID + "-" + spanID + "-2", false, false, false, "", true},
+		{"upper case", "4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID, false, false, false, "", true},
+		{"short span", traceID + "-00f067aa", false, false, false, "", true},
+		{"zero span", traceID + "-0000000000000000", false, false, false, "", true},
+		{"too many parts", traceID + "-" + spanID + "-1-" + parentID + "-x", false, false, false, "", true},
+		{"empty", "", false, false, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := tracing.ParseB3(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseB3() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				if !errors.Is(err, tracing.ErrInvalidB3) {
+					t.Errorf("ParseB3() error = %v, want ErrInvalidB3", err)
+				}
+				return
+			}
+			if tc.IsValid() != tt.valid || tc.Sampled != tt.sampled || tc.Debug != tt.debug {
+				t.Errorf("ParseB3() = %+v, want valid %v, sampled %v, debug %v", tc, tt.valid, tt.sampled, tt.debug)
+			}
+			if tt.parent != "" && tc.ParentSpanID.String() != tt.parent {
+				t.Errorf("ParseB3() ParentSpanID = %s, want %s", tc.ParentSpanID, tt.parent)
+			}
+		})
+	}
+}
+
+func TestB3(t *testing.T) {
+	tc, _ := tracing.ParseB3("a3ce929d0e0e4736-" + spanID + "-d-" + parentID)
+	expected := "0000000000000000a3ce929d0e0e4736-" + spanID + "-d-" + parentID
+	if got := tc.B3(); got != expected {
+		t.Errorf("B3() = %q, want %q", got, expected)
+	}
+	if got := (tracing.TraceContext{}).B3(); got != "0" {
+		t.Errorf("B3() of an empty TraceContext = %q, want %q", got, "0")
+	}
+}
+
+func TestParseB3Multi(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    map[string]string
+		valid     bool
+		sampled   bool
+		debug     bool
+		wantError bool
+	}{
+		{"full", map[string]string{
+			headers.XB3TraceID: traceID, headers.XB3SpanID: spanID, headers.XB3ParentSpanID: parentID, headers.XB3Sampled: "1",
+		}, true, true, false, false},
+		{"legacy true", map[string]string{
+			headers.XB3TraceID: traceID, headers.XB3SpanID: spanID, headers.XB3Sampled: "true",
+		}, true, true, false, false},
+		{"debug flag", map[string]string{
+			headers.XB3TraceID: traceID, headers.XB3SpanID: spanID, headers.XB3Flags: "1",
+		}, true, true, true, false},
+		{"sampling only", map[string]string{headers.XB3Sampled: "0"}, false, false, false, false},
+		{"none", map[string]string{}, false, false, false, false},
+		{"trace ID alone", map[string]string{headers.XB3TraceID: traceID}, false, false, false, true},
+		{"bad sampled", map[string]string{headers.XB3Sampled: "yes"}, false, false, false, true},
+		{"bad parent", map[string]string{
+			headers.XB3TraceID: traceID, headers.XB3SpanID: spanID, headers.XB3ParentSpanID: "x",
+		}, false, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for name, value := range tt.header {
+				h.Set(name, value)
+			}
+			tc, err := tracing.ParseB3Multi(h)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseB3Multi() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				if !errors.Is(err, tracing.ErrInvalidB3) {
+					t.Errorf("ParseB3Multi() error = %v, want ErrInvalidB3", err)
+				}
+				return
+			}
+			if tc.IsValid() != tt.valid || tc.Sampled != tt.sampled || tc.Debug != tt.debug {
+				t.Errorf("ParseB3Multi() = %+v, want valid %v, sampled %v, debug %v", tc, tt.valid, tt.sampled, tt.debug)
+			}
+		})
+	}
+}
+
+func TestSetB3Multi(t *testing.T) {
+	tc, _ := tracing.ParseB3(traceID + "-" + spanID + "-1-" + parentID)
+	h := http.Header{}
+	h.Set(headers.XB3Flags, "1")
+	tracing.SetB3Multi(h, tc)
+
+	expected := map[string]string{
+		headers.XB3TraceID:      traceID,
+		headers.XB3SpanID:       spanID,
+		headers.XB3ParentSpanID: parentID,
+		headers.XB3Sampled:      "1",
+		headers.XB3Flags:        "",
+	}
+	for name, want := range expected {
+		if got := h.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	back, err := tracing.ParseB3Multi(h)
+	if err != nil || back.TraceID != tc.TraceID || back.SpanID != tc.SpanID || back.ParentSpanID != tc.ParentSpanID || !back.Sampled {
+		t.Errorf("ParseB3Multi() of SetB3Multi() = %+v, %v, want %+v", back, err, tc)
+	}
+}
@@ -0,0 +1,24 @@
+// Package tracing propagates distributed tracing headers, so that a service
+// can join the traces of its callers and pass them on to the services it
+// calls without adopting a full tracing SDK.
+//
+// Extract reads the trace of an incoming request from the W3C Trace Context
+// traceparent and tracestate headers or the Zipkin B3 ones, along with its
+// X-Request-ID, and Inject writes it to an outgoing request, after Child has
+// given it a new span:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    tc := tracing.Extract(r)
+//	    log.Printf("trace=%s request=%s", tc.TraceID, tc.RequestID)
+//
+//	    out := tc.Child()
+//	    req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, inventoryURL, nil)
+//	    tracing.Inject(req.Header, out)
+//	    resp, err := http.DefaultClient.Do(req)
+//	    // ...
+//	}
+//
+// ParseTraceparent, ParseTracestate, ParseB3 and ParseB3Multi parse each
+// format on its own, and TraceContext.Traceparent, Tracestate.String,
+// TraceContext.B3 and SetB3Multi write them.
+package tracing
@@ -0,0 +1,193 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// traceparent flags.
+const flagSampled = 0x01
+
+// maxTracestateMembers is the most list members a tracestate may have.
+const maxTracestateMembers = 32
+
+// ParseTraceparent parses a W3C Trace Context traceparent value, such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01". Values of a
+// later version are parsed as version 00, ignoring what follows, as the
+// specification requires. Returns an error wrapping ErrInvalidTraceparent
+// for a malformed value or a zero trace or span ID.
+func ParseTraceparent(value string) (TraceContext, error) {
+	var tc TraceContext
+	value = strings.TrimSpace(value)
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return tc, ErrInvalidTraceparent
+	}
+	version, ok := lowerHex(value[:2])
+	if !ok || version[0] == 0xff || version[0] == 0 && len(value) != 55 || len(value) > 55 && value[55] != '-' {
+		return tc, fmt.Errorf("%w: version %q", ErrInvalidTraceparent, value[:2])
+	}
+	traceID, ok := lowerHex(value[3:35])
+	if !ok {
+		return tc, fmt.Errorf("%w: trace ID", ErrInvalidTraceparent)
+	}
+	spanID, ok := lowerHex(value[36:52])
+	if !ok {
+		return tc, fmt.Errorf("%w: parent ID", ErrInvalidTraceparent)
+	}
+	flags, ok := lowerHex(value[53:55])
+	if !ok {
+		return tc, fmt.Errorf("%w: flags", ErrInvalidTraceparent)
+	}
+	copy(tc.TraceID[:], traceID)
+	copy(tc.SpanID[:], spanID)
+	if !tc.IsValid() {
+		return TraceContext{}, fmt.Errorf("%w: zero ID", ErrInvalidTraceparent)
+	}
+	tc.Sampled = flags[0]&flagSampled != 0
+	return tc, nil
+}
+
+// Traceparent formats tc as a version 00 traceparent value. Debug implies
+// the sampled flag. The value is meaningless unless tc is valid.
+func (tc TraceContext) Traceparent() string {
+	flags := "00"
+	if tc.Sampled || tc.Debug {
+		flags = "01"
+	}
+	return "00-" + tc.TraceID.String() + "-" + tc.SpanID.String() + "-" + flags
+}
+
+// lowerHex decodes s, which must be lower-case hex.
+func lowerHex(s string) ([]byte, bool) {
+	for i := range len(s) {
+		if c := s[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// TracestateMember is one vendor entry of a tracestate header.
+type TracestateMember struct {
+	Key   string // Such as "congo" or "tenant@vendor"
+	Value string
+}
+
+// Tracestate is the list of vendor entries of a tracestate header, most
+// recently updated first.
+type Tracestate []TracestateMember
+
+// ParseTracestate parses tracestate header values, such as
+// "congo=t61rcWkgMzE, rojo=00f067aa0ba902b7". Empty list members are
+// skipped. Returns an error wrapping ErrInvalidTracestate for a malformed
+// member, a duplicate key or more than 32 members, in which case the whole
+// header must be ignored.
+func ParseTracestate(values ...string) (Tracestate, error) {
+	var ts Tracestate
+	for _, value := range values {
+		for member := range strings.SplitSeq(value, ",") {
+			member = strings.Trim(member, " \t")
+			if member == "" {
+				continue
+			}
+			key, val, ok := strings.Cut(member, "=")
+			if !ok || !validTracestateKey(key) || !validTracestateValue(val) {
+				return nil, fmt.Errorf("%w: member %q", ErrInvalidTracestate, member)
+			}
+			if len(ts) == maxTracestateMembers {
+				return nil, fmt.Errorf("%w: more than %d members", ErrInvalidTracestate, maxTracestateMembers)
+			}
+			if ts.Get(key) != "" {
+				return nil, fmt.Errorf("%w: duplicate key %q", ErrInvalidTracestate, key)
+			}
+			ts = append(ts, TracestateMember{Key: key, Value: val})
+		}
+	}
+	return ts, nil
+}
+
+// String formats ts as a tracestate header value.
+func (ts Tracestate) String() string {
+	members := make([]string, len(ts))
+	for i, m := range ts {
+		members[i] = m.Key + "=" + m.Value
+	}
+	return strings.Join(members, ",")
+}
+
+// Get returns the value for key, or "" if ts has none.
+func (ts Tracestate) Get(key string) string {
+	for _, m := range ts {
+		if m.Key == key {
+			return m.Value
+		}
+	}
+	return ""
+}
+
+// Put returns ts with key set to value and moved to the front, as a vendor
+// does when it updates its entry, dropping the last members beyond the
+// limit of 32. Returns an error wrapping ErrInvalidTracestate if key or
+// value is malformed. ts itself is not modified.
+//
+// Example:
+//
+//	tc := tracing.Extract(r).Child()
+//	tc.State, err = tc.State.Put("acme", tc.SpanID.String())
+func (ts Tracestate) Put(key, value string) (Tracestate, error) {
+	if !validTracestateKey(key) || !validTracestateValue(value) {
+		return ts, fmt.Errorf("%w: member %q", ErrInvalidTracestate, key+"="+value)
+	}
+	out := Tracestate{{Key: key, Value: value}}
+	for _, m := range ts {
+		if m.Key != key && len(out) < maxTracestateMembers {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// validTracestateKey reports whether key is a simple key, such as "rojo",
+// or a multi-tenant one, such as "tenant@vendor".
+func validTracestateKey(key string) bool {
+	tenant, system, multi := strings.Cut(key, "@")
+	if !multi {
+		return len(key) <= 256 && validKeyPart(key, true)
+	}
+	return len(tenant) <= 241 && validKeyPart(tenant, false) &&
+		len(system) <= 14 && validKeyPart(system, true)
+}
+
+// validKeyPart checks the characters of a key part, which must start with a
+// lower-case letter, or also with a digit unless letterFirst is set.
+func validKeyPart(s string, letterFirst bool) bool {
+	if s == "" {
+		return false
+	}
+	if c := s[0]; !('a' <= c && c <= 'z' || !letterFirst && '0' <= c && c <= '9') {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '*' || c == '/') {
+			return false
+		}
+	}
+	return true
+}
+
+// validTracestateValue reports whether value is up to 256 printable ASCII
+// characters other than "," and "=", not ending with a space.
+func validTracestateValue(value string) bool {
+	if value == "" || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := range len(value) {
+		if c := value[i]; c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package tracing_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/tracing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		sampled   bool
+		wantError bool
+	}{
+		{"sampled", "00-" + traceID + "-" + spanID + "-01", true, false},
+		{"not sampled", "00-" + traceID + "-" + spanID + "-00", false, false},
+		{"other flags", "00-" + traceID + "-" + spanID + "-09", true, false},
+		{"future version", "cc-" + traceID + "-" + spanID + "-01-what-the-future-holds", true, false},
+		{"future version exact", "01-" + traceID + "-" + spanID + "-01", true, false},
+		{"version 00 too long", "00-" + traceID + "-" + spanID + "-01-extra", false, true},
+		{"future version bad suffix", "01-" + traceID + "-" + spanID + "-01x", false, true},
+		{"version ff", "ff-" + traceID + "-" + spanID + "-01", false, true},
+		{"upper case", "00-" + strings.ToUpper(traceID) + "-" + spanID + "-01", false, true},
+		{"zero trace ID", "00-" + strings.Repeat("0", 32) + "-" + spanID + "-01", false, true},
+		{"zero span ID", "00-" + traceID + "-" + strings.Repeat("0", 16) + "-01", false, true},
+		{"short", "00-" + traceID + "-" + spanID, false, true},
+		{"empty", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := tracing.ParseTraceparent(tt.value)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseTraceparent() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				if !errors.Is(err, tracing.ErrInvalidTraceparent) {
+					t.Errorf("ParseTraceparent() error = %v, want ErrInvalidTraceparent", err)
+				}
+				return
+			}
+			if tc.TraceID.String() != traceID || tc.SpanID.String() != spanID || tc.Sampled != tt.sampled {
+				t.Errorf("ParseTraceparent() = %s %s %v, want %s %s %v", tc.TraceID, tc.SpanID, tc.Sampled, traceID, spanID, tt.sampled)
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	for _, value := range []string{
+		"00-" + traceID + "-" + spanID + "-01",
+		"00-" + traceID + "-" + spanID + "-00",
+	} {
+		tc, err := tracing.ParseTraceparent(value)
+		if err != nil {
+			t.Fatalf("ParseTraceparent(%q) error = %v", value, err)
+		}
+		if got := tc.Traceparent(); got != value {
+			t.Errorf("Traceparent() = %q, want %q", got, value)
+		}
+	}
+}
+
+func TestParseTracestate(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		expected  string
+		wantError bool
+	}{
+		{"single", []string{"congo=t61rcWkgMzE"}, "congo=t61rcWkgMzE", false},
+		{"several lines", []string{"rojo=00f067aa0ba902b7", "congo=t61rcWkgMzE"}, "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", false},
+		{"whitespace and empty members", []string{" rojo=1 ,, congo=2\t"}, "rojo=1,congo=2", false},
+		{"multi-tenant key", []string{"1tenant@vendor=x"}, "1tenant@vendor=x", false},
+		{"value with spaces", []string{"k=a b"}, "k=a b", false},
+		{"upper-case key", []string{"Congo=1"}, "", true},
+		{"digit first simple key", []string{"1congo=1"}, "", true},
+		{"missing value", []string{"congo"}, "", true},
+		{"equals in value", []string{"congo=a=b"}, "", true},
+		{"duplicate key", []string{"congo=1,congo=2"}, "", true},
+		{"too many members", []string{tooManyMembers(33)}, "", true},
+		{"far too many members", []string{tooManyMembers(100000)}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := tracing.ParseTracestate(tt.values...)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseTracestate() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil && !errors.Is(err, tracing.ErrInvalidTracestate) {
+				t.Errorf("ParseTracestate() error = %v, want ErrInvalidTracestate", err)
+			}
+			if got := ts.String(); got != tt.expected {
+				t.Errorf("ParseTracestate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func tooManyMembers(n int) string {
+	members := make([]string, n)
+	for i := range members {
+		members[i] = fmt.Sprintf("k%d=v", i)
+	}
+	return strings.Join(members, ",")
+}
+
+func TestTracestatePut(t *testing.T) {
+	ts, _ := tracing.ParseTracestate("rojo=1,congo=2")
+
+	got, err := ts.Put("congo", "3")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if got.String() != "congo=3,rojo=1" {
+		t.Errorf("Put() = %q, want %q", got, "congo=3,rojo=1")
+	}
+	if ts.String() != "rojo=1,congo=2" {
+		t.Errorf("Put() modified its receiver: %q", ts)
+	}
+	if got.Get("rojo") != "1" || got.Get("missing") != "" {
+		t.Errorf("Get() = %q, %q", got.Get("rojo"), got.Get("missing"))
+	}
+
+	if _, err := ts.Put("Bad", "1"); !errors.Is(err, tracing.ErrInvalidTracestate) {
+		t.Errorf("Put() of a bad key error = %v, want ErrInvalidTracestate", err)
+	}
+
+	full, _ := tracing.ParseTracestate(strings.Join(strings.Split(tooManyMembers(33), ",")[:32], ","))
+	got, _ = full.Put("new", "1")
+	if len(got) != 32 || got[0].Key != "new" || got.Get("k31") != "" {
+		t.Errorf("Put() on a full Tracestate = %q, want the last member dropped", got)
+	}
+}
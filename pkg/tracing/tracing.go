@@ -0,0 +1,187 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+)
+
+var (
+	// ErrInvalidTraceparent is returned for a traceparent value that does not
+	// follow the W3C Trace Context format.
+	ErrInvalidTraceparent = errors.New("tracing: invalid traceparent header")
+
+	// ErrInvalidTracestate is returned for a malformed tracestate value.
+	ErrInvalidTracestate = errors.New("tracing: invalid tracestate header")
+
+	// ErrInvalidB3 is returned for B3 headers that do not follow the Zipkin
+	// B3 format.
+	ErrInvalidB3 = errors.New("tracing: invalid B3 header")
+)
+
+// TraceID identifies a trace, the tree of spans of a request across
+// services. The zero TraceID is invalid.
+type TraceID [16]byte
+
+// String returns id as 32 lower-case hex digits.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether id is not zero.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span, the work of one service on a request. The zero
+// SpanID is invalid.
+type SpanID [8]byte
+
+// String returns id as 16 lower-case hex digits.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether id is not zero.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// TraceContext is the tracing state a request carries from service to
+// service.
+type TraceContext struct {
+	TraceID      TraceID
+	SpanID       SpanID     // Span of the caller, the parent of the receiver's
+	ParentSpanID SpanID     // Parent of SpanID, carried by B3 only; zero if unknown
+	Sampled      bool       // The caller records the trace
+	Debug        bool       // B3 debug flag, which implies Sampled
+	State        Tracestate // W3C vendor data, passed on unchanged
+	RequestID    string     // X-Request-ID, "" if none
+}
+
+// IsValid reports whether tc has a trace ID and a span ID, so that spans
+// recorded for it join an existing trace. A request may carry a sampling
+// decision or a request ID without them.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID.IsValid() && tc.SpanID.IsValid()
+}
+
+// New returns a TraceContext starting a new trace, with random IDs.
+func New(sampled bool) TraceContext {
+	tc := TraceContext{Sampled: sampled, SpanID: newSpanID()}
+	for !tc.TraceID.IsValid() {
+		_, _ = rand.Read(tc.TraceID[:]) // never fails, see crypto/rand.Read
+	}
+	return tc
+}
+
+// Child returns the TraceContext for a request made while serving tc: the
+// same trace with a new span, whose parent is the span of tc. A tc without
+// a valid trace starts a new one, keeping the sampling decision.
+//
+// Example:
+//
+//	out := tracing.Extract(r).Child()
+//	req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, inventoryURL, nil)
+//	tracing.Inject(req.Header, out)
+func (tc TraceContext) Child() TraceContext {
+	if !tc.IsValid() {
+		child := New(tc.Sampled || tc.Debug)
+		child.Debug = tc.Debug
+		child.State = tc.State
+		child.RequestID = tc.RequestID
+		return child
+	}
+	tc.ParentSpanID = tc.SpanID
+	tc.SpanID = newSpanID()
+	return tc
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:]) // never fails, see crypto/rand.Read
+	}
+	return id
+}
+
+// Extract returns the TraceContext of r, read from the first of these that
+// is present and valid: the traceparent header with its tracestate, the
+// single b3 header, or the X-B3-* headers. A tracestate that is malformed is
+// dropped, as the W3C specification requires. The X-Request-ID header is
+// read in any case, unless it holds anything but visible ASCII or is longer
+// than MaxRequestIDLength, as it usually ends up in logs.
+//
+// Extract never fails: a request without tracing headers, or with malformed
+// ones, yields a TraceContext that is not valid, from which Child starts a
+// new trace.
+//
+// Example:
+//
+//	tc := tracing.Extract(r)
+//	logger := slog.With("trace_id", tc.TraceID, "request_id", tc.RequestID)
+func Extract(r *http.Request) TraceContext {
+	var tc TraceContext
+	if v := r.Header.Get(headers.Traceparent); v != "" {
+		if parsed, err := ParseTraceparent(v); err == nil {
+			tc = parsed
+			tc.State, _ = ParseTracestate(r.Header.Values(headers.Tracestate)...)
+		}
+	}
+	if !tc.IsValid() {
+		if v := r.Header.Get(headers.B3); v != "" {
+			tc, _ = ParseB3(v)
+		} else {
+			tc, _ = ParseB3Multi(r.Header)
+		}
+	}
+	if id := r.Header.Get(headers.XRequestID); validRequestID(id) {
+		tc.RequestID = id
+	}
+	return tc
+}
+
+// Inject sets the tracing headers of an outgoing request from tc: the
+// traceparent and tracestate headers, the single b3 header, understood by
+// Zipkin-based services, and X-Request-ID. Headers for which tc has nothing,
+// including every trace header if tc is not valid, are removed, so that h
+// never carries a stale trace. Use Child for the TraceContext of a request
+// made while serving another.
+func Inject(h http.Header, tc TraceContext) {
+	for _, name := range []string{
+		headers.Traceparent, headers.Tracestate, headers.B3,
+		headers.XB3TraceID, headers.XB3SpanID, headers.XB3ParentSpanID, headers.XB3Sampled, headers.XB3Flags,
+	} {
+		h.Del(name)
+	}
+	if tc.IsValid() {
+		h.Set(headers.Traceparent, tc.Traceparent())
+		if len(tc.State) > 0 {
+			h.Set(headers.Tracestate, tc.State.String())
+		}
+		h.Set(headers.B3, tc.B3())
+	}
+	if tc.RequestID != "" {
+		h.Set(headers.XRequestID, tc.RequestID)
+	} else {
+		h.Del(headers.XRequestID)
+	}
+}
+
+// MaxRequestIDLength is the longest X-Request-ID Extract accepts.
+const MaxRequestIDLength = 200
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
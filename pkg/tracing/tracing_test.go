@@ -0,0 +1,157 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mallardduck/go-http-helpers/pkg/headers"
+	"github.com/mallardduck/go-http-helpers/pkg/tracing"
+)
+
+const (
+	traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID   = "00f067aa0ba902b7"
+	parentID = "05e3ac9a4f6e3b90"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    map[string]string
+		traceID   string
+		spanID    string
+		sampled   bool
+		state     string
+		requestID string
+	}{
+		{
+			name: "traceparent",
+			header: map[string]string{
+				headers.Traceparent: "00-" + traceID + "-" + spanID + "-01",
+				headers.Tracestate:  "congo=t61rcWkgMzE",
+				headers.B3:          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-0",
+			},
+			traceID: traceID, spanID: spanID, sampled: true, state: "congo=t61rcWkgMzE",
+		},
+		{
+			name: "malformed tracestate dropped",
+			header: map[string]string{
+				headers.Traceparent: "00-" + traceID + "-" + spanID + "-00",
+				headers.Tracestate:  "Congo=t61rcWkgMzE",
+			},
+			traceID: traceID, spanID: spanID,
+		},
+		{
+			name: "invalid traceparent falls back to b3",
+			header: map[string]string{
+				headers.Traceparent: "00-" + strings.Repeat("0", 32) + "-" + spanID + "-01",
+				headers.B3:          traceID + "-" + spanID + "-1",
+			},
+			traceID: traceID, spanID: spanID, sampled: true,
+		},
+		{
+			name: "b3 multi",
+			header: map[string]string{
+				headers.XB3TraceID: traceID,
+				headers.XB3SpanID:  spanID,
+				headers.XB3Sampled: "1",
+			},
+			traceID: traceID, spanID: spanID, sampled: true,
+		},
+		{
+			name:      "request ID only",
+			header:    map[string]string{headers.XRequestID: "f058ebd6-02f7-4d3f-942e-904344e8cde5"},
+			requestID: "f058ebd6-02f7-4d3f-942e-904344e8cde5",
+		},
+		{
+			name:   "request ID with control characters",
+			header: map[string]string{headers.XRequestID: "abc\x1b[31m"},
+		},
+		{
+			name:   "none",
+			header: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.header {
+				r.Header.Set(name, value)
+			}
+			tc := tracing.Extract(r)
+			if tt.traceID == "" {
+				if tc.IsValid() {
+					t.Errorf("Extract() = %+v, want no valid trace", tc)
+				}
+			} else if tc.TraceID.String() != tt.traceID || tc.SpanID.String() != tt.spanID {
+				t.Errorf("Extract() IDs = %s %s, want %s %s", tc.TraceID, tc.SpanID, tt.traceID, tt.spanID)
+			}
+			if tc.Sampled != tt.sampled {
+				t.Errorf("Extract() Sampled = %v, want %v", tc.Sampled, tt.sampled)
+			}
+			if got := tc.State.String(); got != tt.state {
+				t.Errorf("Extract() State = %q, want %q", got, tt.state)
+			}
+			if tc.RequestID != tt.requestID {
+				t.Errorf("Extract() RequestID = %q, want %q", tc.RequestID, tt.requestID)
+			}
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(headers.Traceparent, "00-"+traceID+"-"+spanID+"-01")
+	r.Header.Set(headers.Tracestate, "rojo=00f067aa0ba902b7")
+	r.Header.Set(headers.XRequestID, "req-1")
+	tc := tracing.Extract(r)
+
+	h := http.Header{}
+	h.Set(headers.XB3TraceID, "stale")
+	tracing.Inject(h, tc)
+
+	expected := map[string]string{
+		headers.Traceparent: "00-" + traceID + "-" + spanID + "-01",
+		headers.Tracestate:  "rojo=00f067aa0ba902b7",
+		headers.B3:          traceID + "-" + spanID + "-1",
+		headers.XRequestID:  "req-1",
+		headers.XB3TraceID:  "",
+	}
+	for name, want := range expected {
+		if got := h.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	tracing.Inject(h, tracing.TraceContext{})
+	if len(h) != 0 {
+		t.Errorf("Inject() of an empty TraceContext left %v", h)
+	}
+}
+
+func TestNewAndChild(t *testing.T) {
+	root := tracing.New(true)
+	if !root.IsValid() || !root.Sampled {
+		t.Fatalf("New(true) = %+v, want a valid sampled trace", root)
+	}
+
+	child := root.Child()
+	if child.TraceID != root.TraceID {
+		t.Errorf("Child() TraceID = %s, want %s", child.TraceID, root.TraceID)
+	}
+	if child.ParentSpanID != root.SpanID || child.SpanID == root.SpanID || !child.SpanID.IsValid() {
+		t.Errorf("Child() spans = %s (parent %s), want a new span with parent %s", child.SpanID, child.ParentSpanID, root.SpanID)
+	}
+	if !child.Sampled {
+		t.Error("Child() lost the sampling decision")
+	}
+
+	// Without a trace, Child starts one, keeping the request ID and decision.
+	orphan := tracing.TraceContext{Debug: true, RequestID: "req-1"}.Child()
+	if !orphan.IsValid() || !orphan.Sampled || !orphan.Debug || orphan.RequestID != "req-1" {
+		t.Errorf("Child() of an empty TraceContext = %+v", orphan)
+	}
+}